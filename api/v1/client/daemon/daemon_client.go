@@ -55,6 +55,38 @@ func (a *Client) GetConfig(params *GetConfigParams) (*GetConfigOK, error) {
 
 }
 
+/*
+GetConfigLabels gets the identity relevant label filter
+
+Returns the label prefixes used to determine which labels of an
+endpoint are relevant for its security identity.
+
+*/
+func (a *Client) GetConfigLabels(params *GetConfigLabelsParams) (*GetConfigLabelsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetConfigLabelsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetConfigLabels",
+		Method:             "GET",
+		PathPattern:        "/config/labels",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetConfigLabelsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetConfigLabelsOK), nil
+
+}
+
 /*
 GetDebuginfo retrieves information about the agent and evironment for debugging
 */
@@ -205,6 +237,39 @@ func (a *Client) PatchConfig(params *PatchConfigParams) (*PatchConfigOK, error)
 
 }
 
+/*
+PutConfigLabels modifies the identity relevant label filter
+
+Replaces the user configured label prefixes and re-identifies all
+endpoints whose identity relevant labels change. If dry-run is set,
+only the impact of the change on the identities in use is reported.
+
+*/
+func (a *Client) PutConfigLabels(params *PutConfigLabelsParams) (*PutConfigLabelsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPutConfigLabelsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PutConfigLabels",
+		Method:             "PUT",
+		PathPattern:        "/config/labels",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PutConfigLabelsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PutConfigLabelsOK), nil
+
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
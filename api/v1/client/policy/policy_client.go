@@ -111,6 +111,71 @@ func (a *Client) GetIdentityID(params *GetIdentityIDParams) (*GetIdentityIDOK, e
 
 }
 
+/*
+GetIdentityIDUsage retrieves usage of an identity
+
+Retrieves the local references to the identity, its local users and
+the nodes in the cluster using it.
+
+*/
+func (a *Client) GetIdentityIDUsage(params *GetIdentityIDUsageParams) (*GetIdentityIDUsageOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIdentityIDUsageParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIdentityIDUsage",
+		Method:             "GET",
+		PathPattern:        "/identity/{id}/usage",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIdentityIDUsageReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIdentityIDUsageOK), nil
+
+}
+
+/*
+GetIdentityStale retrieves identities without local users
+
+Retrieves the identities which are still referenced by the identity
+allocator of this node but have not been used by any local endpoint
+or CIDR prefix for at least the specified duration.
+
+*/
+func (a *Client) GetIdentityStale(params *GetIdentityStaleParams) (*GetIdentityStaleOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIdentityStaleParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIdentityStale",
+		Method:             "GET",
+		PathPattern:        "/identity/stale",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIdentityStaleReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIdentityStaleOK), nil
+
+}
+
 /*
 GetPolicy retrieves entire policy tree
 
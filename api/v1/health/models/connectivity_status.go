@@ -17,27 +17,61 @@ import (
 
 type ConnectivityStatus struct {
 
+	// Statistics over the recent probes of this path
+	History *ConnectivityHistory `json:"history,omitempty"`
+
 	// Round trip time to node in nanoseconds
 	Latency int64 `json:"latency,omitempty"`
 
+	// Percentage of probes lost
+	Loss float64 `json:"loss,omitempty"`
+
 	// Human readable status/error/warning message
 	Status string `json:"status,omitempty"`
 }
 
+/* polymorph ConnectivityStatus history false */
+
 /* polymorph ConnectivityStatus latency false */
 
+/* polymorph ConnectivityStatus loss false */
+
 /* polymorph ConnectivityStatus status false */
 
 // Validate validates this connectivity status
 func (m *ConnectivityStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateHistory(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *ConnectivityStatus) validateHistory(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.History) { // not required
+		return nil
+	}
+
+	if m.History != nil {
+
+		if err := m.History.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("history")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *ConnectivityStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -27,6 +27,12 @@ type PathStatus struct {
 
 	// IP address queried for the connectivity status
 	IP string `json:"ip,omitempty"`
+
+	// Path MTU discovered towards the IP
+	Pmtu *PathMTUStatus `json:"pmtu,omitempty"`
+
+	// Connectivity status of TCP connects to the health port
+	TCP *ConnectivityStatus `json:"tcp,omitempty"`
 }
 
 /* polymorph PathStatus http false */
@@ -35,6 +41,10 @@ type PathStatus struct {
 
 /* polymorph PathStatus ip false */
 
+/* polymorph PathStatus pmtu false */
+
+/* polymorph PathStatus tcp false */
+
 // Validate validates this path status
 func (m *PathStatus) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -49,6 +59,16 @@ func (m *PathStatus) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validatePmtu(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTCP(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -93,6 +113,44 @@ func (m *PathStatus) validateIcmp(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *PathStatus) validatePmtu(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Pmtu) { // not required
+		return nil
+	}
+
+	if m.Pmtu != nil {
+
+		if err := m.Pmtu.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("pmtu")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *PathStatus) validateTCP(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.TCP) { // not required
+		return nil
+	}
+
+	if m.TCP != nil {
+
+		if err := m.TCP.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("tcp")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *PathStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -103,14 +103,56 @@ func init() {
     }
   },
   "definitions": {
+    "ConnectivityHistory": {
+      "description": "Statistics over a sliding window of connectivity probes of a path",
+      "type": "object",
+      "properties": {
+        "flaps": {
+          "description": "Number of transitions between reachable and unreachable within the window",
+          "type": "integer"
+        },
+        "latency-avg": {
+          "description": "Average round trip time in nanoseconds",
+          "type": "integer"
+        },
+        "latency-max": {
+          "description": "Maximum round trip time in nanoseconds",
+          "type": "integer"
+        },
+        "latency-min": {
+          "description": "Minimum round trip time in nanoseconds",
+          "type": "integer"
+        },
+        "latency-p99": {
+          "description": "99th percentile of the round trip time in nanoseconds",
+          "type": "integer"
+        },
+        "loss": {
+          "description": "Percentage of probes lost within the window",
+          "type": "number"
+        },
+        "samples": {
+          "description": "Number of probe results in the window",
+          "type": "integer"
+        }
+      }
+    },
     "ConnectivityStatus": {
       "description": "Connectivity status of a path",
       "type": "object",
       "properties": {
+        "history": {
+          "description": "Statistics over the recent probes of this path",
+          "$ref": "#/definitions/ConnectivityHistory"
+        },
         "latency": {
           "description": "Round trip time to node in nanoseconds",
           "type": "integer"
         },
+        "loss": {
+          "description": "Percentage of probes lost",
+          "type": "number"
+        },
         "status": {
           "description": "Human readable status/error/warning message",
           "type": "string"
@@ -205,6 +247,24 @@ func init() {
         }
       }
     },
+    "PathMTUStatus": {
+      "description": "Path MTU probed towards an IP",
+      "type": "object",
+      "properties": {
+        "expected": {
+          "description": "MTU in bytes configured by Cilium for the path",
+          "type": "integer"
+        },
+        "mtu": {
+          "description": "Largest packet size in bytes which reached the peer without fragmentation",
+          "type": "integer"
+        },
+        "status": {
+          "description": "Human readable status/error/warning message",
+          "type": "string"
+        }
+      }
+    },
     "PathStatus": {
       "description": "Connectivity status via different paths, for example using different\npolicies or service redirection\n",
       "type": "object",
@@ -220,6 +280,14 @@ func init() {
         "ip": {
           "description": "IP address queried for the connectivity status",
           "type": "string"
+        },
+        "pmtu": {
+          "description": "Path MTU discovered towards the IP",
+          "$ref": "#/definitions/PathMTUStatus"
+        },
+        "tcp": {
+          "description": "Connectivity status of TCP connects to the health port",
+          "$ref": "#/definitions/ConnectivityStatus"
         }
       }
     },
@@ -23,12 +23,18 @@ type StatusResponse struct {
 	// Status of cluster
 	Cluster *ClusterStatus `json:"cluster,omitempty"`
 
+	// Status of ClusterMesh
+	ClusterMesh *ClusterMeshStatus `json:"cluster-mesh,omitempty"`
+
 	// Status of local container runtime
 	ContainerRuntime *Status `json:"container-runtime,omitempty"`
 
 	// Status of all endpoint controllers
 	Controllers ControllerStatuses `json:"controllers"`
 
+	// Status of transparent encryption
+	Encryption *EncryptionStatus `json:"encryption,omitempty"`
+
 	// Status of IP address management
 	IPAM *IPAMStatus `json:"ipam,omitempty"`
 
@@ -43,16 +49,23 @@ type StatusResponse struct {
 
 	// Status of proxy
 	Proxy *ProxyStatus `json:"proxy,omitempty"`
+
+	// Status of the routing between nodes
+	Routing *RoutingStatus `json:"routing,omitempty"`
 }
 
 /* polymorph StatusResponse cilium false */
 
 /* polymorph StatusResponse cluster false */
 
+/* polymorph StatusResponse cluster-mesh false */
+
 /* polymorph StatusResponse container-runtime false */
 
 /* polymorph StatusResponse controllers false */
 
+/* polymorph StatusResponse encryption false */
+
 /* polymorph StatusResponse ipam false */
 
 /* polymorph StatusResponse kubernetes false */
@@ -63,6 +76,8 @@ type StatusResponse struct {
 
 /* polymorph StatusResponse proxy false */
 
+/* polymorph StatusResponse routing false */
+
 // Validate validates this status response
 func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -77,11 +92,21 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateClusterMesh(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateContainerRuntime(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validateEncryption(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateIPAM(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -107,6 +132,11 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateRouting(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -151,6 +181,25 @@ func (m *StatusResponse) validateCluster(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateClusterMesh(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ClusterMesh) { // not required
+		return nil
+	}
+
+	if m.ClusterMesh != nil {
+
+		if err := m.ClusterMesh.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("cluster-mesh")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateContainerRuntime(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.ContainerRuntime) { // not required
@@ -170,6 +219,25 @@ func (m *StatusResponse) validateContainerRuntime(formats strfmt.Registry) error
 	return nil
 }
 
+func (m *StatusResponse) validateEncryption(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Encryption) { // not required
+		return nil
+	}
+
+	if m.Encryption != nil {
+
+		if err := m.Encryption.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("encryption")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateIPAM(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.IPAM) { // not required
@@ -265,6 +333,25 @@ func (m *StatusResponse) validateProxy(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateRouting(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Routing) { // not required
+		return nil
+	}
+
+	if m.Routing != nil {
+
+		if err := m.Routing.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("routing")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *StatusResponse) MarshalBinary() ([]byte, error) {
 	if m == nil {
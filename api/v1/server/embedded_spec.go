@@ -80,6 +80,53 @@ func init() {
         }
       }
     },
+    "/config/labels": {
+      "get": {
+        "description": "Returns the label prefixes used to determine which labels of an\nendpoint are relevant for its security identity.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Get the identity relevant label filter",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/LabelFilter"
+            }
+          }
+        }
+      },
+      "put": {
+        "description": "Replaces the user configured label prefixes and re-identifies all\nendpoints whose identity relevant labels change. If dry-run is set,\nonly the impact of the change on the identities in use is reported.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Modify the identity relevant label filter",
+        "parameters": [
+          {
+            "$ref": "#/parameters/label-filter-spec"
+          },
+          {
+            "$ref": "#/parameters/dry-run"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/LabelFilterImpact"
+            }
+          },
+          "400": {
+            "description": "Invalid label prefix",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "InvalidPrefix"
+          }
+        }
+      }
+    },
     "/debuginfo": {
       "get": {
         "tags": [
@@ -511,6 +558,41 @@ func init() {
         }
       }
     },
+    "/identity/stale": {
+      "get": {
+        "description": "Retrieves the identities which are still referenced by the identity\nallocator of this node but have not been used by any local endpoint\nor CIDR prefix for at least the specified duration.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Retrieve identities without local users",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Minimum duration an identity must have been without local users,\ne.g. ` + "`" + `` + "`" + `10m` + "`" + `` + "`" + `. Defaults to ` + "`" + `` + "`" + `0s` + "`" + `` + "`" + `.\n",
+            "name": "min-age",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/IdentityUsage"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid duration provided",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "InvalidDuration"
+          }
+        }
+      }
+    },
     "/identity/{id}": {
       "get": {
         "tags": [
@@ -552,6 +634,41 @@ func init() {
         }
       }
     },
+    "/identity/{id}/usage": {
+      "get": {
+        "description": "Retrieves the local references to the identity, its local users and\nthe nodes in the cluster using it.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Retrieve usage of an identity",
+        "parameters": [
+          {
+            "$ref": "#/parameters/identity-id"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IdentityUsage"
+            }
+          },
+          "400": {
+            "description": "Invalid identity provided"
+          },
+          "404": {
+            "description": "Identity not found"
+          },
+          "520": {
+            "description": "Identity storage unreachable. Likely a network problem.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Unreachable"
+          }
+        }
+      }
+    },
     "/ipam": {
       "post": {
         "tags": [
@@ -1146,6 +1263,22 @@ func init() {
         }
       }
     },
+    "ClusterMeshStatus": {
+      "description": "Status of ClusterMesh",
+      "properties": {
+        "clusters": {
+          "description": "List of remote clusters",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/RemoteCluster"
+          }
+        },
+        "num-global-services": {
+          "description": "Number of global services learned from remote clusters",
+          "type": "integer"
+        }
+      }
+    },
     "ClusterStatus": {
       "description": "Status of cluster",
       "properties": {
@@ -1360,6 +1493,27 @@ func init() {
         }
       }
     },
+    "EncryptionStatus": {
+      "description": "Status of transparent encryption",
+      "properties": {
+        "ipsec": {
+          "description": "Status of IPsec encryption",
+          "$ref": "#/definitions/IPSecStatus"
+        },
+        "mode": {
+          "description": "Encryption mode",
+          "type": "string",
+          "enum": [
+            "Disabled",
+            "IPsec"
+          ]
+        },
+        "msg": {
+          "description": "Human readable error/warning message",
+          "type": "string"
+        }
+      }
+    },
     "Endpoint": {
       "description": "An endpoint is a namespaced network interface to which cilium applies policies",
       "type": "object",
@@ -1796,6 +1950,47 @@ func init() {
         }
       }
     },
+    "IPSecPeer": {
+      "description": "Node the traffic is encrypted to via IPsec",
+      "properties": {
+        "ip": {
+          "description": "IP of the node",
+          "type": "string"
+        },
+        "key-index": {
+          "description": "Index of the key used to encrypt traffic to the node, 0 if the traffic is not encrypted",
+          "type": "integer"
+        },
+        "name": {
+          "description": "Name of the node",
+          "type": "string"
+        },
+        "remote-key-index": {
+          "description": "Index of the key announced by the node",
+          "type": "integer"
+        }
+      }
+    },
+    "IPSecStatus": {
+      "description": "Status of IPsec encryption",
+      "properties": {
+        "key-index": {
+          "description": "Index of the key announced by the local node",
+          "type": "integer"
+        },
+        "peers": {
+          "description": "Nodes the traffic is encrypted to",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/IPSecPeer"
+          }
+        },
+        "previous-key-index": {
+          "description": "Index of the key replaced by the current key, still accepted until all nodes have loaded the current key",
+          "type": "integer"
+        }
+      }
+    },
     "Identity": {
       "description": "Security identity",
       "type": "object",
@@ -1814,6 +2009,43 @@ func init() {
         }
       }
     },
+    "IdentityUsage": {
+      "description": "Usage of a security identity",
+      "type": "object",
+      "properties": {
+        "id": {
+          "description": "Unique identifier",
+          "type": "integer"
+        },
+        "labels": {
+          "description": "Labels describing the identity",
+          "$ref": "#/definitions/Labels"
+        },
+        "local-references": {
+          "description": "Number of references held by the identity allocator of this node",
+          "type": "integer"
+        },
+        "nodes": {
+          "description": "Nodes in the cluster using the identity",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "unused-since": {
+          "description": "Time since when the identity has had no local users",
+          "type": "string",
+          "format": "date-time"
+        },
+        "users": {
+          "description": "Local endpoints and CIDR prefixes using the identity",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
     "K8sStatus": {
       "description": "Status of Kubernetes integration",
       "type": "object",
@@ -1922,6 +2154,77 @@ func init() {
         }
       }
     },
+    "LabelFilter": {
+      "description": "Label filter determining the identity relevant labels",
+      "type": "object",
+      "properties": {
+        "spec": {
+          "$ref": "#/definitions/LabelFilterSpec"
+        },
+        "status": {
+          "$ref": "#/definitions/LabelFilterStatus"
+        }
+      }
+    },
+    "LabelFilterImpact": {
+      "description": "Impact of a label filter change on the identities of endpoints",
+      "type": "object",
+      "properties": {
+        "dry-run": {
+          "description": "True if the label filter change was not applied",
+          "type": "boolean"
+        },
+        "endpoints-affected": {
+          "description": "Number of endpoints changing identity",
+          "type": "integer"
+        },
+        "identities-after": {
+          "description": "Number of identities used by endpoints after the change",
+          "type": "integer"
+        },
+        "identities-before": {
+          "description": "Number of identities used by endpoints before the change",
+          "type": "integer"
+        },
+        "identities-merged": {
+          "description": "Number of identities replacing more than one identity",
+          "type": "integer"
+        },
+        "identities-split": {
+          "description": "Number of identities replaced by more than one identity",
+          "type": "integer"
+        }
+      }
+    },
+    "LabelFilterSpec": {
+      "description": "User configured label prefixes",
+      "type": "object",
+      "properties": {
+        "prefixes": {
+          "description": "Label prefixes in the format of the --labels option, appended to\nthe prefixes read from the label prefix file or the defaults\n",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "LabelFilterStatus": {
+      "description": "Label prefixes in use",
+      "type": "object",
+      "properties": {
+        "effective-prefixes": {
+          "description": "All label prefixes evaluated by the label filter",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "realized": {
+          "$ref": "#/definitions/LabelFilterSpec"
+        }
+      }
+    },
     "Labels": {
       "description": "Set of labels",
       "type": "array",
@@ -1972,6 +2275,132 @@ func init() {
         }
       }
     },
+    "MonitorEvent": {
+      "description": "Event observed by the node monitor",
+      "type": "object",
+      "properties": {
+        "cpu": {
+          "description": "CPU the event was observed on",
+          "type": "integer"
+        },
+        "event": {
+          "description": "Type specific representation of the event",
+          "type": "object"
+        },
+        "lost": {
+          "description": "Number of lost events, only set for the lost type",
+          "type": "integer"
+        },
+        "node": {
+          "description": "Name of the node the event was observed on",
+          "type": "string"
+        },
+        "time": {
+          "description": "Time the node monitor observed the event",
+          "type": "string",
+          "format": "date-time"
+        },
+        "type": {
+          "description": "Type of the event",
+          "type": "string",
+          "enum": [
+            "drop",
+            "trace",
+            "policy-verdict",
+            "capture",
+            "debug",
+            "l7",
+            "agent",
+            "lost"
+          ]
+        }
+      }
+    },
+    "MonitorEventFilter": {
+      "description": "Filter on monitor events. Each non-empty field restricts the events to\nthe ones carrying one of the listed values.\n",
+      "type": "object",
+      "properties": {
+        "cidrs": {
+          "description": "Prefixes or IPs the source or destination IP must be part of",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "fromEndpoints": {
+          "description": "Source endpoint IDs",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "identities": {
+          "description": "Source or destination security identities",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "ports": {
+          "description": "Source or destination L4 ports",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "relatedEndpoints": {
+          "description": "Source or destination endpoint IDs",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "toEndpoints": {
+          "description": "Destination endpoint IDs",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "types": {
+          "description": "Event types",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "verdicts": {
+          "description": "Verdicts of the events",
+          "type": "array",
+          "items": {
+            "type": "string",
+            "enum": [
+              "forwarded",
+              "dropped",
+              "audited"
+            ]
+          }
+        }
+      }
+    },
+    "MonitorEventRequest": {
+      "description": "Subscription to the events of the node monitor, sent by remote\nlisteners\n",
+      "type": "object",
+      "properties": {
+        "filter": {
+          "$ref": "#/definitions/MonitorEventFilter"
+        },
+        "last": {
+          "description": "Limit the recorded events sent to the most recent ones",
+          "type": "integer"
+        },
+        "since": {
+          "description": "Request the recorded events observed at or after this time",
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
     "MonitorStatus": {
       "description": "Status of the node monitor",
       "properties": {
@@ -2200,6 +2629,53 @@ func init() {
         }
       }
     },
+    "RemoteCluster": {
+      "description": "Status of remote cluster",
+      "properties": {
+        "last-failure": {
+          "description": "Time of last failure that occurred while attempting to reach the cluster",
+          "type": "string",
+          "format": "date-time"
+        },
+        "last-sync": {
+          "description": "Time of last successful synchronization with the cluster",
+          "type": "string",
+          "format": "date-time"
+        },
+        "name": {
+          "description": "Name of the cluster",
+          "type": "string"
+        },
+        "num-failures": {
+          "description": "Number of failures reaching the cluster",
+          "type": "integer"
+        },
+        "num-identities": {
+          "description": "Number of identities in the cluster",
+          "type": "integer"
+        },
+        "num-ipcache-entries": {
+          "description": "Number of ipcache entries synchronized from the cluster",
+          "type": "integer"
+        },
+        "num-nodes": {
+          "description": "Number of nodes in the cluster",
+          "type": "integer"
+        },
+        "num-shared-services": {
+          "description": "Number of services in the cluster shared as global services",
+          "type": "integer"
+        },
+        "ready": {
+          "description": "Indicates readiness of the remote cluster",
+          "type": "boolean"
+        },
+        "status": {
+          "description": "Status of the control plane",
+          "type": "string"
+        }
+      }
+    },
     "RequestResponseStatistics": {
       "description": "Statistics of a proxy redirect",
       "type": "object",
@@ -2212,6 +2688,41 @@ func init() {
         }
       }
     },
+    "RoutingStatus": {
+      "description": "Status of the routing between nodes",
+      "properties": {
+        "exported-prefixes": {
+          "description": "Allocation prefixes of the local node exported to the routing daemon",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "exporter": {
+          "description": "Name of the exporter announcing the allocation prefixes of the local node",
+          "type": "string"
+        },
+        "mode": {
+          "description": "Routing mode between nodes",
+          "type": "string",
+          "enum": [
+            "Tunnel",
+            "Direct"
+          ]
+        },
+        "msg": {
+          "description": "Human readable error/warning message",
+          "type": "string"
+        },
+        "unreachable-prefixes": {
+          "description": "Allocation prefixes of remote nodes which are not reachable",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/UnreachablePrefix"
+          }
+        }
+      }
+    },
     "Service": {
       "description": "Collection of endpoints to be served",
       "type": "object",
@@ -2303,6 +2814,10 @@ func init() {
           "description": "Status of cluster",
           "$ref": "#/definitions/ClusterStatus"
         },
+        "cluster-mesh": {
+          "description": "Status of ClusterMesh",
+          "$ref": "#/definitions/ClusterMeshStatus"
+        },
         "container-runtime": {
           "description": "Status of local container runtime",
           "$ref": "#/definitions/Status"
@@ -2311,6 +2826,10 @@ func init() {
           "description": "Status of all endpoint controllers",
           "$ref": "#/definitions/ControllerStatuses"
         },
+        "encryption": {
+          "description": "Status of transparent encryption",
+          "$ref": "#/definitions/EncryptionStatus"
+        },
         "ipam": {
           "description": "Status of IP address management",
           "$ref": "#/definitions/IPAMStatus"
@@ -2330,6 +2849,10 @@ func init() {
         "proxy": {
           "description": "Status of proxy",
           "$ref": "#/definitions/ProxyStatus"
+        },
+        "routing": {
+          "description": "Status of the routing between nodes",
+          "$ref": "#/definitions/RoutingStatus"
         }
       }
     },
@@ -2371,9 +2894,28 @@ func init() {
           "$ref": "#/definitions/Labels"
         }
       }
+    },
+    "UnreachablePrefix": {
+      "description": "Allocation prefix of a remote node which is not reachable without tunneling",
+      "properties": {
+        "node": {
+          "description": "Name of the node",
+          "type": "string"
+        },
+        "prefix": {
+          "description": "Allocation prefix of the node",
+          "type": "string"
+        }
+      }
     }
   },
   "parameters": {
+    "dry-run": {
+      "type": "boolean",
+      "description": "Only report the impact of the change without applying it",
+      "name": "dry-run",
+      "in": "query"
+    },
     "endpoint-change-request": {
       "name": "endpoint",
       "in": "body",
@@ -2412,6 +2954,15 @@ func init() {
       "in": "path",
       "required": true
     },
+    "label-filter-spec": {
+      "description": "Label prefixes relevant for security identities",
+      "name": "label-filter-spec",
+      "in": "body",
+      "required": true,
+      "schema": {
+        "$ref": "#/definitions/LabelFilterSpec"
+      }
+    },
     "labels": {
       "description": "List of labels\n",
       "name": "labels",
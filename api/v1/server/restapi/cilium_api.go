@@ -58,6 +58,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		DaemonGetConfigHandler: daemon.GetConfigHandlerFunc(func(params daemon.GetConfigParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetConfig has not yet been implemented")
 		}),
+		DaemonGetConfigLabelsHandler: daemon.GetConfigLabelsHandlerFunc(func(params daemon.GetConfigLabelsParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetConfigLabels has not yet been implemented")
+		}),
 		DaemonGetDebuginfoHandler: daemon.GetDebuginfoHandlerFunc(func(params daemon.GetDebuginfoParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetDebuginfo has not yet been implemented")
 		}),
@@ -88,6 +91,12 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		PolicyGetIdentityIDHandler: policy.GetIdentityIDHandlerFunc(func(params policy.GetIdentityIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetIdentityID has not yet been implemented")
 		}),
+		PolicyGetIdentityIDUsageHandler: policy.GetIdentityIDUsageHandlerFunc(func(params policy.GetIdentityIDUsageParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetIdentityIDUsage has not yet been implemented")
+		}),
+		PolicyGetIdentityStaleHandler: policy.GetIdentityStaleHandlerFunc(func(params policy.GetIdentityStaleParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetIdentityStale has not yet been implemented")
+		}),
 		DaemonGetMapHandler: daemon.GetMapHandlerFunc(func(params daemon.GetMapParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetMap has not yet been implemented")
 		}),
@@ -133,6 +142,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		IPAMPostIPAMIPHandler: ipam.PostIPAMIPHandlerFunc(func(params ipam.PostIPAMIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation IPAMPostIPAMIP has not yet been implemented")
 		}),
+		DaemonPutConfigLabelsHandler: daemon.PutConfigLabelsHandlerFunc(func(params daemon.PutConfigLabelsParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonPutConfigLabels has not yet been implemented")
+		}),
 		EndpointPutEndpointIDHandler: endpoint.PutEndpointIDHandlerFunc(func(params endpoint.PutEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointPutEndpointID has not yet been implemented")
 		}),
@@ -181,6 +193,8 @@ type CiliumAPI struct {
 	ServiceDeleteServiceIDHandler service.DeleteServiceIDHandler
 	// DaemonGetConfigHandler sets the operation handler for the get config operation
 	DaemonGetConfigHandler daemon.GetConfigHandler
+	// DaemonGetConfigLabelsHandler sets the operation handler for the get config labels operation
+	DaemonGetConfigLabelsHandler daemon.GetConfigLabelsHandler
 	// DaemonGetDebuginfoHandler sets the operation handler for the get debuginfo operation
 	DaemonGetDebuginfoHandler daemon.GetDebuginfoHandler
 	// EndpointGetEndpointHandler sets the operation handler for the get endpoint operation
@@ -201,6 +215,10 @@ type CiliumAPI struct {
 	PolicyGetIdentityHandler policy.GetIdentityHandler
 	// PolicyGetIdentityIDHandler sets the operation handler for the get identity ID operation
 	PolicyGetIdentityIDHandler policy.GetIdentityIDHandler
+	// PolicyGetIdentityIDUsageHandler sets the operation handler for the get identity ID usage operation
+	PolicyGetIdentityIDUsageHandler policy.GetIdentityIDUsageHandler
+	// PolicyGetIdentityStaleHandler sets the operation handler for the get identity stale operation
+	PolicyGetIdentityStaleHandler policy.GetIdentityStaleHandler
 	// DaemonGetMapHandler sets the operation handler for the get map operation
 	DaemonGetMapHandler daemon.GetMapHandler
 	// DaemonGetMapNameHandler sets the operation handler for the get map name operation
@@ -231,6 +249,8 @@ type CiliumAPI struct {
 	IPAMPostIPAMHandler ipam.PostIPAMHandler
 	// IPAMPostIPAMIPHandler sets the operation handler for the post IP a m IP operation
 	IPAMPostIPAMIPHandler ipam.PostIPAMIPHandler
+	// DaemonPutConfigLabelsHandler sets the operation handler for the put config labels operation
+	DaemonPutConfigLabelsHandler daemon.PutConfigLabelsHandler
 	// EndpointPutEndpointIDHandler sets the operation handler for the put endpoint ID operation
 	EndpointPutEndpointIDHandler endpoint.PutEndpointIDHandler
 	// PolicyPutPolicyHandler sets the operation handler for the put policy operation
@@ -320,6 +340,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "daemon.GetConfigHandler")
 	}
 
+	if o.DaemonGetConfigLabelsHandler == nil {
+		unregistered = append(unregistered, "daemon.GetConfigLabelsHandler")
+	}
+
 	if o.DaemonGetDebuginfoHandler == nil {
 		unregistered = append(unregistered, "daemon.GetDebuginfoHandler")
 	}
@@ -360,6 +384,14 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "policy.GetIdentityIDHandler")
 	}
 
+	if o.PolicyGetIdentityIDUsageHandler == nil {
+		unregistered = append(unregistered, "policy.GetIdentityIDUsageHandler")
+	}
+
+	if o.PolicyGetIdentityStaleHandler == nil {
+		unregistered = append(unregistered, "policy.GetIdentityStaleHandler")
+	}
+
 	if o.DaemonGetMapHandler == nil {
 		unregistered = append(unregistered, "daemon.GetMapHandler")
 	}
@@ -420,6 +452,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "ipam.PostIPAMIPHandler")
 	}
 
+	if o.DaemonPutConfigLabelsHandler == nil {
+		unregistered = append(unregistered, "daemon.PutConfigLabelsHandler")
+	}
+
 	if o.EndpointPutEndpointIDHandler == nil {
 		unregistered = append(unregistered, "endpoint.PutEndpointIDHandler")
 	}
@@ -547,6 +583,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/config"] = daemon.NewGetConfig(o.context, o.DaemonGetConfigHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/config/labels"] = daemon.NewGetConfigLabels(o.context, o.DaemonGetConfigLabelsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -597,6 +638,16 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/identity/{id}"] = policy.NewGetIdentityID(o.context, o.PolicyGetIdentityIDHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/identity/{id}/usage"] = policy.NewGetIdentityIDUsage(o.context, o.PolicyGetIdentityIDUsageHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/identity/stale"] = policy.NewGetIdentityStale(o.context, o.PolicyGetIdentityStaleHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -672,6 +723,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["POST"]["/ipam/{ip}"] = ipam.NewPostIPAMIP(o.context, o.IPAMPostIPAMIPHandler)
 
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/config/labels"] = daemon.NewPutConfigLabels(o.context, o.DaemonPutConfigLabelsHandler)
+
 	if o.handlers["PUT"] == nil {
 		o.handlers["PUT"] = make(map[string]http.Handler)
 	}
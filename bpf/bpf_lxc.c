@@ -219,7 +219,10 @@ skip_service_lookup:
 	 * bound for the host/outside, perform the CIDR policy check. */
 	verdict = policy_can_egress6(skb, tuple, *dstID,
 				     ipv6_ct_tuple_get_daddr(tuple));
-	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0) {
+	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0 &&
+	    !policy_audit_denied(skb, SECLABEL, *dstID, tuple->dport,
+				 tuple->nexthdr, METRIC_EGRESS, verdict,
+				 ret)) {
 		/* If the connection was previously known and packet is now
 		 * denied, remove the connection tracking entry */
 		if (ret == CT_ESTABLISHED)
@@ -235,6 +238,8 @@ skip_service_lookup:
 		 * Create a CT entry which allows to track replies and to
 		 * reverse NAT.
 		 */
+		policy_verdict_new_flow(skb, SECLABEL, *dstID, tuple->dport,
+					tuple->nexthdr, METRIC_EGRESS, verdict);
 		ct_state_new.src_sec_id = SECLABEL;
 		ret = ct_create6(get_ct_map6(tuple), tuple, skb, CT_EGRESS, &ct_state_new);
 		if (IS_ERR(ret))
@@ -525,7 +530,10 @@ skip_service_lookup:
 	 * within the cluster, it must match policy or be dropped. If it's
 	 * bound for the host/outside, perform the CIDR policy check. */
 	verdict = policy_can_egress4(skb, &tuple, *dstID, ipv4_ct_tuple_get_daddr(&tuple));
-	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0) {
+	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0 &&
+	    !policy_audit_denied(skb, SECLABEL, *dstID, tuple.dport,
+				 tuple.nexthdr, METRIC_EGRESS, verdict,
+				 ret)) {
 		/* If the connection was previously known and packet is now
 		 * denied, remove the connection tracking entry */
 		if (ret == CT_ESTABLISHED)
@@ -541,6 +549,8 @@ skip_service_lookup:
 		 * Create a CT entry which allows to track replies and to
 		 * reverse NAT.
 		 */
+		policy_verdict_new_flow(skb, SECLABEL, *dstID, tuple.dport,
+					tuple.nexthdr, METRIC_EGRESS, verdict);
 		ct_state_new.src_sec_id = SECLABEL;
 		ret = ct_create4(get_ct_map4(&tuple), &tuple, skb, CT_EGRESS,
 				 &ct_state_new);
@@ -617,6 +627,20 @@ skip_service_lookup:
 		return ipv4_local_delivery(skb, l3_off, l4_off, SECLABEL, ip4, ep, METRIC_EGRESS);
 	}
 
+#if defined ENCAP_IFINDEX && defined ENABLE_EGRESS_NAT
+	/* Traffic of selected endpoints towards destinations outside of the
+	 * cluster is steered through the tunnel to the egress NAT gateway
+	 * node which masquerades it with the configured egress IP. */
+	if (!tunnel_endpoint) {
+		struct egress_info *info;
+
+		info = lookup_ip4_egress_gw(ip4->saddr, orig_dip);
+		if (info)
+			return encap_and_redirect_with_nodeid(skb, info->gateway_ip,
+							      SECLABEL, monitor);
+	}
+#endif
+
 #ifdef ENCAP_IFINDEX
 	if (tunnel_endpoint) {
 		return encap_and_redirect_with_nodeid(skb, tunnel_endpoint,
@@ -817,7 +841,10 @@ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 
 	/* Reply packets and related packets are allowed, all others must be
 	 * permitted by policy */
-	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0) {
+	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0 &&
+	    !policy_audit_denied(skb, src_label, SECLABEL, tuple.dport,
+				 tuple.nexthdr, METRIC_INGRESS, verdict,
+				 ret)) {
 		/* If the connection was previously known and packet is now
 		 * denied, remove the connection tracking entry */
 		if (ret == CT_ESTABLISHED)
@@ -830,6 +857,8 @@ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 		verdict = 0;
 
 	if (ret == CT_NEW) {
+		policy_verdict_new_flow(skb, src_label, SECLABEL, tuple.dport,
+					tuple.nexthdr, METRIC_INGRESS, verdict);
 		ct_state_new.orig_dport = tuple.dport;
 		ct_state_new.src_sec_id = src_label;
 		ret = ct_create6(get_ct_map6(&tuple), &tuple, skb, CT_INGRESS, &ct_state_new);
@@ -951,7 +980,10 @@ ipv4_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 
 	/* Reply packets and related packets are allowed, all others must be
 	 * permitted by policy */
-	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0) {
+	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0 &&
+	    !policy_audit_denied(skb, src_label, SECLABEL, tuple.dport,
+				 tuple.nexthdr, METRIC_INGRESS, verdict,
+				 ret)) {
 		/* If the connection was previously known and packet is now
 		 * denied, remove the connection tracking entry */
 		if (ret == CT_ESTABLISHED)
@@ -964,6 +996,8 @@ ipv4_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 		verdict = 0;
 
 	if (ret == CT_NEW) {
+		policy_verdict_new_flow(skb, src_label, SECLABEL, tuple.dport,
+					tuple.nexthdr, METRIC_INGRESS, verdict);
 		ct_state_new.orig_dport = tuple.dport;
 		ct_state_new.src_sec_id = src_label;
 		ret = ct_create4(get_ct_map4(&tuple), &tuple, skb, CT_INGRESS, &ct_state_new);
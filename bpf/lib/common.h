@@ -212,6 +212,7 @@ enum {
 	CILIUM_NOTIFY_DBG_MSG,
 	CILIUM_NOTIFY_DBG_CAPTURE,
 	CILIUM_NOTIFY_TRACE,
+	CILIUM_NOTIFY_POLICY_VERDICT,
 };
 
 #define NOTIFY_COMMON_HDR \
@@ -275,6 +276,11 @@ enum {
  */
 #define REASON_FORWARDED  0
 
+/* Cilium metrics reason for forwarding a packet which would have been
+ * dropped by policy if the endpoint was not in policy audit mode.
+ */
+#define REASON_POLICY_AUDIT  1
+
 /* Cilium metrics direction for dropping/forwarding packet */
 #define METRIC_INGRESS  1
 #define METRIC_EGRESS   2
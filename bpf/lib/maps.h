@@ -169,6 +169,48 @@ struct bpf_elf_map __section_maps cilium_ipcache = {
 	.flags		= BPF_F_NO_PREALLOC,
 };
 
+struct egress_key {
+	struct bpf_lpm_trie_key lpm_key;
+	__u32 saddr;
+	__u32 daddr;
+};
+
+/* The traffic is masqueraded by the gateway node, only the node IP of the
+ * gateway is required to steer the traffic towards it. */
+struct egress_info {
+	__u32 gateway_ip;
+};
+
+#ifdef ENABLE_EGRESS_NAT
+/* (Source IP, destination prefix) -> egress gateway map for steering traffic
+ * of selected endpoints towards an egress NAT gateway node */
+struct bpf_elf_map __section_maps cilium_egress_v4 = {
+	.type		= BPF_MAP_TYPE_LPM_TRIE,
+	.size_key	= sizeof(struct egress_key),
+	.size_value	= sizeof(struct egress_info),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= EGRESS_MAP_SIZE,
+	.flags		= BPF_F_NO_PREALLOC,
+};
+
+/* The source address must always match fully, the destination is matched
+ * against the configured prefix. */
+#define EGRESS_STATIC_PREFIX (8 * sizeof(__u32))
+#define EGRESS_PREFIX_LEN(PREFIX) (EGRESS_STATIC_PREFIX + (PREFIX))
+
+static __always_inline struct egress_info *
+lookup_ip4_egress_gw(__be32 saddr, __be32 daddr)
+{
+	struct egress_key key = {
+		.lpm_key = { EGRESS_PREFIX_LEN(32) },
+		.saddr = saddr,
+		.daddr = daddr,
+	};
+
+	return map_lookup_elem(&cilium_egress_v4, &key);
+}
+#endif /* ENABLE_EGRESS_NAT */
+
 #ifndef SKIP_CALLS_MAP
 static __always_inline void ep_tail_call(struct __sk_buff *skb, uint32_t index)
 {
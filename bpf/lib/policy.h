@@ -21,6 +21,8 @@
 #include "drop.h"
 #include "eps.h"
 #include "maps.h"
+#include "metrics.h"
+#include "policy_log.h"
 
 /**
  * identity_is_reserved is used to determine whether an identity is one of the
@@ -30,6 +32,8 @@
  * - IdentityUnknown		(0)
  * - ReservedIdentityHost	(1)
  * - ReservedIdentityWorld	(2)
+ * - ReservedIdentityRemoteNode	(6)
+ * - ReservedIdentityKubeAPIServer (7)
  *
  * The following identities are given to endpoints so return false for these:
  * - ReservedIdentityUnmanaged  (3)
@@ -40,7 +44,8 @@
  */
 static inline bool identity_is_reserved(__u32 identity)
 {
-	return identity < UNMANAGED_ID;
+	return identity < UNMANAGED_ID || identity == REMOTE_NODE_ID ||
+	       identity == KUBE_APISERVER_ID;
 }
 
 #ifdef SOCKMAP
@@ -249,6 +254,67 @@ policy_can_egress4(struct __sk_buff *skb, struct ipv4_ct_tuple *tuple,
 }
 #endif /* LXC_ID */
 
+/**
+ * policy_audit_denied - report a policy denial in audit mode
+ * @arg skb		packet
+ * @arg src		source identity
+ * @arg dst		destination identity
+ * @arg dport		destination port of the packet
+ * @arg proto		L4 protocol of the packet
+ * @arg dir		direction (METRIC_INGRESS or METRIC_EGRESS)
+ * @arg verdict	result of the policy lookup
+ * @arg ct_ret		result of the connection tracking lookup
+ *
+ * If the endpoint is in policy audit mode (POLICY_AUDIT_MODE), a packet
+ * which would be dropped by policy is accounted in the metrics. Only the
+ * first packet of a flow (CT_NEW) is reported via a policy verdict
+ * notification so that established flows do not flood the monitor.
+ *
+ * Returns true if the denial was audited and the packet must be allowed.
+ */
+static inline bool __inline__
+policy_audit_denied(struct __sk_buff *skb, __u32 src, __u32 dst, __u16 dport,
+		    __u8 proto, __u8 dir, int verdict, int ct_ret)
+{
+#ifdef POLICY_AUDIT_MODE
+	if (verdict != DROP_POLICY)
+		return false;
+
+	if (ct_ret == CT_NEW)
+		send_policy_verdict_notify(skb, src, dst, dport, proto, dir,
+					   verdict, POLICY_VERDICT_FLAG_AUDITED);
+	update_metrics(skb->len, dir, REASON_POLICY_AUDIT);
+	return true;
+#else
+	return false;
+#endif
+}
+
+/**
+ * policy_verdict_new_flow - report the policy verdict of a new flow
+ * @arg skb		packet
+ * @arg src		source identity
+ * @arg dst		destination identity
+ * @arg dport		destination port of the packet
+ * @arg proto		L4 protocol of the packet
+ * @arg dir		direction (METRIC_INGRESS or METRIC_EGRESS)
+ * @arg verdict	result of the policy lookup
+ *
+ * If policy verdict notifications are enabled (POLICY_VERDICT_NOTIFY), the
+ * verdict of the first packet of a flow which is allowed by policy is
+ * reported. Audited denials are reported by policy_audit_denied().
+ */
+static inline void __inline__
+policy_verdict_new_flow(struct __sk_buff *skb, __u32 src, __u32 dst,
+			__u16 dport, __u8 proto, __u8 dir, int verdict)
+{
+#ifdef POLICY_VERDICT_NOTIFY
+	if (verdict >= 0)
+		send_policy_verdict_notify(skb, src, dst, dport, proto, dir,
+					   verdict, 0);
+#endif
+}
+
 /**
  * Mark skb to skip policy enforcement
  * @arg skb	packet
@@ -36,6 +36,8 @@
 #define UNMANAGED_ID 3
 #define HEALTH_ID 4
 #define INIT_ID 5
+#define REMOTE_NODE_ID 6
+#define KUBE_APISERVER_ID 7
 #define HOST_IFINDEX_MAC { .addr = { 0xce, 0x72, 0xa7, 0x03, 0x88, 0x56 } }
 #define NAT46_PREFIX { .addr = { 0xbe, 0xef, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0xa, 0x0, 0x0, 0x0, 0x0, 0x0 } }
 #define IPV4_MASK 0xffff
@@ -59,6 +61,8 @@
 #define PROXY_MAP_SIZE 524288
 #define POLICY_MAP_SIZE 16384
 #define IPCACHE_MAP_SIZE 512000
+#define EGRESS_MAP_SIZE 16384
+#define ENABLE_EGRESS_NAT 1
 #define POLICY_PROG_MAP_SIZE ENDPOINTS_MAP_SIZE
 #ifndef SKIP_DEBUG
 #define LB_DEBUG
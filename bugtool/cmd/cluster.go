@@ -0,0 +1,245 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/pkg/defaults"
+)
+
+const (
+	// nodeTimeout is the maximum time to wait for the bugtool to complete
+	// inside of a single Cilium pod.
+	nodeTimeout = 10 * time.Minute
+
+	// indexName is the name of the summary of a cluster-wide collection.
+	indexName = "index.md"
+)
+
+// archivePathRegex matches the location of the archive reported by a
+// bugtool run.
+var archivePathRegex = regexp.MustCompile(`ARCHIVE at (\S+)`)
+
+// nodeResult is the outcome of the collection from a single Cilium pod.
+type nodeResult struct {
+	pod      string
+	node     string
+	files    int
+	duration time.Duration
+	err      error
+}
+
+// remoteBugtoolArgs returns the arguments for the bugtool run inside of
+// each pod. Redaction is performed after merging the results, so that IP
+// addresses are anonymized consistently across all nodes.
+func remoteBugtoolArgs(pod string) []string {
+	args := []string{
+		"--archiveType", "tar",
+		"--archive-prefix", pod,
+		"--exec-timeout", execTimeout.String(),
+		"--profile", strings.Join(profileNamesOpt, ","),
+		"--redact=false",
+	}
+	if parallel > 0 {
+		args = append(args, "--parallel", fmt.Sprintf("%d", parallel))
+	}
+	if enableMarkdown {
+		args = append(args, "--enable-markdown")
+	}
+	return args
+}
+
+// collectNode runs the bugtool inside of 'pod' and extracts the resulting
+// archive into 'dst'.
+func collectNode(pod, dst string) nodeResult {
+	start := time.Now()
+	res := nodeResult{pod: pod}
+	defer func() {
+		res.duration = time.Since(start)
+	}()
+
+	if out, err := execCommand(fmt.Sprintf("kubectl -n %s get pod %s -o jsonpath={.spec.nodeName}", k8sNamespace, pod)); err == nil {
+		res.node = strings.TrimSpace(out)
+	}
+
+	prompt := podPrefix(pod, "cilium-bugtool "+strings.Join(remoteBugtoolArgs(pod), " "))
+	out, err := execCommandWithTimeout(prompt, nodeTimeout)
+	if err != nil {
+		res.err = fmt.Errorf("bugtool failed: %s", err)
+		return res
+	}
+	m := archivePathRegex.FindStringSubmatch(out)
+	if m == nil {
+		res.err = fmt.Errorf("bugtool did not report an archive")
+		return res
+	}
+	remoteArchive := m[1]
+	defer execCommand(podPrefix(pod, "rm -f "+remoteArchive))
+
+	localArchive := dst + ".tar"
+	defer os.Remove(localArchive)
+	if _, err := execCommandWithTimeout(fmt.Sprintf("kubectl cp %s/%s:%s %s",
+		k8sNamespace, pod, remoteArchive, localArchive), nodeTimeout); err != nil {
+		res.err = fmt.Errorf("unable to copy archive: %s", err)
+		return res
+	}
+
+	res.files, res.err = extractArchive(localArchive, dst)
+	return res
+}
+
+// extractArchive extracts the bugtool archive at 'path' into 'dst', removing
+// the top-level directory of the archive. It returns the number of files.
+func extractArchive(path, dst string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	files := 0
+	reader := tar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return files, nil
+		} else if err != nil {
+			return files, err
+		}
+
+		name := filepath.Clean(header.Name)
+		if parts := strings.SplitN(name, string(filepath.Separator), 2); len(parts) == 2 {
+			name = parts[1]
+		} else {
+			// The top-level directory itself
+			continue
+		}
+		if strings.HasPrefix(name, "..") || filepath.IsAbs(name) {
+			return files, fmt.Errorf("invalid path %q in archive", header.Name)
+		}
+		target := filepath.Join(dst, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, defaults.RuntimePathRights); err != nil {
+				return files, err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), defaults.RuntimePathRights); err != nil {
+				return files, err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return files, err
+			}
+			_, err = io.Copy(out, reader)
+			out.Close()
+			if err != nil {
+				return files, err
+			}
+			files++
+		}
+	}
+}
+
+// runAllNodes runs the bugtool in every Cilium pod and merges the results
+// into 'dbgDir' with one folder per pod, along with the cluster-wide
+// Kubernetes state and a summary index.
+func runAllNodes(dbgDir, cmdDir string, k8sPods []string) {
+	nodesDir := createDir(dbgDir, "nodes")
+
+	// Cluster-wide commands which do not depend on any pod
+	runAll(k8sCommands(nil, nil), cmdDir, nil)
+
+	numRoutinesAtOnce := len(k8sPods)
+	if parallel > 0 && parallel < numRoutinesAtOnce {
+		numRoutinesAtOnce = parallel
+	}
+	semaphore := make(chan struct{}, numRoutinesAtOnce)
+
+	var (
+		wg      sync.WaitGroup
+		mutex   sync.Mutex
+		results []nodeResult
+	)
+	for _, pod := range k8sPods {
+		wg.Add(1)
+		go func(pod string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			fmt.Printf("Collecting from pod %s\n", pod)
+			res := collectNode(pod, filepath.Join(nodesDir, pod))
+			if res.err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to collect from pod %s: %s\n", pod, res.err)
+			}
+
+			mutex.Lock()
+			results = append(results, res)
+			mutex.Unlock()
+		}(pod)
+	}
+	wg.Wait()
+
+	if err := writeIndex(filepath.Join(dbgDir, indexName), results); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write index %s\n", err)
+	}
+}
+
+// writeIndex writes a summary of the cluster-wide collection to 'path'.
+func writeIndex(path string, results []nodeResult) error {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].pod < results[j].pod
+	})
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "# Cilium bugtool cluster collection\n\n")
+	fmt.Fprintf(f, "Profiles: %s\n\n", strings.Join(profileNamesOpt, ", "))
+	fmt.Fprintf(f, "Kubernetes state is in `cmd/`, the output of each Cilium pod in `nodes/<pod>/`.\n\n")
+
+	fmt.Fprintf(f, "```\n")
+	w := tabwriter.NewWriter(f, 2, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "POD\tNODE\tFILES\tDURATION\tSTATUS\n")
+	for _, res := range results {
+		status := "OK"
+		if res.err != nil {
+			status = res.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", res.pod, res.node, res.files,
+			res.duration.Round(time.Second), status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "```\n")
+	return err
+}
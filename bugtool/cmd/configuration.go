@@ -35,14 +35,34 @@ import (
 type BugtoolConfiguration struct {
 	// Commands is the exact commands that will be run by the bugtool
 	Commands []string `json:"commands"`
+
+	// RedactPatterns are additional regular expressions whose matches
+	// are removed from the collected output
+	RedactPatterns []string `json:"redact-patterns,omitempty"`
+
+	// Profiles are the collection profiles to run if Commands is empty
+	Profiles []string `json:"profiles,omitempty"`
+
+	// Timeouts overrides the execution timeout of all commands containing
+	// the key, for example {"cilium debuginfo": "5m"}
+	Timeouts map[string]string `json:"timeouts,omitempty"`
 }
 
 func setupDefaultConfig(path string, k8sPods []string, confDir, cmdDir string) (*BugtoolConfiguration, error) {
-	c := BugtoolConfiguration{defaultCommands(confDir, cmdDir, k8sPods)}
+	commands, err := profileCommands(profileNamesOpt, confDir, cmdDir, k8sPods)
+	if err != nil {
+		return nil, err
+	}
+	c := BugtoolConfiguration{
+		Commands:       commands,
+		RedactPatterns: redactPatterns,
+		Timeouts:       formatTimeouts(defaultTimeouts),
+	}
 	return &c, save(&c, path)
 }
 
-func defaultCommands(confDir string, cmdDir string, k8sPods []string) []string {
+// fullCommands returns all commands of the "full" collection profile.
+func fullCommands(confDir string, cmdDir string, k8sPods []string) []string {
 	var commands []string
 	// Not expecting all of the commands to be available
 	commands = []string{
@@ -91,7 +111,7 @@ func defaultCommands(confDir string, cmdDir string, k8sPods []string) []string {
 	commands = append(commands, copyConfigCommands(confDir, k8sPods)...)
 	commands = append(commands, copyCiliumInfoCommands(cmdDir, k8sPods)...)
 
-	return k8sCommands(commands, k8sPods)
+	return commands
 }
 
 func save(c *BugtoolConfiguration, path string) error {
@@ -212,7 +232,7 @@ func copyConfigCommands(confDir string, k8sPods []string) []string {
 func copyCiliumInfoCommands(cmdDir string, k8sPods []string) []string {
 	// Most of the output should come via debuginfo but also adding
 	// these ones for skimming purposes
-	ciliumCommands := []string{
+	infoCommands := []string{
 		"cilium debuginfo",
 		"cilium metrics list",
 		"cilium config",
@@ -234,29 +254,35 @@ func copyCiliumInfoCommands(cmdDir string, k8sPods []string) []string {
 	if len(k8sPods) == 0 { // Assuming this is a non k8s deployment
 		dst := filepath.Join(cmdDir, defaults.StateDir)
 		commands = append(commands, fmt.Sprintf("cp -r %s %s", stateDir, dst))
-		for _, cmd := range ciliumCommands {
-			// Add the host flag if set
-			if len(host) > 0 {
-				cmd = fmt.Sprintf("%s -H %s", cmd, host)
-			}
-			commands = append(commands, cmd)
-		}
 	} else { // Found k8s pods
 		for _, pod := range k8sPods {
 			dst := filepath.Join(cmdDir, fmt.Sprintf("%s-%s", pod, defaults.StateDir))
 			kubectlArg := fmt.Sprintf("%s/%s:%s", k8sNamespace, pod, stateDir)
 			// kubectl cp kube-system/cilium-xrzwr:/var/run/cilium/state cilium-xrzwr-state
 			commands = append(commands, fmt.Sprintf("kubectl cp %s %s", kubectlArg, dst))
-			for _, cmd := range ciliumCommands {
-				// Add the host flag if set
-				if len(host) > 0 {
-					cmd = fmt.Sprintf("%s -H %s", cmd, host)
-				}
-				commands = append(commands, podPrefix(pod, cmd))
-			}
 		}
 	}
 
+	return append(commands, ciliumCommands(infoCommands, k8sPods)...)
+}
+
+// ciliumCommands prepares the Cilium CLI commands to be run against the
+// local agent, or inside of each of the Cilium pods.
+func ciliumCommands(ciliumCmds []string, k8sPods []string) []string {
+	var commands []string
+	for _, cmd := range ciliumCmds {
+		// Add the host flag if set
+		if len(host) > 0 {
+			cmd = fmt.Sprintf("%s -H %s", cmd, host)
+		}
+		if len(k8sPods) == 0 {
+			commands = append(commands, cmd)
+			continue
+		}
+		for _, pod := range k8sPods {
+			commands = append(commands, podPrefix(pod, cmd))
+		}
+	}
 	return commands
 }
 
@@ -269,19 +295,26 @@ func k8sCommands(allCommands []string, pods []string) []string {
 		"kubectl version",
 	}
 
+	// Commands which already invoke kubectl, for example to run a command
+	// in a specific pod, are run as is.
+	var podCommands []string
+	for _, cmd := range allCommands {
+		if strings.HasPrefix(cmd, "kubectl ") {
+			commands = append(commands, cmd)
+		} else {
+			podCommands = append(podCommands, cmd)
+		}
+	}
+
 	// Prepare to run all the commands inside of the pod(s)
 	for _, pod := range pods {
-		for _, cmd := range allCommands {
+		for _, cmd := range podCommands {
 			// Add the host flag if set
 			if strings.HasPrefix(cmd, "cilium") &&
 				!strings.Contains(cmd, "-H") && len(host) > 0 {
 				cmd = fmt.Sprintf("%s -H %s", cmd, host)
 			}
-
-			if !strings.Contains(cmd, "kubectl exec") {
-				cmd = podPrefix(pod, cmd)
-			}
-			commands = append(commands, cmd)
+			commands = append(commands, podPrefix(pod, cmd))
 		}
 
 		// Retrieve current version of pod logs
@@ -0,0 +1,200 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultProfile is the profile collected when no profile is selected.
+const defaultProfile = "full"
+
+// collectionProfile is a named set of commands run by the bugtool.
+type collectionProfile struct {
+	description string
+
+	// commands returns the commands of the profile. Host commands are
+	// run inside of each Cilium pod in Kubernetes mode.
+	commands func(confDir, cmdDir string, k8sPods []string) []string
+}
+
+// profiles are the built-in collection profiles.
+var profiles = map[string]collectionProfile{
+	"full": {
+		description: "Everything that the bugtool knows how to collect",
+		commands:    fullCommands,
+	},
+	"minimal": {
+		description: "Versions and status of the node and of Cilium",
+		commands: func(confDir, cmdDir string, k8sPods []string) []string {
+			commands := []string{
+				"hostname",
+				"uname -a",
+				"uptime",
+				"ip a",
+				"ip r",
+			}
+			return append(commands, ciliumCommands([]string{
+				"cilium version",
+				"cilium status --verbose",
+				"cilium config",
+				"cilium-health status",
+			}, k8sPods)...)
+		},
+	},
+	"datapath": {
+		description: "Routing, iptables, BPF programs and maps",
+		commands: func(confDir, cmdDir string, k8sPods []string) []string {
+			commands := []string{
+				"ip a",
+				"ip r",
+				"ip link",
+				"ip rule",
+				"ip -4 route show table 2005",
+				"ip -6 route show table 2005",
+				"iptables-save",
+				"ip6tables -S",
+				"bpftool map show",
+				"bpftool prog show",
+			}
+			commands = append(commands, ethoolCommands()...)
+			return append(commands, ciliumCommands([]string{
+				"cilium status --verbose",
+				"cilium node list",
+				"cilium service list",
+				"cilium map list --verbose",
+				"cilium bpf tunnel list",
+				"cilium bpf lb list",
+				"cilium bpf endpoint list",
+				"cilium bpf ct list global",
+				"cilium bpf proxy list",
+				"cilium bpf ipcache list",
+			}, k8sPods)...)
+		},
+	},
+	"policy": {
+		description: "Policy, identities and endpoints",
+		commands: func(confDir, cmdDir string, k8sPods []string) []string {
+			return ciliumCommands([]string{
+				"cilium status --verbose",
+				"cilium policy get",
+				"cilium endpoint list",
+				"cilium identity list",
+				"cilium bpf policy get --all --numeric",
+				"cilium bpf ipcache list",
+			}, k8sPods)
+		},
+	},
+	"kvstore": {
+		description: "Key-value store configuration and contents",
+		commands: func(confDir, cmdDir string, k8sPods []string) []string {
+			return ciliumCommands([]string{
+				"cilium status --verbose",
+				"cilium config",
+				"cilium node list",
+				"cilium kvstore get --recursive cilium",
+			}, k8sPods)
+		},
+	},
+}
+
+// defaultTimeouts overrides the execution timeout for commands which are
+// known to take long on large nodes. Keys match any command containing them.
+var defaultTimeouts = map[string]time.Duration{
+	"cilium debuginfo":                      2 * time.Minute,
+	"cilium bpf ct list global":             2 * time.Minute,
+	"cilium kvstore get":                    2 * time.Minute,
+	"journalctl":                            time.Minute,
+	"cilium bpf policy get --all --numeric": time.Minute,
+}
+
+// profileNames returns the names of all profiles in alphabetical order.
+func profileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileCommands returns the de-duplicated commands of all profiles in
+// 'names', prepared to be run in the Cilium pods if any were found.
+func profileCommands(names []string, confDir, cmdDir string, k8sPods []string) ([]string, error) {
+	seen := map[string]struct{}{}
+	commands := []string{}
+	for _, name := range names {
+		p, ok := profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q, must be one of %s",
+				name, strings.Join(profileNames(), ", "))
+		}
+		for _, cmd := range p.commands(confDir, cmdDir, k8sPods) {
+			if _, ok := seen[cmd]; !ok {
+				seen[cmd] = struct{}{}
+				commands = append(commands, cmd)
+			}
+		}
+	}
+	return k8sCommands(commands, k8sPods), nil
+}
+
+// commandTimeout returns the execution timeout for 'prompt'. If several
+// keys of 'timeouts' are contained in the prompt, the longest one wins.
+func commandTimeout(prompt string, timeouts map[string]time.Duration) time.Duration {
+	timeout, match := execTimeout, ""
+	for key, t := range timeouts {
+		if len(key) > len(match) && strings.Contains(prompt, key) {
+			timeout, match = t, key
+		}
+	}
+	return timeout
+}
+
+// parseTimeouts merges the timeouts from the configuration file into the
+// default timeouts. Default timeouts for commands which are covered by a
+// configured key are dropped, so that the configuration takes precedence.
+func parseTimeouts(config map[string]string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(defaultTimeouts)+len(config))
+defaults:
+	for key, t := range defaultTimeouts {
+		for configKey := range config {
+			if strings.Contains(key, configKey) {
+				continue defaults
+			}
+		}
+		timeouts[key] = t
+	}
+	for key, value := range config {
+		t, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q for %q: %s", value, key, err)
+		}
+		timeouts[key] = t
+	}
+	return timeouts, nil
+}
+
+// formatTimeouts converts timeouts into their configuration file format.
+func formatTimeouts(timeouts map[string]time.Duration) map[string]string {
+	config := make(map[string]string, len(timeouts))
+	for key, t := range timeouts {
+		config[key] = t.String()
+	}
+	return config
+}
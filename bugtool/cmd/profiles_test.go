@@ -0,0 +1,148 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type ProfilesSuite struct{}
+
+var _ = Suite(&ProfilesSuite{})
+
+func (s *ProfilesSuite) TestProfileCommands(c *C) {
+	_, err := profileCommands([]string{"unknown"}, "conf", "cmd", nil)
+	c.Assert(err, NotNil)
+
+	policy, err := profileCommands([]string{"policy"}, "conf", "cmd", nil)
+	c.Assert(err, IsNil)
+	c.Assert(policy, Not(HasLen), 0)
+
+	// Commands shared between profiles are only run once.
+	both, err := profileCommands([]string{"policy", "minimal"}, "conf", "cmd", nil)
+	c.Assert(err, IsNil)
+	seen := map[string]bool{}
+	for _, cmd := range both {
+		c.Assert(seen[cmd], Equals, false, Commentf("duplicate command %q", cmd))
+		seen[cmd] = true
+	}
+	c.Assert(seen["cilium status --verbose"], Equals, true)
+	c.Assert(seen["cilium identity list"], Equals, true)
+	c.Assert(seen["cilium version"], Equals, true)
+
+	// In Kubernetes mode, commands run inside of each pod.
+	pods, err := profileCommands([]string{"policy"}, "conf", "cmd", []string{"cilium-a", "cilium-b"})
+	c.Assert(err, IsNil)
+	for _, cmd := range profiles["policy"].commands("conf", "cmd", nil) {
+		c.Assert(pods, checkerContains, podPrefix("cilium-a", cmd))
+		c.Assert(pods, checkerContains, podPrefix("cilium-b", cmd))
+	}
+	seen = map[string]bool{}
+	for _, cmd := range pods {
+		c.Assert(seen[cmd], Equals, false, Commentf("duplicate command %q", cmd))
+		seen[cmd] = true
+	}
+}
+
+func (s *ProfilesSuite) TestCommandTimeout(c *C) {
+	oldTimeout := execTimeout
+	execTimeout = 30 * time.Second
+	defer func() { execTimeout = oldTimeout }()
+
+	timeouts, err := parseTimeouts(map[string]string{
+		"cilium bpf":         "45s",
+		"cilium bpf ct list": "3m",
+	})
+	c.Assert(err, IsNil)
+	c.Assert(commandTimeout("ip a", timeouts), Equals, 30*time.Second)
+	c.Assert(commandTimeout("cilium bpf lb list", timeouts), Equals, 45*time.Second)
+	c.Assert(commandTimeout("kubectl exec cilium-a -n kube-system -- cilium bpf ct list global", timeouts), Equals, 3*time.Minute)
+	c.Assert(commandTimeout("cilium debuginfo", timeouts), Equals, defaultTimeouts["cilium debuginfo"])
+
+	_, err = parseTimeouts(map[string]string{"ip a": "soon"})
+	c.Assert(err, NotNil)
+}
+
+func (s *ProfilesSuite) TestExtractArchive(c *C) {
+	tmp, err := ioutil.TempDir("", "bugtool-extract")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "cilium-a-cilium-bugtool-1")
+	c.Assert(os.MkdirAll(filepath.Join(src, "cmd"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(src, "cmd", "ip-a.md"), []byte("lo"), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(src, "top"), []byte("x"), 0644), IsNil)
+	archivePath, err := createArchive(src)
+	c.Assert(err, IsNil)
+
+	dst := filepath.Join(tmp, "nodes", "cilium-a")
+	files, err := extractArchive(archivePath, dst)
+	c.Assert(err, IsNil)
+	c.Assert(files, Equals, 2)
+
+	data, err := ioutil.ReadFile(filepath.Join(dst, "cmd", "ip-a.md"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "lo")
+}
+
+func (s *ProfilesSuite) TestWriteIndex(c *C) {
+	tmp, err := ioutil.TempDir("", "bugtool-index")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, indexName)
+	c.Assert(writeIndex(path, []nodeResult{
+		{pod: "cilium-b", node: "k8s2", err: errors.New("bugtool failed")},
+		{pod: "cilium-a", node: "k8s1", files: 12, duration: time.Second},
+	}), IsNil)
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	index := string(data)
+	c.Assert(strings.Index(index, "cilium-a") < strings.Index(index, "cilium-b"), Equals, true)
+	c.Assert(strings.Contains(index, "cilium-a   k8s1   12      1s         OK"), Equals, true)
+	c.Assert(strings.Contains(index, "bugtool failed"), Equals, true)
+}
+
+type containsChecker struct {
+	*CheckerInfo
+}
+
+// checkerContains checks whether a slice of strings contains a value.
+var checkerContains Checker = &containsChecker{
+	&CheckerInfo{Name: "Contains", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *containsChecker) Check(params []interface{}, names []string) (bool, string) {
+	list, ok := params[0].([]string)
+	if !ok {
+		return false, "obtained must be []string"
+	}
+	for _, s := range list {
+		if s == params[1] {
+			return true, ""
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,306 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+)
+
+const (
+	redacted = "[REDACTED]"
+
+	// manifestName is the name of the file listing all redactions, placed
+	// at the top of the archive.
+	manifestName = "redaction-manifest.txt"
+
+	// binarySniffLen is the number of bytes inspected to decide whether a
+	// file is binary and thus cannot be redacted.
+	binarySniffLen = 8000
+)
+
+// redactionRule replaces all matches of a regular expression. The
+// replacement may refer to capture groups, for example to preserve the key
+// of a key/value pair while removing the value.
+type redactionRule struct {
+	name        string
+	re          *regexp.Regexp
+	replacement []byte
+}
+
+// builtinRules are always applied when redaction is enabled.
+var builtinRules = []redactionRule{
+	{
+		name:        "tls-private-key",
+		re:          regexp.MustCompile(`-----BEGIN ([A-Z ]*)PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+		replacement: []byte("-----BEGIN ${1}PRIVATE KEY-----\n" + redacted + "\n-----END ${1}PRIVATE KEY-----"),
+	},
+	{
+		name:        "bearer-token",
+		re:          regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		replacement: []byte("${1}" + redacted),
+	},
+	{
+		name:        "jwt",
+		re:          regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		replacement: []byte(redacted),
+	},
+	{
+		name:        "kubeconfig-credentials",
+		re:          regexp.MustCompile(`(?i)((?:client-certificate|client-key|certificate-authority)(?:-data)?["']?\s*:\s*["']?)[^\s"',]+`),
+		replacement: []byte("${1}" + redacted),
+	},
+	{
+		name:        "tls-file-path",
+		re:          regexp.MustCompile(`(?i)((?:ca|cert|key|trusted-ca)[-_]?file["']?\s*[:=]\s*["']?)[^\s"',]+`),
+		replacement: []byte("${1}" + redacted),
+	},
+	{
+		name:        "token",
+		re:          regexp.MustCompile(`(?i)((?:token|api[-_]?key)["']?\s*[:=]\s*["']?)[^\s"',]+`),
+		replacement: []byte("${1}" + redacted),
+	},
+	{
+		name:        "password",
+		re:          regexp.MustCompile(`(?i)((?:password|passwd|secret)["']?\s*[:=]\s*["']?)[^\s"',]+`),
+		replacement: []byte("${1}" + redacted),
+	},
+}
+
+var (
+	ipv4Candidate = regexp.MustCompile(`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}\b`)
+	ipv6Candidate = regexp.MustCompile(`[0-9A-Fa-f]*:[0-9A-Fa-f]*:[0-9A-Fa-f:]*`)
+
+	// Anonymized addresses are allocated from the benchmarking and the
+	// documentation ranges respectively, so they are easily recognized.
+	// Candidates which do not parse as IP addresses, such as timestamps or
+	// MAC addresses, are left untouched.
+	anonIPv4Base = net.ParseIP("198.18.0.0").To4()
+	anonIPv6Base = net.ParseIP("2001:db8::")
+)
+
+// redactionEntry records how often a rule matched in a file.
+type redactionEntry struct {
+	file  string
+	rule  string
+	count int
+}
+
+// redactor removes sensitive data from the files collected by the bugtool.
+// IP addresses are optionally replaced by anonymized addresses, where every
+// address is consistently mapped to the same replacement across all files.
+type redactor struct {
+	rules        []redactionRule
+	anonymizeIPs bool
+	ipMap        map[string]string
+	nextIPv4     uint32
+	nextIPv6     uint32
+	entries      []redactionEntry
+	skipped      []string
+}
+
+// newRedactor creates a redactor which applies the user supplied regular
+// expressions in 'patterns' and, if 'secrets' is true, the built-in rules.
+// Matches of user supplied patterns are replaced in full.
+func newRedactor(secrets bool, patterns []string, anonymizeIPs bool) (*redactor, error) {
+	r := &redactor{
+		anonymizeIPs: anonymizeIPs,
+		ipMap:        make(map[string]string),
+	}
+	if secrets {
+		r.rules = append(r.rules, builtinRules...)
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %s", p, err)
+		}
+		r.rules = append(r.rules, redactionRule{
+			name:        "custom:" + p,
+			re:          re,
+			replacement: []byte(redacted),
+		})
+	}
+	return r, nil
+}
+
+// redact returns 'data' with all sensitive information removed and records
+// the redactions for 'file' in the manifest.
+func (r *redactor) redact(file string, data []byte) []byte {
+	for _, rule := range r.rules {
+		if n := len(rule.re.FindAllIndex(data, -1)); n > 0 {
+			data = rule.re.ReplaceAll(data, rule.replacement)
+			r.entries = append(r.entries, redactionEntry{file, rule.name, n})
+		}
+	}
+
+	if r.anonymizeIPs {
+		for _, c := range []struct {
+			name string
+			re   *regexp.Regexp
+		}{
+			{"ipv4-address", ipv4Candidate},
+			{"ipv6-address", ipv6Candidate},
+		} {
+			n := 0
+			data = c.re.ReplaceAllFunc(data, func(match []byte) []byte {
+				anon, ok := r.anonymizeIP(string(match))
+				if !ok {
+					return match
+				}
+				n++
+				return []byte(anon)
+			})
+			if n > 0 {
+				r.entries = append(r.entries, redactionEntry{file, c.name, n})
+			}
+		}
+	}
+
+	return data
+}
+
+// anonymizeIP returns the anonymized replacement for 'addr', or false if
+// 'addr' is not an IP address which needs to be anonymized.
+func (r *redactor) anonymizeIP(addr string) (string, bool) {
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+		return "", false
+	}
+	// Keep netmasks such as 255.255.255.0 intact.
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 255 {
+		return "", false
+	}
+
+	key := ip.String()
+	if anon, ok := r.ipMap[key]; ok {
+		return anon, true
+	}
+
+	var anon net.IP
+	if ip.To4() != nil {
+		r.nextIPv4++
+		anon = make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(anon, binary.BigEndian.Uint32(anonIPv4Base)+r.nextIPv4)
+	} else {
+		r.nextIPv6++
+		anon = make(net.IP, net.IPv6len)
+		copy(anon, anonIPv6Base)
+		binary.BigEndian.PutUint32(anon[12:], r.nextIPv6)
+	}
+	r.ipMap[key] = anon.String()
+	return r.ipMap[key], true
+}
+
+// redactDir redacts all regular files below 'dir' in place. Binary files
+// cannot be redacted reliably, they are left as is and listed in the
+// manifest for manual review.
+func (r *redactor) redactDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sniff := data
+		if len(sniff) > binarySniffLen {
+			sniff = sniff[:binarySniffLen]
+		}
+		if bytes.IndexByte(sniff, 0) != -1 {
+			r.skipped = append(r.skipped, rel)
+			return nil
+		}
+
+		return ioutil.WriteFile(path, r.redact(rel, data), info.Mode())
+	})
+}
+
+// writeManifest writes the list of redactions to 'path'.
+func (r *redactor) writeManifest(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "Sensitive data in this archive was replaced with %q.\n", redacted)
+	if r.anonymizeIPs {
+		fmt.Fprintf(f, "%d IP addresses were consistently replaced with anonymized addresses.\n", len(r.ipMap))
+	}
+	fmt.Fprintln(f)
+
+	w := tabwriter.NewWriter(f, 2, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "FILE\tRULE\tCOUNT\n")
+	for _, e := range r.entries {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", e.file, e.rule, e.count)
+	}
+	for _, s := range r.skipped {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s, "binary, not redacted", "-")
+	}
+	return w.Flush()
+}
+
+// writeIPMapping writes the mapping of original to anonymized IP addresses
+// to 'path'. The mapping must not be shared together with the archive.
+func (r *redactor) writeIPMapping(path string) error {
+	addrs := make([]string, 0, len(r.ipMap))
+	for addr := range r.ipMap {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	buf := &bytes.Buffer{}
+	for _, addr := range addrs {
+		fmt.Fprintf(buf, "%s %s\n", r.ipMap[addr], addr)
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// redactDebugDir redacts all files collected in 'dbgDir' and adds the
+// redaction manifest to it. If IP addresses are anonymized, the mapping is
+// stored next to 'dbgDir' and its path is returned.
+func redactDebugDir(dbgDir string, secrets bool, patterns []string, anonymizeIPs bool) (string, error) {
+	r, err := newRedactor(secrets, patterns, anonymizeIPs)
+	if err != nil {
+		return "", err
+	}
+	if err := r.redactDir(dbgDir); err != nil {
+		return "", err
+	}
+	if err := r.writeManifest(filepath.Join(dbgDir, manifestName)); err != nil {
+		return "", err
+	}
+	if !anonymizeIPs {
+		return "", nil
+	}
+
+	mappingPath := dbgDir + "-ip-mapping.txt"
+	return mappingPath, r.writeIPMapping(mappingPath)
+}
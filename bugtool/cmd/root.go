@@ -52,6 +52,7 @@ var BugtoolRootCmd = &cobra.Command{
 	$ kubectl -n kube-system exec cilium-kg8lv cilium-bugtool
 	$ kubectl cp kube-system/cilium-kg8lv:/tmp/cilium-bugtool-243785589.tar /tmp/cilium-bugtool-243785589.tar`,
 	Run: func(cmd *cobra.Command, args []string) {
+		profileSet = cmd.Flags().Changed("profile")
 		runTool()
 	},
 }
@@ -66,20 +67,30 @@ for sensitive information.
 )
 
 var (
-	archive        bool
-	archiveType    string
-	k8s            bool
-	serve          bool
-	port           int
-	dumpPath       string
-	host           string
-	k8sNamespace   string
-	k8sLabel       string
-	execTimeout    time.Duration
-	configPath     string
-	dryRunMode     bool
-	enableMarkdown bool
-	archivePrefix  string
+	archive         bool
+	archiveType     string
+	k8s             bool
+	serve           bool
+	port            int
+	dumpPath        string
+	host            string
+	k8sNamespace    string
+	k8sLabel        string
+	execTimeout     time.Duration
+	configPath      string
+	dryRunMode      bool
+	enableMarkdown  bool
+	archivePrefix   string
+	redact          bool
+	redactPatterns  []string
+	anonymizeIPs    bool
+	profileNamesOpt []string
+	profileSet      bool
+	parallel        int
+	allNodes        bool
+
+	// cmdTimeouts holds the execution timeouts of individual commands
+	cmdTimeouts = defaultTimeouts
 )
 
 func init() {
@@ -97,6 +108,13 @@ func init() {
 	BugtoolRootCmd.Flags().StringVarP(&configPath, "config", "", "./.cilium-bugtool.config", "Configuration to decide what should be run")
 	BugtoolRootCmd.Flags().BoolVar(&enableMarkdown, "enable-markdown", false, "Dump output of commands in markdown format")
 	BugtoolRootCmd.Flags().StringVarP(&archivePrefix, "archive-prefix", "", "", "String to prefix to name of archive if created (e.g., with cilium pod-name)")
+	BugtoolRootCmd.Flags().BoolVar(&redact, "redact", true, "Remove secrets such as tokens, private keys and passwords from the collected output")
+	BugtoolRootCmd.Flags().StringSliceVar(&redactPatterns, "redact-pattern", []string{}, "Additional regular expression whose matches are removed from the collected output (can be repeated)")
+	BugtoolRootCmd.Flags().BoolVar(&anonymizeIPs, "anonymize-ips", false, "Consistently replace IP addresses in the collected output with anonymized addresses")
+	BugtoolRootCmd.Flags().StringSliceVar(&profileNamesOpt, "profile", []string{defaultProfile},
+		fmt.Sprintf("Collection profiles to run: %s", strings.Join(profileNames(), " | ")))
+	BugtoolRootCmd.Flags().IntVar(&parallel, "parallel", 0, "Number of commands to run in parallel (0 runs half of the commands at once)")
+	BugtoolRootCmd.Flags().BoolVar(&allNodes, "all-nodes", false, "Run the bugtool in every Cilium pod and merge the results into one archive")
 }
 
 func getVerifyCiliumPods() []string {
@@ -193,18 +211,49 @@ func runTool() {
 	if config, _ := loadConfigFile(configPath); config != nil {
 		// All of of the commands run are from the configuration file
 		commands = config.Commands
+		redactPatterns = append(redactPatterns, config.RedactPatterns...)
+		if len(config.Profiles) > 0 && !profileSet {
+			profileNamesOpt = config.Profiles
+		}
+		if cmdTimeouts, err = parseTimeouts(config.Timeouts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
 	}
-	if len(commands) == 0 {
-		// Found no configuration file or empty so fall back to default commands.
-		commands = defaultCommands(confDir, cmdDir, k8sPods)
+	if len(commands) == 0 && !allNodes {
+		// Found no configuration file or empty so fall back to the
+		// commands of the selected profiles.
+		if commands, err = profileCommands(profileNamesOpt, confDir, cmdDir, k8sPods); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
 	}
 	defer printDisclaimer()
 
-	runAll(commands, cmdDir, k8sPods)
+	if allNodes {
+		if len(k8sPods) == 0 {
+			fmt.Fprint(os.Stderr, "Error: --all-nodes requires Cilium pods to be found\n")
+			os.Exit(1)
+		}
+		runAllNodes(dbgDir, cmdDir, k8sPods)
+	} else {
+		runAll(commands, cmdDir, k8sPods)
+	}
 
 	removeIfEmpty(cmdDir)
 	removeIfEmpty(confDir)
 
+	if redact || len(redactPatterns) > 0 || anonymizeIPs {
+		mappingPath, err := redactDebugDir(dbgDir, redact, redactPatterns, anonymizeIPs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to redact collected output %s\n", err)
+			os.Exit(1)
+		}
+		if mappingPath != "" {
+			fmt.Printf("IP address mapping at %s (do not share)\n", mappingPath)
+		}
+	}
+
 	if archive {
 		switch archiveType {
 		case "gz":
@@ -314,6 +363,9 @@ func runAll(commands []string, cmdDir string, k8sPods []string) {
 		// No commands
 		return
 	}
+	if parallel > 0 && parallel < numRoutinesAtOnce {
+		numRoutinesAtOnce = parallel
+	}
 	semaphore := make(chan bool, numRoutinesAtOnce)
 	for i := 0; i < numRoutinesAtOnce; i++ {
 		// This will not block because the channel is buffered and we
@@ -354,7 +406,11 @@ func runAll(commands []string, cmdDir string, k8sPods []string) {
 }
 
 func execCommand(prompt string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	return execCommandWithTimeout(prompt, execTimeout)
+}
+
+func execCommandWithTimeout(prompt string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	output, err := exec.CommandContext(ctx, "bash", "-c", prompt).CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
@@ -396,7 +452,7 @@ func writeCmdToFile(cmdDir, prompt string, k8sPods []string, enableMarkdown bool
 		}
 	}
 	// Write prompt as header and the output as body, and / or error but delete empty output.
-	output, err := execCommand(prompt)
+	output, err := execCommandWithTimeout(prompt, commandTimeout(prompt, cmdTimeouts))
 	if err != nil {
 		fmt.Fprintf(f, fmt.Sprintf("> Error while running '%s':  %s\n\n", prompt, err))
 	}
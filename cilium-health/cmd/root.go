@@ -83,6 +83,10 @@ func init() {
 	flags.StringP("host", "H", "", "URI to cilium-health server API")
 	flags.StringP("cilium", "c", "", "URI to Cilium server API")
 	flags.UintP("interval", "i", 60, "Interval (in seconds) for periodic connectivity probes")
+	flags.Uint("icmp-probes", 3, "Number of ICMP echo requests sent to each IP per probe interval (0 to disable)")
+	flags.Uint("history-size", 30, "Number of probe results kept per path for the connectivity history (0 to disable)")
+	flags.Bool("pmtu-probe", true, "Probe the path MTU towards each IP and compare it against the configured MTU")
+	flags.Uint("tcp-probes", 3, "Number of TCP connects to the health port of each IP per probe interval (0 to disable)")
 	flags.StringSlice("log-driver", []string{}, "Logging endpoints to use for example syslog, fluentd")
 	flags.Var(option.NewNamedMapOptions("log-opts", &logOpts, nil),
 		"log-opt", "Log driver options for cilium-health")
@@ -119,12 +123,16 @@ func initConfig() {
 
 	if viper.GetBool("daemon") {
 		config := serverPkg.Config{
-			CiliumURI:     viper.GetString("cilium"),
-			Debug:         viper.GetBool("debug"),
-			Passive:       viper.GetBool("passive"),
-			Admin:         getAdminOption(),
-			ProbeInterval: time.Duration(viper.GetInt("interval")) * time.Second,
-			ProbeDeadline: time.Second,
+			CiliumURI:      viper.GetString("cilium"),
+			Debug:          viper.GetBool("debug"),
+			Passive:        viper.GetBool("passive"),
+			Admin:          getAdminOption(),
+			ProbeInterval:  time.Duration(viper.GetInt("interval")) * time.Second,
+			ProbeDeadline:  time.Second,
+			ICMPProbeCount: viper.GetInt("icmp-probes"),
+			TCPProbeCount:  viper.GetInt("tcp-probes"),
+			HistorySize:    viper.GetInt("history-size"),
+			PMTUProbe:      viper.GetBool("pmtu-probe"),
 		}
 		if srv, err := serverPkg.NewServer(config); err != nil {
 			Fatalf("Error while creating server: %s\n", err)
@@ -26,6 +26,7 @@ import (
 )
 
 var (
+	history  bool
 	probe    bool
 	succinct bool
 	verbose  bool
@@ -61,6 +62,10 @@ var statusGetCmd = &cobra.Command{
 			if err := command.PrintOutput(sr); err != nil {
 				os.Exit(1)
 			}
+		} else if history {
+			w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+			clientPkg.FormatHealthStatusHistory(w, sr)
+			w.Flush()
 		} else {
 			w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
 			clientPkg.FormatHealthStatusResponse(w, sr, true, succinct, verbose, 0)
@@ -71,6 +76,8 @@ var statusGetCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(statusGetCmd)
+	statusGetCmd.Flags().BoolVarP(&history, "history", "", false,
+		"Print loss, latency and flap statistics over recent probes")
 	statusGetCmd.Flags().BoolVarP(&probe, "probe", "", false,
 		"Synchronously probe connectivity status")
 	statusGetCmd.Flags().BoolVarP(&succinct, "succinct", "", false,
@@ -248,7 +248,9 @@ func LaunchAsEndpoint(owner endpoint.Owner, hostAddressing *models.NodeAddressin
 		return fmt.Errorf("Error while adding endpoint: %s", err)
 	}
 
-	// Propagate health IPs to all other nodes
+	// Propagate health IPs to all other nodes. The health IPs are part of
+	// the CiliumNode resource, the node annotations are still written for
+	// agents of the previous version which do not watch CiliumNodes yet.
 	if k8s.IsEnabled() {
 		err := k8s.AnnotateNode(k8s.Client(), node.GetName(), nil, nil, ip4, ip6, nil)
 		if err != nil {
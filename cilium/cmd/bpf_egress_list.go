@@ -0,0 +1,63 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/command"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	egressSourceTitle  = "SOURCE IP & DESTINATION CIDR"
+	egressGatewayTitle = "GATEWAY IP"
+)
+
+var bpfEgressListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List egress NAT gateway entries",
+	Run: func(cmd *cobra.Command, args []string) {
+		common.RequireRootPrivilege("cilium bpf egress list")
+
+		if err := egressmap.EgressMap.Open(); err != nil {
+			Fatalf("Unable to open %s: %s: egress NAT may be disabled", egressmap.MapName, err)
+		}
+		defer egressmap.EgressMap.Close()
+
+		egressList := make(map[string][]string)
+		if err := egressmap.EgressMap.Dump(egressList); err != nil {
+			os.Exit(1)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(egressList); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		TablePrinter(egressSourceTitle, egressGatewayTitle, egressList)
+	},
+}
+
+func init() {
+	bpfEgressCmd.AddCommand(bpfEgressListCmd)
+	command.AddJSONOutput(bpfEgressListCmd)
+}
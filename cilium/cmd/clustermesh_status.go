@@ -0,0 +1,59 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	pkg "github.com/cilium/cilium/pkg/client"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterMeshStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display status of remote clusters",
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.Daemon.GetHealthz(nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", pkg.Hint(err))
+			os.Exit(1)
+		}
+
+		cm := resp.Payload.ClusterMesh
+		if cm == nil {
+			fmt.Println("ClusterMesh is not enabled")
+			return
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(cm); err != nil {
+				os.Exit(1)
+			}
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+			pkg.FormatClusterMeshStatus(w, cm, true)
+			w.Flush()
+		}
+	},
+}
+
+func init() {
+	clusterMeshCmd.AddCommand(clusterMeshStatusCmd)
+	command.AddJSONOutput(clusterMeshStatusCmd)
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	labelsDryRun bool
+	labelsReset  bool
+)
+
+var configLabelsCmd = &cobra.Command{
+	Use:   "labels [<prefix> ...]",
+	Short: "Display or change the identity relevant label prefixes",
+	Long: `Display or change the label prefixes which select the labels relevant for
+security identities. The given prefixes replace the prefixes configured with
+--labels on the agent and use the same format. Endpoints whose identity
+relevant labels change are assigned new identities.`,
+	Example: `  cilium config labels k8s:app k8s:io.kubernetes.pod.namespace
+  cilium config labels --dry-run k8s:app
+  cilium config labels --reset`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 && !labelsReset {
+			showLabelFilter()
+			return
+		}
+		if len(args) > 0 && labelsReset {
+			Fatalf("Label prefixes cannot be combined with --reset")
+		}
+
+		impact, err := client.ConfigLabelsPut(&models.LabelFilterSpec{Prefixes: args}, labelsDryRun)
+		if err != nil {
+			Fatalf("Unable to change label prefixes: %s", err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(impact); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+		if impact.DryRun {
+			fmt.Fprintln(w, "Dry run, label prefixes have not been changed")
+		}
+		fmt.Fprintf(w, "Identities before:\t%d\n", impact.IdentitiesBefore)
+		fmt.Fprintf(w, "Identities after:\t%d\n", impact.IdentitiesAfter)
+		fmt.Fprintf(w, "Identities merged:\t%d\n", impact.IdentitiesMerged)
+		fmt.Fprintf(w, "Identities split:\t%d\n", impact.IdentitiesSplit)
+		fmt.Fprintf(w, "Endpoints affected:\t%d\n", impact.EndpointsAffected)
+		w.Flush()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configLabelsCmd)
+	configLabelsCmd.Flags().BoolVarP(&labelsDryRun, "dry-run", "", false, "Only report the impact on identities without applying the change")
+	configLabelsCmd.Flags().BoolVarP(&labelsReset, "reset", "", false, "Remove all label prefixes configured in addition to the defaults")
+	command.AddJSONOutput(configLabelsCmd)
+}
+
+func showLabelFilter() {
+	lf, err := client.ConfigLabelsGet()
+	if err != nil {
+		Fatalf("Unable to retrieve label prefixes: %s", err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(lf); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("Configured prefixes:")
+	if lf.Spec != nil {
+		for _, p := range lf.Spec.Prefixes {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+	fmt.Println("Effective prefixes:")
+	if lf.Status != nil {
+		for _, p := range lf.Status.EffectivePrefixes {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pkg "github.com/cilium/cilium/pkg/client"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// xfrmStatPath is the path to the XFRM error counters of the kernel
+const xfrmStatPath = "/proc/net/xfrm_stat"
+
+var encryptStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display status of transparent encryption",
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.Daemon.GetHealthz(nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", pkg.Hint(err))
+			os.Exit(1)
+		}
+
+		es := resp.Payload.Encryption
+		if es == nil {
+			fmt.Println("Encryption status is not available")
+			return
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(es); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+		pkg.FormatEncryptionStatus(w, es, true)
+		if es.Ipsec != nil {
+			if f, err := os.Open(xfrmStatPath); err == nil {
+				formatXfrmErrors(w, f)
+				f.Close()
+			}
+		}
+		w.Flush()
+	},
+}
+
+// formatXfrmErrors writes all non-zero XFRM error counters read from r to w
+func formatXfrmErrors(w io.Writer, r io.Reader) {
+	var errors []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil && n != 0 {
+			errors = append(errors, fmt.Sprintf("   %s:\t%d\n", fields[0], n))
+		}
+	}
+
+	if len(errors) == 0 {
+		fmt.Fprintf(w, "XFRM errors:\tnone\n")
+		return
+	}
+
+	fmt.Fprintf(w, "XFRM errors:\n")
+	for _, e := range errors {
+		fmt.Fprint(w, e)
+	}
+}
+
+func init() {
+	encryptCmd.AddCommand(encryptStatusCmd)
+	command.AddJSONOutput(encryptStatusCmd)
+}
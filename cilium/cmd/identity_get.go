@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	identityApi "github.com/cilium/cilium/api/v1/client/policy"
 	"github.com/cilium/cilium/api/v1/models"
@@ -58,6 +59,58 @@ func printIdentities(identities []*models.Identity) {
 	}
 }
 
+func printIdentityUsages(usages []*models.IdentityUsage) {
+	if command.OutputJSON() {
+		if err := command.PrintOutput(usages); err != nil {
+			Fatalf("Unable to provide JSON output: %s", err)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "ID\tLABELS\tREFS\tUSERS\tNODES\tUNUSED SINCE\n")
+	for _, usage := range usages {
+		lbls := labels.NewLabelsFromModel(usage.Labels).GetPrintableModel()
+		unused := ""
+		if since := time.Time(usage.UnusedSince); !since.IsZero() {
+			unused = since.Format(time.RFC3339)
+		}
+
+		lines := len(lbls)
+		if len(usage.Users) > lines {
+			lines = len(usage.Users)
+		}
+		if len(usage.Nodes) > lines {
+			lines = len(usage.Nodes)
+		}
+		if lines == 0 {
+			lines = 1
+		}
+
+		for i := 0; i < lines; i++ {
+			var lbl, user, node string
+			if i < len(lbls) {
+				lbl = lbls[i]
+			}
+			if i < len(usage.Users) {
+				user = usage.Users[i]
+			}
+			if i < len(usage.Nodes) {
+				node = usage.Nodes[i]
+			}
+			if i == 0 {
+				fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", usage.ID, lbl,
+					usage.LocalReferences, user, node, unused)
+			} else {
+				fmt.Fprintf(w, "\t%s\t\t%s\t%s\t\n", lbl, user, node)
+			}
+		}
+	}
+
+	w.Flush()
+}
+
 // identityGetCmd represents the identity_get command
 var identityGetCmd = &cobra.Command{
 	Use:   "get",
@@ -75,6 +128,15 @@ var identityGetCmd = &cobra.Command{
 				Usagef(cmd, "Invalid identity ID")
 			}
 
+			if verbose {
+				usage, err := client.IdentityUsageGet(args[0])
+				if err != nil {
+					Fatalf("Cannot get usage of identity %s: %s\n", args[0], err)
+				}
+				printIdentityUsages([]*models.IdentityUsage{usage})
+				return
+			}
+
 			params := identityApi.NewGetIdentityIDParams().WithID(args[0]).WithTimeout(api.ClientTimeout)
 			if id, err := client.Policy.GetIdentityID(params); err != nil {
 				Fatalf("Cannot get identity for given ID %s: %s\n", id, err)
@@ -88,5 +150,6 @@ var identityGetCmd = &cobra.Command{
 func init() {
 	identityCmd.AddCommand(identityGetCmd)
 	identityGetCmd.Flags().StringSliceVar(&lookupLabels, "label", []string{}, "Label to lookup")
+	identityGetCmd.Flags().BoolVar(&verbose, "verbose", false, "Print local references, users and nodes using the identity")
 	command.AddJSONOutput(identityGetCmd)
 }
@@ -0,0 +1,46 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var staleMinAge string
+
+// identityStaleCmd represents the identity_stale command
+var identityStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List identities without local users",
+	Long: `List the identities which are still referenced by the identity allocator
+of this node but have not been used by any local endpoint or CIDR prefix
+for at least the duration given with --min-age. Such identities are
+likely leaked.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		usages, err := client.IdentityStaleGet(staleMinAge)
+		if err != nil {
+			Fatalf("Cannot get stale identities: %s\n", err)
+		}
+		printIdentityUsages(usages)
+	},
+}
+
+func init() {
+	identityCmd.AddCommand(identityStaleCmd)
+	identityStaleCmd.Flags().StringVar(&staleMinAge, "min-age", "10m", "Minimum duration an identity must have been without local users")
+	command.AddJSONOutput(identityStaleCmd)
+}
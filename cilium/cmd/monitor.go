@@ -46,12 +46,34 @@ var (
 programs attached to endpoints and devices. This includes:
   * Dropped packet notifications
   * Captured packet traces
-  * Debugging information`,
+  * Debugging information
+
+Packets of drop, trace and capture events can be written to a pcapng file
+with --output pcapng and replayed later with --read. Only pcapng files
+written by cilium monitor can be replayed, classic pcap files and pcapng
+files captured with other tools lack the metadata required to reconstruct
+the events and are rejected.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			runMonitor(args)
 		},
 	}
 	printer = format.NewMonitorFormatter(format.INFO)
+
+	filterIdentities []uint
+	filterCIDRs      []string
+	filterPorts      format.Uint16Flags
+	filterVerdicts   []string
+
+	historySince time.Duration
+	historyLast  int
+
+	outputFormat string
+	outputFile   string
+	replayFile   string
+
+	// infoOut receives informational messages, it is stderr if events are
+	// written to stdout in a binary format
+	infoOut io.Writer = os.Stdout
 )
 
 func init() {
@@ -61,6 +83,16 @@ func init() {
 	monitorCmd.Flags().Var(&printer.FromSource, "from", "Filter by source endpoint id")
 	monitorCmd.Flags().Var(&printer.ToDst, "to", "Filter by destination endpoint id")
 	monitorCmd.Flags().Var(&printer.Related, "related-to", "Filter by either source or destination endpoint id")
+	monitorCmd.Flags().UintSliceVar(&filterIdentities, "identity", nil, "Filter by either source or destination security identity")
+	monitorCmd.Flags().StringSliceVar(&filterCIDRs, "cidr", nil, "Filter by either source or destination IP or CIDR")
+	monitorCmd.Flags().Var(&filterPorts, "port", "Filter by either source or destination L4 port")
+	monitorCmd.Flags().StringSliceVar(&filterVerdicts, "verdict", nil, fmt.Sprintf("Filter by verdict [%s %s %s]",
+		monitor.VerdictForwarded, monitor.VerdictDropped, monitor.VerdictAudited))
+	monitorCmd.Flags().DurationVar(&historySince, "since", 0, "Show recorded flow events observed within this duration before connecting, e.g. 5m")
+	monitorCmd.Flags().IntVar(&historyLast, "last", 0, "Show the last N recorded flow events before connecting")
+	monitorCmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Write packets of drop, trace and capture events in the given format instead of printing events [pcapng]")
+	monitorCmd.Flags().StringVar(&outputFile, "output-file", "", "Write the output of --output to this file instead of stdout")
+	monitorCmd.Flags().StringVar(&replayFile, "read", "", "Read events from a pcapng file written with --output pcapng instead of the monitor ('-' for stdin), other capture files are not supported")
 	monitorCmd.Flags().BoolVarP(&printer.Verbose, "verbose", "v", false, "Enable verbose output")
 	monitorCmd.Flags().BoolVarP(&printer.JSONOutput, "json", "j", false, "Enable json output. Shadows -v flag")
 }
@@ -80,7 +112,7 @@ func setupSigHandler() {
 	signal.Notify(signalChan, os.Interrupt)
 	go func() {
 		for range signalChan {
-			fmt.Printf("\nReceived an interrupt, disconnecting from monitor...\n\n")
+			fmt.Fprintf(infoOut, "\nReceived an interrupt, disconnecting from monitor...\n\n")
 			os.Exit(0)
 		}
 	}()
@@ -91,6 +123,13 @@ func setupSigHandler() {
 func openMonitorSock() (conn net.Conn, version listener.Version, err error) {
 	errors := make([]string, 0)
 
+	// try the 1.3 socket
+	conn, err = net.Dial("unix", defaults.MonitorSockPath1_3)
+	if err == nil {
+		return conn, listener.Version1_3, nil
+	}
+	errors = append(errors, defaults.MonitorSockPath1_3+": "+err.Error())
+
 	// try the 1.2 socket
 	conn, err = net.Dial("unix", defaults.MonitorSockPath1_2)
 	if err == nil {
@@ -108,12 +147,50 @@ func openMonitorSock() (conn net.Conn, version listener.Version, err error) {
 	return nil, listener.VersionUnsupported, fmt.Errorf("Cannot find or open a supported node-monitor socket. %s", strings.Join(errors, ","))
 }
 
+// getMonitorFilter returns the filter built from the monitor command flags
+func getMonitorFilter() (*monitor.Filter, error) {
+	filter := &monitor.Filter{
+		MessageTypes:     printer.EventTypes,
+		FromEndpoints:    printer.FromSource,
+		ToEndpoints:      printer.ToDst,
+		RelatedEndpoints: printer.Related,
+		CIDRs:            filterCIDRs,
+		Ports:            filterPorts,
+		Verdicts:         filterVerdicts,
+	}
+	for _, id := range filterIdentities {
+		filter.Identities = append(filter.Identities, uint32(id))
+	}
+
+	return filter, filter.Validate()
+}
+
+// sendMonitorHandshake registers the filter of h with the node monitor so that
+// only matching events are sent on conn, and requests the recorded events
+// of h. Only the 1.3 API supports handshakes, clients must filter the events
+// themselves as well.
+func sendMonitorHandshake(conn net.Conn, version listener.Version, h *listener.Handshake) error {
+	if version != listener.Version1_3 {
+		if h.WantsHistory() {
+			log.Warn("Node monitor does not support recorded events, only showing new events")
+		}
+		return nil
+	}
+	return listener.SendHandshake(conn, h)
+}
+
 // consumeMonitorEvents handles and prints events on a monitor connection. It
 // calls getMonitorParsed to construct a monitor-version appropraite parser.
+// Events are filtered by the filter of h, which is sent to the node monitor
+// along with the request for recorded events.
 // It closes conn on return, and returns on error, including io.EOF
-func consumeMonitorEvents(conn net.Conn, version listener.Version) error {
+func consumeMonitorEvents(conn net.Conn, version listener.Version, h *listener.Handshake) error {
 	defer conn.Close()
 
+	if err := sendMonitorHandshake(conn, version, h); err != nil {
+		return err
+	}
+
 	getParsedPayload, err := getMonitorParser(conn, version)
 	if err != nil {
 		return err
@@ -124,13 +201,29 @@ func consumeMonitorEvents(conn net.Conn, version listener.Version) error {
 		if err != nil {
 			return err
 		}
-		if !printer.FormatEvent(pl) {
-			// earlier code used an else to handle this case, along with pl.Type ==
-			// payload.RecordLost above. It should be safe to call lostEvent to match
-			// the earlier behaviour, despite it not being wholly correct.
-			log.WithError(err).WithField("type", pl.Type).Warn("Unknown payload type")
-			format.LostEvent(pl.Lost, pl.CPU)
+		if !h.Filter.Match(pl) {
+			continue
 		}
+		handleMonitorEvent(pl)
+	}
+}
+
+// handleMonitorEvent prints the event, or writes it to the pcapng output if
+// enabled.
+func handleMonitorEvent(pl *payload.Payload) {
+	if pcapOutput != nil {
+		if err := pcapOutput.writeEvent(pl); err != nil {
+			Fatalf("%s", err)
+		}
+		return
+	}
+
+	if !printer.FormatEvent(pl) {
+		// earlier code used an else to handle this case, along with pl.Type ==
+		// payload.RecordLost above. It should be safe to call lostEvent to match
+		// the earlier behaviour, despite it not being wholly correct.
+		log.WithField("type", pl.Type).Warn("Unknown payload type")
+		format.LostEvent(pl.Lost, pl.CPU)
 	}
 }
 
@@ -156,13 +249,13 @@ func getMonitorParser(conn net.Conn, version listener.Version) (parser eventPars
 			return &pl, nil
 		}, nil
 
-	case listener.Version1_2:
+	case listener.Version1_2, listener.Version1_3:
 		var (
 			pl  payload.Payload
 			dec = gob.NewDecoder(conn)
 		)
-		// This implemenents the newer 1.2 API. Each listener maintains its own gob
-		// session, and type information is only ever sent once.
+		// This implemenents the newer 1.2 and 1.3 API. Each listener maintains
+		// its own gob session, and type information is only ever sent once.
 		return func() (*payload.Payload, error) {
 			if err := pl.DecodeBinary(dec); err != nil {
 				return nil, err
@@ -183,13 +276,34 @@ func runMonitor(args []string) {
 
 	setVerbosity()
 	setupSigHandler()
+
+	filter, err := getMonitorFilter()
+	if err != nil {
+		Fatalf("Invalid filter: %s", err)
+	}
+	h := &listener.Handshake{Filter: *filter, Last: historyLast}
+	if historySince > 0 {
+		h.Since = time.Now().Add(-historySince)
+	}
+
+	if err := setupPcapOutput(); err != nil {
+		Fatalf("Unable to set up output: %s", err)
+	}
+
+	if replayFile != "" {
+		if err := replayPcapng(replayFile, filter); err != nil {
+			Fatalf("Unable to read %s: %s", replayFile, err)
+		}
+		return
+	}
+
 	if resp, err := client.Daemon.GetHealthz(nil); err == nil {
 		if nm := resp.Payload.NodeMonitor; nm != nil {
-			fmt.Printf("Listening for events on %d CPUs with %dx%d of shared memory\n",
+			fmt.Fprintf(infoOut, "Listening for events on %d CPUs with %dx%d of shared memory\n",
 				nm.Cpus, nm.Npages, nm.Pagesize)
 		}
 	}
-	fmt.Printf("Press Ctrl-C to quit\n")
+	fmt.Fprintf(infoOut, "Press Ctrl-C to quit\n")
 
 	// On EOF, retry
 	// On other errors, exit
@@ -201,7 +315,10 @@ func runMonitor(args []string) {
 			return
 		}
 
-		err = consumeMonitorEvents(conn, version)
+		err = consumeMonitorEvents(conn, version, h)
+		// Only request recorded events on the first connection, they
+		// have been shown already when reconnecting
+		h.Since, h.Last = time.Time{}, 0
 		switch {
 		case err == nil:
 		// no-op
@@ -0,0 +1,161 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/monitor/pcapng"
+)
+
+const outputFormatPcapng = "pcapng"
+
+// pcapOutput writes the packets of monitor events as pcapng, nil if events
+// are printed
+var pcapOutput *monitorPcapWriter
+
+// monitorPcapWriter writes the packets carried by monitor events to a pcapng
+// file, annotated with the metadata of the events
+type monitorPcapWriter struct {
+	w *pcapng.Writer
+}
+
+// setupPcapOutput sets up pcapOutput according to the output flags
+func setupPcapOutput() error {
+	switch outputFormat {
+	case "":
+		if outputFile != "" {
+			return fmt.Errorf("--output-file requires --output")
+		}
+		return nil
+	case outputFormatPcapng:
+	default:
+		return fmt.Errorf("unsupported output format %q", outputFormat)
+	}
+
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return err
+		}
+		out = f
+	} else {
+		infoOut = os.Stderr
+	}
+
+	w, err := pcapng.NewWriter(out, pcapng.LinkTypeEthernet, "cilium monitor")
+	if err != nil {
+		return err
+	}
+	pcapOutput = &monitorPcapWriter{w: w}
+	return nil
+}
+
+// writeEvent writes the packet carried by the event. Events which don't carry
+// a packet are ignored.
+func (pw *monitorPcapWriter) writeEvent(pl *payload.Payload) error {
+	switch pl.Type {
+	case payload.RecordLost:
+		fmt.Fprintf(infoOut, "CPU %02d: Lost %d events\n", pl.CPU, pl.Lost)
+		return nil
+	case payload.EventSample:
+	default:
+		return nil
+	}
+
+	pkt, err := monitor.GetEventPacket(pl.Data, pl.CPU)
+	if err != nil {
+		log.WithError(err).Warn("Skipping monitor event")
+		return nil
+	}
+	if pkt == nil || len(pkt.Data) == 0 {
+		return nil
+	}
+
+	return pw.w.WritePacket(&pcapng.Packet{
+		Timestamp: time.Now(),
+		Data:      pkt.Data,
+		OrigLen:   pkt.OrigLen,
+		Comments:  []string{pkt.Summary, pkt.Metadata},
+	})
+}
+
+// replayPcapng reads the packets of a pcapng file written by
+// monitorPcapWriter and handles the events they have been taken from as if
+// they had been received from the monitor.
+func replayPcapng(path string, filter *monitor.Filter) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return replayEvents(in, filter, handleMonitorEvent)
+}
+
+// replayEvents calls handle for each event of the pcapng data read from in
+// which matches filter. Packets lacking the metadata written by
+// monitorPcapWriter can't be turned back into events, files captured with
+// other tools are therefore rejected.
+func replayEvents(in io.Reader, filter *monitor.Filter, handle func(*payload.Payload)) error {
+	r, err := pcapng.NewReader(in)
+	if err != nil {
+		return err
+	}
+
+	for n := 1; ; n++ {
+		pkt, err := r.ReadPacket()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		pl, err := packetToPayload(pkt)
+		if err != nil {
+			return fmt.Errorf("%s: packet %d: %s (only files written with --output %s can be read)",
+				pcapng.ErrUnsupportedFormat, n, err, outputFormatPcapng)
+		}
+		if filter.Match(pl) {
+			handle(pl)
+		}
+	}
+}
+
+// packetToPayload reconstructs the monitor event of a packet from the
+// metadata in its comments
+func packetToPayload(pkt *pcapng.Packet) (*payload.Payload, error) {
+	for _, comment := range pkt.Comments {
+		if !strings.HasPrefix(comment, monitor.PacketMetadataPrefix) {
+			continue
+		}
+		data, cpu, err := monitor.ParseEventPacket(comment, pkt.Data, pkt.OrigLen)
+		if err != nil {
+			return nil, err
+		}
+		return &payload.Payload{Type: payload.EventSample, Data: data, CPU: cpu}, nil
+	}
+	return nil, fmt.Errorf("no cilium monitor event metadata")
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/monitor/pcapng"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *CMDHelpersSuite) TestReplayEvents(c *C) {
+	hdr := monitor.DropNotify{Type: monitor.MessageTypeDrop, SubType: 133, Source: 10, OrigLen: 4, CapLen: 4}
+	data := new(bytes.Buffer)
+	c.Assert(binary.Write(data, byteorder.Native, hdr), IsNil)
+	data.Write([]byte{1, 2, 3, 4})
+	ep, err := monitor.GetEventPacket(data.Bytes(), 2)
+	c.Assert(err, IsNil)
+
+	eventPkt := &pcapng.Packet{
+		Timestamp: time.Now(),
+		Data:      ep.Data,
+		OrigLen:   ep.OrigLen,
+		Comments:  []string{ep.Summary, ep.Metadata},
+	}
+	foreignPkt := &pcapng.Packet{Timestamp: time.Now(), Data: []byte{1, 2, 3, 4}, OrigLen: 4}
+
+	capture := func(packets ...*pcapng.Packet) *bytes.Buffer {
+		buf := new(bytes.Buffer)
+		w, err := pcapng.NewWriter(buf, pcapng.LinkTypeEthernet, "test")
+		c.Assert(err, IsNil)
+		for _, p := range packets {
+			c.Assert(w.WritePacket(p), IsNil)
+		}
+		return buf
+	}
+
+	var events []*payload.Payload
+	handle := func(pl *payload.Payload) { events = append(events, pl) }
+
+	err = replayEvents(capture(eventPkt), &monitor.Filter{}, handle)
+	c.Assert(err, IsNil)
+	c.Assert(len(events), Equals, 1)
+	c.Assert(events[0].Data, DeepEquals, data.Bytes())
+	c.Assert(events[0].CPU, Equals, 2)
+
+	// Packets captured by other tools carry no event metadata
+	events = nil
+	err = replayEvents(capture(eventPkt, foreignPkt), &monitor.Filter{}, handle)
+	c.Assert(err, Not(IsNil))
+	c.Assert(strings.HasPrefix(err.Error(), pcapng.ErrUnsupportedFormat.Error()), Equals, true)
+	c.Assert(len(events), Equals, 1)
+
+	// Classic pcap files
+	pcap := make([]byte, 24)
+	binary.LittleEndian.PutUint32(pcap, 0xA1B2C3D4)
+	err = replayEvents(bytes.NewReader(pcap), &monitor.Filter{}, handle)
+	c.Assert(err, Equals, pcapng.ErrUnsupportedFormat)
+}
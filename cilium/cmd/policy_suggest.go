@@ -0,0 +1,183 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/policy/suggest"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+var (
+	suggestDuration time.Duration
+	suggestInput    string
+	suggestOutput   string
+)
+
+// policySuggestCmd represents the policy_suggest command
+var policySuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest a policy based on observed traffic",
+	Long: `Suggests a policy which allows all traffic observed on this node.
+
+Flows are taken from policy verdict notifications and access log records of
+the node monitor. Unless --input is specified, the monitor is observed for the
+duration given with --duration. Enable policy audit mode and policy verdict
+notifications to capture flows which would otherwise be dropped.`,
+	Example: `  cilium policy suggest --duration 5m
+  cilium monitor --json > flows.json; cilium policy suggest --input flows.json -o yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			Fatalf("Error: arguments not recognized")
+		}
+
+		s := suggest.NewSuggester(resolveIdentityLabels)
+
+		var err error
+		if suggestInput != "" {
+			err = suggestFromFile(s, suggestInput)
+		} else {
+			err = suggestFromMonitor(s, suggestDuration)
+		}
+		if err != nil {
+			Fatalf("Unable to collect flows: %s", err)
+		}
+
+		ruleList, err := s.Rules()
+		if err != nil {
+			Fatalf("Unable to suggest policy: %s", err)
+		}
+		if err := validatePolicy(ruleList); err != nil {
+			Fatalf("Suggested policy is invalid: %s", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Suggested %d rules from %d observed flows\n",
+			len(ruleList), s.NumFlows())
+
+		var policy []byte
+		switch suggestOutput {
+		case "", "json":
+			policy, err = json.MarshalIndent(ruleList, "", "  ")
+		case "yaml":
+			policy, err = yaml.Marshal(ruleList)
+		default:
+			Fatalf("Unsupported output format %q", suggestOutput)
+		}
+		if err != nil {
+			Fatalf("Cannot marshal policy: %s\n", err)
+		}
+		fmt.Println(string(policy))
+	},
+}
+
+func init() {
+	policyCmd.AddCommand(policySuggestCmd)
+	policySuggestCmd.Flags().DurationVarP(&suggestDuration, "duration", "d", time.Minute, "Duration to observe traffic for")
+	policySuggestCmd.Flags().StringVarP(&suggestInput, "input", "i", "", "Read flows from output of 'cilium monitor --json' instead ('-' for stdin)")
+	policySuggestCmd.Flags().StringVarP(&suggestOutput, "output", "o", "json", "Output format of the policy: json| yaml")
+}
+
+// resolveIdentityLabels retrieves the labels of a security identity from the
+// agent
+func resolveIdentityLabels(id identity.NumericIdentity) (labels.LabelArray, error) {
+	resp, err := client.IdentityGet(id.StringID())
+	if err != nil {
+		return nil, err
+	}
+	return labels.NewLabelsFromModel(resp.Labels).LabelArray(), nil
+}
+
+// suggestFromFile adds all flows found in a file of monitor events in JSON
+// format
+func suggestFromFile(s *suggest.Suggester, path string) error {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			// Skip informational output of cilium monitor
+			continue
+		}
+		if err := s.AddJSON(line); err != nil {
+			log.WithError(err).Warn("Skipping monitor event")
+		}
+	}
+	return scanner.Err()
+}
+
+// suggestFromMonitor adds all flows observed on the monitor socket within
+// duration
+func suggestFromMonitor(s *suggest.Suggester, duration time.Duration) error {
+	conn, version, err := openMonitorSock()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Only policy verdicts and access log records carry flows
+	h := &listener.Handshake{
+		Filter: monitor.Filter{
+			MessageTypes: monitor.MessageTypeFilter{monitor.MessageTypePolicyVerdict, monitor.MessageTypeAccessLog},
+		},
+	}
+	if err := sendMonitorHandshake(conn, version, h); err != nil {
+		return err
+	}
+
+	getParsedPayload, err := getMonitorParser(conn, version)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Observing traffic for %s\n", duration)
+	conn.SetReadDeadline(time.Now().Add(duration))
+	for {
+		pl, err := getParsedPayload()
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				return nil
+			}
+			return err
+		}
+		if err := s.AddPayload(pl); err != nil {
+			log.WithError(err).Warn("Skipping monitor event")
+		}
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cilium/cilium/pkg/policy/api"
+
 	"github.com/spf13/cobra"
 )
 
@@ -31,10 +33,8 @@ var policyValidateCmd = &cobra.Command{
 		if ruleList, err := loadPolicy(path); err != nil {
 			Fatalf("Validation of policy has failed: %s\n", err)
 		} else {
-			for _, r := range ruleList {
-				if err := r.Sanitize(); err != nil {
-					Fatalf("Validation of policy has failed: %s\n", err)
-				}
+			if err := validatePolicy(ruleList); err != nil {
+				Fatalf("Validation of policy has failed: %s\n", err)
 			}
 			fmt.Printf("All policy elements are valid.\n")
 
@@ -49,6 +49,16 @@ var policyValidateCmd = &cobra.Command{
 	},
 }
 
+// validatePolicy sanitizes all rules of ruleList and returns the first error
+func validatePolicy(ruleList api.Rules) error {
+	for _, r := range ruleList {
+		if err := r.Sanitize(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func init() {
 	policyCmd.AddCommand(policyValidateCmd)
 	policyValidateCmd.Flags().BoolVarP(&printPolicy, "print", "", false, "Print policy after validation")
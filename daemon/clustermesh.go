@@ -0,0 +1,94 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shareGlobalService publishes the local backends of a global service to all
+// other clusters of the ClusterMesh. Each agent publishes the service under
+// its own key so the service remains available as long as any agent is
+// running. Must be called with d.loadBalancer.K8sMU held.
+func (d *Daemon) shareGlobalService(svc loadbalancer.K8sServiceNamespace, se *loadbalancer.K8sServiceEndpoint) {
+	if d.globalServiceStore == nil {
+		return
+	}
+
+	clusterService := service.NewClusterService(option.Config.ClusterName, node.GetName(), svc, se)
+	if err := d.globalServiceStore.UpdateLocalKeySync(clusterService); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			logfields.K8sSvcName:   svc.ServiceName,
+			logfields.K8sNamespace: svc.Namespace,
+		}).Warning("Unable to share global service with other clusters")
+	}
+}
+
+// unshareGlobalService withdraws the key of this agent for a global service
+// from all other clusters of the ClusterMesh. Must be called with
+// d.loadBalancer.K8sMU held.
+func (d *Daemon) unshareGlobalService(svc loadbalancer.K8sServiceNamespace) {
+	if d.globalServiceStore == nil {
+		return
+	}
+
+	d.globalServiceStore.DeleteLocalKey(service.NewClusterService(option.Config.ClusterName, node.GetName(), svc, nil))
+}
+
+// MergeExternalServiceUpdate merges the backends of a global service learned
+// from a remote cluster into the local service of the same name. The remote
+// backends are only used while no local backend is available.
+func (d *Daemon) MergeExternalServiceUpdate(clusterService *service.ClusterService) {
+	svc := clusterService.NamespacedName()
+
+	d.loadBalancer.K8sMU.Lock()
+	defer d.loadBalancer.K8sMU.Unlock()
+
+	external, ok := d.loadBalancer.K8sExternalEndpoints[svc]
+	if !ok {
+		external = map[string]*loadbalancer.K8sServiceEndpoint{}
+		d.loadBalancer.K8sExternalEndpoints[svc] = external
+	}
+	external[clusterService.Cluster] = clusterService.Endpoint()
+
+	d.syncLB(nil, &svc, nil)
+}
+
+// MergeExternalServiceDelete removes the backends of a global service of a
+// remote cluster from the local service of the same name.
+func (d *Daemon) MergeExternalServiceDelete(clusterService *service.ClusterService) {
+	svc := clusterService.NamespacedName()
+
+	d.loadBalancer.K8sMU.Lock()
+	defer d.loadBalancer.K8sMU.Unlock()
+
+	external, ok := d.loadBalancer.K8sExternalEndpoints[svc]
+	if !ok {
+		return
+	}
+
+	delete(external, clusterService.Cluster)
+	if len(external) == 0 {
+		delete(d.loadBalancer.K8sExternalEndpoints, svc)
+	}
+
+	d.syncLB(nil, &svc, nil)
+}
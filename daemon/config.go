@@ -24,7 +24,17 @@ func populateConfig() {
 	option.Config.ClusterName = viper.GetString(option.ClusterName)
 	option.Config.ClusterID = viper.GetInt(option.ClusterIDName)
 	option.Config.ClusterMeshConfig = viper.GetString(option.ClusterMeshConfigName)
+	option.Config.ClusterMeshConfigSecret = viper.GetString(option.ClusterMeshConfigSecretName)
+	option.Config.IdentityAllocationMode = viper.GetString(option.IdentityAllocationModeName)
+	option.Config.EnableIPSec = viper.GetBool(option.EnableIPSecName)
+	option.Config.IPSecKeyFile = viper.GetString(option.IPSecKeyFileName)
+	option.Config.IPSecKeySecret = viper.GetString(option.IPSecKeySecretName)
+	option.Config.AutoDirectNodeRoutes = viper.GetBool(option.AutoDirectNodeRoutesName)
+	option.Config.RouteExporter = viper.GetString(option.RouteExporterName)
+	option.Config.RouteExportTable = viper.GetInt(option.RouteExportTableName)
+	option.Config.EnableEgressNAT = viper.GetBool(option.EnableEgressNATName)
 	option.Config.CTMapEntriesGlobalTCP = viper.GetInt(option.CTMapEntriesGlobalTCPName)
 	option.Config.CTMapEntriesGlobalAny = viper.GetInt(option.CTMapEntriesGlobalAnyName)
 	option.Config.UseSingleClusterRoute = viper.GetBool(option.SingleClusterRouteName)
+	option.Config.ToGroupsGenericSource = viper.GetString(option.ToGroupsGenericSourceName)
 }
@@ -44,7 +44,9 @@ import (
 	"github.com/cilium/cilium/pkg/counter"
 	bpfIPCache "github.com/cilium/cilium/pkg/datapath/ipcache"
 	"github.com/cilium/cilium/pkg/datapath/prefilter"
+	"github.com/cilium/cilium/pkg/datapath/routing"
 	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/egressnat"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/envoy"
@@ -53,12 +55,14 @@ import (
 	"github.com/cilium/cilium/pkg/ipam"
 	"github.com/cilium/cilium/pkg/ipcache"
 	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/kvstore/store"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/loadbalancer"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
 	"github.com/cilium/cilium/pkg/maps/eppolicymap"
 	ipcachemap "github.com/cilium/cilium/pkg/maps/ipcache"
 	"github.com/cilium/cilium/pkg/maps/lbmap"
@@ -74,10 +78,13 @@ import (
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	policyApi "github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/policy/groups"
 	"github.com/cilium/cilium/pkg/policy/trafficdirection"
 	"github.com/cilium/cilium/pkg/proxy"
 	"github.com/cilium/cilium/pkg/proxy/logger"
+	"github.com/cilium/cilium/pkg/proxy/logger/sink"
 	"github.com/cilium/cilium/pkg/revert"
+	"github.com/cilium/cilium/pkg/service"
 	"github.com/cilium/cilium/pkg/sockops"
 	"github.com/cilium/cilium/pkg/u8proto"
 	"github.com/cilium/cilium/pkg/workloads"
@@ -87,6 +94,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/vishvananda/netlink"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -134,6 +142,9 @@ type Daemon struct {
 	// dnsPoller is used to implement ToFQDN rules
 	dnsPoller *fqdn.DNSPoller
 
+	// groupsPoller is used to implement ToGroups rules
+	groupsPoller *groups.GroupsPoller
+
 	// k8sAPIs is a set of k8s API in use. They are setup in EnableK8sWatcher,
 	// and may be disabled while the agent runs.
 	// This is on this object, instead of a global, because EnableK8sWatcher is
@@ -150,6 +161,10 @@ type Daemon struct {
 
 	clustermesh *clustermesh.ClusterMesh
 
+	// globalServiceStore is the shared store used to publish the
+	// backends of local global services to other clusters
+	globalServiceStore *store.SharedStore
+
 	// k8sResourceSyncWaitGroup is used to block the starting of the daemon,
 	// including regenerating restored endpoints (if specified) until all
 	// policies, services, ingresses, and endpoints stored in Kubernetes at the
@@ -524,12 +539,34 @@ var ciliumChains = []customChain{
 	},
 }
 
+// egressNATChains is the list of custom iptables chains used by the egress
+// NAT gateway. The chains are fed from the beginning of the Cilium chains
+// and are only installed if egress NAT is enabled.
+var egressNATChains = []customChain{
+	{
+		name:       egressnat.NATChain,
+		table:      "nat",
+		hook:       ciliumPostNatChain,
+		feederArgs: []string{""},
+	},
+	{
+		name:       egressnat.ForwardChain,
+		table:      "filter",
+		hook:       ciliumForwardChain,
+		feederArgs: []string{""},
+	},
+}
+
 func (d *Daemon) removeIptablesRules() {
 	tables := []string{"nat", "mangle", "raw", "filter"}
 	for _, t := range tables {
 		removeCiliumRules(t)
 	}
 
+	for _, c := range egressNATChains {
+		c.remove()
+	}
+
 	for _, c := range ciliumChains {
 		c.remove()
 	}
@@ -542,6 +579,20 @@ func (d *Daemon) installIptablesRules() error {
 		}
 	}
 
+	// The egress NAT chains are fed while the Cilium chains are still
+	// empty so that the traffic selected by egress NAT policies bypasses
+	// the generic masquerade rules installed below.
+	if option.Config.EnableEgressNAT {
+		for _, c := range egressNATChains {
+			if err := c.add(); err != nil {
+				return fmt.Errorf("cannot add custom chain %s: %s", c.name, err)
+			}
+			if err := c.installFeeder(); err != nil {
+				return fmt.Errorf("cannot install feeder rule %s: %s", c.feederArgs, err)
+			}
+		}
+	}
+
 	// Clear the Kubernetes masquerading mark bit to skip source PAT
 	// performed by kube-proxy for all packets destined for Cilium. Cilium
 	// installs a dedicated rule which does the source PAT to the right
@@ -974,6 +1025,8 @@ func createNodeConfigHeaderfile() error {
 	fmt.Fprintf(fw, "#define HEALTH_ID %d\n", identity.GetReservedID(labels.IDNameHealth))
 	fmt.Fprintf(fw, "#define UNMANAGED_ID %d\n", identity.GetReservedID(labels.IDNameUnmanaged))
 	fmt.Fprintf(fw, "#define INIT_ID %d\n", identity.GetReservedID(labels.IDNameInit))
+	fmt.Fprintf(fw, "#define REMOTE_NODE_ID %d\n", identity.GetReservedID(labels.IDNameRemoteNode))
+	fmt.Fprintf(fw, "#define KUBE_APISERVER_ID %d\n", identity.GetReservedID(labels.IDNameKubeAPIServer))
 	fmt.Fprintf(fw, "#define LB_RR_MAX_SEQ %d\n", lbmap.MaxSeq)
 	fmt.Fprintf(fw, "#define CILIUM_LB_MAP_MAX_ENTRIES %d\n", lbmap.MaxEntries)
 	fmt.Fprintf(fw, "#define TUNNEL_ENDPOINT_MAP_SIZE %d\n", tunnel.MaxEntries)
@@ -984,6 +1037,11 @@ func createNodeConfigHeaderfile() error {
 	fmt.Fprintf(fw, "#define IPCACHE_MAP_SIZE %d\n", ipcachemap.MaxEntries)
 	fmt.Fprintf(fw, "#define POLICY_PROG_MAP_SIZE %d\n", policymap.ProgArrayMaxEntries)
 	fmt.Fprintf(fw, "#define SOCKOPS_MAP_SIZE %d\n", sockmap.MaxEntries)
+	fmt.Fprintf(fw, "#define EGRESS_MAP_SIZE %d\n", egressmap.MaxEntries)
+
+	if option.Config.EnableEgressNAT {
+		fw.WriteString("#define ENABLE_EGRESS_NAT 1\n")
+	}
 
 	fmt.Fprintf(fw, "#define TRACE_PAYLOAD_LEN %dULL\n", tracePayloadLen)
 	fmt.Fprintf(fw, "#define MTU %d\n", mtu.GetDeviceMTU())
@@ -1272,6 +1330,9 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 		log.WithError(err).Fatal("postinit failed")
 	}
 
+	// The node annotations are still written for agents of the previous
+	// version which do not watch CiliumNodes yet. They can be removed in
+	// the next release.
 	if k8s.IsEnabled() {
 		log.Info("Annotating k8s node with CIDR ranges")
 		err := k8s.AnnotateNode(k8s.Client(), node.GetName(),
@@ -1305,23 +1366,71 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 		log.Infof("  Loopback IPv4: %s", node.GetIPv4Loopback().String())
 	}
 
+	if err := d.initIPsec(); err != nil {
+		log.WithError(err).Fatal("Unable to initialize IPsec")
+	}
+
+	if err := routing.Init(option.Config.RouteExporter); err != nil {
+		log.WithError(err).Fatal("Unable to initialize routing between nodes")
+	}
+
 	if err := node.ConfigureLocalNode(); err != nil {
 		log.WithError(err).Fatal("Unable to initialize local node")
 	}
 
+	if err := d.watchIPsecKey(); err != nil {
+		log.WithError(err).Fatal("Unable to watch IPsec key")
+	}
+
+	if k8s.IsEnabled() {
+		log.Info("Registering local node as CiliumNode resource")
+		d.syncCiliumNode()
+	}
+
 	// This needs to be done after the node addressing has been configured
 	// as the node address is required as sufix
-	identity.InitIdentityAllocator(&d)
+	if option.Config.IdentityAllocationMode == option.IdentityAllocationModeCRD {
+		if !k8s.IsEnabled() {
+			log.Fatal("Kubernetes is required to allocate identities as CiliumIdentity resources")
+		}
+		if err := d.initCRDIdentityAllocator(); err != nil {
+			log.WithError(err).Fatal("Unable to initialize CiliumIdentity allocator")
+		}
+	} else {
+		identity.InitIdentityAllocator(&d)
+	}
 
-	if path := option.Config.ClusterMeshConfig; path != "" {
+	path, secret := option.Config.ClusterMeshConfig, option.Config.ClusterMeshConfigSecret
+	if path != "" || secret != "" {
 		if option.Config.ClusterID == 0 {
 			log.Info("Cluster-ID is not specified, skipping ClusterMesh initialization")
 		} else {
-			log.WithField("path", path).Info("Initializing ClusterMesh routing")
+			var k8sClient kubernetes.Interface
+			if secret != "" {
+				if !k8s.IsEnabled() {
+					log.Fatalf("Kubernetes is required to read the ClusterMesh configuration from secret %s", secret)
+				}
+				k8sClient = k8s.Client()
+			}
+
+			log.WithFields(logrus.Fields{
+				"path":   path,
+				"secret": secret,
+			}).Info("Initializing ClusterMesh routing")
+			globalServiceStore, err := service.JoinClusterServiceStore(option.Config.ClusterName)
+			if err != nil {
+				log.WithError(err).Fatal("Unable to join global service store")
+			}
+			d.globalServiceStore = globalServiceStore
+
 			clustermesh, err := clustermesh.NewClusterMesh(clustermesh.Configuration{
 				Name:            "clustermesh",
 				ConfigDirectory: path,
+				ConfigSecret:    secret,
+				K8sClient:       k8sClient,
+				StateDirectory:  filepath.Join(option.Config.RunDir, "clustermesh"),
 				NodeKeyCreator:  node.KeyCreator,
+				ServiceMerger:   &d,
 			})
 			if err != nil {
 				log.WithError(err).Fatal("Unable to initialize ClusterMesh")
@@ -1341,10 +1450,23 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 	// we populate the IPCache with the host's IP(s).
 	ipcache.InitIPIdentityWatcher()
 
+	if err := d.initEgressNAT(); err != nil {
+		log.WithError(err).Fatal("Unable to initialize egress NAT")
+	}
+
 	// FIXME: Make the port range configurable.
 	d.l7Proxy = proxy.StartProxySupport(10000, 20000, option.Config.RunDir,
 		option.Config.AccessLog, &d, option.Config.AgentLabels)
 
+	for _, u := range option.Config.AccessLogSinks {
+		s, err := sink.NewSink(u)
+		if err != nil {
+			return nil, restoredEndpoints, fmt.Errorf("invalid access log sink %q: %s", u, err)
+		}
+		logger.AddSink(sink.NewExporter(s, option.Config.AccessLogSinkBufferSize))
+		log.WithField("sink", s.Name()).Info("Exporting L7 access log records")
+	}
+
 	d.startStatusCollector()
 
 	if err := fqdn.ConfigFromResolvConf(); err != nil {
@@ -1362,6 +1484,19 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 		}})
 	fqdn.StartDNSPoller(d.dnsPoller)
 
+	if source := option.Config.ToGroupsGenericSource; source != "" {
+		groups.RegisterProvider(groups.GenericProviderName, groups.NewGenericProvider(source))
+	}
+	d.groupsPoller = groups.NewGroupsPoller(groups.GroupsPollerConfig{
+		AddGeneratedRules: func(generatedRules []*policyApi.Rule) error {
+			// Insert the new rules into the policy repository. We need them to
+			// replace the previous set. This requires the labels to match (including
+			// the ToGroups-UUID one).
+			_, err := d.PolicyAdd(generatedRules, &AddOptions{Replace: true, Generated: true})
+			return err
+		}})
+	groups.StartGroupsPoller(d.groupsPoller)
+
 	return &d, restoredEndpoints, nil
 }
 
@@ -1410,6 +1545,10 @@ func (d *Daemon) checkStaleGlobalMap(path string, filename string) {
 	if !globalCTinUse && ctmap.NameIsGlobal(filename) {
 		d.removeStaleMap(path)
 	}
+
+	if !option.Config.EnableEgressNAT && filename == egressmap.MapName {
+		d.removeStaleMap(path)
+	}
 }
 
 func (d *Daemon) staleMapWalker(path string) error {
@@ -68,6 +68,7 @@ type DaemonSuite struct {
 	OnGetCompilationLock      func() *lock.RWMutex
 	OnSendNotification        func(typ monitor.AgentNotification, text string) error
 	OnNewProxyLogRecord       func(l *accesslog.LogRecord) error
+	OnEndpointIdentityChanged func(e *e.Endpoint)
 }
 
 func (ds *DaemonSuite) SetUpTest(c *C) {
@@ -123,6 +124,7 @@ func (ds *DaemonSuite) SetUpTest(c *C) {
 	ds.OnGetCompilationLock = nil
 	ds.OnSendNotification = nil
 	ds.OnNewProxyLogRecord = nil
+	ds.OnEndpointIdentityChanged = nil
 }
 
 func (ds *DaemonSuite) TearDownTest(c *C) {
@@ -263,6 +265,12 @@ func (ds *DaemonSuite) SendNotification(typ monitor.AgentNotification, text stri
 	panic("SendNotification should not have been called")
 }
 
+func (ds *DaemonSuite) EndpointIdentityChanged(e *e.Endpoint) {
+	if ds.OnEndpointIdentityChanged != nil {
+		ds.OnEndpointIdentityChanged(e)
+	}
+}
+
 func (ds *DaemonSuite) NewProxyLogRecord(l *accesslog.LogRecord) error {
 	if ds.OnNewProxyLogRecord != nil {
 		return ds.OnNewProxyLogRecord(l)
@@ -0,0 +1,67 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/egressnat"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/option"
+)
+
+// initEgressNAT starts to steer the traffic selected by CiliumEgressNATPolicy
+// resources to the egress NAT gateway nodes. It must be called after the
+// iptables rules have been installed.
+func (d *Daemon) initEgressNAT() error {
+	if !option.Config.EnableEgressNAT {
+		return nil
+	}
+
+	if !k8s.IsEnabled() {
+		return fmt.Errorf("Kubernetes is required to read CiliumEgressNATPolicy resources")
+	}
+
+	return egressnat.Init(getEgressNATEndpoints)
+}
+
+// EndpointIdentityChanged triggers a synchronization of egress NAT as the
+// policies selecting the endpoint may have changed
+func (d *Daemon) EndpointIdentityChanged(e *endpoint.Endpoint) {
+	egressnat.TriggerSync()
+}
+
+// getEgressNATEndpoints returns the IPv4 address and the security relevant
+// labels of all local endpoints with a security identity
+func getEgressNATEndpoints() []egressnat.Endpoint {
+	endpoints := []egressnat.Endpoint{}
+
+	for _, e := range endpointmanager.GetEndpoints() {
+		if err := e.RLockAlive(); err != nil {
+			continue
+		}
+		if e.IPv4 != nil && e.SecurityIdentity != nil {
+			endpoints = append(endpoints, egressnat.Endpoint{
+				IPv4:   e.IPv4.IP(),
+				Labels: e.SecurityIdentity.LabelArray,
+			})
+		}
+		e.RUnlock()
+	}
+
+	return endpoints
+}
@@ -15,8 +15,12 @@
 package main
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/cilium/cilium/api/v1/models"
 	. "github.com/cilium/cilium/api/v1/server/restapi/policy"
+	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -65,3 +69,52 @@ func (h *getIdentityID) Handle(params GetIdentityIDParams) middleware.Responder
 
 	return NewGetIdentityIDOK().WithPayload(identity.GetModel())
 }
+
+type getIdentityIDUsage struct{}
+
+func newGetIdentityIDUsageHandler(d *Daemon) GetIdentityIDUsageHandler {
+	return &getIdentityIDUsage{}
+}
+
+func (h *getIdentityIDUsage) Handle(params GetIdentityIDUsageParams) middleware.Responder {
+	nid, err := identity.ParseNumericIdentity(params.ID)
+	if err != nil {
+		return NewGetIdentityIDUsageBadRequest()
+	}
+
+	usage, err := identity.GetIdentityUsage(nid)
+	if err != nil {
+		return api.Error(GetIdentityIDUsageUnreachableCode, err)
+	}
+	if usage == nil {
+		return NewGetIdentityIDUsageNotFound()
+	}
+
+	return NewGetIdentityIDUsageOK().WithPayload(usage.GetModel())
+}
+
+type getIdentityStale struct{}
+
+func newGetIdentityStaleHandler(d *Daemon) GetIdentityStaleHandler { return &getIdentityStale{} }
+
+func (h *getIdentityStale) Handle(params GetIdentityStaleParams) middleware.Responder {
+	var minAge time.Duration
+	if params.MinAge != nil {
+		var err error
+		minAge, err = time.ParseDuration(*params.MinAge)
+		if err != nil {
+			return api.Error(GetIdentityStaleInvalidDurationCode, err)
+		}
+		if minAge < 0 {
+			return api.Error(GetIdentityStaleInvalidDurationCode,
+				fmt.Errorf("negative duration %s", minAge))
+		}
+	}
+
+	stale := []*models.IdentityUsage{}
+	for _, usage := range identity.GetStaleIdentities(minAge) {
+		stale = append(stale, usage.GetModel())
+	}
+
+	return NewGetIdentityStaleOK().WithPayload(stale)
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/datapath/ipsec"
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/option"
+)
+
+// ipsecKeyFileSyncInterval is the interval in which the IPsec key file is
+// checked for a new key
+const ipsecKeyFileSyncInterval = 10 * time.Second
+
+// initIPsec loads the IPsec key. It must be called before the local node is
+// configured so the local node announces the key right away. If IPsec is
+// disabled, all IPsec states and policies of a previous run are removed.
+func (d *Daemon) initIPsec() error {
+	if !option.Config.EnableIPSec {
+		if err := ipsec.RemoveStaleEntries(); err != nil {
+			log.WithError(err).Debug("Unable to remove stale IPsec states and policies")
+		}
+		return nil
+	}
+
+	var (
+		key *ipsec.Key
+		err error
+	)
+	if secret := option.Config.IPSecKeySecret; secret != "" {
+		if !k8s.IsEnabled() {
+			return fmt.Errorf("Kubernetes is required to read the IPsec key from secret %s", secret)
+		}
+		key, err = ipsec.LoadKeySecret(k8s.Client(), secret)
+	} else {
+		key, err = ipsec.LoadKeyFile(option.Config.IPSecKeyFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	ipsec.SetKey(key)
+	node.SetEncryptionKey(key.SPI)
+
+	return nil
+}
+
+// watchIPsecKey watches the source of the IPsec key for a new key
+func (d *Daemon) watchIPsecKey() error {
+	if !option.Config.EnableIPSec {
+		return nil
+	}
+
+	if secret := option.Config.IPSecKeySecret; secret != "" {
+		return ipsec.WatchKeySecret(k8s.Client(), secret, d.rotateIPsecKey)
+	}
+
+	controller.NewManager().UpdateController("ipsec-key-file-sync",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				key, err := ipsec.LoadKeyFile(option.Config.IPSecKeyFile)
+				if err != nil {
+					return err
+				}
+				d.rotateIPsecKey(key)
+				return nil
+			},
+			RunInterval: ipsecKeyFileSyncInterval,
+		})
+
+	return nil
+}
+
+// rotateIPsecKey loads key and announces its index to all other nodes if it
+// has changed
+func (d *Daemon) rotateIPsecKey(key *ipsec.Key) {
+	ipsec.SetKey(key)
+
+	if node.GetEncryptionKey() == key.SPI {
+		return
+	}
+
+	node.SetEncryptionKey(key.SPI)
+	node.NotifyLocalNodeUpdated()
+	if k8s.IsEnabled() {
+		d.syncCiliumNode()
+	}
+}
+
+// getEncryptionStatus returns the status of the transparent encryption
+func (d *Daemon) getEncryptionStatus() *models.EncryptionStatus {
+	if !option.Config.EnableIPSec {
+		return &models.EncryptionStatus{Mode: models.EncryptionStatusModeDisabled}
+	}
+	return ipsec.Status()
+}
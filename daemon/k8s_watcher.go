@@ -25,9 +25,9 @@ import (
 	"sync"
 	"time"
 
-	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/comparator"
 	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/egressnat"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/identity"
@@ -76,14 +76,18 @@ const (
 	k8sAPIGroupNetworkingV1Core = "networking.k8s.io/v1::NetworkPolicy"
 	k8sAPIGroupIngressV1Beta1   = "extensions/v1beta1::Ingress"
 	k8sAPIGroupCiliumV2         = "cilium/v2::CiliumNetworkPolicy"
+	k8sAPIGroupCiliumNodeV2     = "cilium/v2::CiliumNode"
+	k8sAPIGroupEgressNATV2      = "cilium/v2::CiliumEgressNATPolicy"
 	cacheSyncTimeout            = time.Duration(3 * time.Minute)
 
+	metricCENP     = "CiliumEgressNATPolicy"
 	metricCNP      = "CiliumNetworkPolicy"
 	metricEndpoint = "Endpoint"
 	metricIngress  = "Ingress"
 	metricKNP      = "NetworkPolicy"
 	metricNS       = "Namespace"
-	metricNode     = "Node"
+	metricNode     = "CiliumNode"
+	metricNodeV1   = "Node"
 	metricPod      = "Pod"
 	metricService  = "Service"
 	metricCreate   = "create"
@@ -100,6 +104,10 @@ var (
 
 	ciliumNPClient clientset.Interface
 
+	// ciliumNodeStore contains the CiliumNode resources known to the
+	// CiliumNode watcher
+	ciliumNodeStore cache.Store
+
 	networkPolicyV1VerConstr = versioncheck.MustCompile(">= 1.7.0")
 
 	ciliumv2VerConstr           = versioncheck.MustCompile(">= 1.8.0")
@@ -115,6 +123,21 @@ var (
 	endpointMetadataCache = endpointImportMetadataCache{
 		endpointImportMetadataMap: make(map[string]endpointImportMetadata),
 	}
+
+	// local cache of the IPs backing the Kubernetes API server.
+	kubeAPIServerIPs = kubeAPIServerIPCache{
+		ips: make(map[string]struct{}),
+	}
+)
+
+const (
+	// kubeAPIServerServiceName is the name of the service of the
+	// Kubernetes API server
+	kubeAPIServerServiceName = "kubernetes"
+
+	// kubeAPIServerServiceNamespace is the namespace of the service of the
+	// Kubernetes API server
+	kubeAPIServerServiceNamespace = "default"
 )
 
 // ruleImportMetadataCache maps the unique identifier of a CiliumNetworkPolicy
@@ -220,6 +243,80 @@ func (r *endpointImportMetadataCache) get(cnp *v1.Endpoints) (endpointImportMeta
 	return endpointImportMeta, ok
 }
 
+// kubeAPIServerIPCache keeps track of the IPs which have been associated with
+// the kube-apiserver reserved identity in the ipcache based on the Endpoints
+// object of the "kubernetes" service.
+type kubeAPIServerIPCache struct {
+	mutex lock.Mutex
+	ips   map[string]struct{}
+}
+
+// isKubeAPIServerEndpoint returns true if the given Endpoints object belongs
+// to the service of the Kubernetes API server.
+func isKubeAPIServerEndpoint(ep *v1.Endpoints) bool {
+	return ep != nil &&
+		ep.ObjectMeta.Name == kubeAPIServerServiceName &&
+		ep.ObjectMeta.Namespace == kubeAPIServerServiceNamespace
+}
+
+// upsert associates all addresses of the given Endpoints object with the
+// kube-apiserver identity and removes the association of all addresses which
+// are no longer part of it. Addresses already associated with the host or
+// remote-node identity are left untouched.
+func (k *kubeAPIServerIPCache) upsert(ep *v1.Endpoints) {
+	newIPs := map[string]struct{}{}
+	for _, sub := range ep.Subsets {
+		for _, addr := range sub.Addresses {
+			newIPs[addr.IP] = struct{}{}
+		}
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	for ip := range k.ips {
+		if _, ok := newIPs[ip]; !ok {
+			k.deleteLocked(ip)
+		}
+	}
+
+	for ip := range newIPs {
+		if id, exists := ipcache.IPIdentityCache.LookupByIP(ip); exists &&
+			(id.ID == identity.ReservedIdentityHost || id.ID == identity.ReservedIdentityRemoteNode) {
+			continue
+		}
+
+		if ipcache.IPIdentityCache.Upsert(ip, nil, ipcache.Identity{
+			ID:     identity.ReservedIdentityKubeAPIServer,
+			Source: ipcache.FromKubernetes,
+		}) {
+			k.ips[ip] = struct{}{}
+		}
+	}
+}
+
+// delete removes the association of all known kube-apiserver addresses with
+// the kube-apiserver identity.
+func (k *kubeAPIServerIPCache) delete() {
+	k.mutex.Lock()
+	for ip := range k.ips {
+		k.deleteLocked(ip)
+	}
+	k.mutex.Unlock()
+}
+
+func (k *kubeAPIServerIPCache) deleteLocked(ip string) {
+	delete(k.ips, ip)
+
+	// The ipcache entry may have been taken over by another source in
+	// the meantime, only delete it if it is still ours.
+	id, exists := ipcache.IPIdentityCache.LookupByIP(ip)
+	if !exists || id.ID != identity.ReservedIdentityKubeAPIServer || id.Source != ipcache.FromKubernetes {
+		return
+	}
+	ipcache.IPIdentityCache.Delete(ip)
+}
+
 // k8sAPIGroupsUsed is a lockable map to hold which k8s API Groups we have
 // enabled/in-use
 // Note: We can replace it with a Go 1.9 map once we require that version
@@ -347,6 +444,60 @@ func blockWaitGroupToSyncResources(waitGroup *sync.WaitGroup, informer cache.Con
 	}()
 }
 
+// initCRDIdentityAllocator installs the CRDs and initializes the identity
+// allocator to store identities as CiliumIdentity resources. The CRDs are
+// installed here already as identities are allocated before the k8s watcher
+// is enabled.
+func (d *Daemon) initCRDIdentityAllocator() error {
+	restConfig, err := k8s.CreateConfig()
+	if err != nil {
+		return fmt.Errorf("Unable to create rest configuration: %s", err)
+	}
+
+	apiextensionsclientset, err := apiextensionsclient.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to create rest configuration for k8s CRD: %s", err)
+	}
+
+	if err := cilium_v2.CreateCustomResourceDefinitions(apiextensionsclientset); err != nil {
+		return fmt.Errorf("Unable to create custom resource definition: %s", err)
+	}
+
+	ciliumClient, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to create cilium identity client: %s", err)
+	}
+
+	identity.InitCRDIdentityAllocator(d, ciliumClient)
+
+	return nil
+}
+
+// syncCiliumNode writes the local node into its CiliumNode resource. The
+// resource is kept up to date by a controller which retries until the
+// CiliumNode custom resource definition has been registered.
+func (d *Daemon) syncCiliumNode() {
+	// The kubernetes node owning the CiliumNode resource is only retrieved
+	// once, the controller is replaced on every change of the local node
+	var owner *v1.Node
+
+	k8sCM.UpdateController("sync-cilium-node",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				if owner == nil {
+					k8sNode, err := k8s.GetNode(k8s.Client(), node.GetName())
+					if err != nil {
+						return fmt.Errorf("unable to retrieve kubernetes node: %s", err)
+					}
+					owner = k8sNode
+				}
+				return k8s.UpdateCiliumNode(k8s.CiliumClient(), owner, node.CopyLocalNode())
+			},
+			RunInterval: time.Minute,
+		},
+	)
+}
+
 // EnableK8sWatcher watches for policy, services and endpoint changes on the Kubernetes
 // api server defined in the receiver's daemon k8sClient. Re-syncs all state from the
 // Kubernetes api server at the given reSyncPeriod duration.
@@ -384,10 +535,7 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 		return fmt.Errorf("Unsupported k8s version. Minimal supported version is %s", ciliumv2VerConstr.String())
 	}
 
-	ciliumNPClient, err = clientset.NewForConfig(restConfig)
-	if err != nil {
-		return fmt.Errorf("Unable to create cilium network policy client: %s", err)
-	}
+	ciliumNPClient = k8s.CiliumClient()
 
 	switch {
 	case networkPolicyV1VerConstr.Check(k8sServerVer):
@@ -588,6 +736,71 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 		blockWaitGroupToSyncResources(&d.k8sResourceSyncWaitGroup, ciliumV2Controller, "CiliumNetworkPolicy")
 
 		ciliumV2Controller.AddEventHandler(rehf)
+
+		ciliumNodeController := si.Cilium().V2().CiliumNodes().Informer()
+		ciliumNodeStore = ciliumNodeController.GetStore()
+		ciliumNodeController.AddEventHandler(k8sUtils.ResourceEventHandlerFactory(
+			func(i interface{}) func() error {
+				return func() error {
+					err := d.addCiliumNodeV2(i.(*cilium_v2.CiliumNode))
+					updateK8sEventMetric(metricNode, metricCreate, err == nil)
+					return nil
+				}
+			},
+			func(i interface{}) func() error {
+				return func() error {
+					err := d.deleteCiliumNodeV2(i.(*cilium_v2.CiliumNode))
+					updateK8sEventMetric(metricNode, metricDelete, err == nil)
+					return nil
+				}
+			},
+			func(old, new interface{}) func() error {
+				return func() error {
+					err := d.updateCiliumNodeV2(old.(*cilium_v2.CiliumNode), new.(*cilium_v2.CiliumNode))
+					updateK8sEventMetric(metricNode, metricUpdate, err == nil)
+					return nil
+				}
+			},
+			d.missingCiliumNodeV2,
+			&cilium_v2.CiliumNode{},
+			ciliumNPClient,
+			reSyncPeriod,
+			metrics.EventTSK8s,
+		))
+		d.k8sAPIGroups.addAPI(k8sAPIGroupCiliumNodeV2)
+
+		if option.Config.EnableEgressNAT {
+			egressNATController := si.Cilium().V2().CiliumEgressNATPolicies().Informer()
+			egressNATController.AddEventHandler(k8sUtils.ResourceEventHandlerFactory(
+				func(i interface{}) func() error {
+					return func() error {
+						err := d.addCiliumEgressNATPolicyV2(i.(*cilium_v2.CiliumEgressNATPolicy))
+						updateK8sEventMetric(metricCENP, metricCreate, err == nil)
+						return nil
+					}
+				},
+				func(i interface{}) func() error {
+					return func() error {
+						err := d.deleteCiliumEgressNATPolicyV2(i.(*cilium_v2.CiliumEgressNATPolicy))
+						updateK8sEventMetric(metricCENP, metricDelete, err == nil)
+						return nil
+					}
+				},
+				func(old, new interface{}) func() error {
+					return func() error {
+						err := d.addCiliumEgressNATPolicyV2(new.(*cilium_v2.CiliumEgressNATPolicy))
+						updateK8sEventMetric(metricCENP, metricUpdate, err == nil)
+						return nil
+					}
+				},
+				d.missingCiliumEgressNATPolicyV2,
+				&cilium_v2.CiliumEgressNATPolicy{},
+				ciliumNPClient,
+				reSyncPeriod,
+				metrics.EventTSK8s,
+			))
+			d.k8sAPIGroups.addAPI(k8sAPIGroupEgressNATV2)
+		}
 	}
 
 	si.Start(wait.NeverStop)
@@ -629,6 +842,9 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 	go podsController.Run(wait.NeverStop)
 	d.k8sAPIGroups.addAPI(k8sAPIGroupPodV1Core)
 
+	// Nodes running an agent which does not register its node as
+	// CiliumNode yet are discovered via the annotations of the node
+	// resource. This is required while upgrading the cluster.
 	nodesController := k8sUtils.ControllerFactory(
 		k8s.Client().CoreV1().RESTClient(),
 		&v1.Node{},
@@ -636,21 +852,21 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 			func(i interface{}) func() error {
 				return func() error {
 					err := d.addK8sNodeV1(i.(*v1.Node))
-					updateK8sEventMetric(metricNode, metricCreate, err == nil)
+					updateK8sEventMetric(metricNodeV1, metricCreate, err == nil)
 					return nil
 				}
 			},
 			func(i interface{}) func() error {
 				return func() error {
 					err := d.deleteK8sNodeV1(i.(*v1.Node))
-					updateK8sEventMetric(metricNode, metricDelete, err == nil)
+					updateK8sEventMetric(metricNodeV1, metricDelete, err == nil)
 					return nil
 				}
 			},
 			func(old, new interface{}) func() error {
 				return func() error {
 					err := d.updateK8sNodeV1(old.(*v1.Node), new.(*v1.Node))
-					updateK8sEventMetric(metricNode, metricUpdate, err == nil)
+					updateK8sEventMetric(metricNodeV1, metricUpdate, err == nil)
 					return nil
 				}
 			},
@@ -803,6 +1019,7 @@ func parseSvcV1(svc *v1.Service) *loadbalancer.K8sServiceInfo {
 		headless = true
 	}
 	newSI := loadbalancer.NewK8sServiceInfo(clusterIP, headless, svc.Labels, svc.Spec.Selector)
+	newSI.IsGlobal = k8s.IsGlobalService(svc)
 
 	// FIXME: Add support for
 	//  - NodePort
@@ -833,6 +1050,10 @@ func (d *Daemon) addK8sServiceV1(svc *v1.Service) error {
 		if oldSI.Equals(newSI) {
 			return nil
 		}
+
+		if oldSI.IsGlobal && !newSI.IsGlobal {
+			d.unshareGlobalService(svcns)
+		}
 	}
 
 	d.loadBalancer.K8sServices[svcns] = newSI
@@ -928,6 +1149,10 @@ func (d *Daemon) addK8sEndpointV1(ep *v1.Endpoints) error {
 
 	newSvcEP := parseK8sEPv1(ep)
 
+	if isKubeAPIServerEndpoint(ep) {
+		kubeAPIServerIPs.upsert(ep)
+	}
+
 	d.loadBalancer.K8sMU.Lock()
 	defer d.loadBalancer.K8sMU.Unlock()
 
@@ -1017,6 +1242,10 @@ func (d *Daemon) deleteK8sEndpointV1(ep *v1.Endpoints) error {
 		Namespace:   ep.ObjectMeta.Namespace,
 	}
 
+	if isKubeAPIServerEndpoint(ep) {
+		kubeAPIServerIPs.delete()
+	}
+
 	d.loadBalancer.K8sMU.Lock()
 	defer d.loadBalancer.K8sMU.Unlock()
 
@@ -1229,7 +1458,9 @@ func (d *Daemon) addK8sSVCs(svc loadbalancer.K8sServiceNamespace, svcInfo *loadb
 
 		besValues := []loadbalancer.LBBackEnd{}
 
-		if k8sBEPort != nil {
+		if svcInfo.IsGlobal {
+			besValues = loadbalancer.GlobalBackends(se, d.loadBalancer.K8sExternalEndpoints[svc], fePortName, svcInfo.FEIP)
+		} else if k8sBEPort != nil {
 			for epIP := range se.BEIPs {
 				bePort := loadbalancer.LBBackEnd{
 					L3n4Addr: loadbalancer.L3n4Addr{IP: net.ParseIP(epIP), L4Addr: *k8sBEPort},
@@ -1257,8 +1488,17 @@ func (d *Daemon) syncLB(newSN, modSN, delSN *loadbalancer.K8sServiceNamespace) e
 
 		endpoint, ok := d.loadBalancer.K8sEndpoints[delSN]
 		if !ok {
-			delete(d.loadBalancer.K8sServices, delSN)
-			return nil
+			// The frontend of a global service may have been
+			// installed with the backends of remote clusters only
+			if !svc.IsGlobal {
+				delete(d.loadBalancer.K8sServices, delSN)
+				return nil
+			}
+			endpoint = loadbalancer.NewK8sServiceEndpoint()
+		}
+
+		if svc.IsGlobal {
+			d.unshareGlobalService(delSN)
 		}
 
 		if err := d.delK8sSVCs(delSN, svc, endpoint); err != nil {
@@ -1282,7 +1522,16 @@ func (d *Daemon) syncLB(newSN, modSN, delSN *loadbalancer.K8sServiceNamespace) e
 
 		endpoint, ok := d.loadBalancer.K8sEndpoints[addSN]
 		if !ok {
-			return nil
+			// A global service may be served entirely by the
+			// backends of remote clusters. The service is synced
+			// even without any remote backend left so backends
+			// of disconnected clusters are removed.
+			if !svcInfo.IsGlobal {
+				return nil
+			}
+			endpoint = loadbalancer.NewK8sServiceEndpoint()
+		} else if svcInfo.IsGlobal {
+			d.shareGlobalService(addSN, endpoint)
 		}
 
 		if err := d.addK8sSVCs(addSN, svcInfo, endpoint); err != nil {
@@ -2171,73 +2420,29 @@ func (d *Daemon) missingK8sNamespaceV1(m versioned.Map) versioned.Map {
 	return missing
 }
 
-func (d *Daemon) updateK8sNodeTunneling(k8sNodeOld, k8sNodeNew *v1.Node) error {
-	nodeNew := k8s.ParseNode(k8sNodeNew, node.FromKubernetes)
+func (d *Daemon) updateCiliumNodeV2(oldCN, newCN *cilium_v2.CiliumNode) error {
+	var nodeOld *node.Node
+	if oldCN != nil {
+		nodeOld = k8s.ParseCiliumNode(oldCN, node.FromKubernetes)
+	}
+	return d.updateK8sNode(nodeOld, k8s.ParseCiliumNode(newCN, node.FromKubernetes))
+}
+
+// updateK8sNode configures the routes and tunnels to the remote node nodeNew
+// discovered via kubernetes
+func (d *Daemon) updateK8sNode(nodeOld, nodeNew *node.Node) error {
 	// Ignore own node
 	if nodeNew.Name == node.GetName() {
 		return nil
 	}
 
-	getIDs := func(node *node.Node, k8sNode *v1.Node) (string, net.IP, error) {
-		if node == nil {
-			return "", nil, nil
-		}
-		hostIP := node.GetNodeIP(false)
-		if ip4 := hostIP.To4(); ip4 == nil {
-			return "", nil, fmt.Errorf("HostIP is not an IPv4 address: %s", hostIP)
-		}
-
-		ciliumIPStr := k8sNode.GetAnnotations()[annotation.CiliumHostIP]
-		if ciliumIPStr == "" {
-			// Don't return an error here, as the node may not have been
-			// annotated yet by the Cilium agent on it. If the node is annotated
-			// later, we will receive an event via the K8s watcher.
-			log.Infof("not updating ipcache entry for node %s because it does not have the CiliumHostIP annotation yet", k8sNode.Name)
-			return "", nil, nil
-		}
-		ciliumIP := net.ParseIP(ciliumIPStr)
-		if ciliumIP == nil {
-			return "", nil, fmt.Errorf("no/invalid Cilium-Host IP for host %s: %s", hostIP, ciliumIPStr)
-		}
-
-		return ciliumIPStr, hostIP, nil
-	}
-
-	ciliumIPStrNew, hostIPNew, err := getIDs(nodeNew, k8sNodeNew)
-	if err != nil || ciliumIPStrNew == "" || hostIPNew == nil {
-		return err
-	}
-
-	if k8sNodeOld != nil {
-		nodeOld := k8s.ParseNode(k8sNodeOld, node.FromKubernetes)
-		var (
-			err            error
-			ciliumIPStrOld string
-		)
-		ciliumIPStrOld, _, err = getIDs(nodeOld, k8sNodeOld)
-		if err != nil {
-			return err
-		}
-
-		if nodeNew.PublicAttrEquals(nodeOld) {
-			// Ignore updates for the same node.
-			return nil
-		}
-
-		// If the annotation of the node resource has changed, the old
-		// ipcache has to be removed manually as Upsert() only handes
-		// updates if the key itself is unchanged.
-		if ciliumIPStrNew != ciliumIPStrOld {
-			d.deleteK8sNodeV1(k8sNodeOld)
-		}
+	if nodeOld != nil && nodeNew.PublicAttrEquals(nodeOld) {
+		// Ignore updates for the same node.
+		return nil
 	}
 
-	selfOwned := ipcache.IPIdentityCache.Upsert(ciliumIPStrNew, hostIPNew, ipcache.Identity{
-		ID:     identity.ReservedIdentityHost,
-		Source: ipcache.FromKubernetes,
-	})
-	if !selfOwned {
-		return fmt.Errorf("ipcache entry owned by kvstore or agent")
+	if hostIP := nodeNew.GetNodeIP(false); hostIP.To4() == nil {
+		return fmt.Errorf("node IP is not an IPv4 address: %s", hostIP)
 	}
 
 	routeTypes := node.TunnelRoute
@@ -2250,63 +2455,85 @@ func (d *Daemon) updateK8sNodeTunneling(k8sNodeOld, k8sNodeNew *v1.Node) error {
 		routeTypes |= node.DirectRoute
 	}
 
+	// The node manager takes care of replacing the ipcache entries of
+	// addresses which are no longer announced by the node.
 	node.UpdateNode(nodeNew, routeTypes, ownAddr)
 
 	return nil
 }
 
-func (d *Daemon) addK8sNodeV1(k8sNode *v1.Node) error {
-	if err := d.updateK8sNodeTunneling(nil, k8sNode); err != nil {
-		log.WithError(err).Warning("Unable to add ipcache entry of Kubernetes node")
+func (d *Daemon) addCiliumNodeV2(cn *cilium_v2.CiliumNode) error {
+	if err := d.updateCiliumNodeV2(nil, cn); err != nil {
+		log.WithError(err).Warning("Unable to add CiliumNode")
 		return err
 	}
 	return nil
 }
 
-func (d *Daemon) updateK8sNodeV1(k8sNodeOld, k8sNodeNew *v1.Node) error {
-	if err := d.updateK8sNodeTunneling(k8sNodeOld, k8sNodeNew); err != nil {
-		log.WithError(err).Warning("Unable to update ipcache entry of Kubernetes node")
-		return err
-	}
-	return nil
-}
-
-func (d *Daemon) deleteK8sNodeV1(k8sNode *v1.Node) error {
-	ip := k8sNode.GetAnnotations()[annotation.CiliumHostIP]
-
-	logger := log.WithFields(logrus.Fields{
-		"K8sNodeName":    k8sNode.ObjectMeta.Name,
-		logfields.IPAddr: ip,
-	})
-
+func (d *Daemon) deleteCiliumNodeV2(cn *cilium_v2.CiliumNode) error {
 	ni := node.Identity{
-		Name:    k8sNode.ObjectMeta.Name,
+		Name:    cn.ObjectMeta.Name,
 		Cluster: option.Config.ClusterName,
 	}
 
 	node.DeleteNode(ni, node.TunnelRoute|node.DirectRoute)
 
-	id, exists := ipcache.IPIdentityCache.LookupByIP(ip)
-	if !exists {
-		logger.Warning("identity for Cilium IP not found")
+	return nil
+}
+
+// hasCiliumNode returns true if a CiliumNode resource exists for the node
+// nodeName
+func hasCiliumNode(nodeName string) bool {
+	if ciliumNodeStore == nil {
+		return false
+	}
+	_, exists, err := ciliumNodeStore.GetByKey(nodeName)
+	return err == nil && exists
+}
+
+func (d *Daemon) updateK8sNodeV1(k8sNodeOld, k8sNodeNew *v1.Node) error {
+	// The CiliumNode resource takes precedence over the node annotations
+	// written by previous versions of the agent
+	if hasCiliumNode(k8sNodeNew.Name) {
 		return nil
 	}
 
-	// The ipcache entry ownership may have been taken over by a kvstore
-	// based entry in which case we should ignore the delete event and wait
-	// for the kvstore delete event.
-	if id.Source != ipcache.FromKubernetes {
-		logger.Debug("ipcache entry for Cilium IP no longer owned by Kubernetes")
+	nodeNew := k8s.ParseLegacyNode(k8sNodeNew, node.FromKubernetes)
+	if nodeNew == nil {
+		// The node has not been annotated by a cilium agent (yet). If
+		// the node is annotated later, we will receive an event via the
+		// K8s watcher.
 		return nil
 	}
 
-	ipcache.IPIdentityCache.Delete(ip)
+	var nodeOld *node.Node
+	if k8sNodeOld != nil {
+		nodeOld = k8s.ParseLegacyNode(k8sNodeOld, node.FromKubernetes)
+	}
 
-	ciliumIP := net.ParseIP(ip)
-	if ciliumIP == nil {
-		logger.Warning("Unable to parse Cilium IP")
+	if err := d.updateK8sNode(nodeOld, nodeNew); err != nil {
+		log.WithError(err).Warning("Unable to update Kubernetes node")
+		return err
+	}
+	return nil
+}
+
+func (d *Daemon) addK8sNodeV1(k8sNode *v1.Node) error {
+	return d.updateK8sNodeV1(nil, k8sNode)
+}
+
+func (d *Daemon) deleteK8sNodeV1(k8sNode *v1.Node) error {
+	if hasCiliumNode(k8sNode.Name) {
 		return nil
 	}
+
+	ni := node.Identity{
+		Name:    k8sNode.ObjectMeta.Name,
+		Cluster: option.Config.ClusterName,
+	}
+
+	node.DeleteNode(ni, node.TunnelRoute|node.DirectRoute)
+
 	return nil
 }
 
@@ -2321,41 +2548,92 @@ func updateK8sEventMetric(scope string, action string, status bool) {
 	metrics.KubernetesEvent.WithLabelValues(scope, action, result).Inc()
 }
 
-// missingK8sNodeV1 checks if all nodes from the possible missing nodes have
-// their CiliumHostIP missing from the ipcache or if the CiliumHostIP is
-// associated with the right node.
-func (d *Daemon) missingK8sNodeV1(m versioned.Map) versioned.Map {
+// missingCiliumNodeV2 checks if all CiliumNodes from the possible missing
+// nodes are known to the node manager with the same public attributes.
+func (d *Daemon) missingCiliumNodeV2(m versioned.Map) versioned.Map {
 	missing := versioned.NewMap()
 	nodes := node.GetNodes()
 	for k, v := range m {
-		n := v.Data.(*v1.Node)
-		ciliumHostIPStr := n.GetAnnotations()[annotation.CiliumHostIP]
-		if ciliumHostIPStr == "" {
+		cn := v.Data.(*cilium_v2.CiliumNode)
+		if cn.GetName() == node.GetName() {
 			continue
 		}
-		_, exists := ipcache.IPIdentityCache.LookupByIP(ciliumHostIPStr)
-		// The node is considered missing if the Cilium HostIP of that
-		// node doesn't exist in the identity cache.
-		if !exists {
+
+		n := k8s.ParseCiliumNode(cn, node.FromKubernetes)
+		existing, ok := nodes[n.Identity()]
+		if !ok {
 			missing.Add(k, v)
 			continue
 		}
 
-		ciliumHostIP := net.ParseIP(ciliumHostIPStr)
-		if ciliumHostIP == nil {
+		// Nodes announced via the kvstore take precedence over the
+		// Kubernetes resource and are not considered missing.
+		if existing.Source == node.FromKubernetes && !existing.PublicAttrEquals(n) {
+			missing.Add(k, v)
+		}
+	}
+	return missing
+}
+
+// missingK8sNodeV1 checks if all nodes annotated by previous versions of the
+// agent and not registered as CiliumNode are known to the node manager with
+// the same public attributes.
+func (d *Daemon) missingK8sNodeV1(m versioned.Map) versioned.Map {
+	missing := versioned.NewMap()
+	nodes := node.GetNodes()
+	for k, v := range m {
+		k8sNode := v.Data.(*v1.Node)
+		if k8sNode.GetName() == node.GetName() || hasCiliumNode(k8sNode.GetName()) {
+			continue
+		}
+
+		n := k8s.ParseLegacyNode(k8sNode, node.FromKubernetes)
+		if n == nil {
 			continue
 		}
 
-		// Or if the CiliumHostIP is the right one for this node.
-		nodeIdentity := node.Identity{Name: n.GetName(), Cluster: option.Config.ClusterName}
-		var found bool
-		for _, v := range nodes[nodeIdentity].IPAddresses {
-			if v.IP.Equal(ciliumHostIP) {
-				found = true
-				break
-			}
+		existing, ok := nodes[n.Identity()]
+		if !ok {
+			missing.Add(k, v)
+			continue
+		}
+
+		// Nodes announced via the kvstore take precedence over the
+		// Kubernetes resource and are not considered missing.
+		if existing.Source == node.FromKubernetes && !existing.PublicAttrEquals(n) {
+			missing.Add(k, v)
 		}
-		if !found {
+	}
+	return missing
+}
+
+func (d *Daemon) addCiliumEgressNATPolicyV2(p *cilium_v2.CiliumEgressNATPolicy) error {
+	scopedLog := log.WithField(logfields.CiliumEgressNATPolicyName, egressnat.PolicyName(p))
+
+	policy, err := egressnat.ParsePolicy(p)
+	if err != nil {
+		scopedLog.WithError(err).Warning("Error parsing CiliumEgressNATPolicy")
+		return err
+	}
+
+	egressnat.UpsertPolicy(policy)
+	scopedLog.Debug("Applied CiliumEgressNATPolicy")
+
+	return nil
+}
+
+func (d *Daemon) deleteCiliumEgressNATPolicyV2(p *cilium_v2.CiliumEgressNATPolicy) error {
+	egressnat.DeletePolicy(egressnat.PolicyName(p))
+	return nil
+}
+
+// missingCiliumEgressNATPolicyV2 returns all CiliumEgressNATPolicies from the
+// possible missing policies which have not been applied
+func (d *Daemon) missingCiliumEgressNATPolicyV2(m versioned.Map) versioned.Map {
+	missing := versioned.NewMap()
+	for k, v := range m {
+		p := v.Data.(*cilium_v2.CiliumEgressNATPolicy)
+		if !egressnat.HasPolicy(egressnat.PolicyName(p)) {
 			missing.Add(k, v)
 		}
 	}
@@ -0,0 +1,113 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build privileged_tests
+
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/service"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type DaemonPrivilegedSuite struct {
+	d *Daemon
+}
+
+var _ = Suite(&DaemonPrivilegedSuite{})
+
+func (ds *DaemonPrivilegedSuite) SetUpSuite(c *C) {
+	_, err := lbmap.Service4Map.OpenOrCreate()
+	c.Assert(err, IsNil)
+	_, err = lbmap.RevNat4Map.OpenOrCreate()
+	c.Assert(err, IsNil)
+	_, err = lbmap.RRSeq4Map.OpenOrCreate()
+	c.Assert(err, IsNil)
+}
+
+func (ds *DaemonPrivilegedSuite) SetUpTest(c *C) {
+	ds.d = &Daemon{loadBalancer: loadbalancer.NewLoadBalancer()}
+}
+
+// numBPFBackends returns the number of backends installed in the BPF
+// service map for the given frontend
+func numBPFBackends(c *C, fe *loadbalancer.L3n4Addr) int {
+	svcMap, _, errors := lbmap.DumpServiceMapsToUserspace(false, false)
+	c.Assert(errors, HasLen, 0)
+
+	svc, ok := svcMap[fe.SHA256Sum()]
+	if !ok {
+		return 0
+	}
+	return len(svc.BES)
+}
+
+func (ds *DaemonPrivilegedSuite) TestGlobalServiceRemoteClusterDisconnect(c *C) {
+	svcName := loadbalancer.K8sServiceNamespace{Namespace: "default", ServiceName: "foo"}
+	feIP := net.ParseIP("192.0.2.10")
+	fe := loadbalancer.NewL3n4Addr(loadbalancer.TCP, feIP, 80)
+
+	svcInfo := loadbalancer.NewK8sServiceInfo(feIP, false, nil, nil)
+	svcInfo.IsGlobal = true
+	svcInfo.Ports["http"] = loadbalancer.NewFEPort(loadbalancer.TCP, 80)
+
+	ds.d.loadBalancer.K8sMU.Lock()
+	ds.d.loadBalancer.K8sServices[svcName] = svcInfo
+	err := ds.d.syncLB(&svcName, nil, nil)
+	ds.d.loadBalancer.K8sMU.Unlock()
+	c.Assert(err, IsNil)
+	defer func() {
+		ds.d.loadBalancer.K8sMU.Lock()
+		ds.d.syncLB(nil, nil, &svcName)
+		ds.d.loadBalancer.K8sMU.Unlock()
+	}()
+
+	remote := &service.ClusterService{
+		Cluster:   "cluster2",
+		Namespace: svcName.Namespace,
+		Name:      svcName.ServiceName,
+		Backends:  map[string]bool{"192.0.2.20": true, "192.0.2.21": true},
+		Ports: map[loadbalancer.FEPortName]*loadbalancer.L4Addr{
+			"http": loadbalancer.NewL4Addr(loadbalancer.TCP, 8080),
+		},
+	}
+
+	// Connecting the remote cluster installs its backends
+	ds.d.MergeExternalServiceUpdate(remote)
+	c.Assert(numBPFBackends(c, fe), Equals, 2)
+
+	// Disconnecting the remote cluster must remove them again
+	ds.d.MergeExternalServiceDelete(remote)
+	c.Assert(numBPFBackends(c, fe), Equals, 0)
+
+	// Deleting the k8s service with remote backends only removes the
+	// frontend
+	ds.d.MergeExternalServiceUpdate(remote)
+	c.Assert(numBPFBackends(c, fe), Equals, 2)
+
+	ds.d.loadBalancer.K8sMU.Lock()
+	err = ds.d.syncLB(nil, nil, &svcName)
+	ds.d.loadBalancer.K8sMU.Unlock()
+	c.Assert(err, IsNil)
+	c.Assert(numBPFBackends(c, fe), Equals, 0)
+}
@@ -945,197 +945,258 @@ func (ds *DaemonSuite) Test_missingK8sPodV1(c *C) {
 	}
 }
 
-func (ds *DaemonSuite) Test_missingK8sNodeV1(c *C) {
+func (ds *DaemonSuite) Test_missingCiliumNodeV2(c *C) {
 	defer node.DeleteAllNodes()
 	prevClusterName := option.Config.ClusterName
 	option.Config.ClusterName = "default"
 	defer func() {
 		option.Config.ClusterName = prevClusterName
 	}()
-	type args struct {
-		m     versioned.Map
-		cache *ipcache.IPCache
+
+	newCiliumNode := func(name, ip string) *v2.CiliumNode {
+		return &v2.CiliumNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v2.NodeSpec{
+				Addresses: []v2.NodeAddress{
+					{
+						Type: string(core_v1.NodeInternalIP),
+						IP:   ip,
+					},
+				},
+			},
+		}
 	}
+	newNode := func(name, ip string, source node.Source) *node.Node {
+		return &node.Node{
+			Name:    name,
+			Cluster: "default",
+			IPAddresses: []node.Address{
+				{
+					AddressType: core_v1.NodeInternalIP,
+					IP:          net.ParseIP(ip),
+				},
+			},
+			Source: source,
+		}
+	}
+
 	tests := []struct {
 		name        string
-		setupArgs   func() args
+		setupArgs   func() versioned.Map
 		setupWanted func() versioned.Map
 	}{
 		{
 			name: "both equal",
-			setupArgs: func() args {
-				return args{
-					cache: ipcache.NewIPCache(),
-					m:     versioned.NewMap(),
-				}
+			setupArgs: func() versioned.Map {
+				return versioned.NewMap()
 			},
 			setupWanted: func() versioned.Map {
 				return versioned.NewMap()
 			},
 		},
 		{
-			name: "ipcache is missing a node",
-			setupArgs: func() args {
+			name: "node package is missing a node",
+			setupArgs: func() versioned.Map {
+				node.DeleteAllNodes()
 				m := versioned.NewMap()
 				m.Add("", versioned.Object{
-					Data: &core_v1.Node{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "foo",
-							Annotations: map[string]string{
-								annotation.CiliumHostIP: "127.0.0.1",
-							},
-						},
-					},
+					Data: newCiliumNode("foo", "172.20.0.1"),
 				})
-
-				return args{
-					m:     m,
-					cache: ipcache.NewIPCache(),
-				}
+				return m
 			},
 			setupWanted: func() versioned.Map {
 				m := versioned.NewMap()
 				m.Add("", versioned.Object{
-					Data: &core_v1.Node{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "foo",
-							Annotations: map[string]string{
-								annotation.CiliumHostIP: "127.0.0.1",
-							},
-						},
-					},
+					Data: newCiliumNode("foo", "172.20.0.1"),
 				})
 				return m
 			},
 		},
 		{
-			name: "ipcache and the node package contains the node. Should be no-op",
-			setupArgs: func() args {
+			name: "node package contains the node. Should be no-op",
+			setupArgs: func() versioned.Map {
 				node.DeleteAllNodes()
-				cache := ipcache.NewIPCache()
-				cache.Upsert("172.20.0.1", net.ParseIP("172.20.0.2"), ipcache.Identity{
-					ID:     identity.ReservedIdentityInit,
-					Source: ipcache.FromKubernetes,
-				})
-				node.UpdateNode(&node.Node{
-					Name:    "foo",
-					Cluster: "default",
-					IPAddresses: []node.Address{
-						{
-							AddressType: core_v1.NodeAddressType(core_v1.NodeInternalIP),
-							IP:          net.ParseIP("172.20.0.1"),
-						},
-					},
-				}, 0, net.ParseIP("172.20.0.2"))
-
+				node.UpdateNode(newNode("foo", "172.20.0.1", node.FromKubernetes), 0, nil)
 				m := versioned.NewMap()
 				m.Add("", versioned.Object{
-					Data: &core_v1.Node{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "foo",
-							Annotations: map[string]string{
-								annotation.CiliumHostIP: "172.20.0.1",
-							},
-						},
-					},
+					Data: newCiliumNode("foo", "172.20.0.1"),
 				})
-
-				return args{
-					m:     m,
-					cache: cache,
-				}
+				return m
 			},
 			setupWanted: func() versioned.Map {
 				return versioned.NewMap()
 			},
 		},
 		{
-			name: "node doesn't contain any cilium host IP. should be no-op",
-			setupArgs: func() args {
+			name: "node package contains an outdated version of the node",
+			setupArgs: func() versioned.Map {
 				node.DeleteAllNodes()
-				cache := ipcache.NewIPCache()
-				cache.Upsert("127.0.0.1", net.ParseIP("127.0.0.2"), ipcache.Identity{
-					ID:     identity.ReservedIdentityInit,
-					Source: ipcache.FromKubernetes,
+				node.UpdateNode(newNode("bar", "172.20.0.1", node.FromKubernetes), 0, nil)
+				m := versioned.NewMap()
+				m.Add("", versioned.Object{
+					Data: newCiliumNode("bar", "172.20.9.1"),
 				})
+				return m
+			},
+			setupWanted: func() versioned.Map {
 				m := versioned.NewMap()
 				m.Add("", versioned.Object{
-					Data: &core_v1.Node{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "foo",
-							Labels: map[string]string{
-								"foo": "bar",
-							},
-						},
-					},
+					Data: newCiliumNode("bar", "172.20.9.1"),
 				})
-
-				return args{
-					m:     m,
-					cache: cache,
-				}
+				return m
+			},
+		},
+		{
+			name: "node was announced via the kvstore. Should be no-op",
+			setupArgs: func() versioned.Map {
+				node.DeleteAllNodes()
+				node.UpdateNode(newNode("bar", "172.20.0.1", node.FromKVStore), 0, nil)
+				m := versioned.NewMap()
+				m.Add("", versioned.Object{
+					Data: newCiliumNode("bar", "172.20.9.1"),
+				})
+				return m
 			},
 			setupWanted: func() versioned.Map {
 				return versioned.NewMap()
 			},
 		},
+	}
+	for _, tt := range tests {
+		args := tt.setupArgs()
+		want := tt.setupWanted()
+		got := ds.d.missingCiliumNodeV2(args)
+		c.Assert(got, DeepEquals, want, Commentf("Test name: %q", tt.name))
+	}
+}
+
+func (ds *DaemonSuite) Test_missingK8sNodeV1(c *C) {
+	defer node.DeleteAllNodes()
+	prevClusterName := option.Config.ClusterName
+	option.Config.ClusterName = "default"
+	prevCiliumNodeStore := ciliumNodeStore
+	ciliumNodeStore = cache.NewStore(cache.MetaNamespaceKeyFunc)
+	defer func() {
+		option.Config.ClusterName = prevClusterName
+		ciliumNodeStore = prevCiliumNodeStore
+	}()
+
+	newK8sNode := func(name, ip, ciliumHostIP string) *core_v1.Node {
+		k8sNode := &core_v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{},
+			},
+			Status: core_v1.NodeStatus{
+				Addresses: []core_v1.NodeAddress{
+					{
+						Type:    core_v1.NodeInternalIP,
+						Address: ip,
+					},
+				},
+			},
+		}
+		if ciliumHostIP != "" {
+			k8sNode.Annotations[annotation.CiliumHostIP] = ciliumHostIP
+		}
+		return k8sNode
+	}
+	newNode := func(name, ip, ciliumHostIP string) *node.Node {
+		return &node.Node{
+			Name:    name,
+			Cluster: "default",
+			IPAddresses: []node.Address{
+				{
+					AddressType: core_v1.NodeInternalIP,
+					IP:          net.ParseIP(ip),
+				},
+				{
+					AddressType: node.NodeCiliumInternalIP,
+					IP:          net.ParseIP(ciliumHostIP),
+				},
+			},
+			Source: node.FromKubernetes,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		setupArgs   func() versioned.Map
+		setupWanted func() versioned.Map
+	}{
 		{
-			name: "ipcache contains the node but the CiliumHostIP is not the right one for the given nodeIP",
-			setupArgs: func() args {
+			name: "node package is missing an annotated node",
+			setupArgs: func() versioned.Map {
 				node.DeleteAllNodes()
-				cache := ipcache.NewIPCache()
-				cache.Upsert("172.20.9.1", net.ParseIP("172.20.1.1"), ipcache.Identity{
-					ID:     identity.ReservedIdentityInit,
-					Source: ipcache.FromKubernetes,
+				m := versioned.NewMap()
+				m.Add("", versioned.Object{
+					Data: newK8sNode("foo", "192.168.0.1", "172.20.0.1"),
 				})
-				node.UpdateNode(&node.Node{
-					Name:    "bar",
-					Cluster: "default",
-					IPAddresses: []node.Address{
-						{
-							AddressType: core_v1.NodeAddressType(core_v1.NodeInternalIP),
-							IP:          net.ParseIP("172.20.0.1"),
-						},
-					},
-				}, 0, net.ParseIP("172.20.2.1"))
+				return m
+			},
+			setupWanted: func() versioned.Map {
 				m := versioned.NewMap()
 				m.Add("", versioned.Object{
-					Data: &core_v1.Node{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "bar",
-							Annotations: map[string]string{
-								annotation.CiliumHostIP: "172.20.9.1",
-							},
-						},
-					},
+					Data: newK8sNode("foo", "192.168.0.1", "172.20.0.1"),
 				})
-
-				return args{
-					m:     m,
-					cache: cache,
-				}
+				return m
+			},
+		},
+		{
+			name: "node package contains the annotated node. Should be no-op",
+			setupArgs: func() versioned.Map {
+				node.DeleteAllNodes()
+				node.UpdateNode(newNode("foo", "192.168.0.1", "172.20.0.1"), 0, nil)
+				m := versioned.NewMap()
+				m.Add("", versioned.Object{
+					Data: newK8sNode("foo", "192.168.0.1", "172.20.0.1"),
+				})
+				return m
 			},
 			setupWanted: func() versioned.Map {
+				return versioned.NewMap()
+			},
+		},
+		{
+			name: "node not annotated by an agent. Should be no-op",
+			setupArgs: func() versioned.Map {
+				node.DeleteAllNodes()
 				m := versioned.NewMap()
 				m.Add("", versioned.Object{
-					Data: &core_v1.Node{
-						ObjectMeta: metav1.ObjectMeta{
-							Name: "bar",
-							Annotations: map[string]string{
-								annotation.CiliumHostIP: "172.20.9.1",
-							},
-						},
+					Data: newK8sNode("foo", "192.168.0.1", ""),
+				})
+				return m
+			},
+			setupWanted: func() versioned.Map {
+				return versioned.NewMap()
+			},
+		},
+		{
+			name: "node registered as CiliumNode. Should be no-op",
+			setupArgs: func() versioned.Map {
+				node.DeleteAllNodes()
+				ciliumNodeStore.Add(&v2.CiliumNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "bar",
 					},
 				})
+				m := versioned.NewMap()
+				m.Add("", versioned.Object{
+					Data: newK8sNode("bar", "192.168.0.2", "172.20.9.1"),
+				})
 				return m
 			},
+			setupWanted: func() versioned.Map {
+				return versioned.NewMap()
+			},
 		},
 	}
 	for _, tt := range tests {
 		args := tt.setupArgs()
 		want := tt.setupWanted()
-		ipcache.IPIdentityCache = args.cache
-		got := ds.d.missingK8sNodeV1(args.m)
+		got := ds.d.missingK8sNodeV1(args)
 		c.Assert(got, DeepEquals, want, Commentf("Test name: %q", tt.name))
 	}
 }
@@ -1573,7 +1634,7 @@ func (ds *DaemonSuite) Test_missingK8sIngressV1Beta1(c *C) {
 }
 
 func (ds *DaemonSuite) Test_addCiliumNetworkPolicyV2(c *C) {
-	// ciliumV2Store cache.Store, oldRules api.Rules, cnp *cilium_v2.CiliumNetworkPolicy
+	// ciliumV2Store cache.Store, oldRules api.Rules, cnp *v2.CiliumNetworkPolicy
 
 	uuid := types.UID("11bba160-ddca-11e8-b697-0800273b04ff")
 	type args struct {
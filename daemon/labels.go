@@ -0,0 +1,150 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/api"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// labelFilterFile is the name of the file in the state directory holding the
+// label prefixes configured at runtime
+const labelFilterFile = "label-filter.json"
+
+func labelFilterPath() string {
+	return filepath.Join(option.Config.StateDir, labelFilterFile)
+}
+
+// saveLabelFilter persists the label prefixes configured at runtime so that
+// they are restored after a restart of the agent
+func saveLabelFilter(spec *models.LabelFilterSpec) error {
+	data, err := spec.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	path := labelFilterPath()
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// restoreLabelFilter installs the label prefixes configured at runtime before
+// the agent was restarted. They replace the prefixes given with --labels.
+func restoreLabelFilter() error {
+	path := labelFilterPath()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	spec := &models.LabelFilterSpec{}
+	if err := spec.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	filter, err := labels.NewLabelFilter(spec.Prefixes)
+	if err != nil {
+		return err
+	}
+
+	log.WithField(logfields.Path, path).Info("Restoring label prefixes configured at runtime")
+	labels.SetLabelFilter(filter)
+	return nil
+}
+
+type getConfigLabels struct {
+	d *Daemon
+}
+
+// NewGetConfigLabelsHandler returns new get handler for api
+func NewGetConfigLabelsHandler(d *Daemon) GetConfigLabelsHandler {
+	return &getConfigLabels{d: d}
+}
+
+func (h *getConfigLabels) Handle(params GetConfigLabelsParams) middleware.Responder {
+	filter := labels.GetLabelFilter()
+	spec := &models.LabelFilterSpec{
+		Prefixes: filter.CustomPrefixes(),
+	}
+	lf := &models.LabelFilter{
+		Spec: spec,
+		Status: &models.LabelFilterStatus{
+			Realized:          spec,
+			EffectivePrefixes: filter.Prefixes(),
+		},
+	}
+	return NewGetConfigLabelsOK().WithPayload(lf)
+}
+
+type putConfigLabels struct {
+	d *Daemon
+
+	// mutex serializes label filter changes
+	mutex lock.Mutex
+}
+
+// NewPutConfigLabelsHandler returns new put handler for api
+func NewPutConfigLabelsHandler(d *Daemon) PutConfigLabelsHandler {
+	return &putConfigLabels{d: d}
+}
+
+func (h *putConfigLabels) Handle(params PutConfigLabelsParams) middleware.Responder {
+	filter, err := labels.NewLabelFilter(params.LabelFilterSpec.Prefixes)
+	if err != nil {
+		return api.Error(PutConfigLabelsInvalidPrefixCode, err)
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var impact endpointmanager.LabelFilterImpact
+	if dryRun {
+		impact = endpointmanager.EvaluateLabelFilter(filter)
+	} else {
+		if err := saveLabelFilter(params.LabelFilterSpec); err != nil {
+			log.WithError(err).Warning("Unable to persist label prefixes, they are reset on restart")
+		}
+		impact = endpointmanager.ApplyLabelFilter(h.d, filter)
+	}
+
+	return NewPutConfigLabelsOK().WithPayload(&models.LabelFilterImpact{
+		DryRun:            dryRun,
+		IdentitiesBefore:  int64(impact.IdentitiesBefore),
+		IdentitiesAfter:   int64(impact.IdentitiesAfter),
+		IdentitiesMerged:  int64(impact.IdentitiesMerged),
+		IdentitiesSplit:   int64(impact.IdentitiesSplit),
+		EndpointsAffected: int64(impact.EndpointsAffected),
+	})
+}
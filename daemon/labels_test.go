@@ -0,0 +1,38 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package main
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestLabelFilterPersistence(c *C) {
+	prevFilter := labels.GetLabelFilter()
+	defer labels.SetLabelFilter(prevFilter)
+
+	// Nothing to restore if the prefixes were never changed at runtime
+	c.Assert(restoreLabelFilter(), IsNil)
+
+	err := saveLabelFilter(&models.LabelFilterSpec{Prefixes: []string{"k8s:app"}})
+	c.Assert(err, IsNil)
+
+	c.Assert(restoreLabelFilter(), IsNil)
+	c.Assert(labels.GetLabelFilter().CustomPrefixes(), DeepEquals, []string{"k8s:app"})
+}
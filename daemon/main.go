@@ -23,6 +23,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/components"
 	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/datapath/routing"
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/envoy"
@@ -55,6 +57,7 @@ import (
 	"github.com/cilium/cilium/pkg/pidfile"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/pprof"
+	"github.com/cilium/cilium/pkg/proxy/logger/sink"
 	"github.com/cilium/cilium/pkg/service"
 	"github.com/cilium/cilium/pkg/sockops"
 	"github.com/cilium/cilium/pkg/version"
@@ -348,6 +351,10 @@ func init() {
 	flags.StringVar(&option.Config.AccessLog,
 		"access-log", "", "Path to access log of supported L7 requests observed")
 	viper.BindEnv("access-log", "CILIUM_ACCESS_LOG")
+	flags.StringSliceVar(&option.Config.AccessLogSinks, option.AccessLogSinkName, []string{},
+		"URLs of sinks to export L7 access log records to (syslog://, syslog+tcp://, fluentd://, kafka://, http(s)://)")
+	flags.IntVar(&option.Config.AccessLogSinkBufferSize, option.AccessLogSinkBufferSizeName, sink.DefaultBufferSize,
+		"Number of L7 access log records buffered per sink before records are dropped")
 	flags.StringSliceVar(&option.Config.AgentLabels,
 		"agent-labels", []string{}, "Additional labels to identify this agent")
 	viper.BindEnv("access-labels", "CILIUM_ACCESS_LABELS")
@@ -367,6 +374,22 @@ func init() {
 	viper.BindEnv(option.ClusterName, option.ClusterNameEnv)
 	flags.String(option.ClusterMeshConfigName, "", "Path to the ClusterMesh configuration directory")
 	viper.BindEnv(option.ClusterMeshConfigName, option.ClusterMeshConfigNameEnv)
+	flags.String(option.ClusterMeshConfigSecretName, "", "Kubernetes secret (namespace/name) holding the ClusterMesh configuration, takes precedence over the configuration directory")
+	viper.BindEnv(option.ClusterMeshConfigSecretName, option.ClusterMeshConfigSecretNameEnv)
+	flags.Bool(option.EnableIPSecName, false, "Enable IPsec encryption of the traffic between nodes")
+	viper.BindEnv(option.EnableIPSecName, option.EnableIPSecNameEnv)
+	flags.String(option.IPSecKeyFileName, "", "Path to the file holding the IPsec key")
+	viper.BindEnv(option.IPSecKeyFileName, option.IPSecKeyFileNameEnv)
+	flags.String(option.IPSecKeySecretName, "", "Kubernetes secret (namespace/name) holding the IPsec key, takes precedence over the key file")
+	viper.BindEnv(option.IPSecKeySecretName, option.IPSecKeySecretNameEnv)
+	flags.Bool(option.AutoDirectNodeRoutesName, false, "Install routes to the allocation prefixes of remote nodes sharing an L2 segment, requires tunneling to be disabled")
+	viper.BindEnv(option.AutoDirectNodeRoutesName, option.AutoDirectNodeRoutesNameEnv)
+	flags.String(option.RouteExporterName, "", fmt.Sprintf("Exporter announcing the allocation prefixes of the local node to a routing daemon { %s }, requires tunneling to be disabled", strings.Join(routing.GetExporters(), " | ")))
+	viper.BindEnv(option.RouteExporterName, option.RouteExporterNameEnv)
+	flags.Int(option.RouteExportTableName, defaults.RouteExportTable, "Kernel routing table used by the kernel-table route exporter")
+	viper.BindEnv(option.RouteExportTableName, option.RouteExportTableNameEnv)
+	flags.Bool(option.EnableEgressNATName, false, "Steer traffic selected by CiliumEgressNATPolicy resources to egress NAT gateway nodes, requires tunneling to be enabled")
+	viper.BindEnv(option.EnableEgressNATName, option.EnableEgressNATNameEnv)
 	flags.StringVar(&cfgFile,
 		"config", "", `Configuration file (default "$HOME/ciliumd.yaml")`)
 	flags.Uint("conntrack-garbage-collector-interval", 60, "Garbage collection interval for the connection tracking table (in seconds)")
@@ -390,6 +413,7 @@ func init() {
 	flags.StringVarP(&dockerEndpoint,
 		"docker", "e", workloads.GetRuntimeDefaultOpt(workloads.Docker, "endpoint"), "Path to docker runtime socket (DEPRECATED: use container-runtime-endpoint instead)")
 	flags.String("enable-policy", option.DefaultEnforcement, "Enable policy enforcement")
+	flags.Bool(option.PolicyAuditModeArg, false, "Enable policy audit (non-drop) mode")
 	flags.BoolVar(&enableTracing,
 		"enable-tracing", false, "Enable tracing while determining policy (debugging)")
 	flags.String("envoy-log", "", "Path to a separate Envoy log file, if any")
@@ -401,6 +425,8 @@ func init() {
 	viper.BindEnv("disable-envoy-version-check", "CILIUM_DISABLE_ENVOY_BUILD")
 	flags.Var(option.NewNamedMapOptions("fixed-identity-mapping", &fixedIdentity, fixedIdentityValidator),
 		"fixed-identity-mapping", "Key-value for the fixed identity mapping which allows to use reserved label for fixed identities")
+	flags.String(option.IdentityAllocationModeName, option.IdentityAllocationModeKVstore, "Method to use for identity allocation { kvstore | crd }")
+	viper.BindEnv(option.IdentityAllocationModeName, option.IdentityAllocationModeNameEnv)
 	flags.IntVar(&v4ClusterCidrMaskSize,
 		"ipv4-cluster-cidr-mask-size", 8, "Mask size for the cluster wide CIDR")
 	flags.StringVar(&v4Prefix,
@@ -460,6 +486,18 @@ func init() {
 	flags.String(option.MonitorAggregationName, "None",
 		"Level of monitor aggregation for traces from the datapath")
 	viper.BindEnv(option.MonitorAggregationName, "CILIUM_MONITOR_AGGREGATION_LEVEL")
+	flags.IntVar(&option.Config.MonitorHistorySize, option.MonitorHistorySizeName, defaults.MonitorHistorySize,
+		"Number of flow events recorded by the node monitor for later retrieval (0 to disable)")
+	flags.StringVar(&option.Config.MonitorRemoteListenAddress, option.MonitorRemoteListenAddressName, "",
+		"TCP address the node monitor serves events over HTTPS to remote listeners on, e.g. \":4244\" (disabled if empty)")
+	flags.StringVar(&option.Config.MonitorRemoteTLSCert, option.MonitorRemoteTLSCertName, "",
+		"Path to the TLS certificate served to remote monitor listeners")
+	flags.StringVar(&option.Config.MonitorRemoteTLSKey, option.MonitorRemoteTLSKeyName, "",
+		"Path to the TLS key served to remote monitor listeners")
+	flags.StringVar(&option.Config.MonitorRemoteTLSClientCA, option.MonitorRemoteTLSClientCAName, "",
+		"Path to the CA bundle to verify client certificates of remote monitor listeners with")
+	flags.BoolVar(&option.Config.MonitorRemoteInsecure, option.MonitorRemoteInsecureName, false,
+		"Serve events to remote monitor listeners over plain HTTP without TLS")
 	flags.IntVar(&option.Config.MTU,
 		option.MTUName, mtu.AutoDetect(), "Overwrite auto-detected MTU of underlying network")
 	flags.Bool(option.PrependIptablesChainsName, true, "Prepend custom iptables chains instead of appending")
@@ -516,6 +554,9 @@ func init() {
 	flags.IntVar(&toFQDNsMinTTL,
 		"tofqdns-min-ttl", defaults.ToFQDNsMinTTL, "The minimum time, in seconds, to use DNS data for toFQDNs policies.")
 
+	flags.String(option.ToGroupsGenericSourceName, "",
+		"Path or http(s):// URL of the JSON document mapping group names to member IPs for the generic toGroups provider")
+
 	viper.BindPFlags(flags)
 }
 
@@ -693,6 +734,19 @@ func initEnv(cmd *cobra.Command) {
 			option.ModePreFilterNative, option.ModePreFilterGeneric)
 	}
 
+	switch viper.GetString(option.IdentityAllocationModeName) {
+	case option.IdentityAllocationModeKVstore, option.IdentityAllocationModeCRD:
+	default:
+		log.Fatalf("Invalid setting for --%s, must be { %s, %s }", option.IdentityAllocationModeName,
+			option.IdentityAllocationModeKVstore, option.IdentityAllocationModeCRD)
+	}
+
+	if option.Config.MonitorRemoteListenAddress != "" && !option.Config.MonitorRemoteInsecure &&
+		(option.Config.MonitorRemoteTLSCert == "" || option.Config.MonitorRemoteTLSKey == "") {
+		log.Fatalf("--%s requires --%s and --%s unless --%s is set", option.MonitorRemoteListenAddressName,
+			option.MonitorRemoteTLSCertName, option.MonitorRemoteTLSKeyName, option.MonitorRemoteInsecureName)
+	}
+
 	scopedLog = log.WithField(logfields.Path, socketPath)
 	socketDir := path.Dir(socketPath)
 	if err := os.MkdirAll(socketDir, defaults.RuntimePathRights); err != nil {
@@ -715,10 +769,12 @@ func initEnv(cmd *cobra.Command) {
 
 	option.Config.Opts.SetBool(option.DropNotify, true)
 	option.Config.Opts.SetBool(option.TraceNotify, true)
+	option.Config.Opts.SetBool(option.PolicyVerdictNotify, true)
 	option.Config.Opts.SetBool(option.PolicyTracing, enableTracing)
 	option.Config.Opts.SetBool(option.Conntrack, !disableConntrack)
 	option.Config.Opts.SetBool(option.ConntrackAccounting, !disableConntrack)
 	option.Config.Opts.SetBool(option.ConntrackLocal, false)
+	option.Config.Opts.SetBool(option.PolicyAuditMode, viper.GetBool(option.PolicyAuditModeArg))
 
 	monitorAggregationLevel, err := option.ParseMonitorAggregationLevel(viper.GetString(option.MonitorAggregationName))
 	if err != nil {
@@ -745,6 +801,9 @@ func initEnv(cmd *cobra.Command) {
 	if err := labels.ParseLabelPrefixCfg(validLabels, labelPrefixFile); err != nil {
 		log.WithError(err).Fatal("Unable to parse Label prefix configuration")
 	}
+	if err := restoreLabelFilter(); err != nil {
+		log.WithError(err).Warning("Unable to restore label prefixes configured at runtime")
+	}
 
 	_, r, err := net.ParseCIDR(nat46prefix)
 	if err != nil {
@@ -849,6 +908,29 @@ func runCiliumHealthEndpoint(d *Daemon) error {
 	return nil
 }
 
+// nodeMonitorArgs returns the arguments the node monitor is launched with in
+// addition to the pipe and the BPF root
+func nodeMonitorArgs() []string {
+	args := []string{
+		"--history-size", strconv.Itoa(option.Config.MonitorHistorySize),
+		"--node-name", node.GetName(),
+	}
+
+	if option.Config.MonitorRemoteListenAddress != "" {
+		args = append(args, "--remote-listen-address", option.Config.MonitorRemoteListenAddress)
+		if option.Config.MonitorRemoteInsecure {
+			args = append(args, "--remote-insecure")
+		} else {
+			args = append(args,
+				"--remote-tls-cert", option.Config.MonitorRemoteTLSCert,
+				"--remote-tls-key", option.Config.MonitorRemoteTLSKey,
+				"--remote-tls-client-ca", option.Config.MonitorRemoteTLSClientCA)
+		}
+	}
+
+	return args
+}
+
 func runDaemon() {
 	log.Info("Initializing daemon")
 	d, restoredEndpoints, err := NewDaemon()
@@ -868,7 +950,7 @@ func runDaemon() {
 	}
 
 	log.Info("Launching node monitor daemon")
-	go d.nodeMonitor.Run(path.Join(defaults.RuntimePath, defaults.EventsPipe), bpf.GetMapRoot())
+	go d.nodeMonitor.Run(path.Join(defaults.RuntimePath, defaults.EventsPipe), bpf.GetMapRoot(), nodeMonitorArgs())
 
 	if err := d.EnableK8sWatcher(5 * time.Minute); err != nil {
 		log.WithError(err).Fatal("Unable to establish connection to Kubernetes apiserver")
@@ -957,6 +1039,9 @@ func runDaemon() {
 	log.Debugf("IPv4 health endpoint address: %s", node.GetIPv4HealthIP())
 	log.Debugf("IPv6 health endpoint address: %s", node.GetIPv6HealthIP())
 	node.NotifyLocalNodeUpdated()
+	if k8s.IsEnabled() {
+		d.syncCiliumNode()
+	}
 
 	// Launch cilium-health in the same namespace as cilium.
 	log.Info("Launching Cilium health daemon")
@@ -1012,6 +1097,10 @@ func runDaemon() {
 	api.DaemonGetConfigHandler = NewGetConfigHandler(d)
 	api.DaemonPatchConfigHandler = NewPatchConfigHandler(d)
 
+	// /config/labels/
+	api.DaemonGetConfigLabelsHandler = NewGetConfigLabelsHandler(d)
+	api.DaemonPutConfigLabelsHandler = NewPutConfigLabelsHandler(d)
+
 	// /endpoint/
 	api.EndpointGetEndpointHandler = NewGetEndpointHandler(d)
 
@@ -1038,6 +1127,8 @@ func runDaemon() {
 	// /identity/
 	api.PolicyGetIdentityHandler = newGetIdentityHandler(d)
 	api.PolicyGetIdentityIDHandler = newGetIdentityIDHandler(d)
+	api.PolicyGetIdentityIDUsageHandler = newGetIdentityIDUsageHandler(d)
+	api.PolicyGetIdentityStaleHandler = newGetIdentityStaleHandler(d)
 
 	// /policy/
 	api.PolicyGetPolicyHandler = newGetPolicyHandler(d)
@@ -180,6 +180,7 @@ func (d *Daemon) PolicyAdd(rules policyAPI.Rules, opts *AddOptions) (uint64, err
 	// These must be marked before actually adding them to the repository since a
 	// copy may be made and we won't be able to add the ToFQDN tracking labels
 	d.dnsPoller.MarkToFQDNRules(rules)
+	d.groupsPoller.MarkToGroupsRules(rules)
 
 	prefixes := policy.GetCIDRPrefixes(rules)
 	log.WithField("prefixes", prefixes).Debug("Policy imported via API, found CIDR prefixes...")
@@ -223,6 +224,7 @@ func (d *Daemon) PolicyAdd(rules policyAPI.Rules, opts *AddOptions) (uint64, err
 				removedPrefixes = append(removedPrefixes, policy.GetCIDRPrefixes(oldRules)...)
 				if len(oldRules) > 0 {
 					d.dnsPoller.StopPollForDNSName(oldRules)
+					d.groupsPoller.StopPollForGroups(oldRules)
 					d.policy.DeleteByLabelsLocked(r.Labels)
 				}
 			}
@@ -232,6 +234,7 @@ func (d *Daemon) PolicyAdd(rules policyAPI.Rules, opts *AddOptions) (uint64, err
 			removedPrefixes = append(removedPrefixes, policy.GetCIDRPrefixes(oldRules)...)
 			if len(oldRules) > 0 {
 				d.dnsPoller.StopPollForDNSName(oldRules)
+				d.groupsPoller.StopPollForGroups(oldRules)
 				d.policy.DeleteByLabelsLocked(opts.ReplaceWithLabels)
 			}
 		}
@@ -254,8 +257,10 @@ func (d *Daemon) PolicyAdd(rules policyAPI.Rules, opts *AddOptions) (uint64, err
 		}
 	}
 
-	// The rules are added, we can begin ToFQDN DNS polling for them
+	// The rules are added, we can begin ToFQDN DNS polling and ToGroups
+	// polling for them
 	d.dnsPoller.StartPollForDNSName(rules)
+	d.groupsPoller.StartPollForGroups(rules)
 
 	log.WithField(logfields.PolicyRevision, rev).Info("Policy imported via API, recalculating...")
 
@@ -319,8 +324,9 @@ func (d *Daemon) PolicyDelete(labels labels.LabelArray) (uint64, error) {
 		}
 	}
 
-	// Stop polling for ToFQDN DNS names for these rules
+	// Stop polling for ToFQDN DNS names and ToGroups groups for these rules
 	d.dnsPoller.StopPollForDNSName(rules)
+	d.groupsPoller.StopPollForGroups(rules)
 
 	d.TriggerPolicyUpdates(false, "policy rules deleted")
 
@@ -21,6 +21,7 @@ import (
 	"github.com/cilium/cilium/api/v1/models"
 	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
 	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/datapath/routing"
 	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/kvstore"
 	"github.com/cilium/cilium/pkg/node"
@@ -166,6 +167,13 @@ func (d *Daemon) getStatus() models.StatusResponse {
 		sr.Cluster.CiliumHealth = d.ciliumHealth.GetStatus()
 	}
 
+	if d.clustermesh != nil {
+		sr.ClusterMesh = d.clustermesh.Status()
+	}
+
+	sr.Encryption = d.getEncryptionStatus()
+	sr.Routing = routing.Status()
+
 	if d.l7Proxy != nil {
 		sr.Proxy = d.l7Proxy.GetStatusModel()
 	}
@@ -79,7 +79,7 @@ func (nm *NodeMonitor) GetPid() int {
 // returns with an error if the FIFO cannot be created, opened or if the an
 // error was encountered while reading stdout from the monitor. The FIFO is always
 // removed again when the function returns.
-func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
+func (nm *NodeMonitor) run(sockPath, bpfRoot string, args []string) error {
 	os.Remove(sockPath)
 	if err := syscall.Mkfifo(sockPath, 0600); err != nil {
 		return fmt.Errorf("Unable to create named pipe %s: %s", sockPath, err)
@@ -98,7 +98,7 @@ func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
 	nm.pipe = pipe
 	nm.pipeLock.Unlock()
 
-	nm.Launcher.SetArgs([]string{"--bpf-root", bpfRoot})
+	nm.Launcher.SetArgs(append([]string{"--bpf-root", bpfRoot}, args...))
 	if err := nm.Launcher.Run(); err != nil {
 		return err
 	}
@@ -122,14 +122,14 @@ func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
 	return fmt.Errorf("Monitor process quit unexepctedly")
 }
 
-// Run starts the node monitor and keeps on restarting it. The function will
-// never return.
-func (nm *NodeMonitor) Run(sockPath, bpfRoot string) {
+// Run starts the node monitor with the additional command line arguments args
+// and keeps on restarting it. The function will never return.
+func (nm *NodeMonitor) Run(sockPath, bpfRoot string, args []string) {
 	backoffConfig := backoff.Exponential{Min: time.Second, Max: 2 * time.Minute}
 
 	nm.SetTarget(targetName)
 	for {
-		if err := nm.run(sockPath, bpfRoot); err != nil {
+		if err := nm.run(sockPath, bpfRoot, args); err != nil {
 			log.WithError(err).Warning("Error while running monitor")
 		}
 
@@ -15,20 +15,30 @@
 package listener
 
 import (
+	"encoding/gob"
 	"net"
 	"os"
 	"syscall"
+	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/monitor"
 	"github.com/cilium/cilium/pkg/monitor/payload"
 )
 
 // Version is the version of a node-monitor listener client. There are
-// two API versions:
+// three API versions:
 // - 1.0 which encodes the gob type information with each payload sent, and
 //   adds a meta object before it.
 // - 1.2 which maintains a gob session per listener, thus only encoding the
 //   type information on the first payload sent. It does NOT prepend the a meta
 //   object.
+// - 1.3 which encodes payloads like 1.2, but clients must start the session
+//   with a Handshake. It is served on a separate socket so that 1.2 clients
+//   are not delayed by waiting for a Handshake they never send.
+// Remote listeners subscribe over HTTP by posting a models.MonitorEventRequest
+// to RemoteEventsPath and receive a stream of JSON encoded
+// models.MonitorEvent objects, see NewRemoteHandshake.
 type Version string
 
 const (
@@ -40,8 +50,82 @@ const (
 
 	// Version1_2 is the API 1.0 version of the protocol (see above).
 	Version1_2 = Version("1.2")
+
+	// Version1_3 is the API 1.3 version of the protocol (see above).
+	Version1_3 = Version("1.3")
+
+	// VersionRemote is the protocol of remote listeners (see above).
+	VersionRemote = Version("remote")
 )
 
+// HandshakeTimeout is the time the node monitor waits for the Handshake of a
+// 1.3 client before closing the connection.
+const HandshakeTimeout = 10 * time.Second
+
+// RemoteEventsPath is the HTTP path remote listeners subscribe to monitor
+// events at
+const RemoteEventsPath = "/v1/monitor/events"
+
+// Handshake is sent by 1.3 clients, gob encoded, right after connecting. It registers a filter which is evaluated by the node monitor
+// before events are queued for the client, and may request the flow events
+// recorded by the node monitor before the client connected.
+type Handshake struct {
+	Filter monitor.Filter
+
+	// Since requests the recorded events observed at or after this time
+	Since time.Time
+	// Last limits the recorded events sent to the most recent ones
+	Last int
+}
+
+// WantsHistory returns true if the Handshake requests recorded events
+func (h *Handshake) WantsHistory() bool {
+	return !h.Since.IsZero() || h.Last > 0
+}
+
+// IsEmpty returns true if the Handshake neither registers a filter nor
+// requests recorded events
+func (h *Handshake) IsEmpty() bool {
+	return h.Filter.IsEmpty() && !h.WantsHistory()
+}
+
+// SendHandshake sends a Handshake on a 1.3 connection. An empty Handshake
+// must be sent to receive all events.
+func SendHandshake(conn net.Conn, h *Handshake) error {
+	return gob.NewEncoder(conn).Encode(h)
+}
+
+// ReadHandshake reads the Handshake of a 1.3 client, which must be sent
+// within HandshakeTimeout. Clients must not send anything after the
+// Handshake.
+func ReadHandshake(conn net.Conn) (*Handshake, error) {
+	conn.SetReadDeadline(time.Now().Add(HandshakeTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	h := &Handshake{}
+	if err := gob.NewDecoder(conn).Decode(h); err != nil {
+		return nil, err
+	}
+	if err := h.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// NewRemoteHandshake returns the Handshake of the subscription request of a
+// remote listener. An empty request, e.g. `{}`, receives all events.
+func NewRemoteHandshake(req *models.MonitorEventRequest) (*Handshake, error) {
+	filter, err := monitor.NewFilterFromModel(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &Handshake{
+		Filter: *filter,
+		Since:  time.Time(req.Since),
+		Last:   int(req.Last),
+	}, nil
+}
+
 // MonitorListener is a generic consumer of monitor events. Implementers are
 // expected to handle errors as needed, including exiting.
 type MonitorListener interface {
@@ -16,25 +16,35 @@ package main
 
 import (
 	"encoding/gob"
+	"io"
 	"net"
+	"time"
 
 	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/monitor/ring"
 	"github.com/cilium/cilium/pkg/monitor/payload"
 )
 
 // listenerv1_2 implements the ciliim-node-monitor API protocol compatible with
-// cilium 1.2
+// cilium 1.2. It also implements the 1.3 protocol, which only differs in the
+// mandatory handshake.
 // cleanupFn is called on exit
 type listenerv1_2 struct {
+	listenerFilter
+
 	conn      net.Conn
-	queue     chan *payload.Payload
+	version   listener.Version
 	cleanupFn func(listener.MonitorListener)
 }
 
-func newListenerv1_2(c net.Conn, queueSize int, cleanupFn func(listener.MonitorListener)) *listenerv1_2 {
+func newListenerv1_2(c net.Conn, version listener.Version, queueSize int, cleanupFn func(listener.MonitorListener), historyFn historyFunc) *listenerv1_2 {
 	ml := &listenerv1_2{
+		listenerFilter: listenerFilter{
+			queue:     make(chan ring.Event, queueSize),
+			historyFn: historyFn,
+		},
 		conn:      c,
-		queue:     make(chan *payload.Payload, queueSize),
+		version:   version,
 		cleanupFn: cleanupFn,
 	}
 
@@ -43,14 +53,6 @@ func newListenerv1_2(c net.Conn, queueSize int, cleanupFn func(listener.MonitorL
 	return ml
 }
 
-func (ml *listenerv1_2) Enqueue(pl *payload.Payload) {
-	select {
-	case ml.queue <- pl:
-	default:
-		log.Debug("Per listener queue is full, dropping message")
-	}
-}
-
 // drainQueue encodes and sends monitor payloads to the listener. It is
 // intended to be a goroutine.
 func (ml *listenerv1_2) drainQueue() {
@@ -59,22 +61,45 @@ func (ml *listenerv1_2) drainQueue() {
 		ml.cleanupFn(ml)
 	}()
 
+	// Only 1.3 clients send a handshake, 1.2 clients receive all events
+	// right away.
+	var h *listener.Handshake
+	if ml.version == listener.Version1_3 {
+		var err error
+		h, err = listener.ReadHandshake(ml.conn)
+		switch {
+		case err == io.EOF:
+			log.Debug("Listener disconnected")
+			return
+
+		case err != nil:
+			log.WithError(err).Warn("Removing listener due to handshake failure")
+			return
+		}
+	}
+
 	enc := gob.NewEncoder(ml.conn)
-	for pl := range ml.queue {
-		if err := pl.EncodeBinary(enc); err != nil {
-			switch {
-			case listener.IsDisconnected(err):
-				log.Debug("Listener disconnected")
-				return
+	ml.drain(h, nil, func(_ time.Time, pl *payload.Payload) bool {
+		return ml.send(enc, pl)
+	})
+}
+
+// send encodes and sends a payload to the listener. It returns false if the
+// listener must be removed.
+func (ml *listenerv1_2) send(enc *gob.Encoder, pl *payload.Payload) bool {
+	if err := pl.EncodeBinary(enc); err != nil {
+		switch {
+		case listener.IsDisconnected(err):
+			log.Debug("Listener disconnected")
 
-			default:
-				log.WithError(err).Warn("Removing listener due to write failure")
-				return
-			}
+		default:
+			log.WithError(err).Warn("Removing listener due to write failure")
 		}
+		return false
 	}
+	return true
 }
 
 func (ml *listenerv1_2) Version() listener.Version {
-	return listener.Version1_2
+	return ml.version
 }
@@ -0,0 +1,127 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/monitor/ring"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/sirupsen/logrus"
+)
+
+// historyFunc calls register to register the handshake of a listener and
+// returns the recorded events requested by the handshake, or nil if the
+// handshake does not request any or no events are recorded.
+type historyFunc func(h *listener.Handshake, register func()) []ring.Event
+
+// listenerFilter implements the filtering and the history replay of the
+// listeners which accept a handshake. It is embedded in listener types.
+type listenerFilter struct {
+	// queue holds the payloads to send along with the time they were
+	// enqueued, i.e. observed by the node monitor
+	queue     chan ring.Event
+	historyFn historyFunc
+
+	// filterMutex protects filter
+	filterMutex lock.RWMutex
+	// filter is the filter registered in the handshake, nil if the
+	// listener did not register a filter (yet)
+	filter *monitor.Filter
+}
+
+// Enqueue queues the payload if it matches the filter of the listener.
+func (lf *listenerFilter) Enqueue(pl *payload.Payload) {
+	if !lf.match(pl) {
+		return
+	}
+
+	select {
+	case lf.queue <- ring.Event{Timestamp: time.Now(), Payload: pl}:
+	default:
+		log.Debug("Per listener queue is full, dropping message")
+	}
+}
+
+// match returns true if the payload matches the filter of the listener
+func (lf *listenerFilter) match(pl *payload.Payload) bool {
+	lf.filterMutex.RLock()
+	defer lf.filterMutex.RUnlock()
+	return lf.filter == nil || lf.filter.Match(pl)
+}
+
+// drain registers the handshake h, which may be nil, and calls send for the
+// requested recorded events and then for all queued payloads until send
+// returns false or done, which may be nil, is closed. Each payload is passed
+// along with the time it was observed.
+func (lf *listenerFilter) drain(h *listener.Handshake, done <-chan struct{}, send func(ts time.Time, pl *payload.Payload) bool) {
+	// Payloads queued before the handshake completed have not been
+	// filtered yet
+	var history []ring.Event
+	unfiltered := 0
+	if h != nil && !h.IsEmpty() {
+		history = lf.historyFn(h, func() {
+			lf.filterMutex.Lock()
+			if !h.Filter.IsEmpty() {
+				lf.filter = &h.Filter
+			}
+			unfiltered = len(lf.queue)
+			lf.filterMutex.Unlock()
+		})
+
+		log.WithFields(logrus.Fields{
+			"filter":         h.Filter,
+			"count.recorded": len(history),
+		}).Debug("Listener completed handshake")
+	}
+
+	for _, ev := range history {
+		if !send(ev.Timestamp, ev.Payload) {
+			return
+		}
+	}
+
+	for {
+		var ev ring.Event
+		select {
+		case <-done:
+			return
+		case e, ok := <-lf.queue:
+			if !ok {
+				return
+			}
+			ev = e
+		}
+
+		if unfiltered > 0 {
+			unfiltered--
+			// Recorded events queued before the handshake have
+			// been sent as part of the history already
+			if history != nil && ring.IsRecorded(ev.Payload) {
+				continue
+			}
+			if !lf.match(ev.Payload) {
+				continue
+			}
+		}
+
+		if !send(ev.Timestamp, ev.Payload) {
+			return
+		}
+	}
+}
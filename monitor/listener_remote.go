@@ -0,0 +1,152 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/monitor/ring"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// maxRemoteRequestSize limits the size of the subscription request of a
+// remote listener
+const maxRemoteRequestSize = 64 * 1024
+
+// listenerRemote streams monitor events to a remote listener subscribed over
+// HTTP, see listener.RemoteEventsPath. It is only registered for the
+// lifetime of the request.
+type listenerRemote struct {
+	listenerFilter
+}
+
+func newListenerRemote(queueSize int, historyFn historyFunc) *listenerRemote {
+	return &listenerRemote{
+		listenerFilter: listenerFilter{
+			queue:     make(chan ring.Event, queueSize),
+			historyFn: historyFn,
+		},
+	}
+}
+
+func (ml *listenerRemote) Version() listener.Version {
+	return listener.VersionRemote
+}
+
+// newRemoteServer returns the HTTP server streaming the events of m to remote
+// listeners
+func newRemoteServer(m *Monitor) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(listener.RemoteEventsPath, m.serveRemoteEvents)
+
+	return &http.Server{
+		Handler: mux,
+		// Only the subscription request is read, the response is
+		// streamed for as long as the listener is connected.
+		ReadTimeout: listener.HandshakeTimeout,
+	}
+}
+
+// serveRemote serves remote listeners on server until ctx is done
+func (m *Monitor) serveRemote(ctx context.Context, server net.Listener) {
+	srv := newRemoteServer(m)
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	if err := srv.Serve(server); err != nil && err != http.ErrServerClosed {
+		log.WithError(err).Error("Error serving remote listeners")
+	}
+}
+
+// writeRemoteError replies to a remote listener with an error
+func writeRemoteError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(models.Error(msg))
+}
+
+// serveRemoteEvents handles the subscription of a remote listener. The
+// request body is a models.MonitorEventRequest, the response a stream of
+// models.MonitorEvent objects matching the request, each one encoded as JSON
+// and flushed to the listener as soon as the event has been observed.
+func (m *Monitor) serveRemoteEvents(w http.ResponseWriter, r *http.Request) {
+	scopedLog := log.WithField("remote", r.RemoteAddr)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeRemoteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeRemoteError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	req := &models.MonitorEventRequest{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRemoteRequestSize)).Decode(req); err != nil && err != io.EOF {
+		writeRemoteError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	if err := req.Validate(strfmt.Default); err != nil {
+		writeRemoteError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	h, err := listener.NewRemoteHandshake(req)
+	if err != nil {
+		writeRemoteError(w, http.StatusBadRequest, "invalid filter: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ml := newListenerRemote(queueSize, m.readHistory)
+	m.registerListener(m.ctx, ml)
+	defer m.removeListener(ml)
+
+	scopedLog.Info("New remote listener connected")
+	defer scopedLog.Debug("Remote listener disconnected")
+
+	enc := json.NewEncoder(w)
+	ml.drain(h, r.Context().Done(), func(ts time.Time, pl *payload.Payload) bool {
+		ev, err := monitor.NewEvent(pl, m.nodeName, ts)
+		if err != nil {
+			scopedLog.WithError(err).Debug("Unable to decode monitor event")
+			return true
+		}
+
+		if err := enc.Encode(ev); err != nil {
+			scopedLog.WithError(err).Debug("Unable to send monitor event")
+			return false
+		}
+		flusher.Flush()
+		return true
+	})
+}
@@ -16,6 +16,9 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/signal"
@@ -23,6 +26,7 @@ import (
 	"syscall"
 
 	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/monitor/listener"
 	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/defaults"
@@ -51,14 +55,44 @@ var (
 	}
 	npages int
 
+	// historySize is the number of flow events recorded for clients
+	// connecting later, 0 disables recording
+	historySize int
+
 	// bpfRoot is the path to the BPF mount. This can be non-default if
 	// cilium-agent mounts bpf at an alternate location.
 	bpfRoot string
+
+	// nodeName is the name of the node reported to remote listeners
+	nodeName string
+
+	// remoteListenAddress is the TCP address remote listeners connect to,
+	// remote listeners are disabled if empty
+	remoteListenAddress string
+
+	// remoteTLSCert and remoteTLSKey are the certificate and key served to
+	// remote listeners, they are required unless remoteInsecure is set
+	remoteTLSCert string
+	remoteTLSKey  string
+
+	// remoteInsecure serves remote listeners over plain HTTP
+	remoteInsecure bool
+
+	// remoteTLSClientCA is the CA bundle remote listener certificates are
+	// verified with, client certificates are not required if empty
+	remoteTLSClientCA string
 )
 
 func init() {
 	rootCmd.Flags().IntVar(&npages, "num-pages", 64, "Number of pages for ring buffer")
+	rootCmd.Flags().IntVar(&historySize, "history-size", 0, "Number of flow events recorded for later retrieval (0 to disable)")
 	rootCmd.Flags().StringVar(&bpfRoot, "bpf-root", "/sys/fs/bpf", "Path to the root of the bpf mount")
+	rootCmd.Flags().StringVar(&nodeName, "node-name", "", "Name of the node reported to remote listeners (defaults to the hostname)")
+	rootCmd.Flags().StringVar(&remoteListenAddress, "remote-listen-address", "", "TCP address to serve monitor events over HTTPS to remote listeners on, e.g. \":4244\" (disabled if empty)")
+	rootCmd.Flags().StringVar(&remoteTLSCert, "remote-tls-cert", "", "Path to the TLS certificate served to remote listeners")
+	rootCmd.Flags().StringVar(&remoteTLSKey, "remote-tls-key", "", "Path to the TLS key of the certificate served to remote listeners")
+	rootCmd.Flags().StringVar(&remoteTLSClientCA, "remote-tls-client-ca", "", "Path to the CA bundle to verify client certificates of remote listeners with")
+	rootCmd.Flags().BoolVar(&remoteInsecure, "remote-insecure", false, "Serve monitor events to remote listeners over plain HTTP without TLS")
 }
 
 func execute() {
@@ -93,6 +127,55 @@ func buildServerOrExit(path string) net.Listener {
 	return server
 }
 
+// buildRemoteServerOrExit opens a TCP listener socket at addr. The socket
+// serves TLS unless remoteInsecure is set and requires client certificates if
+// a client CA is configured. It exits with logging on all errors.
+func buildRemoteServerOrExit(addr string) net.Listener {
+	scopedLog := log.WithField("address", addr)
+
+	if remoteInsecure {
+		if remoteTLSCert != "" || remoteTLSKey != "" || remoteTLSClientCA != "" {
+			scopedLog.Fatal("--remote-insecure cannot be combined with the --remote-tls-* options")
+		}
+	} else if remoteTLSCert == "" || remoteTLSKey == "" {
+		scopedLog.Fatal("--remote-tls-cert and --remote-tls-key are required unless --remote-insecure is set")
+	}
+
+	server, err := net.Listen("tcp", addr)
+	if err != nil {
+		scopedLog.WithError(err).Fatal("Cannot listen on remote address")
+	}
+
+	if remoteInsecure {
+		scopedLog.Warn("Serving monitor events to remote listeners without TLS")
+		return server
+	}
+
+	cert, err := tls.LoadX509KeyPair(remoteTLSCert, remoteTLSKey)
+	if err != nil {
+		scopedLog.WithError(err).Fatal("Cannot load TLS certificate")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if remoteTLSClientCA != "" {
+		ca, err := ioutil.ReadFile(remoteTLSClientCA)
+		if err != nil {
+			scopedLog.WithError(err).Fatal("Cannot read TLS client CA")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			scopedLog.Fatalf("No certificates found in %s", remoteTLSClientCA)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tls.NewListener(server, config)
+}
+
 func runNodeMonitor() {
 	bpf.SetMapRoot(bpfRoot)
 
@@ -118,9 +201,30 @@ func runNodeMonitor() {
 	defer server1_2.Close() // Stop accepting new v1.2 connections
 	log.Infof("Serving cilium node monitor v1.2 API at unix://%s", defaults.MonitorSockPath1_2)
 
+	server1_3 := buildServerOrExit(defaults.MonitorSockPath1_3)
+	defer server1_3.Close() // Stop accepting new v1.3 connections
+	log.Infof("Serving cilium node monitor v1.3 API at unix://%s", defaults.MonitorSockPath1_3)
+
+	var serverRemote net.Listener
+	if remoteListenAddress != "" {
+		serverRemote = buildRemoteServerOrExit(remoteListenAddress)
+		defer serverRemote.Close() // Stop accepting new remote connections
+		scheme := "https"
+		if remoteInsecure {
+			scheme = "http"
+		}
+		log.Infof("Serving cilium node monitor events to remote listeners at %s://%s%s", scheme, remoteListenAddress, listener.RemoteEventsPath)
+	}
+
+	if nodeName == "" {
+		if nodeName, err = os.Hostname(); err != nil {
+			log.WithError(err).Warn("Unable to retrieve local hostname")
+		}
+	}
+
 	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
 
-	monitorSingleton, err = NewMonitor(mainCtx, npages, pipe, server1_0, server1_2)
+	monitorSingleton, err = NewMonitor(mainCtx, npages, historySize, nodeName, pipe, server1_0, server1_2, server1_3, serverRemote)
 	if err != nil {
 		log.WithError(err).Fatal("Error initialising monitor handlers")
 	}
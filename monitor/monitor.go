@@ -27,6 +27,7 @@ import (
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/monitor/ring"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/lock"
@@ -72,6 +73,13 @@ type Monitor struct {
 	listeners        map[listener.MonitorListener]struct{}
 	nPages           int
 	monitorEvents    *bpf.PerCpuEvents
+
+	// history records the most recent flow events, nil if recording is
+	// disabled
+	history *ring.Ring
+
+	// nodeName is the name of the node reported to remote listeners
+	nodeName string
 }
 
 // agentPipeReader reads agent events from the agentPipe and distributes to all listeners
@@ -102,18 +110,32 @@ func (m *Monitor) agentPipeReader(ctx context.Context, agentPipe io.Reader) {
 // NewMonitor creates a Monitor, and starts client connection handling and agent event
 // handling.
 // Note that the perf buffer reader is started only when listeners are
-// connected.
-func NewMonitor(ctx context.Context, nPages int, agentPipe io.Reader, server1_0, server1_2 net.Listener) (m *Monitor, err error) {
+// connected, unless historySize is greater than zero. In that case the perf
+// buffer is read continuously and the most recent historySize flow events are
+// recorded for clients requesting them in their handshake.
+// serverRemote is optional, if set, remote listeners subscribe to the events
+// of node nodeName over HTTP on it.
+func NewMonitor(ctx context.Context, nPages, historySize int, nodeName string, agentPipe io.Reader, server1_0, server1_2, server1_3, serverRemote net.Listener) (m *Monitor, err error) {
 	m = &Monitor{
 		ctx:              ctx,
 		listeners:        make(map[listener.MonitorListener]struct{}),
 		nPages:           nPages,
+		nodeName:         nodeName,
 		perfReaderCancel: func() {}, // no-op to avoid doing null checks everywhere
 	}
 
+	if historySize > 0 {
+		m.history = ring.NewRing(historySize)
+		go m.perfEventReader(ctx, nPages)
+	}
+
 	// start new MonitorListener handler
 	go m.connectionHandler1_0(ctx, server1_0)
-	go m.connectionHandler1_2(ctx, server1_2)
+	go m.connectionHandler1_2(ctx, server1_2, listener.Version1_2)
+	go m.connectionHandler1_2(ctx, server1_3, listener.Version1_3)
+	if serverRemote != nil {
+		go m.serveRemote(ctx, serverRemote)
+	}
 
 	// start agent event pipe reader
 	go m.agentPipeReader(ctx, agentPipe)
@@ -130,31 +152,47 @@ func (m *Monitor) registerNewListener(parentCtx context.Context, conn net.Conn,
 	m.Lock()
 	defer m.Unlock()
 
-	// If this is the first listener, start the perf reader
-	if len(m.listeners) == 0 {
-		m.perfReaderCancel() // don't leak any old readers, just in case.
-		perfEventReaderCtx, cancel := context.WithCancel(parentCtx)
-		m.perfReaderCancel = cancel
-		go m.perfEventReader(perfEventReaderCtx, m.nPages)
-	}
-
+	// The listeners are created with the lock held, so that they can't
+	// remove themselves before they have been added
 	switch version {
 	case listener.Version1_0:
-		newListener := newListenerv1_0(conn, queueSize, m.removeListener)
-		m.listeners[newListener] = struct{}{}
+		m.addListenerLocked(parentCtx, newListenerv1_0(conn, queueSize, m.removeListener))
 
-	case listener.Version1_2:
-		newListener := newListenerv1_2(conn, queueSize, m.removeListener)
-		m.listeners[newListener] = struct{}{}
+	case listener.Version1_2, listener.Version1_3:
+		m.addListenerLocked(parentCtx, newListenerv1_2(conn, version, queueSize, m.removeListener, m.readHistory))
 
 	default:
 		conn.Close()
 		log.WithField("version", version).Error("Closing new connection from unsupported monitor client version")
 	}
+}
+
+// registerListener adds the MonitorListener ml to the global list, see
+// registerNewListener.
+func (m *Monitor) registerListener(parentCtx context.Context, ml listener.MonitorListener) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.addListenerLocked(parentCtx, ml)
+}
+
+// addListenerLocked adds ml to the global list and starts the perf reader if
+// ml is the first listener. The monitor lock must be held.
+func (m *Monitor) addListenerLocked(parentCtx context.Context, ml listener.MonitorListener) {
+	// If this is the first listener, start the perf reader. It is always
+	// running if events are recorded.
+	if len(m.listeners) == 0 && m.history == nil {
+		m.perfReaderCancel() // don't leak any old readers, just in case.
+		perfEventReaderCtx, cancel := context.WithCancel(parentCtx)
+		m.perfReaderCancel = cancel
+		go m.perfEventReader(perfEventReaderCtx, m.nPages)
+	}
+
+	m.listeners[ml] = struct{}{}
 
 	log.WithFields(logrus.Fields{
 		"count.listener": len(m.listeners),
-		"version":        version,
+		"version":        ml.Version(),
 	}).Debug("New listener connected")
 }
 
@@ -175,7 +213,7 @@ func (m *Monitor) removeListener(ml listener.MonitorListener) {
 	// Note: it is critical to hold the lock and check the number of listeners.
 	// This guards against an older generation listener calling the
 	// current generation perfReaderCancel
-	if len(m.listeners) == 0 {
+	if len(m.listeners) == 0 && m.history == nil {
 		m.perfReaderCancel()
 	}
 }
@@ -279,9 +317,10 @@ func (m *Monitor) connectionHandler1_0(parentCtx context.Context, server net.Lis
 }
 
 // connectionHandler1_2 handles all the incoming connections and sets up the
-// listener objects. It will block on Accept, but expects the caller to close
-// server, inducing a return.
-func (m *Monitor) connectionHandler1_2(parentCtx context.Context, server net.Listener) {
+// listener objects of the given version, which is either 1.2 or 1.3 as both
+// share the same encoding. It will block on Accept, but expects the caller to
+// close server, inducing a return.
+func (m *Monitor) connectionHandler1_2(parentCtx context.Context, server net.Listener, version listener.Version) {
 	for !isCtxDone(parentCtx) {
 		conn, err := server.Accept()
 		switch {
@@ -297,14 +336,32 @@ func (m *Monitor) connectionHandler1_2(parentCtx context.Context, server net.Lis
 			continue
 		}
 
-		m.registerNewListener(parentCtx, conn, listener.Version1_2)
+		m.registerNewListener(parentCtx, conn, version)
+	}
+}
+
+// readHistory calls register to register the handshake of a listener and
+// returns the recorded events requested by the handshake. The monitor lock is
+// held, so that all returned events have been enqueued to the listener before
+// register is called and all events recorded later are enqueued after.
+func (m *Monitor) readHistory(h *listener.Handshake, register func()) []ring.Event {
+	m.Lock()
+	defer m.Unlock()
+
+	register()
+	if m.history == nil || !h.WantsHistory() {
+		return nil
 	}
+	return m.history.Read(h.Since, h.Last, h.Filter.Match)
 }
 
-// send enqueues the payload to all listeners.
+// send records the payload and enqueues it to all listeners.
 func (m *Monitor) send(pl *payload.Payload) {
 	m.Lock()
 	defer m.Unlock()
+	if m.history != nil {
+		m.history.Add(time.Now(), pl)
+	}
 	for ml := range m.listeners {
 		ml.Enqueue(pl)
 	}
@@ -0,0 +1,132 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/monitor/ring"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MonitorSuite struct{}
+
+var _ = Suite(&MonitorSuite{})
+
+func dropPayload(c *C, source uint16) *payload.Payload {
+	buf := new(bytes.Buffer)
+	dn := monitor.DropNotify{Type: monitor.MessageTypeDrop, Source: source}
+	c.Assert(binary.Write(buf, byteorder.Native, dn), IsNil)
+	return &payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}
+}
+
+func agentPayload(c *C, text string) *payload.Payload {
+	buf := bytes.NewBuffer([]byte{byte(monitor.MessageTypeAgent)})
+	an := monitor.AgentNotify{Type: monitor.AgentNotifyGeneric, Text: text}
+	c.Assert(gob.NewEncoder(buf).Encode(an), IsNil)
+	return &payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}
+}
+
+func (s *MonitorSuite) TestServeRemoteEvents(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Events are recorded, so that the perf reader is not started when
+	// listeners are registered
+	m := &Monitor{
+		ctx:              ctx,
+		listeners:        make(map[listener.MonitorListener]struct{}),
+		history:          ring.NewRing(16),
+		nodeName:         "node1",
+		perfReaderCancel: func() {},
+	}
+	m.send(dropPayload(c, 1))
+	m.send(dropPayload(c, 2))
+
+	srv := httptest.NewServer(newRemoteServer(m).Handler)
+	defer srv.Close()
+	url := srv.URL + listener.RemoteEventsPath
+
+	resp, err := http.Get(url)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusMethodNotAllowed)
+
+	resp, err = http.Post(url, "application/json", bytes.NewBufferString(`{"filter":{"verdicts":["foo"]}}`))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp, err = http.Post(url, "application/json", bytes.NewBufferString(`{"filter":{"types":["foo"]}}`))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp, err = http.Post(url, "application/json", bytes.NewBufferString(`{"filter":{"types":["drop","agent"]},"last":1}`))
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	dec := json.NewDecoder(bufio.NewReader(resp.Body))
+	readEvent := func() *models.MonitorEvent {
+		ev := &models.MonitorEvent{}
+		c.Assert(dec.Decode(ev), IsNil)
+		return ev
+	}
+
+	// The most recent recorded event is sent first
+	ev := readEvent()
+	c.Assert(ev.Type, Equals, models.MonitorEventTypeDrop)
+	c.Assert(ev.Node, Equals, "node1")
+	c.Assert(ev.Event.(map[string]interface{})["source"], Equals, float64(2))
+
+	// Events observed after the subscription are streamed
+	for {
+		m.Lock()
+		n := len(m.listeners)
+		m.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	m.send(&payload.Payload{Type: payload.EventSample, Data: []byte{byte(monitor.MessageTypeDebug)}})
+	m.send(agentPayload(c, "hello"))
+	ev = readEvent()
+	c.Assert(ev.Type, Equals, models.MonitorEventTypeAgent)
+	c.Assert(ev.Event.(map[string]interface{})["message"], Equals, "hello")
+}
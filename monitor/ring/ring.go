@@ -0,0 +1,116 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ring
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+)
+
+// Event is a monitor event recorded in the ring buffer
+type Event struct {
+	// Timestamp is the time the event was observed by the node monitor
+	Timestamp time.Time
+	Payload   *payload.Payload
+}
+
+// Ring is a bounded in-memory buffer of the most recent flow events seen by
+// the node monitor. Once the buffer is full, the oldest events are
+// overwritten.
+type Ring struct {
+	mutex  lock.RWMutex
+	events []Event
+	// next is the index the next event is written to
+	next int
+	// count is the number of valid events in events
+	count int
+}
+
+// NewRing returns a ring buffer holding up to size events
+func NewRing(size int) *Ring {
+	return &Ring{events: make([]Event, size)}
+}
+
+// IsRecorded returns true if the payload is a flow event which is recorded
+// in the ring buffer: drop, trace and policy verdict notifications as well as
+// access log records.
+func IsRecorded(pl *payload.Payload) bool {
+	if pl.Type != payload.EventSample || len(pl.Data) == 0 {
+		return false
+	}
+
+	switch pl.Data[0] {
+	case monitor.MessageTypeDrop, monitor.MessageTypeTrace,
+		monitor.MessageTypePolicyVerdict, monitor.MessageTypeAccessLog:
+		return true
+	}
+	return false
+}
+
+// Add records the payload observed at ts if it is a flow event. The payload
+// must not be modified afterwards.
+func (r *Ring) Add(ts time.Time, pl *payload.Payload) {
+	if len(r.events) == 0 || !IsRecorded(pl) {
+		return
+	}
+
+	r.mutex.Lock()
+	r.events[r.next] = Event{Timestamp: ts, Payload: pl}
+	r.next = (r.next + 1) % len(r.events)
+	if r.count < len(r.events) {
+		r.count++
+	}
+	r.mutex.Unlock()
+}
+
+// Len returns the number of recorded events
+func (r *Ring) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.count
+}
+
+// Read returns the recorded events along with the time they were observed,
+// oldest first, which were observed at or after since and for which match
+// returns true. A nil match matches all events. If last is greater than zero,
+// only the last most recent of these events are returned.
+func (r *Ring) Read(since time.Time, last int, match func(*payload.Payload) bool) []Event {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := []Event{}
+	oldest := r.next - r.count
+	if oldest < 0 {
+		oldest += len(r.events)
+	}
+	for i := 0; i < r.count; i++ {
+		ev := r.events[(oldest+i)%len(r.events)]
+		if ev.Timestamp.Before(since) {
+			continue
+		}
+		if match != nil && !match(ev.Payload) {
+			continue
+		}
+		result = append(result, ev)
+	}
+
+	if last > 0 && len(result) > last {
+		result = result[len(result)-last:]
+	}
+	return result
+}
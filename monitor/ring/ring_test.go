@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type RingSuite struct{}
+
+var _ = Suite(&RingSuite{})
+
+func newPayload(messageType int, id byte) *payload.Payload {
+	return &payload.Payload{Type: payload.EventSample, Data: []byte{byte(messageType), id}}
+}
+
+func ids(events []Event) []byte {
+	result := []byte{}
+	for _, ev := range events {
+		result = append(result, ev.Payload.Data[1])
+	}
+	return result
+}
+
+func (s *RingSuite) TestIsRecorded(c *C) {
+	c.Assert(IsRecorded(newPayload(monitor.MessageTypeDrop, 0)), Equals, true)
+	c.Assert(IsRecorded(newPayload(monitor.MessageTypeTrace, 0)), Equals, true)
+	c.Assert(IsRecorded(newPayload(monitor.MessageTypePolicyVerdict, 0)), Equals, true)
+	c.Assert(IsRecorded(newPayload(monitor.MessageTypeAccessLog, 0)), Equals, true)
+	c.Assert(IsRecorded(newPayload(monitor.MessageTypeDebug, 0)), Equals, false)
+	c.Assert(IsRecorded(newPayload(monitor.MessageTypeAgent, 0)), Equals, false)
+	c.Assert(IsRecorded(&payload.Payload{Type: payload.RecordLost, Lost: 1}), Equals, false)
+}
+
+func (s *RingSuite) TestRing(c *C) {
+	r := NewRing(3)
+	start := time.Now()
+	c.Assert(r.Read(time.Time{}, 0, nil), HasLen, 0)
+
+	r.Add(start, newPayload(monitor.MessageTypeDrop, 1))
+	r.Add(start.Add(time.Second), newPayload(monitor.MessageTypeDebug, 2))
+	r.Add(start.Add(2*time.Second), newPayload(monitor.MessageTypeTrace, 3))
+	c.Assert(r.Len(), Equals, 2)
+	c.Assert(ids(r.Read(time.Time{}, 0, nil)), DeepEquals, []byte{1, 3})
+
+	// Overwrites the oldest events
+	r.Add(start.Add(3*time.Second), newPayload(monitor.MessageTypeDrop, 4))
+	r.Add(start.Add(4*time.Second), newPayload(monitor.MessageTypeDrop, 5))
+	c.Assert(r.Len(), Equals, 3)
+	c.Assert(ids(r.Read(time.Time{}, 0, nil)), DeepEquals, []byte{3, 4, 5})
+
+	c.Assert(ids(r.Read(start.Add(3*time.Second), 0, nil)), DeepEquals, []byte{4, 5})
+	c.Assert(ids(r.Read(time.Time{}, 2, nil)), DeepEquals, []byte{4, 5})
+	c.Assert(ids(r.Read(time.Time{}, 10, nil)), DeepEquals, []byte{3, 4, 5})
+
+	drops := func(pl *payload.Payload) bool { return pl.Data[0] == monitor.MessageTypeDrop }
+	c.Assert(ids(r.Read(time.Time{}, 0, drops)), DeepEquals, []byte{4, 5})
+	c.Assert(ids(r.Read(time.Time{}, 1, drops)), DeepEquals, []byte{5})
+	c.Assert(r.Read(start.Add(time.Minute), 0, nil), HasLen, 0)
+
+	// The time the events were observed is returned along with them
+	events := r.Read(time.Time{}, 0, nil)
+	c.Assert(events[0].Timestamp.Equal(start.Add(2*time.Second)), Equals, true)
+	c.Assert(events[2].Timestamp.Equal(start.Add(4*time.Second)), Equals, true)
+}
+
+func (s *RingSuite) TestRingDisabled(c *C) {
+	r := NewRing(0)
+	r.Add(time.Now(), newPayload(monitor.MessageTypeDrop, 1))
+	c.Assert(r.Len(), Equals, 0)
+	c.Assert(r.Read(time.Time{}, 0, nil), HasLen, 0)
+}
@@ -39,6 +39,7 @@ import (
 
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
 	"github.com/cilium/cilium/pkg/maps/ipcache"
 	"github.com/cilium/cilium/pkg/maps/lbmap"
 	"github.com/cilium/cilium/pkg/maps/lxcmap"
@@ -95,6 +96,14 @@ var cToGO = map[reflect.Type]valueToCheck{
 		sizeOfC:  C.sizeof_struct_remote_endpoint_info,
 		goStruct: reflect.TypeOf(ipcache.RemoteEndpointInfo{}),
 	},
+	reflect.TypeOf(C.struct_egress_key{}): {
+		sizeOfC:  C.sizeof_struct_egress_key,
+		goStruct: reflect.TypeOf(egressmap.Key{}),
+	},
+	reflect.TypeOf(C.struct_egress_info{}): {
+		sizeOfC:  C.sizeof_struct_egress_info,
+		goStruct: reflect.TypeOf(egressmap.Value{}),
+	},
 	reflect.TypeOf(C.struct_lb4_key{}): {
 		sizeOfC:  C.sizeof_struct_lb4_key,
 		goStruct: reflect.TypeOf(lbmap.Service4Key{}),
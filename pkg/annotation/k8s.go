@@ -37,4 +37,9 @@ const (
 	// CiliumHostIP is the annotation name used to store the IPv4 address
 	// of the cilium host interface in the node's annotations.
 	CiliumHostIP = "io.cilium.network.ipv4-cilium-host"
+
+	// GlobalService if set to true, marks a service to become a global
+	// service whose backends are shared with all clusters of a
+	// ClusterMesh
+	GlobalService = "io.cilium/global-service"
 )
@@ -293,6 +293,18 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 
 	}
 
+	if sr.ClusterMesh != nil {
+		FormatClusterMeshStatus(w, sr.ClusterMesh, false)
+	}
+
+	if sr.Encryption != nil {
+		FormatEncryptionStatus(w, sr.Encryption, false)
+	}
+
+	if sr.Routing != nil {
+		FormatRoutingStatus(w, sr.Routing)
+	}
+
 	if sr.Proxy != nil {
 		fmt.Fprintf(w, "Proxy Status:\tOK, ip %s, port-range %s\n",
 			sr.Proxy.IP, sr.Proxy.PortRange)
@@ -300,3 +312,90 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 		fmt.Fprintf(w, "Proxy Status:\tNo managed proxy redirect\n")
 	}
 }
+
+// FormatClusterMeshStatus writes a summary of the ClusterMesh status to w.
+// Details of a remote cluster are only printed if the cluster is not ready or
+// if 'verbose' is true.
+func FormatClusterMeshStatus(w io.Writer, cm *models.ClusterMeshStatus, verbose bool) {
+	nReady := 0
+	for _, cluster := range cm.Clusters {
+		if cluster.Ready {
+			nReady++
+		}
+	}
+
+	fmt.Fprintf(w, "ClusterMesh:\t%d/%d clusters ready, %d global-services\n",
+		nReady, len(cm.Clusters), cm.NumGlobalServices)
+
+	for _, cluster := range cm.Clusters {
+		if cluster.Ready && !verbose {
+			continue
+		}
+
+		ready := "not-ready"
+		if cluster.Ready {
+			ready = "ready"
+		}
+
+		fmt.Fprintf(w, "   %s: %s, %d nodes, %d identities, %d ipcache entries, %d services, %d failures (last: %s), last sync %s\n",
+			cluster.Name, ready, cluster.NumNodes, cluster.NumIdentities,
+			cluster.NumIpcacheEntries, cluster.NumSharedServices, cluster.NumFailures,
+			timeSince(time.Time(cluster.LastFailure)), timeSince(time.Time(cluster.LastSync)))
+		fmt.Fprintf(w, "   └  %s\n", cluster.Status)
+	}
+}
+
+// FormatEncryptionStatus writes a summary of the encryption status to w.
+// The individual peers are only printed if 'verbose' is true.
+func FormatEncryptionStatus(w io.Writer, es *models.EncryptionStatus, verbose bool) {
+	if es.Ipsec == nil {
+		fmt.Fprintf(w, "Encryption:\t%s\n", es.Mode)
+		return
+	}
+
+	nEncrypted := 0
+	for _, peer := range es.Ipsec.Peers {
+		if peer.KeyIndex != 0 {
+			nEncrypted++
+		}
+	}
+
+	fmt.Fprintf(w, "Encryption:\t%s, key index %d, %d/%d peers encrypted\n",
+		es.Mode, es.Ipsec.KeyIndex, nEncrypted, len(es.Ipsec.Peers))
+	if es.Ipsec.PreviousKeyIndex != 0 {
+		fmt.Fprintf(w, "   Previous key index %d still in use\n", es.Ipsec.PreviousKeyIndex)
+	}
+	if es.Msg != "" {
+		fmt.Fprintf(w, "   └  %s\n", es.Msg)
+	}
+
+	if !verbose {
+		return
+	}
+
+	for _, peer := range es.Ipsec.Peers {
+		fmt.Fprintf(w, "   %s (%s): key index %d, announced key index %d\n",
+			peer.Name, peer.IP, peer.KeyIndex, peer.RemoteKeyIndex)
+	}
+}
+
+// FormatRoutingStatus writes a summary of the routing between nodes to w.
+// All allocation prefixes of remote nodes which are not reachable are
+// listed.
+func FormatRoutingStatus(w io.Writer, rs *models.RoutingStatus) {
+	if rs.Mode != models.RoutingStatusModeDirect {
+		fmt.Fprintf(w, "Routing:\t%s\n", rs.Mode)
+		return
+	}
+
+	exporter := "disabled"
+	if rs.Exporter != "" {
+		exporter = fmt.Sprintf("%s (%s)", rs.Exporter, strings.Join(rs.ExportedPrefixes, ", "))
+	}
+
+	fmt.Fprintf(w, "Routing:\t%s, exporter %s, %d unreachable prefixes\n",
+		rs.Mode, exporter, len(rs.UnreachablePrefixes))
+	for _, p := range rs.UnreachablePrefixes {
+		fmt.Fprintf(w, "   %s: %s not reachable without tunneling\n", p.Node, p.Prefix)
+	}
+}
@@ -47,3 +47,24 @@ func (c *Client) ConfigPatch(cfg models.DaemonConfigurationSpec) error {
 	_, err = c.Daemon.PatchConfig(params)
 	return Hint(err)
 }
+
+// ConfigLabelsGet returns the identity relevant label filter.
+func (c *Client) ConfigLabelsGet() (*models.LabelFilter, error) {
+	resp, err := c.Daemon.GetConfigLabels(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// ConfigLabelsPut replaces the user configured label prefixes of the identity
+// relevant label filter. If dryRun is true, the filter is not changed and only
+// the impact of the change is returned.
+func (c *Client) ConfigLabelsPut(spec *models.LabelFilterSpec, dryRun bool) (*models.LabelFilterImpact, error) {
+	params := daemon.NewPutConfigLabelsParams().WithLabelFilterSpec(spec).WithDryRun(&dryRun).WithTimeout(api.ClientTimeout)
+	resp, err := c.Daemon.PutConfigLabels(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
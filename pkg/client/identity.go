@@ -30,3 +30,29 @@ func (c *Client) IdentityGet(id string) (*models.Identity, error) {
 	}
 	return resp.Payload, nil
 }
+
+// IdentityUsageGet returns the usage of a security identity.
+func (c *Client) IdentityUsageGet(id string) (*models.IdentityUsage, error) {
+	params := policy.NewGetIdentityIDUsageParams().WithID(id).WithTimeout(api.ClientTimeout)
+
+	resp, err := c.Policy.GetIdentityIDUsage(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// IdentityStaleGet returns the identities which have had no local users for
+// at least minAge.
+func (c *Client) IdentityStaleGet(minAge string) ([]*models.IdentityUsage, error) {
+	params := policy.NewGetIdentityStaleParams().WithTimeout(api.ClientTimeout)
+	if minAge != "" {
+		params.SetMinAge(&minAge)
+	}
+
+	resp, err := c.Policy.GetIdentityStale(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
@@ -16,11 +16,15 @@ package clustermesh
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/kvstore/store"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/option"
+
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -40,9 +44,25 @@ type Configuration struct {
 	// configuration files to appear
 	ConfigDirectory string
 
+	// ConfigSecret is the namespace/name of the Kubernetes secret holding
+	// the etcd configuration of all remote clusters. If set, the secret
+	// is watched instead of ConfigDirectory.
+	ConfigSecret string
+
+	// K8sClient is the Kubernetes client used to watch ConfigSecret
+	K8sClient kubernetes.Interface
+
+	// StateDirectory is the directory in which the configurations found
+	// in ConfigSecret are stored for use by the etcd client
+	StateDirectory string
+
 	// NodeKeyCreator is the function used to create node instances as
 	// nodes are being discovered in remote clusters
 	NodeKeyCreator store.KeyCreator
+
+	// ServiceMerger is notified about global services discovered in
+	// remote clusters. This parameter is optional.
+	ServiceMerger ServiceMerger
 }
 
 // ClusterMesh is a cache of multiple remote clusters
@@ -53,26 +73,31 @@ type ClusterMesh struct {
 	mutex         lock.RWMutex
 	clusters      map[string]*remoteCluster
 	controllers   *controller.Manager
-	configWatcher *configDirectoryWatcher
+	configWatcher configSource
+
+	// globalServices is the cache of global services of all remote
+	// clusters
+	globalServices *globalServiceCache
 }
 
 // NewClusterMesh creates a new remote cluster cache based on the
 // provided configuration
 func NewClusterMesh(c Configuration) (*ClusterMesh, error) {
 	cm := &ClusterMesh{
-		conf:        c,
-		clusters:    map[string]*remoteCluster{},
-		controllers: controller.NewManager(),
+		conf:           c,
+		clusters:       map[string]*remoteCluster{},
+		controllers:    controller.NewManager(),
+		globalServices: newGlobalServiceCache(c.ServiceMerger),
 	}
 
-	w, err := createConfigDirectoryWatcher(c.ConfigDirectory, cm)
+	w, err := newConfigSource(c, cm)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create config directory watcher: %s", err)
+		return nil, fmt.Errorf("unable to create config watcher: %s", err)
 	}
 
 	cm.configWatcher = w
 
-	controllerName := fmt.Sprintf("clustermesh-%s-config-fsnotify", c.Name)
+	controllerName := fmt.Sprintf("clustermesh-%s-config-watcher", c.Name)
 	cm.controllers.UpdateController(controllerName,
 		controller.ControllerParams{
 			DoFunc: func() error { return cm.configWatcher.watch() },
@@ -162,3 +187,24 @@ func (cm *ClusterMesh) NumReadyClusters() int {
 
 	return nready
 }
+
+// Status returns the status of the ClusterMesh subsystem
+func (cm *ClusterMesh) Status() *models.ClusterMeshStatus {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	status := &models.ClusterMeshStatus{
+		Clusters:          make([]*models.RemoteCluster, 0, len(cm.clusters)),
+		NumGlobalServices: int64(cm.globalServices.numGlobalServices()),
+	}
+
+	for _, cluster := range cm.clusters {
+		status.Clusters = append(status.Clusters, cluster.status())
+	}
+
+	sort.Slice(status.Clusters, func(i, j int) bool {
+		return status.Clusters[i].Name < status.Clusters[j].Name
+	})
+
+	return status
+}
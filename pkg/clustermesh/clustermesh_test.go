@@ -182,3 +182,71 @@ func (s *ClusterMeshTestSuite) TestClusterMesh(c *C) {
 
 	cm.Close()
 }
+
+func (s *ClusterMeshTestSuite) TestStatus(c *C) {
+	cm := &ClusterMesh{
+		clusters:       map[string]*remoteCluster{},
+		globalServices: newGlobalServiceCache(nil),
+	}
+
+	lastFailure := time.Now()
+	for _, name := range []string{"cluster2", "cluster1"} {
+		rc := cm.newRemoteCluster(name, "")
+		rc.failures = 2
+		rc.lastFailure = lastFailure
+		cm.clusters[name] = rc
+	}
+
+	status := cm.Status()
+	c.Assert(status.Clusters, HasLen, 2)
+	c.Assert(status.Clusters[0].Name, Equals, "cluster1")
+	c.Assert(status.Clusters[1].Name, Equals, "cluster2")
+	c.Assert(status.Clusters[0].Ready, Equals, false)
+	c.Assert(status.Clusters[0].NumFailures, Equals, int64(2))
+	c.Assert(time.Time(status.Clusters[0].LastFailure).Equal(lastFailure), Equals, true)
+	c.Assert(time.Time(status.Clusters[0].LastSync).IsZero(), Equals, true)
+	c.Assert(status.NumGlobalServices, Equals, int64(0))
+}
+
+// statusBackend is a kvstore backend which only implements Status
+type statusBackend struct {
+	kvstore.BackendOperations
+	err error
+}
+
+func (b *statusBackend) Status() (string, error) {
+	return "", b.err
+}
+
+func (s *ClusterMeshTestSuite) TestSynchronize(c *C) {
+	cm := &ClusterMesh{
+		clusters:       map[string]*remoteCluster{},
+		globalServices: newGlobalServiceCache(nil),
+	}
+	rc := cm.newRemoteCluster("cluster1", "")
+
+	// Not connected yet
+	c.Assert(rc.synchronize(), IsNil)
+	c.Assert(rc.failures, Equals, 0)
+	c.Assert(rc.lastSync.IsZero(), Equals, true)
+
+	backend := &statusBackend{}
+	rc.backend = backend
+	c.Assert(rc.synchronize(), IsNil)
+	c.Assert(rc.failures, Equals, 0)
+	lastSync := rc.lastSync
+	c.Assert(lastSync.IsZero(), Equals, false)
+
+	backend.err = fmt.Errorf("unreachable")
+	c.Assert(rc.synchronize(), Not(IsNil))
+	c.Assert(rc.failures, Equals, 1)
+	c.Assert(rc.lastFailure.IsZero(), Equals, false)
+	c.Assert(rc.lastSync, Equals, lastSync)
+
+	// Every successful synchronization updates lastSync
+	backend.err = nil
+	time.Sleep(time.Millisecond)
+	c.Assert(rc.synchronize(), IsNil)
+	c.Assert(rc.lastSync.After(lastSync), Equals, true)
+	c.Assert(rc.failures, Equals, 1)
+}
@@ -30,6 +30,30 @@ type clusterLifecycle interface {
 	remove(clusterName string)
 }
 
+// configSource is the interface implemented by all sources of remote cluster
+// configurations. A source reports all configurations it finds and all
+// subsequent changes to the clusterLifecycle it has been created with.
+type configSource interface {
+	// watch reports all existing configurations and then blocks watching
+	// for changes until close() is called
+	watch() error
+
+	// close stops watching for changes
+	close()
+}
+
+// newConfigSource returns the configuration source selected by the
+// configuration. The Kubernetes secret takes precedence over the directory.
+func newConfigSource(c Configuration, lifecycle clusterLifecycle) (configSource, error) {
+	if c.ConfigSecret != "" {
+		return createConfigSecretWatcher(c.K8sClient, c.ConfigSecret, c.StateDirectory, lifecycle)
+	}
+
+	return createConfigDirectoryWatcher(c.ConfigDirectory, lifecycle)
+}
+
+// configDirectoryWatcher watches a directory of etcd configuration files, one
+// file per remote cluster named after the cluster
 type configDirectoryWatcher struct {
 	watcher   *fsnotify.Watcher
 	lifecycle clusterLifecycle
@@ -0,0 +1,303 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustermesh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// configFileName is the name of the etcd configuration file written
+	// for each remote cluster found in the secret
+	configFileName = "config"
+)
+
+// inlineTLSFiles maps the suffix of secret keys holding TLS material of a
+// remote cluster to the etcd configuration option referring to the file
+//
+// Example: The client key of cluster "cluster5" is stored in the secret key
+// "cluster5.etcd-client.key"
+var inlineTLSFiles = map[string]string{
+	".etcd-client.key":    "key-file",
+	".etcd-client.crt":    "cert-file",
+	".etcd-client-ca.crt": "trusted-ca-file",
+}
+
+// configSecretWatcher watches a Kubernetes secret holding the etcd
+// configuration of all remote clusters. Each secret key names a remote
+// cluster and contains its etcd configuration. The TLS material of a cluster
+// can be provided inline in additional keys, see inlineTLSFiles. As the etcd
+// client requires files, all configurations are written to the state
+// directory before being reported.
+type configSecretWatcher struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	stateDir  string
+	lifecycle clusterLifecycle
+	stop      chan struct{}
+
+	// mutex protects fingerprints
+	mutex lock.Mutex
+
+	// fingerprints contains a hash of the configuration of each remote
+	// cluster reported to the lifecycle, indexed by cluster name
+	fingerprints map[string]string
+}
+
+func createConfigSecretWatcher(client kubernetes.Interface, secret, stateDir string, lifecycle clusterLifecycle) (*configSecretWatcher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("Kubernetes client required to watch secret %s", secret)
+	}
+
+	namespace, name := "", secret
+	if parts := strings.SplitN(secret, "/", 2); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	}
+	if namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid secret %q, must be in the form namespace/name", secret)
+	}
+
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &configSecretWatcher{
+		client:       client,
+		namespace:    namespace,
+		name:         name,
+		stateDir:     stateDir,
+		lifecycle:    lifecycle,
+		stop:         make(chan struct{}, 0),
+		fingerprints: map[string]string{},
+	}, nil
+}
+
+func (csw *configSecretWatcher) watch() error {
+	log.WithField(fieldConfig, csw.namespace+"/"+csw.name).Debug("Starting config secret watcher")
+
+	selector := fields.OneTermEqualSelector("metadata.name", csw.name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return csw.client.CoreV1().Secrets(csw.namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return csw.client.CoreV1().Secrets(csw.namespace).Watch(options)
+		},
+	}
+
+	_, controller := cache.NewInformer(lw, &v1.Secret{}, 0,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if secret, ok := obj.(*v1.Secret); ok {
+					csw.update(secret.Data)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if secret, ok := newObj.(*v1.Secret); ok {
+					csw.update(secret.Data)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				csw.update(nil)
+			},
+		},
+	)
+
+	controller.Run(csw.stop)
+	return nil
+}
+
+// clusterNames returns the sorted names of all remote clusters configured in
+// the secret data
+func clusterNames(data map[string][]byte) []string {
+	names := []string{}
+	for key := range data {
+		if strings.HasPrefix(key, ".") {
+			continue
+		}
+
+		isTLS := false
+		for suffix := range inlineTLSFiles {
+			if strings.HasSuffix(key, suffix) {
+				isTLS = true
+				break
+			}
+		}
+
+		if !isTLS {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fingerprint returns a hash covering the configuration and the inline TLS
+// material of a remote cluster
+func fingerprint(name string, data map[string][]byte) string {
+	keys := []string{name}
+	for suffix := range inlineTLSFiles {
+		keys = append(keys, name+suffix)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s:%d:", key, len(data[key]))
+		h.Write(data[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to
+// filename so readers never observe a partially written file
+func writeFileAtomic(filename string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0600)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// writeClusterConfig writes the etcd configuration and the inline TLS
+// material of a remote cluster to the state directory and returns the path
+// to the configuration file
+func (csw *configSecretWatcher) writeClusterConfig(name string, data map[string][]byte) (string, error) {
+	dir := filepath.Join(csw.stateDir, name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	config := data[name]
+	overrides := map[string]interface{}{}
+	for suffix, option := range inlineTLSFiles {
+		content, ok := data[name+suffix]
+		if !ok {
+			continue
+		}
+
+		filename := filepath.Join(dir, name+suffix)
+		if err := writeFileAtomic(filename, content); err != nil {
+			return "", err
+		}
+		overrides[option] = filename
+	}
+
+	// Point the configuration to the TLS material written above
+	if len(overrides) > 0 {
+		parsed := map[string]interface{}{}
+		if err := yaml.Unmarshal(config, &parsed); err != nil {
+			return "", fmt.Errorf("unable to parse etcd configuration: %s", err)
+		}
+		for option, filename := range overrides {
+			parsed[option] = filename
+		}
+
+		var err error
+		if config, err = yaml.Marshal(parsed); err != nil {
+			return "", err
+		}
+	}
+
+	configPath := filepath.Join(dir, configFileName)
+	if err := writeFileAtomic(configPath, config); err != nil {
+		return "", err
+	}
+
+	return configPath, nil
+}
+
+// update synchronizes the remote clusters with the data of the secret. Only
+// clusters whose configuration has changed are reported to the lifecycle,
+// which causes the connection to be re-established.
+func (csw *configSecretWatcher) update(data map[string][]byte) {
+	csw.mutex.Lock()
+	defer csw.mutex.Unlock()
+
+	found := map[string]struct{}{}
+	for _, name := range clusterNames(data) {
+		found[name] = struct{}{}
+
+		fp := fingerprint(name, data)
+		if csw.fingerprints[name] == fp {
+			continue
+		}
+
+		configPath, err := csw.writeClusterConfig(name, data)
+		if err != nil {
+			log.WithError(err).WithField(fieldClusterName, name).
+				Warning("Unable to write configuration of remote cluster")
+			continue
+		}
+
+		log.WithField(fieldClusterName, name).Debug("Found configuration in secret")
+		csw.fingerprints[name] = fp
+		csw.lifecycle.add(name, configPath)
+	}
+
+	for name := range csw.fingerprints {
+		if _, ok := found[name]; ok {
+			continue
+		}
+
+		log.WithField(fieldClusterName, name).Debug("Configuration removed from secret")
+		delete(csw.fingerprints, name)
+		csw.lifecycle.remove(name)
+
+		if err := os.RemoveAll(filepath.Join(csw.stateDir, name)); err != nil {
+			log.WithError(err).WithField(fieldClusterName, name).
+				Warning("Unable to remove configuration of remote cluster")
+		}
+	}
+}
+
+func (csw *configSecretWatcher) close() {
+	close(csw.stop)
+}
@@ -25,6 +25,9 @@ import (
 	"github.com/cilium/cilium/pkg/testutils"
 
 	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func createFile(c *C, name string) {
@@ -125,3 +128,71 @@ func (s *ClusterMeshTestSuite) TestWatchConfigDirectory(c *C) {
 	expectNotExist(c, cm, "cluster3")
 
 }
+
+func (s *ClusterMeshTestSuite) TestWatchConfigSecret(c *C) {
+	skipKvstoreConnection = true
+
+	dir, err := ioutil.TempDir("", "multicluster")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      "cilium-clustermesh",
+		},
+		Data: map[string][]byte{
+			"cluster1":                    []byte("endpoints:\n- https://cluster1:2379\n"),
+			"cluster1.etcd-client.key":    []byte("key"),
+			"cluster1.etcd-client.crt":    []byte("crt"),
+			"cluster1.etcd-client-ca.crt": []byte("ca"),
+			"cluster2":                    []byte("endpoints:\n- http://cluster2:2379\n"),
+			"..data":                      []byte("ignored"),
+			"cluster3.etcd-client.key":    []byte("key without configuration"),
+		},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	cm, err := NewClusterMesh(Configuration{
+		Name:           "test-secret",
+		ConfigSecret:   "kube-system/cilium-clustermesh",
+		K8sClient:      client,
+		StateDirectory: dir,
+		NodeKeyCreator: testNodeCreator,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(cm, Not(IsNil))
+	defer cm.Close()
+
+	// wait for cluster1 and cluster2 to appear
+	c.Assert(testutils.WaitUntil(func() bool { return len(cm.clusters) == 2 }, time.Second), IsNil)
+	expectExists(c, cm, "cluster1")
+	expectExists(c, cm, "cluster2")
+	expectNotExist(c, cm, "cluster3")
+
+	// the inline TLS material is referred to by the written configuration
+	config, err := ioutil.ReadFile(cm.clusters["cluster1"].configPath)
+	c.Assert(err, IsNil)
+	c.Assert(string(config), Matches, "(?s).*key-file: "+path.Join(dir, "cluster1", "cluster1.etcd-client.key")+".*")
+	key, err := ioutil.ReadFile(path.Join(dir, "cluster1", "cluster1.etcd-client.key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(key), Equals, "key")
+
+	// changing the TLS material of cluster1 reconnects cluster1 only
+	secret.Data["cluster1.etcd-client.crt"] = []byte("new crt")
+	delete(secret.Data, "cluster2")
+	_, err = client.CoreV1().Secrets("kube-system").Update(secret)
+	c.Assert(err, IsNil)
+
+	c.Assert(testutils.WaitUntil(func() bool { return len(cm.clusters) == 1 }, time.Second), IsNil)
+	expectChange(c, cm, "cluster1")
+	expectNotExist(c, cm, "cluster2")
+
+	_, err = os.Stat(path.Join(dir, "cluster2"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+
+	// deleting the secret removes all clusters
+	err = client.CoreV1().Secrets("kube-system").Delete("cilium-clustermesh", &metav1.DeleteOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(testutils.WaitUntil(func() bool { return len(cm.clusters) == 0 }, time.Second), IsNil)
+}
@@ -27,4 +27,5 @@ const (
 	fieldConfig        = "config"
 	fieldKVStoreStatus = "kvstoreStatus"
 	fieldKVStoreErr    = "kvstoreErr"
+	fieldServiceName   = "serviceName"
 )
@@ -19,6 +19,7 @@ import (
 	"path"
 	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipcache"
@@ -27,7 +28,9 @@ import (
 	"github.com/cilium/cilium/pkg/kvstore/store"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/service"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/sirupsen/logrus"
 )
 
@@ -57,6 +60,7 @@ type remoteCluster struct {
 	// mutex protects the following variables
 	// - store
 	// - remoteNodes
+	// - remoteServices
 	// - ipCacheWatcher
 	// - remoteIdentityCache
 	mutex lock.RWMutex
@@ -64,6 +68,10 @@ type remoteCluster struct {
 	// store is the shared store representing all nodes in the remote cluster
 	remoteNodes *store.SharedStore
 
+	// remoteServices is the shared store representing all global services
+	// in the remote cluster
+	remoteServices *store.SharedStore
+
 	// ipCacheWatcher is the watcher that notifies about IP<->identity
 	// changes in the remote cluster
 	ipCacheWatcher *ipcache.IPIdentityWatcher
@@ -74,8 +82,25 @@ type remoteCluster struct {
 
 	// backend is the kvstore backend being used
 	backend kvstore.BackendOperations
+
+	// failures is the number of failed attempts to connect to, watch or
+	// synchronize with the remote cluster
+	failures int
+
+	// lastFailure is the time of the last failure
+	lastFailure time.Time
+
+	// lastSync is the time of the last successful synchronization with the
+	// remote cluster
+	lastSync time.Time
 }
 
+const (
+	// remoteSyncInterval is the interval in which the connection to the
+	// etcd of a remote cluster is verified
+	remoteSyncInterval = 30 * time.Second
+)
+
 var (
 	// skipKvstoreConnection skips the etcd connection, used for testing
 	skipKvstoreConnection bool
@@ -99,70 +124,147 @@ func (rc *remoteCluster) getLogger() *logrus.Entry {
 	})
 }
 
-func (rc *remoteCluster) restartRemoteConnection() {
-	rc.controllers.UpdateController(rc.remoteConnectionControllerName,
-		controller.ControllerParams{
-			DoFunc: func() error {
-				backend, err := kvstore.NewClient(kvstore.EtcdBackendName,
-					map[string]string{
-						kvstore.EtcdOptionConfig: rc.configPath,
-					})
-				if err != nil {
-					return err
-				}
+// connect establishes the connection to the etcd of the remote cluster and
+// starts synchronizing all resources
+func (rc *remoteCluster) connect() error {
+	backend, err := kvstore.NewClient(kvstore.EtcdBackendName,
+		map[string]string{
+			kvstore.EtcdOptionConfig: rc.configPath,
+		},
+		&kvstore.ExtraOptions{
+			WatchErrorHandler: func(err error) {
+				rc.recordFailure()
+			},
+		})
+	if err != nil {
+		return err
+	}
 
-				remoteNodes, err := store.JoinSharedStore(store.Configuration{
-					Prefix:                  path.Join(node.NodeStorePrefix, rc.name),
-					KeyCreator:              rc.mesh.conf.NodeKeyCreator,
-					SynchronizationInterval: time.Minute,
-					Backend:                 backend,
-				})
-				if err != nil {
-					backend.Close()
-					return err
-				}
+	remoteNodes, err := store.JoinSharedStore(store.Configuration{
+		Prefix:                  path.Join(node.NodeStorePrefix, rc.name),
+		KeyCreator:              rc.mesh.conf.NodeKeyCreator,
+		SynchronizationInterval: time.Minute,
+		Backend:                 backend,
+	})
+	if err != nil {
+		backend.Close()
+		return err
+	}
 
-				ipCacheWatcher := ipcache.NewIPIdentityWatcher(backend)
-				go ipCacheWatcher.Watch()
+	remoteServices, err := store.JoinSharedStore(store.Configuration{
+		Prefix:                  path.Join(service.ServiceStorePrefix, rc.name),
+		KeyCreator:              rc.serviceKeyCreator(),
+		SynchronizationInterval: time.Minute,
+		Backend:                 backend,
+	})
+	if err != nil {
+		remoteNodes.Close()
+		backend.Close()
+		return err
+	}
 
-				remoteIdentityCache := identity.WatchRemoteIdentities(backend)
+	ipCacheWatcher := ipcache.NewIPIdentityWatcher(backend)
+	go ipCacheWatcher.Watch()
 
-				rc.mutex.Lock()
-				rc.remoteNodes = remoteNodes
-				rc.backend = backend
-				rc.ipCacheWatcher = ipCacheWatcher
-				rc.remoteIdentityCache = remoteIdentityCache
-				rc.mutex.Unlock()
+	remoteIdentityCache := identity.WatchRemoteIdentities(backend)
 
-				rc.getLogger().Info("Established connection to remote etcd")
+	rc.mutex.Lock()
+	rc.remoteNodes = remoteNodes
+	rc.remoteServices = remoteServices
+	rc.backend = backend
+	rc.ipCacheWatcher = ipCacheWatcher
+	rc.remoteIdentityCache = remoteIdentityCache
+	rc.lastSync = time.Now()
+	rc.mutex.Unlock()
 
-				return nil
-			},
-			StopFunc: func() error {
-				rc.mutex.Lock()
-				if rc.ipCacheWatcher != nil {
-					rc.ipCacheWatcher.Close()
-				}
+	rc.getLogger().Info("Established connection to remote etcd")
 
-				if rc.remoteNodes != nil {
-					rc.remoteNodes.Close()
-				}
-				if rc.backend != nil {
-					rc.backend.Close()
-				}
-				if rc.remoteIdentityCache != nil {
-					rc.remoteIdentityCache.Close()
-				}
-				rc.mutex.Unlock()
+	return nil
+}
+
+// disconnect closes the connection to the etcd of the remote cluster and
+// releases all resources learned from it
+func (rc *remoteCluster) disconnect() {
+	rc.mutex.Lock()
+	if rc.ipCacheWatcher != nil {
+		rc.ipCacheWatcher.Close()
+	}
 
-				rc.getLogger().Info("All resources of remote cluster cleaned up")
+	if rc.remoteNodes != nil {
+		rc.remoteNodes.Close()
+	}
+	if rc.remoteServices != nil {
+		rc.remoteServices.Close()
+	}
+	if rc.backend != nil {
+		rc.backend.Close()
+	}
+	if rc.remoteIdentityCache != nil {
+		rc.remoteIdentityCache.Close()
+	}
+	rc.mutex.Unlock()
 
+	// Closing the shared store does not remove the services learned so
+	// far, withdraw them explicitly
+	rc.mesh.globalServices.onClusterDelete(rc.name)
+
+	rc.getLogger().Info("All resources of remote cluster cleaned up")
+}
+
+func (rc *remoteCluster) restartRemoteConnection() {
+	rc.controllers.UpdateController(rc.remoteConnectionControllerName,
+		controller.ControllerParams{
+			DoFunc: func() error {
+				err := rc.connect()
+				if err != nil {
+					rc.recordFailure()
+				}
+				return err
+			},
+			StopFunc: func() error {
+				rc.disconnect()
 				return nil
 			},
 		},
 	)
 }
 
+// recordFailure accounts for a failed attempt to connect to, watch or
+// synchronize with the remote cluster
+func (rc *remoteCluster) recordFailure() {
+	rc.mutex.Lock()
+	rc.failures++
+	rc.lastFailure = time.Now()
+	rc.mutex.Unlock()
+}
+
+// synchronize verifies that the connection to the etcd of the remote cluster
+// is healthy and, if so, records the time of the successful synchronization.
+// The watchers of the remote cluster keep synchronizing on their own, this
+// only accounts for the state of the connection.
+func (rc *remoteCluster) synchronize() error {
+	rc.mutex.RLock()
+	backend := rc.backend
+	rc.mutex.RUnlock()
+
+	// The connection has not been established yet, failures are accounted
+	// for by the controller in restartRemoteConnection
+	if backend == nil {
+		return nil
+	}
+
+	if _, err := backend.Status(); err != nil {
+		rc.recordFailure()
+		return err
+	}
+
+	rc.mutex.Lock()
+	rc.lastSync = time.Now()
+	rc.mutex.Unlock()
+
+	return nil
+}
+
 func (rc *remoteCluster) onInsert() {
 	rc.getLogger().Info("New remote cluster discovered")
 
@@ -173,6 +275,13 @@ func (rc *remoteCluster) onInsert() {
 	rc.remoteConnectionControllerName = fmt.Sprintf("remote-etcd-%s", rc.name)
 	rc.restartRemoteConnection()
 
+	rc.controllers.UpdateController(fmt.Sprintf("remote-etcd-sync-%s", rc.name),
+		controller.ControllerParams{
+			DoFunc:      rc.synchronize,
+			RunInterval: remoteSyncInterval,
+		},
+	)
+
 	go func() {
 		for {
 			val := <-rc.changed
@@ -198,5 +307,52 @@ func (rc *remoteCluster) isReady() bool {
 	rc.mutex.RLock()
 	defer rc.mutex.RUnlock()
 
+	return rc.isReadyLocked()
+}
+
+func (rc *remoteCluster) isReadyLocked() bool {
 	return rc.backend != nil && rc.remoteNodes != nil && rc.ipCacheWatcher != nil
 }
+
+// status returns the status of the remote cluster
+func (rc *remoteCluster) status() *models.RemoteCluster {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	// This can happen when the controller in restartRemoteConnection is
+	// not yet able to establish a connection to the remote cluster
+	var backendStatus string
+	if rc.backend != nil {
+		backendStatus, _ = rc.backend.Status()
+	} else {
+		backendStatus = "Waiting for initial connection to be established"
+	}
+
+	status := &models.RemoteCluster{
+		Name:        rc.name,
+		Ready:       rc.isReadyLocked(),
+		Status:      backendStatus,
+		NumFailures: int64(rc.failures),
+	}
+
+	if !rc.lastFailure.IsZero() {
+		status.LastFailure = strfmt.DateTime(rc.lastFailure)
+	}
+	if !rc.lastSync.IsZero() {
+		status.LastSync = strfmt.DateTime(rc.lastSync)
+	}
+	if rc.remoteNodes != nil {
+		status.NumNodes = int64(rc.remoteNodes.NumEntries())
+	}
+	if rc.remoteServices != nil {
+		status.NumSharedServices = int64(rc.remoteServices.NumEntries())
+	}
+	if rc.remoteIdentityCache != nil {
+		status.NumIdentities = int64(rc.remoteIdentityCache.NumEntries())
+	}
+	if rc.ipCacheWatcher != nil {
+		status.NumIpcacheEntries = int64(rc.ipCacheWatcher.NumEntries())
+	}
+
+	return status
+}
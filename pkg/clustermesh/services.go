@@ -0,0 +1,224 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustermesh
+
+import (
+	"github.com/cilium/cilium/pkg/kvstore/store"
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ServiceMerger is the interface to be implemented by the owner of the local
+// services. The functions must merge the backends of global services learned
+// from remote clusters with the local services.
+type ServiceMerger interface {
+	// MergeExternalServiceUpdate is called when the backends of a global
+	// service in a remote cluster have been added or updated
+	MergeExternalServiceUpdate(service *service.ClusterService)
+
+	// MergeExternalServiceDelete is called when a global service of a
+	// remote cluster has been removed or the remote cluster has
+	// disconnected
+	MergeExternalServiceDelete(service *service.ClusterService)
+}
+
+// globalServiceCache is the cache of all global services learned from
+// remote clusters
+type globalServiceCache struct {
+	// merger is notified of all changes, it may be nil
+	merger ServiceMerger
+
+	// mutex protects byCluster and serializes calls to the merger
+	mutex lock.Mutex
+
+	// byCluster contains the services of each remote cluster indexed by
+	// cluster name, service name and name of the node sharing the service
+	byCluster map[string]map[loadbalancer.K8sServiceNamespace]map[string]*service.ClusterService
+}
+
+func newGlobalServiceCache(merger ServiceMerger) *globalServiceCache {
+	return &globalServiceCache{
+		merger:    merger,
+		byCluster: map[string]map[loadbalancer.K8sServiceNamespace]map[string]*service.ClusterService{},
+	}
+}
+
+// mergeNodes returns the union of the backends of a service as shared by
+// all nodes of a remote cluster
+func mergeNodes(cluster string, svc loadbalancer.K8sServiceNamespace, byNode map[string]*service.ClusterService) *service.ClusterService {
+	merged := service.NewClusterService(cluster, "", svc, nil)
+	for _, nodeService := range byNode {
+		for ip := range nodeService.Backends {
+			merged.Backends[ip] = true
+		}
+		for name, port := range nodeService.Ports {
+			merged.Ports[name] = port
+		}
+	}
+	return merged
+}
+
+func (c *globalServiceCache) onUpdate(svc *service.ClusterService) {
+	// The shared store reuses the key instance for subsequent updates,
+	// keep a copy to hand out
+	cached := service.NewClusterService(svc.Cluster, svc.Node, svc.NamespacedName(), svc.Endpoint())
+
+	log.WithFields(logrus.Fields{
+		fieldClusterName: cached.Cluster,
+		fieldServiceName: cached.GetKeyName(),
+	}).Debug("Received update of global service")
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	services, ok := c.byCluster[cached.Cluster]
+	if !ok {
+		services = map[loadbalancer.K8sServiceNamespace]map[string]*service.ClusterService{}
+		c.byCluster[cached.Cluster] = services
+	}
+
+	name := cached.NamespacedName()
+	byNode, ok := services[name]
+	if !ok {
+		byNode = map[string]*service.ClusterService{}
+		services[name] = byNode
+	}
+	byNode[cached.Node] = cached
+
+	if c.merger != nil {
+		c.merger.MergeExternalServiceUpdate(mergeNodes(cached.Cluster, name, byNode))
+	}
+}
+
+func (c *globalServiceCache) onDelete(svc *service.ClusterService) {
+	log.WithFields(logrus.Fields{
+		fieldClusterName: svc.Cluster,
+		fieldServiceName: svc.GetKeyName(),
+	}).Debug("Received deletion of global service")
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	services, ok := c.byCluster[svc.Cluster]
+	if !ok {
+		return
+	}
+
+	name := svc.NamespacedName()
+	byNode, ok := services[name]
+	if !ok {
+		return
+	}
+
+	if _, ok := byNode[svc.Node]; !ok {
+		return
+	}
+
+	delete(byNode, svc.Node)
+
+	// The service remains available as long as any node of the remote
+	// cluster is still sharing it
+	if len(byNode) > 0 {
+		if c.merger != nil {
+			c.merger.MergeExternalServiceUpdate(mergeNodes(svc.Cluster, name, byNode))
+		}
+		return
+	}
+
+	delete(services, name)
+	if len(services) == 0 {
+		delete(c.byCluster, svc.Cluster)
+	}
+
+	if c.merger != nil {
+		c.merger.MergeExternalServiceDelete(service.NewClusterService(svc.Cluster, "", name, nil))
+	}
+}
+
+// onClusterDelete removes all services of a remote cluster, this is called
+// when the connection to the remote cluster is closed
+func (c *globalServiceCache) onClusterDelete(cluster string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for name := range c.byCluster[cluster] {
+		if c.merger != nil {
+			c.merger.MergeExternalServiceDelete(service.NewClusterService(cluster, "", name, nil))
+		}
+	}
+
+	delete(c.byCluster, cluster)
+}
+
+// numServices returns the number of global services learned from the given
+// remote cluster
+func (c *globalServiceCache) numServices(cluster string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.byCluster[cluster])
+}
+
+// numGlobalServices returns the number of distinct global services learned
+// from all remote clusters
+func (c *globalServiceCache) numGlobalServices() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	services := map[loadbalancer.K8sServiceNamespace]struct{}{}
+	for _, byName := range c.byCluster {
+		for name := range byName {
+			services[name] = struct{}{}
+		}
+	}
+
+	return len(services)
+}
+
+// remoteService is a global service as found in the shared service store of
+// a remote cluster
+type remoteService struct {
+	service.ClusterService
+
+	// cluster is the remote cluster the service store belongs to
+	cluster *remoteCluster
+}
+
+// OnUpdate is called when the service has been updated in the shared store
+// of the remote cluster
+func (s *remoteService) OnUpdate() {
+	// Never trust the cluster name found in the remote kvstore, a remote
+	// cluster can only provide backends for itself
+	s.Cluster = s.cluster.name
+	s.cluster.mesh.globalServices.onUpdate(&s.ClusterService)
+}
+
+// OnDelete is called when the service has been deleted from the shared store
+// of the remote cluster
+func (s *remoteService) OnDelete() {
+	s.Cluster = s.cluster.name
+	s.cluster.mesh.globalServices.onDelete(&s.ClusterService)
+}
+
+// serviceKeyCreator returns a key creator for the shared service store of
+// the remote cluster
+func (rc *remoteCluster) serviceKeyCreator() store.KeyCreator {
+	return func() store.Key {
+		return &remoteService{cluster: rc}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package clustermesh
+
+import (
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/service"
+
+	. "gopkg.in/check.v1"
+)
+
+type fakeServiceMerger struct {
+	updated  map[string]int
+	deleted  map[string]int
+	backends map[string]int
+}
+
+func newFakeServiceMerger() *fakeServiceMerger {
+	return &fakeServiceMerger{
+		updated:  map[string]int{},
+		deleted:  map[string]int{},
+		backends: map[string]int{},
+	}
+}
+
+func (f *fakeServiceMerger) MergeExternalServiceUpdate(svc *service.ClusterService) {
+	f.updated[svc.GetKeyName()]++
+	f.backends[svc.GetKeyName()] = len(svc.Backends)
+}
+
+func (f *fakeServiceMerger) MergeExternalServiceDelete(svc *service.ClusterService) {
+	f.deleted[svc.GetKeyName()]++
+}
+
+func (s *ClusterMeshTestSuite) TestGlobalServiceCache(c *C) {
+	merger := newFakeServiceMerger()
+	cm := &ClusterMesh{globalServices: newGlobalServiceCache(merger)}
+	rc := cm.newRemoteCluster("cluster1", "")
+
+	ep := loadbalancer.NewK8sServiceEndpoint()
+	ep.BEIPs["10.0.0.1"] = true
+	ep.Ports["http"] = loadbalancer.NewL4Addr(loadbalancer.TCP, 80)
+
+	foo := rc.serviceKeyCreator()().(*remoteService)
+	data, err := service.NewClusterService("cluster2", "node1", loadbalancer.K8sServiceNamespace{Namespace: "default", ServiceName: "foo"}, ep).Marshal()
+	c.Assert(err, IsNil)
+	c.Assert(foo.Unmarshal(data), IsNil)
+
+	// the cluster name is always enforced to the name of the remote cluster
+	foo.OnUpdate()
+	c.Assert(merger.updated["cluster1/default/foo"], Equals, 1)
+	c.Assert(cm.globalServices.numServices("cluster1"), Equals, 1)
+	c.Assert(cm.globalServices.numServices("cluster2"), Equals, 0)
+
+	bar := rc.serviceKeyCreator()().(*remoteService)
+	bar.ClusterService = *service.NewClusterService("cluster1", "node1", loadbalancer.K8sServiceNamespace{Namespace: "default", ServiceName: "bar"}, ep)
+	bar.OnUpdate()
+	c.Assert(cm.globalServices.numServices("cluster1"), Equals, 2)
+
+	// the keys of all nodes sharing the same service are merged
+	ep2 := loadbalancer.NewK8sServiceEndpoint()
+	ep2.BEIPs["10.0.0.2"] = true
+	ep2.Ports["http"] = loadbalancer.NewL4Addr(loadbalancer.TCP, 80)
+	foo2 := rc.serviceKeyCreator()().(*remoteService)
+	foo2.ClusterService = *service.NewClusterService("cluster1", "node2", loadbalancer.K8sServiceNamespace{Namespace: "default", ServiceName: "foo"}, ep2)
+	foo2.OnUpdate()
+	c.Assert(merger.updated["cluster1/default/foo"], Equals, 2)
+	c.Assert(merger.backends["cluster1/default/foo"], Equals, 2)
+	c.Assert(cm.globalServices.numServices("cluster1"), Equals, 2)
+	c.Assert(cm.globalServices.numGlobalServices(), Equals, 2)
+
+	// the service remains available while any node is sharing it
+	foo.OnDelete()
+	c.Assert(merger.deleted["cluster1/default/foo"], Equals, 0)
+	c.Assert(merger.updated["cluster1/default/foo"], Equals, 3)
+	c.Assert(merger.backends["cluster1/default/foo"], Equals, 1)
+	c.Assert(cm.globalServices.numServices("cluster1"), Equals, 2)
+
+	foo2.OnDelete()
+	c.Assert(merger.deleted["cluster1/default/foo"], Equals, 1)
+	c.Assert(cm.globalServices.numServices("cluster1"), Equals, 1)
+
+	// deleting an unknown service is ignored
+	foo.OnDelete()
+	c.Assert(merger.deleted["cluster1/default/foo"], Equals, 1)
+
+	cm.globalServices.onClusterDelete("cluster1")
+	c.Assert(merger.deleted["cluster1/default/bar"], Equals, 1)
+	c.Assert(cm.globalServices.numServices("cluster1"), Equals, 0)
+}
@@ -0,0 +1,594 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// reqID is the request ID of all IPsec states and policies managed by the
+// agent. It is used to identify stale entries left behind by a previous run.
+const reqID = 1
+
+// Peer is a node the traffic is encrypted to
+type Peer struct {
+	// Name is the full name of the node
+	Name string
+
+	// LocalIP is the IP of the local node used as source of the
+	// encrypted traffic
+	LocalIP net.IP
+
+	// RemoteIP is the IP of the peer used as destination of the encrypted
+	// traffic
+	RemoteIP net.IP
+
+	// LocalCIDR and RemoteCIDR are the allocation CIDRs of the local node
+	// and of the peer. If set, the traffic between both CIDRs is
+	// encrypted. Otherwise the tunnel traffic between LocalIP and
+	// RemoteIP is encrypted.
+	LocalCIDR  *net.IPNet
+	RemoteCIDR *net.IPNet
+
+	// KeyIndex is the index of the key the peer has announced, 0 if the
+	// peer does not encrypt its traffic
+	KeyIndex uint8
+
+	// IPv6 is true if the IPv6 traffic exchanged with the peer is
+	// encrypted. Each address family is configured as separate peer.
+	IPv6 bool
+}
+
+// peerKey identifies the configuration of an address family of a peer
+type peerKey struct {
+	name string
+	ipv6 bool
+}
+
+func (p *Peer) key() peerKey {
+	return peerKey{name: p.Name, ipv6: p.IPv6}
+}
+
+// family returns the name of the address family of the peer
+func (p *Peer) family() string {
+	if p.IPv6 {
+		return "IPv6"
+	}
+	return "IPv4"
+}
+
+// selector selects the traffic matched by an IPsec policy
+type selector struct {
+	src     *net.IPNet
+	dst     *net.IPNet
+	proto   netlink.Proto
+	dstPort int
+}
+
+func hostNet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// tunnelPort returns the UDP port of the tunnel device, 0 if tunneling is
+// disabled
+func tunnelPort() int {
+	switch option.Config.Tunnel {
+	case option.TunnelVXLAN:
+		return defaults.TunnelPortVXLAN
+	case option.TunnelGeneve:
+		return defaults.TunnelPortGeneve
+	}
+	return 0
+}
+
+// selectors returns the selectors of the egress and ingress traffic
+// exchanged with the peer
+func (p *Peer) selectors() (out, in selector, err error) {
+	if p.LocalIP == nil || p.RemoteIP == nil {
+		err = fmt.Errorf("%s address of node %s unknown", p.family(), p.Name)
+		return
+	}
+
+	if p.LocalCIDR != nil && p.RemoteCIDR != nil {
+		out = selector{src: p.LocalCIDR, dst: p.RemoteCIDR}
+		in = selector{src: p.RemoteCIDR, dst: p.LocalCIDR}
+		return
+	}
+
+	port := tunnelPort()
+	if port == 0 {
+		err = fmt.Errorf("%s allocation CIDR of node %s unknown", p.family(), p.Name)
+		return
+	}
+
+	udp := netlink.Proto(syscall.IPPROTO_UDP)
+	out = selector{src: hostNet(p.LocalIP), dst: hostNet(p.RemoteIP), proto: udp, dstPort: port}
+	in = selector{src: hostNet(p.RemoteIP), dst: hostNet(p.LocalIP), proto: udp, dstPort: port}
+	return
+}
+
+// peerState is the IPsec configuration installed for a peer
+type peerState struct {
+	Peer
+
+	// outKey is the key used to encrypt traffic to the peer, nil if the
+	// traffic is not encrypted
+	outKey *Key
+
+	// inKeys are the keys accepted for traffic from the peer
+	inKeys []*Key
+
+	// err is the error which occurred while installing the IPsec
+	// configuration, nil on success
+	err error
+
+	// plaintext is true if the traffic to the peer is not encrypted
+	// although a key is loaded on the local node, as the peer has not
+	// announced a key
+	plaintext bool
+}
+
+var (
+	// mutex protects all variables below
+	mutex lock.Mutex
+
+	// currentKey is the key announced by the local node
+	currentKey *Key
+
+	// previousKey is the key replaced by currentKey. It remains in use
+	// until all peers have announced currentKey.
+	previousKey *Key
+
+	// peers are all peers indexed by name and address family
+	peers = map[peerKey]*peerState{}
+)
+
+// selectOutKey returns the key used to encrypt traffic to a peer which has
+// announced the key index remote. Traffic is only encrypted if both nodes
+// have a key loaded, a peer announcing the key index 0 is reached in
+// plaintext. While a key is being rotated, the previous key is used
+// for peers which have not yet loaded the current key. Peers which already
+// announce a key unknown to the local node still accept the current key as
+// their previous key.
+func selectOutKey(current, previous *Key, remote uint8) *Key {
+	switch {
+	case current == nil || remote == 0:
+		return nil
+	case previous != nil && previous.SPI == remote:
+		return previous
+	default:
+		return current
+	}
+}
+
+func (ps *peerState) getLogger() *logrus.Entry {
+	return log.WithFields(logrus.Fields{
+		fieldPeer:     ps.Name,
+		"family":      ps.family(),
+		"remoteIP":    ps.RemoteIP,
+		fieldKeyIndex: ps.KeyIndex,
+	})
+}
+
+func newState(src, dst net.IP, key *Key) *netlink.XfrmState {
+	return &netlink.XfrmState{
+		Src:          src,
+		Dst:          dst,
+		Proto:        netlink.XFRM_PROTO_ESP,
+		Mode:         netlink.XFRM_MODE_TUNNEL,
+		Spi:          int(key.SPI),
+		Reqid:        reqID,
+		ReplayWindow: 32,
+		Auth:         key.Auth,
+		Crypt:        key.Crypt,
+		Aead:         key.Aead,
+	}
+}
+
+func newPolicy(sel selector, dir netlink.Dir, src, dst net.IP, spi uint8) *netlink.XfrmPolicy {
+	return &netlink.XfrmPolicy{
+		Src:     sel.src,
+		Dst:     sel.dst,
+		Proto:   sel.proto,
+		DstPort: sel.dstPort,
+		Dir:     dir,
+		Tmpls: []netlink.XfrmPolicyTmpl{
+			{
+				Src:   src,
+				Dst:   dst,
+				Proto: netlink.XFRM_PROTO_ESP,
+				Mode:  netlink.XFRM_MODE_TUNNEL,
+				Spi:   int(spi),
+				Reqid: reqID,
+			},
+		},
+	}
+}
+
+// upsertState creates the IPsec state or updates it if it already exists
+func upsertState(state *netlink.XfrmState) error {
+	err := netlink.XfrmStateAdd(state)
+	if err == syscall.EEXIST {
+		err = netlink.XfrmStateUpdate(state)
+	}
+	return err
+}
+
+func deleteState(src, dst net.IP, spi uint8) {
+	state := &netlink.XfrmState{
+		Src:   src,
+		Dst:   dst,
+		Proto: netlink.XFRM_PROTO_ESP,
+		Spi:   int(spi),
+	}
+	if err := netlink.XfrmStateDel(state); err != nil {
+		log.WithError(err).WithField(fieldKeyIndex, spi).Debug("Unable to delete IPsec state")
+	}
+}
+
+func deletePolicy(sel selector, dir netlink.Dir) {
+	policy := &netlink.XfrmPolicy{
+		Src:     sel.src,
+		Dst:     sel.dst,
+		Proto:   sel.proto,
+		DstPort: sel.dstPort,
+		Dir:     dir,
+	}
+	if err := netlink.XfrmPolicyDel(policy); err != nil {
+		log.WithError(err).WithField("dir", dir).Debug("Unable to delete IPsec policy")
+	}
+}
+
+// isPlaintext returns true if ps is known and reached in plaintext
+func (ps *peerState) isPlaintext() bool {
+	return ps != nil && ps.plaintext
+}
+
+// hasFwdPolicy returns true if forwarded traffic from the peer must be
+// matched. This is only required if the traffic between the allocation
+// CIDRs is encrypted as it is routed to the endpoints.
+func (ps *peerState) hasFwdPolicy() bool {
+	return ps.LocalCIDR != nil && ps.RemoteCIDR != nil
+}
+
+// install installs the IPsec states and policies to encrypt the traffic to
+// the peer with outKey and to decrypt the traffic from the peer with any of
+// inKeys. States and policies no longer required are removed.
+func (ps *peerState) install(outKey *Key, inKeys []*Key) error {
+	out, in, err := ps.selectors()
+	if err != nil {
+		return err
+	}
+
+	if outKey == nil {
+		ps.remove()
+		return nil
+	}
+
+	for _, key := range inKeys {
+		if err := upsertState(newState(ps.RemoteIP, ps.LocalIP, key)); err != nil {
+			return fmt.Errorf("unable to install ingress IPsec state for key %d: %s", key.SPI, err)
+		}
+	}
+
+	if err := upsertState(newState(ps.LocalIP, ps.RemoteIP, outKey)); err != nil {
+		return fmt.Errorf("unable to install egress IPsec state for key %d: %s", outKey.SPI, err)
+	}
+
+	// The ingress templates do not restrict the SPI so that all
+	// installed ingress states are accepted
+	policies := []*netlink.XfrmPolicy{
+		newPolicy(out, netlink.XFRM_DIR_OUT, ps.LocalIP, ps.RemoteIP, outKey.SPI),
+		newPolicy(in, netlink.XFRM_DIR_IN, ps.RemoteIP, ps.LocalIP, 0),
+	}
+	if ps.hasFwdPolicy() {
+		policies = append(policies, newPolicy(in, netlink.XFRM_DIR_FWD, ps.RemoteIP, ps.LocalIP, 0))
+	}
+	for _, policy := range policies {
+		if err := netlink.XfrmPolicyUpdate(policy); err != nil {
+			return fmt.Errorf("unable to install IPsec policy %s: %s", policy.Dir, err)
+		}
+	}
+
+	// Remove the states of keys no longer in use once the policies refer
+	// to the new states
+	if ps.outKey != nil && ps.outKey.SPI != outKey.SPI {
+		deleteState(ps.LocalIP, ps.RemoteIP, ps.outKey.SPI)
+	}
+	for _, old := range ps.inKeys {
+		if !containsKey(inKeys, old.SPI) {
+			deleteState(ps.RemoteIP, ps.LocalIP, old.SPI)
+		}
+	}
+
+	ps.outKey = outKey
+	ps.inKeys = inKeys
+	return nil
+}
+
+// remove removes all IPsec states and policies of the peer
+func (ps *peerState) remove() {
+	if ps.outKey == nil && len(ps.inKeys) == 0 {
+		return
+	}
+
+	if out, in, err := ps.selectors(); err == nil {
+		deletePolicy(out, netlink.XFRM_DIR_OUT)
+		deletePolicy(in, netlink.XFRM_DIR_IN)
+		if ps.hasFwdPolicy() {
+			deletePolicy(in, netlink.XFRM_DIR_FWD)
+		}
+	}
+
+	if ps.outKey != nil {
+		deleteState(ps.LocalIP, ps.RemoteIP, ps.outKey.SPI)
+	}
+	for _, key := range ps.inKeys {
+		deleteState(ps.RemoteIP, ps.LocalIP, key.SPI)
+	}
+
+	ps.outKey = nil
+	ps.inKeys = nil
+}
+
+func containsKey(keys []*Key, spi uint8) bool {
+	for _, key := range keys {
+		if key.SPI == spi {
+			return true
+		}
+	}
+	return false
+}
+
+// syncPeer installs the IPsec configuration of the peer matching the keys
+// loaded. Must be called with mutex held.
+func syncPeer(ps *peerState) {
+	outKey := selectOutKey(currentKey, previousKey, ps.KeyIndex)
+
+	var inKeys []*Key
+	if outKey != nil {
+		inKeys = append(inKeys, currentKey)
+		if previousKey != nil {
+			inKeys = append(inKeys, previousKey)
+		}
+	}
+
+	if ps.err = ps.install(outKey, inKeys); ps.err != nil {
+		ps.getLogger().WithError(ps.err).Warning("Unable to configure IPsec encryption")
+		return
+	}
+
+	plaintext := currentKey != nil && outKey == nil
+	if plaintext && !ps.plaintext {
+		ps.getLogger().Warning("Peer has not announced an IPsec key, traffic to the peer is not encrypted")
+	}
+	ps.plaintext = plaintext
+
+	ps.getLogger().Debug("Configured IPsec encryption")
+}
+
+// releasePreviousKey stops accepting the previous key once all peers have
+// moved away from it. Must be called with mutex held.
+func releasePreviousKey() {
+	if previousKey == nil {
+		return
+	}
+
+	for _, ps := range peers {
+		if ps.KeyIndex == previousKey.SPI {
+			return
+		}
+	}
+
+	log.WithField(fieldKeyIndex, previousKey.SPI).Info("Releasing previous IPsec key")
+	previousKey = nil
+	for _, ps := range peers {
+		syncPeer(ps)
+	}
+}
+
+// SetKey loads key as the key announced by the local node. If the index of
+// the key differs from the key currently loaded, the current key becomes the
+// previous key and remains accepted until all peers have loaded the new key.
+func SetKey(key *Key) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scopedLog := log.WithField(fieldKeyIndex, key.SPI)
+
+	switch {
+	case currentKey == nil:
+		scopedLog.Info("Loaded IPsec key")
+	case reflect.DeepEqual(currentKey, key):
+		return
+	case currentKey.SPI == key.SPI:
+		scopedLog.Warning("IPsec key changed without changing the key index, traffic to other nodes may be interrupted until they have loaded the new key")
+	default:
+		scopedLog.WithField("previousKeyIndex", currentKey.SPI).Info("Rotating IPsec key")
+		previousKey = currentKey
+	}
+
+	currentKey = key
+	for _, ps := range peers {
+		syncPeer(ps)
+	}
+	releasePreviousKey()
+}
+
+// UpsertPeer installs or updates the IPsec configuration to encrypt the
+// traffic exchanged with the peer
+func UpsertPeer(peer Peer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	ps, ok := peers[peer.key()]
+	if ok && !ps.sameAddressing(&peer) {
+		ps.remove()
+		ok = false
+	}
+	if !ok {
+		ps = &peerState{}
+		peers[peer.key()] = ps
+	}
+
+	ps.Peer = peer
+	syncPeer(ps)
+	releasePreviousKey()
+}
+
+func ipNetEqual(a, b *net.IPNet) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
+
+// sameAddressing returns true if the IPsec states and policies installed for
+// ps also apply to peer
+func (ps *peerState) sameAddressing(peer *Peer) bool {
+	return ps.LocalIP.Equal(peer.LocalIP) &&
+		ps.RemoteIP.Equal(peer.RemoteIP) &&
+		ipNetEqual(ps.LocalCIDR, peer.LocalCIDR) &&
+		ipNetEqual(ps.RemoteCIDR, peer.RemoteCIDR)
+}
+
+// DeletePeer removes the IPsec configuration of all address families of the
+// peer with the given name
+func DeletePeer(name string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	deleted := false
+	for key, ps := range peers {
+		if key.name == name {
+			ps.remove()
+			delete(peers, key)
+			deleted = true
+		}
+	}
+
+	if deleted {
+		releasePreviousKey()
+	}
+}
+
+// RemoveStaleEntries removes all IPsec states and policies installed by a
+// previous run of the agent
+func RemoveStaleEntries() error {
+	policies, err := netlink.XfrmPolicyList(netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if len(policy.Tmpls) == 1 && policy.Tmpls[0].Reqid == reqID {
+			if err := netlink.XfrmPolicyDel(&policy); err != nil {
+				log.WithError(err).Debug("Unable to delete stale IPsec policy")
+			}
+		}
+	}
+
+	states, err := netlink.XfrmStateList(netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+	for _, state := range states {
+		if state.Reqid == reqID {
+			if err := netlink.XfrmStateDel(&state); err != nil {
+				log.WithError(err).Debug("Unable to delete stale IPsec state")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Status returns the status of the IPsec encryption
+func Status() *models.EncryptionStatus {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	status := &models.IPSecStatus{
+		Peers: []*models.IPSecPeer{},
+	}
+	if currentKey != nil {
+		status.KeyIndex = int64(currentKey.SPI)
+	}
+	if previousKey != nil {
+		status.PreviousKeyIndex = int64(previousKey.SPI)
+	}
+
+	var (
+		failed    []error
+		plaintext []string
+	)
+	for key, ps := range peers {
+		if ps.err != nil {
+			failed = append(failed, ps.err)
+		}
+		// Report each plaintext node once for all address families
+		if ps.plaintext && (!key.ipv6 || !peers[peerKey{name: key.name}].isPlaintext()) {
+			plaintext = append(plaintext, ps.Name)
+		}
+
+		peer := &models.IPSecPeer{
+			Name:           ps.Name,
+			IP:             ps.RemoteIP.String(),
+			RemoteKeyIndex: int64(ps.KeyIndex),
+		}
+		if ps.outKey != nil {
+			peer.KeyIndex = int64(ps.outKey.SPI)
+		}
+		status.Peers = append(status.Peers, peer)
+	}
+	sort.Slice(status.Peers, func(i, j int) bool {
+		if status.Peers[i].Name != status.Peers[j].Name {
+			return status.Peers[i].Name < status.Peers[j].Name
+		}
+		return status.Peers[i].IP < status.Peers[j].IP
+	})
+
+	es := &models.EncryptionStatus{
+		Mode:  models.EncryptionStatusModeIPsec,
+		Ipsec: status,
+	}
+	switch {
+	case currentKey == nil:
+		es.Msg = "No IPsec key loaded"
+	case len(failed) > 0:
+		es.Msg = fmt.Sprintf("Unable to configure %d peers: %s", len(failed), failed[0])
+	case len(plaintext) > 0:
+		sort.Strings(plaintext)
+		es.Msg = fmt.Sprintf("Traffic to %d peers without IPsec key is not encrypted: %s",
+			len(plaintext), strings.Join(plaintext, ", "))
+	}
+
+	return es
+}
@@ -0,0 +1,320 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package ipsec
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/vishvananda/netlink"
+	. "gopkg.in/check.v1"
+)
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return ipnet
+}
+
+func (s *IPsecSuite) TestSelectOutKey(c *C) {
+	current := &Key{SPI: 2}
+	previous := &Key{SPI: 1}
+
+	tests := []struct {
+		name     string
+		current  *Key
+		previous *Key
+		remote   uint8
+		want     *Key
+	}{
+		{
+			name:   "no local key",
+			remote: 1,
+			want:   nil,
+		},
+		{
+			name:     "no local key after release of the previous key",
+			previous: previous,
+			remote:   1,
+			want:     nil,
+		},
+		{
+			name:    "peer without key",
+			current: current,
+			remote:  0,
+			want:    nil,
+		},
+		{
+			name:     "peer without key during rotation",
+			current:  current,
+			previous: previous,
+			remote:   0,
+			want:     nil,
+		},
+		{
+			name:    "peer announces the current key",
+			current: current,
+			remote:  2,
+			want:    current,
+		},
+		{
+			name:     "rotation, peer still announces the previous key",
+			current:  current,
+			previous: previous,
+			remote:   1,
+			want:     previous,
+		},
+		{
+			name:     "rotation, peer already announces the current key",
+			current:  current,
+			previous: previous,
+			remote:   2,
+			want:     current,
+		},
+		{
+			name:    "previous key already released",
+			current: current,
+			remote:  1,
+			want:    current,
+		},
+		{
+			name:     "peer announces a key unknown to the local node",
+			current:  current,
+			previous: previous,
+			remote:   3,
+			want:     current,
+		},
+	}
+	for _, tt := range tests {
+		got := selectOutKey(tt.current, tt.previous, tt.remote)
+		c.Assert(got, Equals, tt.want, Commentf("Test name: %q", tt.name))
+	}
+}
+
+func (s *IPsecSuite) TestSelectors(c *C) {
+	prevTunnel := option.Config.Tunnel
+	defer func() {
+		option.Config.Tunnel = prevTunnel
+	}()
+
+	localIP := net.ParseIP("192.168.0.1")
+	remoteIP := net.ParseIP("192.168.0.2")
+	localCIDR := mustParseCIDR("10.1.0.0/16")
+	remoteCIDR := mustParseCIDR("10.2.0.0/16")
+	udp := netlink.Proto(syscall.IPPROTO_UDP)
+
+	tests := []struct {
+		name    string
+		tunnel  string
+		peer    Peer
+		wantOut selector
+		wantIn  selector
+		wantErr bool
+	}{
+		{
+			name:   "allocation CIDRs",
+			tunnel: option.TunnelDisabled,
+			peer: Peer{
+				LocalIP:    localIP,
+				RemoteIP:   remoteIP,
+				LocalCIDR:  localCIDR,
+				RemoteCIDR: remoteCIDR,
+			},
+			wantOut: selector{src: localCIDR, dst: remoteCIDR},
+			wantIn:  selector{src: remoteCIDR, dst: localCIDR},
+		},
+		{
+			name:   "vxlan tunnel",
+			tunnel: option.TunnelVXLAN,
+			peer: Peer{
+				LocalIP:  localIP,
+				RemoteIP: remoteIP,
+			},
+			wantOut: selector{src: hostNet(localIP), dst: hostNet(remoteIP), proto: udp, dstPort: defaults.TunnelPortVXLAN},
+			wantIn:  selector{src: hostNet(remoteIP), dst: hostNet(localIP), proto: udp, dstPort: defaults.TunnelPortVXLAN},
+		},
+		{
+			name:   "geneve tunnel",
+			tunnel: option.TunnelGeneve,
+			peer: Peer{
+				LocalIP:  localIP,
+				RemoteIP: remoteIP,
+			},
+			wantOut: selector{src: hostNet(localIP), dst: hostNet(remoteIP), proto: udp, dstPort: defaults.TunnelPortGeneve},
+			wantIn:  selector{src: hostNet(remoteIP), dst: hostNet(localIP), proto: udp, dstPort: defaults.TunnelPortGeneve},
+		},
+		{
+			name:   "IPv6 allocation CIDRs",
+			tunnel: option.TunnelDisabled,
+			peer: Peer{
+				LocalIP:    net.ParseIP("f00d::1"),
+				RemoteIP:   net.ParseIP("f00d::2"),
+				LocalCIDR:  mustParseCIDR("f00d:0:0:0:1::/96"),
+				RemoteCIDR: mustParseCIDR("f00d:0:0:0:2::/96"),
+				IPv6:       true,
+			},
+			wantOut: selector{src: mustParseCIDR("f00d:0:0:0:1::/96"), dst: mustParseCIDR("f00d:0:0:0:2::/96")},
+			wantIn:  selector{src: mustParseCIDR("f00d:0:0:0:2::/96"), dst: mustParseCIDR("f00d:0:0:0:1::/96")},
+		},
+		{
+			name:   "IPv6 address of the peer unknown",
+			tunnel: option.TunnelDisabled,
+			peer: Peer{
+				LocalIP:    net.ParseIP("f00d::1"),
+				LocalCIDR:  mustParseCIDR("f00d:0:0:0:1::/96"),
+				RemoteCIDR: mustParseCIDR("f00d:0:0:0:2::/96"),
+				IPv6:       true,
+			},
+			wantErr: true,
+		},
+		{
+			name:   "allocation CIDR of the peer unknown",
+			tunnel: option.TunnelDisabled,
+			peer: Peer{
+				LocalIP:   localIP,
+				RemoteIP:  remoteIP,
+				LocalCIDR: localCIDR,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		option.Config.Tunnel = tt.tunnel
+		out, in, err := tt.peer.selectors()
+		if tt.wantErr {
+			c.Assert(err, Not(IsNil), Commentf("Test name: %q", tt.name))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("Test name: %q", tt.name))
+		c.Assert(out, checker.DeepEquals, tt.wantOut, Commentf("Test name: %q", tt.name))
+		c.Assert(in, checker.DeepEquals, tt.wantIn, Commentf("Test name: %q", tt.name))
+	}
+}
+
+func (s *IPsecSuite) TestSameAddressing(c *C) {
+	ps := &peerState{
+		Peer: Peer{
+			Name:       "node2",
+			LocalIP:    net.ParseIP("192.168.0.1"),
+			RemoteIP:   net.ParseIP("192.168.0.2"),
+			LocalCIDR:  mustParseCIDR("10.1.0.0/16"),
+			RemoteCIDR: mustParseCIDR("10.2.0.0/16"),
+			KeyIndex:   1,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *Peer)
+		want   bool
+	}{
+		{
+			name:   "unchanged",
+			modify: func(p *Peer) {},
+			want:   true,
+		},
+		{
+			name:   "key rotated by the peer",
+			modify: func(p *Peer) { p.KeyIndex = 2 },
+			want:   true,
+		},
+		{
+			name:   "encryption disabled on the peer",
+			modify: func(p *Peer) { p.KeyIndex = 0 },
+			want:   true,
+		},
+		{
+			name:   "remote IP changed",
+			modify: func(p *Peer) { p.RemoteIP = net.ParseIP("192.168.0.3") },
+			want:   false,
+		},
+		{
+			name:   "local IP changed",
+			modify: func(p *Peer) { p.LocalIP = net.ParseIP("192.168.0.3") },
+			want:   false,
+		},
+		{
+			name:   "remote CIDR changed",
+			modify: func(p *Peer) { p.RemoteCIDR = mustParseCIDR("10.3.0.0/16") },
+			want:   false,
+		},
+		{
+			name:   "switched to tunneling",
+			modify: func(p *Peer) { p.LocalCIDR, p.RemoteCIDR = nil, nil },
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		peer := ps.Peer
+		tt.modify(&peer)
+		c.Assert(ps.sameAddressing(&peer), Equals, tt.want, Commentf("Test name: %q", tt.name))
+	}
+}
+
+func (s *IPsecSuite) TestStatusPlaintextPeer(c *C) {
+	prevTunnel := option.Config.Tunnel
+	option.Config.Tunnel = option.TunnelDisabled
+	defer func() {
+		option.Config.Tunnel = prevTunnel
+		mutex.Lock()
+		currentKey, previousKey = nil, nil
+		peers = map[peerKey]*peerState{}
+		mutex.Unlock()
+	}()
+
+	// No IPsec state is installed for peers which have not announced a
+	// key, the peer is reported as not encrypted
+	UpsertPeer(Peer{
+		Name:       "node2",
+		LocalIP:    net.ParseIP("192.168.0.1"),
+		RemoteIP:   net.ParseIP("192.168.0.2"),
+		LocalCIDR:  mustParseCIDR("10.1.0.0/16"),
+		RemoteCIDR: mustParseCIDR("10.2.0.0/16"),
+	})
+	UpsertPeer(Peer{
+		Name:       "node2",
+		LocalIP:    net.ParseIP("f00d::1"),
+		RemoteIP:   net.ParseIP("f00d::2"),
+		LocalCIDR:  mustParseCIDR("f00d:0:0:0:1::/96"),
+		RemoteCIDR: mustParseCIDR("f00d:0:0:0:2::/96"),
+		IPv6:       true,
+	})
+
+	es := Status()
+	c.Assert(es.Msg, Equals, "No IPsec key loaded")
+
+	SetKey(&Key{SPI: 1})
+
+	es = Status()
+	c.Assert(es.Msg, Equals, "Traffic to 1 peers without IPsec key is not encrypted: node2")
+	c.Assert(es.Ipsec.KeyIndex, Equals, int64(1))
+	c.Assert(es.Ipsec.Peers, checker.DeepEquals, []*models.IPSecPeer{
+		{Name: "node2", IP: "192.168.0.2"},
+		{Name: "node2", IP: "f00d::2"},
+	})
+
+	// Both address families are removed along with the peer
+	DeletePeer("node2")
+	c.Assert(Status().Ipsec.Peers, HasLen, 0)
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Key is an IPsec key shared by all nodes. The key is identified by its
+// index which is used as SPI of the IPsec states and announced by each node
+// to signal which keys it has loaded.
+type Key struct {
+	// SPI is the index of the key, 0 is reserved to indicate that
+	// encryption is disabled
+	SPI uint8
+
+	// Auth is the authentication algorithm, nil if Aead is used
+	Auth *netlink.XfrmStateAlgo
+
+	// Crypt is the encryption algorithm, nil if Aead is used
+	Crypt *netlink.XfrmStateAlgo
+
+	// Aead is the combined authentication and encryption algorithm
+	Aead *netlink.XfrmStateAlgo
+}
+
+// String returns the key without revealing the key material
+func (k *Key) String() string {
+	if k.Aead != nil {
+		return fmt.Sprintf("%d %s", k.SPI, k.Aead.Name)
+	}
+	return fmt.Sprintf("%d %s %s", k.SPI, k.Auth.Name, k.Crypt.Name)
+}
+
+// parseKeyMaterial parses a hex encoded key with an optional 0x prefix
+func parseKeyMaterial(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %q: %s", s, err)
+	}
+	return key, nil
+}
+
+// ParseKey parses an IPsec key. Empty lines and lines starting with '#' are
+// ignored, exactly one key must be present in one of the forms:
+//
+//	<spi> <auth-algo> <auth-key> <enc-algo> <enc-key>
+//	<spi> <aead-algo> <aead-key> <icv-bits>
+//
+// Example: "3 rfc4106(gcm(aes)) 0x44434241343332312423222114131211f4f3f2f1 128"
+func ParseKey(data []byte) (*Key, error) {
+	var fields []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields != nil {
+			return nil, fmt.Errorf("more than one key found")
+		}
+		fields = strings.Fields(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if fields == nil {
+		return nil, fmt.Errorf("no key found")
+	}
+
+	spi, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil || spi == 0 {
+		return nil, fmt.Errorf("invalid key index %q, must be in range 1..255", fields[0])
+	}
+
+	key := &Key{SPI: uint8(spi)}
+
+	switch len(fields) {
+	case 5:
+		authKey, err := parseKeyMaterial(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		cryptKey, err := parseKeyMaterial(fields[4])
+		if err != nil {
+			return nil, err
+		}
+		key.Auth = &netlink.XfrmStateAlgo{Name: fields[1], Key: authKey}
+		key.Crypt = &netlink.XfrmStateAlgo{Name: fields[3], Key: cryptKey}
+	case 4:
+		aeadKey, err := parseKeyMaterial(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		icvLen, err := strconv.Atoi(fields[3])
+		if err != nil || icvLen <= 0 {
+			return nil, fmt.Errorf("invalid ICV length %q", fields[3])
+		}
+		key.Aead = &netlink.XfrmStateAlgo{Name: fields[1], Key: aeadKey, ICVLen: icvLen}
+	default:
+		return nil, fmt.Errorf("invalid key format, expected 4 or 5 fields, found %d", len(fields))
+	}
+
+	return key, nil
+}
+
+// LoadKeyFile reads the IPsec key from the file at path
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse IPsec key file %s: %s", path, err)
+	}
+
+	return key, nil
+}
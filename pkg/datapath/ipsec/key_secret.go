@@ -0,0 +1,127 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SecretKeyName is the name of the key in the Kubernetes secret holding the
+// IPsec key
+const SecretKeyName = "keys"
+
+func parseSecretName(secret string) (string, string, error) {
+	namespace, name := "", secret
+	if parts := strings.SplitN(secret, "/", 2); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	}
+	if namespace == "" || name == "" {
+		return "", "", fmt.Errorf("invalid secret %q, must be in the form namespace/name", secret)
+	}
+	return namespace, name, nil
+}
+
+func parseSecret(secret *v1.Secret) (*Key, error) {
+	data, ok := secret.Data[SecretKeyName]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s does not contain key %q",
+			secret.Namespace, secret.Name, SecretKeyName)
+	}
+
+	key, err := ParseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse IPsec key in secret %s/%s: %s",
+			secret.Namespace, secret.Name, err)
+	}
+
+	return key, nil
+}
+
+// LoadKeySecret reads the IPsec key from the Kubernetes secret in the form
+// namespace/name
+func LoadKeySecret(client kubernetes.Interface, secret string) (*Key, error) {
+	namespace, name, err := parseSecretName(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSecret(s)
+}
+
+// WatchKeySecret watches the Kubernetes secret in the form namespace/name
+// and calls update whenever a valid key is found in the secret. Invalid keys
+// are logged and ignored so the key in use remains active.
+func WatchKeySecret(client kubernetes.Interface, secret string, update func(*Key)) error {
+	namespace, name, err := parseSecretName(secret)
+	if err != nil {
+		return err
+	}
+
+	selector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return client.CoreV1().Secrets(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return client.CoreV1().Secrets(namespace).Watch(options)
+		},
+	}
+
+	handle := func(obj interface{}) {
+		s, ok := obj.(*v1.Secret)
+		if !ok {
+			return
+		}
+
+		key, err := parseSecret(s)
+		if err != nil {
+			log.WithError(err).Warning("Ignoring invalid IPsec key")
+			return
+		}
+		update(key)
+	}
+
+	_, controller := cache.NewInformer(lw, &v1.Secret{}, 0,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: handle,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				handle(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				log.Warningf("IPsec key secret %s deleted, continuing to use the current key", secret)
+			},
+		},
+	)
+
+	go controller.Run(wait.NeverStop)
+	return nil
+}
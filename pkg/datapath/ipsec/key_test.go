@@ -0,0 +1,89 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package ipsec
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type IPsecSuite struct{}
+
+var _ = Suite(&IPsecSuite{})
+
+func (s *IPsecSuite) TestParseKey(c *C) {
+	key, err := ParseKey([]byte("# comment\n\n3 rfc4106(gcm(aes)) 0x0102030405060708090a0b0c0d0e0f1011121314 128\n"))
+	c.Assert(err, IsNil)
+	c.Assert(key.SPI, Equals, uint8(3))
+	c.Assert(key.Auth, IsNil)
+	c.Assert(key.Crypt, IsNil)
+	c.Assert(key.Aead.Name, Equals, "rfc4106(gcm(aes))")
+	c.Assert(len(key.Aead.Key), Equals, 20)
+	c.Assert(key.Aead.ICVLen, Equals, 128)
+	c.Assert(key.String(), Equals, "3 rfc4106(gcm(aes))")
+
+	key, err = ParseKey([]byte("255 hmac(sha256) 0102030405060708 cbc(aes) 0x0102030405060708090a0b0c0d0e0f10"))
+	c.Assert(err, IsNil)
+	c.Assert(key.SPI, Equals, uint8(255))
+	c.Assert(key.Aead, IsNil)
+	c.Assert(key.Auth.Name, Equals, "hmac(sha256)")
+	c.Assert(len(key.Auth.Key), Equals, 8)
+	c.Assert(key.Crypt.Name, Equals, "cbc(aes)")
+	c.Assert(len(key.Crypt.Key), Equals, 16)
+
+	for _, invalid := range []string{
+		"",
+		"# comment only",
+		"0 rfc4106(gcm(aes)) 0102 128",
+		"256 rfc4106(gcm(aes)) 0102 128",
+		"3 rfc4106(gcm(aes)) xyz 128",
+		"3 rfc4106(gcm(aes)) 0102 0",
+		"3 rfc4106(gcm(aes)) 0102",
+		"3 rfc4106(gcm(aes)) 0102 128\n4 rfc4106(gcm(aes)) 0102 128",
+	} {
+		_, err = ParseKey([]byte(invalid))
+		c.Assert(err, Not(IsNil), Commentf("%q", invalid))
+	}
+}
+
+func (s *IPsecSuite) TestLoadKeySecret(c *C) {
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "ipsec"},
+		Data: map[string][]byte{
+			SecretKeyName: []byte("5 rfc4106(gcm(aes)) 0102030405060708 128"),
+		},
+	})
+
+	key, err := LoadKeySecret(client, "kube-system/ipsec")
+	c.Assert(err, IsNil)
+	c.Assert(key.SPI, Equals, uint8(5))
+
+	_, err = LoadKeySecret(client, "ipsec")
+	c.Assert(err, Not(IsNil))
+
+	_, err = LoadKeySecret(client, "kube-system/missing")
+	c.Assert(err, Not(IsNil))
+}
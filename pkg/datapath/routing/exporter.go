@@ -0,0 +1,79 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+// Exporter announces the allocation prefixes of the local node to a routing
+// daemon which in turn makes them reachable from the rest of the network
+type Exporter interface {
+	// Name returns the name the exporter was registered with
+	Name() string
+
+	// Export announces prefix
+	Export(prefix *net.IPNet) error
+
+	// Withdraw withdraws a prefix previously announced with Export
+	Withdraw(prefix *net.IPNet) error
+}
+
+// ExporterFactory creates a new exporter
+type ExporterFactory func() (Exporter, error)
+
+var (
+	exportersMutex lock.Mutex
+	exporters      = map[string]ExporterFactory{}
+)
+
+// RegisterExporter registers an exporter under the given name so it can be
+// selected via the route-exporter option
+func RegisterExporter(name string, factory ExporterFactory) {
+	exportersMutex.Lock()
+	exporters[name] = factory
+	exportersMutex.Unlock()
+}
+
+// GetExporters returns the names of all registered exporters
+func GetExporters() []string {
+	exportersMutex.Lock()
+	defer exportersMutex.Unlock()
+
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NewExporter creates the exporter registered under the given name
+func NewExporter(name string) (Exporter, error) {
+	exportersMutex.Lock()
+	factory, ok := exporters[name]
+	exportersMutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown route exporter %q, available exporters: %v", name, GetExporters())
+	}
+
+	return factory()
+}
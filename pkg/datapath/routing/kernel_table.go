@@ -0,0 +1,117 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// KernelTableExporterName is the name of the exporter writing the allocation
+// prefixes of the local node into a kernel routing table. Routing daemons
+// such as BIRD or FRR can import the routes of the table and announce them.
+const KernelTableExporterName = "kernel-table"
+
+type kernelTableExporter struct {
+	table int
+}
+
+func init() {
+	RegisterExporter(KernelTableExporterName, newKernelTableExporter)
+}
+
+// validTable returns true if table can hold the exported routes. The
+// tables reserved by the kernel are rejected.
+func validTable(table int) bool {
+	return table > unix.RT_TABLE_UNSPEC &&
+		(table < unix.RT_TABLE_COMPAT || table > unix.RT_TABLE_LOCAL)
+}
+
+func newKernelTableExporter() (Exporter, error) {
+	table := option.Config.RouteExportTable
+	if !validTable(table) {
+		return nil, fmt.Errorf("invalid routing table %d for route exporter %s", table, KernelTableExporterName)
+	}
+
+	e := &kernelTableExporter{table: table}
+	e.removeStaleRoutes()
+
+	return e, nil
+}
+
+// removeStaleRoutes removes all routes exported by a previous run
+func (e *kernelTableExporter) removeStaleRoutes() {
+	filter := &netlink.Route{Table: e.table, Protocol: routeProtocol}
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, filter,
+		netlink.RT_FILTER_TABLE|netlink.RT_FILTER_PROTOCOL)
+	if err != nil {
+		log.WithError(err).Warning("Unable to list stale exported routes")
+		return
+	}
+
+	for _, r := range routes {
+		if err := netlink.RouteDel(&r); err != nil {
+			log.WithError(err).WithField(fieldPrefix, r.Dst).Warning("Unable to remove stale exported route")
+		}
+	}
+}
+
+func (e *kernelTableExporter) route(prefix *net.IPNet) (*netlink.Route, error) {
+	link, err := netlink.LinkByName(defaults.HostDevice)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lookup interface %s: %s", defaults.HostDevice, err)
+	}
+
+	r := &netlink.Route{
+		Dst:       prefix,
+		LinkIndex: link.Attrs().Index,
+		Table:     e.table,
+		Protocol:  routeProtocol,
+	}
+	if prefix.IP.To4() != nil {
+		r.Scope = netlink.SCOPE_LINK
+	}
+
+	return r, nil
+}
+
+// Name returns the name of the exporter
+func (e *kernelTableExporter) Name() string {
+	return KernelTableExporterName
+}
+
+// Export writes a route for prefix via the host device into the table
+func (e *kernelTableExporter) Export(prefix *net.IPNet) error {
+	r, err := e.route(prefix)
+	if err != nil {
+		return err
+	}
+	return netlink.RouteReplace(r)
+}
+
+// Withdraw removes the route for prefix from the table
+func (e *kernelTableExporter) Withdraw(prefix *net.IPNet) error {
+	r, err := e.route(prefix)
+	if err != nil {
+		return err
+	}
+	return netlink.RouteDel(r)
+}
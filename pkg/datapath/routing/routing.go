@@ -0,0 +1,441 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// routeProtocol is the protocol identifier of all routes installed by this
+// package. It allows routing daemons to select the routes and the agent to
+// remove the routes left behind by a previous run.
+const routeProtocol = 201
+
+// reachabilityInterval is the interval in which the reachability of the
+// prefixes of remote nodes without direct route is re-evaluated. Routes
+// covering the prefixes may be learned or withdrawn by routing daemons at
+// any time.
+const reachabilityInterval = time.Minute
+
+// Prefix is an allocation prefix of a node
+type Prefix struct {
+	// CIDR is the allocation prefix
+	CIDR *net.IPNet
+
+	// NodeIP is the IP of the node in the address family of CIDR
+	NodeIP net.IP
+}
+
+// Node is a node whose allocation prefixes are routed
+type Node struct {
+	// Name is the full name of the node
+	Name string
+
+	// Local is true if the node is the local node. The prefixes of the
+	// local node are exported, the prefixes of remote nodes are routed.
+	Local bool
+
+	// Prefixes are the allocation prefixes of the node
+	Prefixes []Prefix
+}
+
+type prefixState struct {
+	Prefix
+
+	// direct is true if a direct route to the prefix via the node IP has
+	// been installed
+	direct bool
+
+	// unreachable is true if the prefix is neither reachable via a direct
+	// route nor covered by any other route
+	unreachable bool
+}
+
+func (ps *prefixState) warnUnreachable(node string) {
+	log.WithFields(logrus.Fields{
+		fieldNode:        node,
+		fieldPrefix:      ps.CIDR,
+		logfields.IPAddr: ps.NodeIP,
+	}).Warning("Allocation prefix of node is not reachable without tunneling")
+}
+
+var (
+	mutex lock.Mutex
+
+	// exporter announces the prefixes of the local node, nil if disabled
+	exporter Exporter
+
+	// exported are the prefixes of the local node announced by exporter
+	exported = map[string]*net.IPNet{}
+
+	// nodes maps the name of each remote node to the state of its prefixes
+	nodes = map[string]map[string]*prefixState{}
+)
+
+// Init removes the direct routes installed by a previous run and creates
+// the exporter registered under exporterName. No prefixes are exported if
+// exporterName is empty.
+func Init(exporterName string) error {
+	removeStaleRoutes()
+
+	if option.Config.Tunnel == option.TunnelDisabled {
+		controller.NewManager().UpdateController("routing-reachability",
+			controller.ControllerParams{
+				DoFunc:      updateReachability,
+				RunInterval: reachabilityInterval,
+			},
+		)
+	}
+
+	if exporterName == "" {
+		return nil
+	}
+
+	e, err := NewExporter(exporterName)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	exporter = e
+	mutex.Unlock()
+
+	return nil
+}
+
+// removeStaleRoutes removes all direct routes from the main routing table
+func removeStaleRoutes() {
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL,
+		&netlink.Route{Protocol: routeProtocol}, netlink.RT_FILTER_PROTOCOL)
+	if err != nil {
+		log.WithError(err).Warning("Unable to list stale direct routes")
+		return
+	}
+
+	for _, r := range routes {
+		if err := netlink.RouteDel(&r); err != nil {
+			log.WithError(err).WithField(logfields.Route, r).Warning("Unable to remove stale direct route")
+		}
+	}
+}
+
+// directLink returns the index of the link via which ip is reachable
+// without a gateway, i.e. if ip is on a directly attached L2 segment
+func directLink(ip net.IP) (int, bool) {
+	routes, err := netlink.RouteGet(ip)
+	if err != nil || len(routes) == 0 {
+		return 0, false
+	}
+
+	if routes[0].Gw != nil || routes[0].LinkIndex == 0 {
+		return 0, false
+	}
+
+	return routes[0].LinkIndex, true
+}
+
+// coveringRoute returns the most specific route covering prefix. Default
+// routes and routes via the link with index ignoreLink are ignored.
+func coveringRoute(routes []netlink.Route, prefix *net.IPNet, ignoreLink int) *netlink.Route {
+	var (
+		best    *netlink.Route
+		bestLen = -1
+	)
+
+	prefixLen, _ := prefix.Mask.Size()
+	for i := range routes {
+		r := &routes[i]
+		if r.Dst == nil || (ignoreLink != 0 && r.LinkIndex == ignoreLink) {
+			continue
+		}
+
+		ones, bits := r.Dst.Mask.Size()
+		if ones == 0 || ones > prefixLen || bits != len(prefix.Mask)*8 {
+			continue
+		}
+
+		if r.Dst.Contains(prefix.IP) && ones > bestLen {
+			best, bestLen = r, ones
+		}
+	}
+
+	return best
+}
+
+// isCovered returns true if prefix is covered by a route of the main routing
+// table, e.g. a route learned by a routing daemon. The routes via the host
+// device are ignored as they loop the traffic back into the local node.
+func isCovered(prefix *net.IPNet) bool {
+	family := netlink.FAMILY_V4
+	if prefix.IP.To4() == nil {
+		family = netlink.FAMILY_V6
+	}
+
+	routes, err := netlink.RouteList(nil, family)
+	if err != nil {
+		log.WithError(err).Warning("Unable to list routes")
+		return false
+	}
+
+	ignoreLink := 0
+	if link, err := netlink.LinkByName(defaults.HostDevice); err == nil {
+		ignoreLink = link.Attrs().Index
+	}
+
+	return coveringRoute(routes, prefix, ignoreLink) != nil
+}
+
+func replaceDirectRoute(p Prefix, linkIndex int) error {
+	return netlink.RouteReplace(&netlink.Route{
+		Dst:       p.CIDR,
+		Gw:        p.NodeIP,
+		LinkIndex: linkIndex,
+		Protocol:  routeProtocol,
+	})
+}
+
+func deleteDirectRoute(p Prefix) {
+	err := netlink.RouteDel(&netlink.Route{
+		Dst:      p.CIDR,
+		Gw:       p.NodeIP,
+		Protocol: routeProtocol,
+	})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			fieldPrefix:      p.CIDR,
+			logfields.IPAddr: p.NodeIP,
+		}).Warning("Unable to delete direct route")
+	}
+}
+
+// exportPrefixes exports prefixes and withdraws all previously exported
+// prefixes which are no longer present
+func exportPrefixes(prefixes []Prefix) {
+	if exporter == nil {
+		return
+	}
+
+	scopedLog := log.WithField(fieldExporter, exporter.Name())
+
+	current := map[string]*net.IPNet{}
+	for _, p := range prefixes {
+		if p.CIDR == nil {
+			continue
+		}
+
+		key := p.CIDR.String()
+		current[key] = p.CIDR
+		if _, ok := exported[key]; ok {
+			continue
+		}
+
+		if err := exporter.Export(p.CIDR); err != nil {
+			scopedLog.WithError(err).WithField(fieldPrefix, key).Warning("Unable to export allocation prefix")
+			continue
+		}
+		exported[key] = p.CIDR
+		scopedLog.WithField(fieldPrefix, key).Info("Exported allocation prefix")
+	}
+
+	for key, prefix := range exported {
+		if _, ok := current[key]; ok {
+			continue
+		}
+
+		if err := exporter.Withdraw(prefix); err != nil {
+			scopedLog.WithError(err).WithField(fieldPrefix, key).Warning("Unable to withdraw allocation prefix")
+		}
+		delete(exported, key)
+	}
+}
+
+// UpsertNode updates the routing of the prefixes of node n. The prefixes of
+// the local node are exported. With auto-direct-node-routes, a route via the
+// node IP is installed for each prefix of a remote node sharing an L2
+// segment with the local node. Prefixes of remote nodes which are not
+// reachable otherwise are reported.
+func UpsertNode(n Node) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if n.Local {
+		exportPrefixes(n.Prefixes)
+		return
+	}
+
+	scopedLog := log.WithField(fieldNode, n.Name)
+	old := nodes[n.Name]
+	current := map[string]*prefixState{}
+
+	for _, p := range n.Prefixes {
+		if p.CIDR == nil || p.NodeIP == nil {
+			continue
+		}
+
+		key := p.CIDR.String()
+		linkIndex, onLink := 0, false
+		if option.Config.AutoDirectNodeRoutes {
+			linkIndex, onLink = directLink(p.NodeIP)
+		}
+
+		o := old[key]
+		if o != nil && o.direct && (!onLink || !o.NodeIP.Equal(p.NodeIP)) {
+			deleteDirectRoute(o.Prefix)
+		}
+
+		ps := &prefixState{Prefix: p}
+		if onLink {
+			if err := replaceDirectRoute(p, linkIndex); err != nil {
+				scopedLog.WithError(err).WithField(fieldPrefix, key).Warning("Unable to install direct route")
+			} else {
+				ps.direct = true
+			}
+		}
+
+		if !ps.direct {
+			ps.unreachable = !isCovered(p.CIDR)
+			if ps.unreachable && (o == nil || !o.unreachable) {
+				ps.warnUnreachable(n.Name)
+			}
+		}
+
+		current[key] = ps
+	}
+
+	for key, o := range old {
+		if _, ok := current[key]; !ok && o.direct {
+			deleteDirectRoute(o.Prefix)
+		}
+	}
+
+	nodes[n.Name] = current
+}
+
+// DeleteNode removes the routes to the prefixes of the remote node name
+func DeleteNode(name string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, ps := range nodes[name] {
+		if ps.direct {
+			deleteDirectRoute(ps.Prefix)
+		}
+	}
+	delete(nodes, name)
+}
+
+// updateReachability re-evaluates the reachability of the prefixes of remote
+// nodes without direct route. The routing table is read without holding
+// mutex, prefixes updated by UpsertNode in the meantime are skipped.
+func updateReachability() error {
+	type candidate struct {
+		node        string
+		key         string
+		ps          *prefixState
+		unreachable bool
+	}
+
+	var candidates []candidate
+	mutex.Lock()
+	for name, prefixes := range nodes {
+		for key, ps := range prefixes {
+			if !ps.direct {
+				candidates = append(candidates, candidate{node: name, key: key, ps: ps})
+			}
+		}
+	}
+	mutex.Unlock()
+
+	for i := range candidates {
+		candidates[i].unreachable = !isCovered(candidates[i].ps.CIDR)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, c := range candidates {
+		if nodes[c.node][c.key] != c.ps {
+			continue
+		}
+
+		if c.unreachable && !c.ps.unreachable {
+			c.ps.warnUnreachable(c.node)
+		}
+		c.ps.unreachable = c.unreachable
+	}
+
+	return nil
+}
+
+// Status returns the status of the routing between nodes. The reachability
+// of the prefixes of remote nodes is reported as last evaluated by UpsertNode
+// or updateReachability.
+func Status() *models.RoutingStatus {
+	if option.Config.Tunnel != option.TunnelDisabled {
+		return &models.RoutingStatus{Mode: models.RoutingStatusModeTunnel}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	status := &models.RoutingStatus{
+		Mode:                models.RoutingStatusModeDirect,
+		ExportedPrefixes:    []string{},
+		UnreachablePrefixes: []*models.UnreachablePrefix{},
+	}
+
+	if exporter != nil {
+		status.Exporter = exporter.Name()
+	}
+	for key := range exported {
+		status.ExportedPrefixes = append(status.ExportedPrefixes, key)
+	}
+	sort.Strings(status.ExportedPrefixes)
+
+	for name, prefixes := range nodes {
+		for key, ps := range prefixes {
+			if ps.unreachable {
+				status.UnreachablePrefixes = append(status.UnreachablePrefixes,
+					&models.UnreachablePrefix{Node: name, Prefix: key})
+			}
+		}
+	}
+	sort.Slice(status.UnreachablePrefixes, func(i, j int) bool {
+		a, b := status.UnreachablePrefixes[i], status.UnreachablePrefixes[j]
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		return a.Prefix < b.Prefix
+	})
+
+	if n := len(status.UnreachablePrefixes); n > 0 {
+		status.Msg = fmt.Sprintf("%d allocation prefixes of remote nodes are not reachable without tunneling", n)
+	}
+
+	return status
+}
@@ -0,0 +1,205 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package routing
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/vishvananda/netlink"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type RoutingSuite struct{}
+
+var _ = Suite(&RoutingSuite{})
+
+type fakeExporter struct {
+	prefixes map[string]bool
+}
+
+func (f *fakeExporter) Name() string {
+	return "fake"
+}
+
+func (f *fakeExporter) Export(prefix *net.IPNet) error {
+	f.prefixes[prefix.String()] = true
+	return nil
+}
+
+func (f *fakeExporter) Withdraw(prefix *net.IPNet) error {
+	delete(f.prefixes, prefix.String())
+	return nil
+}
+
+func mustParseCIDR(c *C, s string) *net.IPNet {
+	_, cidr, err := net.ParseCIDR(s)
+	c.Assert(err, IsNil)
+	return cidr
+}
+
+func (s *RoutingSuite) TestCoveringRoute(c *C) {
+	routes := []netlink.Route{
+		{Dst: nil, LinkIndex: 1},
+		{Dst: mustParseCIDR(c, "0.0.0.0/0"), LinkIndex: 1},
+		{Dst: mustParseCIDR(c, "10.0.0.0/8"), LinkIndex: 2},
+		{Dst: mustParseCIDR(c, "10.1.0.0/16"), LinkIndex: 3},
+		{Dst: mustParseCIDR(c, "10.1.1.0/24"), LinkIndex: 4},
+		{Dst: mustParseCIDR(c, "f00d::/16"), LinkIndex: 2},
+	}
+
+	r := coveringRoute(routes, mustParseCIDR(c, "10.1.1.0/24"), 0)
+	c.Assert(r, Not(IsNil))
+	c.Assert(r.LinkIndex, Equals, 4)
+
+	r = coveringRoute(routes, mustParseCIDR(c, "10.1.1.0/24"), 4)
+	c.Assert(r, Not(IsNil))
+	c.Assert(r.LinkIndex, Equals, 3)
+
+	r = coveringRoute(routes, mustParseCIDR(c, "10.2.0.0/16"), 0)
+	c.Assert(r, Not(IsNil))
+	c.Assert(r.LinkIndex, Equals, 2)
+
+	// A more specific route only covers part of the prefix
+	r = coveringRoute(routes, mustParseCIDR(c, "10.0.0.0/7"), 0)
+	c.Assert(r, IsNil)
+
+	// Default routes are ignored
+	c.Assert(coveringRoute(routes, mustParseCIDR(c, "192.168.0.0/16"), 0), IsNil)
+
+	r = coveringRoute(routes, mustParseCIDR(c, "f00d::a00:0:0:0/96"), 0)
+	c.Assert(r, Not(IsNil))
+	c.Assert(r.LinkIndex, Equals, 2)
+}
+
+func (s *RoutingSuite) TestValidTable(c *C) {
+	c.Assert(validTable(0), Equals, false)
+	c.Assert(validTable(-1), Equals, false)
+	c.Assert(validTable(100), Equals, true)
+	c.Assert(validTable(252), Equals, false)
+	c.Assert(validTable(254), Equals, false)
+	c.Assert(validTable(1000), Equals, true)
+}
+
+func (s *RoutingSuite) TestExporters(c *C) {
+	RegisterExporter("fake", func() (Exporter, error) {
+		return &fakeExporter{prefixes: map[string]bool{}}, nil
+	})
+
+	c.Assert(GetExporters(), DeepEquals, []string{"fake", KernelTableExporterName})
+
+	e, err := NewExporter("fake")
+	c.Assert(err, IsNil)
+	c.Assert(e.Name(), Equals, "fake")
+
+	_, err = NewExporter("unknown")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *RoutingSuite) TestExportLocalPrefixes(c *C) {
+	oldTunnel := option.Config.Tunnel
+	option.Config.Tunnel = option.TunnelDisabled
+	defer func() { option.Config.Tunnel = oldTunnel }()
+
+	fake := &fakeExporter{prefixes: map[string]bool{}}
+	mutex.Lock()
+	exporter = fake
+	exported = map[string]*net.IPNet{}
+	mutex.Unlock()
+	defer func() {
+		mutex.Lock()
+		exporter = nil
+		exported = map[string]*net.IPNet{}
+		mutex.Unlock()
+	}()
+
+	UpsertNode(Node{
+		Name:  "default/local",
+		Local: true,
+		Prefixes: []Prefix{
+			{CIDR: mustParseCIDR(c, "10.1.0.0/16"), NodeIP: net.ParseIP("192.168.1.1")},
+			{CIDR: mustParseCIDR(c, "f00d::a01:0:0:0/96"), NodeIP: net.ParseIP("fd00::1")},
+		},
+	})
+	c.Assert(fake.prefixes, DeepEquals, map[string]bool{
+		"10.1.0.0/16":        true,
+		"f00d::a01:0:0:0/96": true,
+	})
+
+	status := Status()
+	c.Assert(status.Mode, Equals, models.RoutingStatusModeDirect)
+	c.Assert(status.Exporter, Equals, "fake")
+	c.Assert(status.ExportedPrefixes, DeepEquals, []string{"10.1.0.0/16", "f00d::a01:0:0:0/96"})
+
+	UpsertNode(Node{
+		Name:  "default/local",
+		Local: true,
+		Prefixes: []Prefix{
+			{CIDR: mustParseCIDR(c, "10.2.0.0/16"), NodeIP: net.ParseIP("192.168.1.1")},
+		},
+	})
+	c.Assert(fake.prefixes, DeepEquals, map[string]bool{"10.2.0.0/16": true})
+
+	option.Config.Tunnel = option.TunnelVXLAN
+	c.Assert(Status(), DeepEquals, &models.RoutingStatus{Mode: models.RoutingStatusModeTunnel})
+}
+
+func (s *RoutingSuite) TestStatusUnreachablePrefixes(c *C) {
+	oldTunnel := option.Config.Tunnel
+	option.Config.Tunnel = option.TunnelDisabled
+	defer func() { option.Config.Tunnel = oldTunnel }()
+
+	direct := &prefixState{
+		Prefix: Prefix{CIDR: mustParseCIDR(c, "10.2.0.0/16"), NodeIP: net.ParseIP("192.168.1.2")},
+		direct: true,
+	}
+	unreachable := &prefixState{
+		Prefix:      Prefix{CIDR: mustParseCIDR(c, "10.3.0.0/16"), NodeIP: net.ParseIP("192.168.2.3")},
+		unreachable: true,
+	}
+	mutex.Lock()
+	nodes = map[string]map[string]*prefixState{
+		"default/node2": {"10.2.0.0/16": direct},
+		"default/node3": {"10.3.0.0/16": unreachable},
+	}
+	mutex.Unlock()
+	defer func() {
+		mutex.Lock()
+		nodes = map[string]map[string]*prefixState{}
+		mutex.Unlock()
+	}()
+
+	// Status reports the reachability as last evaluated
+	status := Status()
+	c.Assert(status.UnreachablePrefixes, DeepEquals, []*models.UnreachablePrefix{
+		{Node: "default/node3", Prefix: "10.3.0.0/16"},
+	})
+	c.Assert(status.Msg, Equals, "1 allocation prefixes of remote nodes are not reachable without tunneling")
+
+	// Prefixes with a direct route are not re-evaluated
+	err := updateReachability()
+	c.Assert(err, IsNil)
+	c.Assert(direct.unreachable, Equals, false)
+}
@@ -59,6 +59,11 @@ const (
 	// This is the 1.2 protocol version.
 	MonitorSockPath1_2 = RuntimePath + "/monitor1_2.sock"
 
+	// MonitorSockPath1_3 is the path to the UNIX domain socket used to
+	// distribute BPF and agent events to listeners.
+	// This is the 1.3 protocol version.
+	MonitorSockPath1_3 = RuntimePath + "/monitor1_3.sock"
+
 	// PidFilePath is the path to the pid file for the agent.
 	PidFilePath = RuntimePath + "/cilium.pid"
 
@@ -94,4 +99,8 @@ const (
 	// already been allocated and other nodes in the cluster have a chance
 	// to whitelist the new upcoming identity of the endpoint.
 	IdentityChangeGracePeriod = 25 * time.Second
+
+	// MonitorHistorySize is the default number of flow events recorded by
+	// the node monitor for retrieval with "cilium monitor --since/--last"
+	MonitorHistorySize = 4096
 )
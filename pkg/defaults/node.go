@@ -38,6 +38,17 @@ const (
 
 	// DefaultNAT46Prefix is the IPv6 prefix to represent NATed IPv4 addresses.
 	DefaultNAT46Prefix = "0:0:0:0:0:FFFF::/96"
+
+	// TunnelPortVXLAN is the UDP port of the VXLAN tunnel device
+	TunnelPortVXLAN = 8472
+
+	// TunnelPortGeneve is the UDP port of the Geneve tunnel device
+	TunnelPortGeneve = 6081
+
+	// RouteExportTable is the kernel routing table the allocation
+	// prefixes of the local node are exported to by the kernel-table
+	// route exporter
+	RouteExportTable = 100
 )
 
 var (
@@ -0,0 +1,19 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package egressnat implements CiliumEgressNATPolicy resources. The traffic
+// of the local endpoints selected by a policy is steered through the tunnel
+// to the gateway node of the policy which masquerades it with the egress IP
+// of the policy before it leaves the cluster.
+package egressnat
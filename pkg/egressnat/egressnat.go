@@ -0,0 +1,303 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressnat
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/trigger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// syncInterval is the interval in which the egress map and the egress
+	// NAT chains are synchronized with the local endpoints and the known
+	// nodes
+	syncInterval = 10 * time.Second
+
+	// syncMinInterval is the minimum interval between synchronizations
+	// triggered with TriggerSync
+	syncMinInterval = time.Second
+)
+
+// Endpoint is a local endpoint which may be selected by a policy
+type Endpoint struct {
+	// IPv4 is the IPv4 address of the endpoint
+	IPv4 net.IP
+
+	// Labels are the security relevant labels of the endpoint
+	Labels labels.LabelArray
+}
+
+// EndpointsFunc returns all local endpoints
+type EndpointsFunc func() []Endpoint
+
+// gateway is the resolved gateway of a policy
+type gateway struct {
+	// nodeIP is the IP of the gateway node the traffic is tunneled to
+	nodeIP net.IP
+
+	// egressIP is the IP the traffic is masqueraded with
+	egressIP net.IP
+
+	// local is true if the local node is the gateway node
+	local bool
+}
+
+var (
+	mutex lock.Mutex
+
+	// policies maps the namespaced name of each policy to the policy
+	policies = map[string]*Policy{}
+
+	// getEndpoints returns the local endpoints, nil until Init is called
+	getEndpoints EndpointsFunc
+
+	// applied are the rules of the egress NAT chains installed by the
+	// last successful sync
+	applied *rules
+
+	// syncTrigger runs Sync when TriggerSync is called, nil until Init is
+	// called
+	syncTrigger *trigger.Trigger
+)
+
+// Init opens the egress map and starts to synchronize it and the egress NAT
+// chains with the policies, the local endpoints returned by endpoints and
+// the known nodes.
+func Init(endpoints EndpointsFunc) error {
+	if _, err := egressmap.EgressMap.OpenOrCreate(); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	getEndpoints = endpoints
+	syncTrigger = trigger.NewTrigger(trigger.Parameters{
+		MinInterval: syncMinInterval,
+		TriggerFunc: func() {
+			if err := Sync(); err != nil {
+				log.WithError(err).Warning("Unable to synchronize egress NAT")
+			}
+		},
+	})
+	mutex.Unlock()
+
+	controller.NewManager().UpdateController("egress-nat-sync",
+		controller.ControllerParams{
+			DoFunc:      Sync,
+			RunInterval: syncInterval,
+		})
+
+	return nil
+}
+
+// TriggerSync schedules a Sync outside of the regular interval, e.g. when the
+// identity of a local endpoint has changed. It does not wait for the Sync to
+// complete. It is a no-op until Init is called.
+func TriggerSync() {
+	mutex.Lock()
+	t := syncTrigger
+	mutex.Unlock()
+
+	if t != nil {
+		t.Trigger()
+	}
+}
+
+// UpsertPolicy adds or replaces a policy
+func UpsertPolicy(p *Policy) {
+	mutex.Lock()
+	policies[p.Name] = p
+	mutex.Unlock()
+
+	if err := Sync(); err != nil {
+		log.WithError(err).WithField(logfields.CiliumEgressNATPolicyName, p.Name).Warning("Unable to apply egress NAT policy")
+	}
+}
+
+// DeletePolicy removes the policy with the given namespaced name
+func DeletePolicy(name string) {
+	mutex.Lock()
+	delete(policies, name)
+	mutex.Unlock()
+
+	if err := Sync(); err != nil {
+		log.WithError(err).WithField(logfields.CiliumEgressNATPolicyName, name).Warning("Unable to remove egress NAT policy")
+	}
+}
+
+// HasPolicy returns true if a policy with the given namespaced name exists
+func HasPolicy(name string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	_, ok := policies[name]
+	return ok
+}
+
+// Sync synchronizes the egress map and the egress NAT chains with the
+// policies, the local endpoints and the known nodes
+func Sync() error {
+	mutex.Lock()
+	endpointsFunc := getEndpoints
+	mutex.Unlock()
+
+	if endpointsFunc == nil {
+		return nil
+	}
+
+	// The endpoints are retrieved without holding mutex as the
+	// endpointmanager must not be called into while holding it
+	endpoints := endpointsFunc()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	sorted := sortedPolicies()
+	gateways := resolveGateways(sorted)
+
+	if err := syncMap(computeEntries(sorted, gateways, endpoints)); err != nil {
+		return err
+	}
+
+	return syncRules(computeRules(sorted, gateways, endpoints,
+		node.GetIPv4AllocRange(), node.GetIPv4ClusterRange()))
+}
+
+// sortedPolicies returns all policies sorted by name. If several policies
+// select the same traffic, the first one wins.
+func sortedPolicies() []*Policy {
+	sorted := make([]*Policy, 0, len(policies))
+	for _, p := range policies {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// resolveGateways returns the gateway of each policy whose gateway node is
+// known, keyed by the name of the policy
+func resolveGateways(policies []*Policy) map[string]gateway {
+	nodes := node.GetNodes()
+	gateways := map[string]gateway{}
+
+	for _, p := range policies {
+		gw := gateway{}
+		if p.GatewayNode == node.GetName() {
+			gw.nodeIP = node.GetExternalIPv4()
+			gw.local = true
+		} else {
+			id := node.Identity{Name: p.GatewayNode, Cluster: option.Config.ClusterName}
+			if n, ok := nodes[id]; ok {
+				gw.nodeIP = n.GetNodeIP(false)
+			}
+		}
+
+		if gw.nodeIP == nil {
+			log.WithFields(logrus.Fields{
+				logfields.CiliumEgressNATPolicyName: p.Name,
+				fieldGateway:                        p.GatewayNode,
+			}).Debug("Gateway node of egress NAT policy is unknown")
+			continue
+		}
+
+		gw.egressIP = p.EgressIP
+		if gw.egressIP == nil {
+			gw.egressIP = gw.nodeIP
+		}
+		gateways[p.Name] = gw
+	}
+
+	return gateways
+}
+
+// selectedEndpoints returns the endpoints with an IPv4 address selected by p
+func selectedEndpoints(p *Policy, endpoints []Endpoint) []Endpoint {
+	selected := []Endpoint{}
+	for _, ep := range endpoints {
+		if ep.IPv4 != nil && p.Selector.Matches(ep.Labels) {
+			selected = append(selected, ep)
+		}
+	}
+	return selected
+}
+
+// computeEntries returns the egress map entries steering the traffic of the
+// selected endpoints to remote gateway nodes. The traffic of endpoints whose
+// gateway is the local node is NATed without leaving the node.
+func computeEntries(policies []*Policy, gateways map[string]gateway, endpoints []Endpoint) map[egressmap.Key]egressmap.Value {
+	entries := map[egressmap.Key]egressmap.Value{}
+
+	for _, p := range policies {
+		gw, ok := gateways[p.Name]
+		if !ok || gw.local {
+			continue
+		}
+
+		for _, ep := range selectedEndpoints(p, endpoints) {
+			for _, cidr := range p.DestinationCIDRs {
+				key := egressmap.NewKey(ep.IPv4, cidr)
+				if _, ok := entries[key]; !ok {
+					entries[key] = egressmap.NewValue(gw.nodeIP)
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// syncMap replaces the entries of the egress map with entries
+func syncMap(entries map[egressmap.Key]egressmap.Value) error {
+	current := map[egressmap.Key]egressmap.Value{}
+	err := egressmap.EgressMap.DumpWithCallback(func(k bpf.MapKey, v bpf.MapValue) {
+		current[*k.(*egressmap.Key)] = *v.(*egressmap.Value)
+	})
+	if err != nil {
+		return err
+	}
+
+	for key := range current {
+		if _, ok := entries[key]; !ok {
+			if err := egressmap.Delete(key); err != nil {
+				log.WithError(err).WithField(logfields.BPFMapKey, key).Warning("Unable to delete stale egress map entry")
+			}
+		}
+	}
+
+	for key, value := range entries {
+		if v, ok := current[key]; ok && v == value {
+			continue
+		}
+		if err := egressmap.Update(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
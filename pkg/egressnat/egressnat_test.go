@@ -0,0 +1,178 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package egressnat
+
+import (
+	"net"
+	"testing"
+
+	v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type EgressNATSuite struct{}
+
+var _ = Suite(&EgressNATSuite{})
+
+func newPolicy(namespace, name string, spec v2.EgressNATPolicySpec) *v2.CiliumEgressNATPolicy {
+	return &v2.CiliumEgressNATPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: spec,
+	}
+}
+
+func mustParsePolicy(c *C, p *v2.CiliumEgressNATPolicy) *Policy {
+	policy, err := ParsePolicy(p)
+	c.Assert(err, IsNil)
+	return policy
+}
+
+var billingSpec = v2.EgressNATPolicySpec{
+	EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("app=billing")),
+	DestinationCIDRs: []api.CIDR{"192.168.10.0/24", "10.10.0.0/16"},
+	GatewayNode:      "gateway",
+}
+
+func (s *EgressNATSuite) TestParsePolicy(c *C) {
+	policy := mustParsePolicy(c, newPolicy("default", "billing", billingSpec))
+	c.Assert(policy.Name, Equals, "default/billing")
+	c.Assert(policy.GatewayNode, Equals, "gateway")
+	c.Assert(policy.EgressIP, IsNil)
+	c.Assert(len(policy.DestinationCIDRs), Equals, 2)
+	c.Assert(policy.DestinationCIDRs[0].String(), Equals, "192.168.10.0/24")
+
+	// The selector is restricted to the namespace of the policy
+	c.Assert(policy.Selector.Matches(labels.ParseLabelArray(
+		"k8s:app=billing", "k8s:io.kubernetes.pod.namespace=default")), Equals, true)
+	c.Assert(policy.Selector.Matches(labels.ParseLabelArray(
+		"k8s:app=billing", "k8s:io.kubernetes.pod.namespace=other")), Equals, false)
+	c.Assert(policy.Selector.Matches(labels.ParseLabelArray(
+		"k8s:app=web", "k8s:io.kubernetes.pod.namespace=default")), Equals, false)
+
+	spec := billingSpec
+	spec.EgressIP = "10.0.0.100"
+	policy = mustParsePolicy(c, newPolicy("default", "billing", spec))
+	c.Assert(policy.EgressIP.String(), Equals, "10.0.0.100")
+
+	invalid := []v2.EgressNATPolicySpec{
+		{DestinationCIDRs: billingSpec.DestinationCIDRs},
+		{GatewayNode: "gateway"},
+		{GatewayNode: "gateway", DestinationCIDRs: []api.CIDR{"192.168.10.0"}},
+		{GatewayNode: "gateway", DestinationCIDRs: []api.CIDR{"f00d::/64"}},
+		{GatewayNode: "gateway", DestinationCIDRs: billingSpec.DestinationCIDRs, EgressIP: "f00d::1"},
+	}
+	for _, spec := range invalid {
+		_, err := ParsePolicy(newPolicy("default", "invalid", spec))
+		c.Assert(err, Not(IsNil), Commentf("spec %+v", spec))
+	}
+
+	_, err := ParsePolicy(newPolicy("default", "", billingSpec))
+	c.Assert(err, Not(IsNil))
+}
+
+var testEndpoints = []Endpoint{
+	{
+		IPv4:   net.ParseIP("10.1.0.10"),
+		Labels: labels.ParseLabelArray("k8s:app=billing", "k8s:io.kubernetes.pod.namespace=default"),
+	},
+	{
+		IPv4:   net.ParseIP("10.1.0.11"),
+		Labels: labels.ParseLabelArray("k8s:app=web", "k8s:io.kubernetes.pod.namespace=default"),
+	},
+	{
+		Labels: labels.ParseLabelArray("k8s:app=billing", "k8s:io.kubernetes.pod.namespace=default"),
+	},
+}
+
+func (s *EgressNATSuite) TestComputeEntries(c *C) {
+	policies := []*Policy{mustParsePolicy(c, newPolicy("default", "billing", billingSpec))}
+
+	// Unknown gateway
+	entries := computeEntries(policies, map[string]gateway{}, testEndpoints)
+	c.Assert(len(entries), Equals, 0)
+
+	// Local gateway
+	gateways := map[string]gateway{
+		"default/billing": {nodeIP: net.ParseIP("10.0.0.1"), egressIP: net.ParseIP("10.0.0.1"), local: true},
+	}
+	entries = computeEntries(policies, gateways, testEndpoints)
+	c.Assert(len(entries), Equals, 0)
+
+	// Remote gateway
+	gateways = map[string]gateway{
+		"default/billing": {nodeIP: net.ParseIP("10.0.0.2"), egressIP: net.ParseIP("10.0.0.100")},
+	}
+	entries = computeEntries(policies, gateways, testEndpoints)
+	c.Assert(len(entries), Equals, 2)
+
+	for _, cidr := range policies[0].DestinationCIDRs {
+		key := egressmap.NewKey(net.ParseIP("10.1.0.10"), cidr)
+		value, ok := entries[key]
+		c.Assert(ok, Equals, true)
+		c.Assert(value.String(), Equals, "10.0.0.2")
+	}
+
+	key := egressmap.NewKey(net.ParseIP("10.1.0.10"), policies[0].DestinationCIDRs[0])
+	c.Assert(key.String(), Equals, "10.1.0.10 192.168.10.0/24")
+	c.Assert(key.Prefixlen, Equals, uint32(56))
+}
+
+func (s *EgressNATSuite) TestComputeRules(c *C) {
+	policies := []*Policy{mustParsePolicy(c, newPolicy("default", "billing", billingSpec))}
+	_, allocRange, _ := net.ParseCIDR("10.1.0.0/16")
+	_, clusterRange, _ := net.ParseCIDR("10.0.0.0/8")
+
+	// Remote gateway
+	gateways := map[string]gateway{
+		"default/billing": {nodeIP: net.ParseIP("10.0.0.2"), egressIP: net.ParseIP("10.0.0.2")},
+	}
+	r := computeRules(policies, gateways, testEndpoints, allocRange, clusterRange)
+	c.Assert(len(r.nat), Equals, 0)
+	c.Assert(len(r.forward), Equals, 0)
+
+	// Local gateway
+	gateways = map[string]gateway{
+		"default/billing": {nodeIP: net.ParseIP("10.0.0.1"), egressIP: net.ParseIP("10.0.0.100"), local: true},
+	}
+	r = computeRules(policies, gateways, testEndpoints, allocRange, clusterRange)
+
+	comment := []string{"-m", "comment", "--comment", "cilium: egress nat default/billing"}
+	c.Assert(r.nat, DeepEquals, [][]string{
+		joinRule([]string{"-s", "10.1.0.10", "-d", "192.168.10.0/24", "!", "-o", "cilium_+"}, comment, snatTarget(net.ParseIP("10.0.0.100"))),
+		joinRule([]string{"-s", "10.1.0.10", "-d", "10.10.0.0/16", "!", "-o", "cilium_+"}, comment, snatTarget(net.ParseIP("10.0.0.100"))),
+		{"-s", "10.1.0.0/16", "-m", "comment", "--comment", "cilium: egress nat skip unselected local endpoints", "-j", "RETURN"},
+		joinRule([]string{"-s", "10.0.0.0/8", "-d", "192.168.10.0/24", "!", "-o", "cilium_+"}, comment, snatTarget(net.ParseIP("10.0.0.100"))),
+		joinRule([]string{"-s", "10.0.0.0/8", "-d", "10.10.0.0/16", "!", "-o", "cilium_+"}, comment, snatTarget(net.ParseIP("10.0.0.100"))),
+	})
+	c.Assert(r.forward, DeepEquals, [][]string{
+		joinRule([]string{"-s", "10.0.0.0/8", "-d", "192.168.10.0/24"}, comment, []string{"-j", "ACCEPT"}),
+		joinRule([]string{"-s", "10.0.0.0/8", "-d", "10.10.0.0/16"}, comment, []string{"-j", "ACCEPT"}),
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressnat
+
+import (
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/cilium/cilium/pkg/command/exec"
+)
+
+const (
+	// NATChain is the nat chain masquerading the traffic of the policies
+	// whose gateway is the local node. It must be fed from the beginning
+	// of the CILIUM_POST chain so that the traffic bypasses the generic
+	// masquerade rules.
+	NATChain = "CILIUM_EGRESS_NAT"
+
+	// ForwardChain is the filter chain accepting the traffic tunneled to
+	// the local node by other nodes. It must be fed from the CILIUM_FORWARD
+	// chain.
+	ForwardChain = "CILIUM_EGRESS_FORWARD"
+
+	// iptablesTimeout is the execution timeout of iptables
+	iptablesTimeout = 30 * time.Second
+)
+
+// rules are the rules of the egress NAT chains
+type rules struct {
+	nat     [][]string
+	forward [][]string
+}
+
+func ruleComment(p *Policy) []string {
+	return []string{"-m", "comment", "--comment", "cilium: egress nat " + p.Name}
+}
+
+func snatTarget(egressIP net.IP) []string {
+	return []string{"-j", "SNAT", "--to-source", egressIP.String()}
+}
+
+// joinRule concatenates the given parts of a rule
+func joinRule(parts ...[]string) []string {
+	rule := []string{}
+	for _, part := range parts {
+		rule = append(rule, part...)
+	}
+	return rule
+}
+
+// computeRules returns the rules of the egress NAT chains for the policies
+// whose gateway is the local node. The traffic of the selected local
+// endpoints is masqueraded directly, the traffic of all other local
+// endpoints is left to the generic masquerade rules. Any other traffic from
+// within clusterRange has been tunneled to the local node by the node of the
+// selected endpoint.
+func computeRules(policies []*Policy, gateways map[string]gateway, endpoints []Endpoint, allocRange, clusterRange *net.IPNet) *rules {
+	r := &rules{}
+	local := []*Policy{}
+	notTunnel := []string{"!", "-o", "cilium_+"}
+
+	for _, p := range policies {
+		gw, ok := gateways[p.Name]
+		if !ok || !gw.local {
+			continue
+		}
+		local = append(local, p)
+
+		for _, ep := range selectedEndpoints(p, endpoints) {
+			for _, cidr := range p.DestinationCIDRs {
+				match := []string{"-s", ep.IPv4.String(), "-d", cidr.String()}
+				r.nat = append(r.nat, joinRule(match, notTunnel, ruleComment(p), snatTarget(gw.egressIP)))
+			}
+		}
+	}
+
+	if len(local) == 0 {
+		return r
+	}
+
+	r.nat = append(r.nat, []string{
+		"-s", allocRange.String(),
+		"-m", "comment", "--comment", "cilium: egress nat skip unselected local endpoints",
+		"-j", "RETURN"})
+
+	for _, p := range local {
+		egressIP := gateways[p.Name].egressIP
+		for _, cidr := range p.DestinationCIDRs {
+			match := []string{"-s", clusterRange.String(), "-d", cidr.String()}
+			r.nat = append(r.nat, joinRule(match, notTunnel, ruleComment(p), snatTarget(egressIP)))
+			r.forward = append(r.forward, joinRule(match, ruleComment(p), []string{"-j", "ACCEPT"}))
+		}
+	}
+
+	return r
+}
+
+func runIptables(args ...string) error {
+	_, err := exec.WithTimeout(iptablesTimeout, "iptables", args...).CombinedOutput(log, true)
+	return err
+}
+
+// installRules replaces all rules of chain in table with rules
+func installRules(table, chain string, rules [][]string) error {
+	if err := runIptables("-t", table, "-F", chain); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if err := runIptables(append([]string{"-t", table, "-A", chain}, rule...)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncRules installs r into the egress NAT chains unless it has been
+// installed by the previous sync
+func syncRules(r *rules) error {
+	if applied != nil && reflect.DeepEqual(applied, r) {
+		return nil
+	}
+
+	// Force a reinstallation on the next sync if any of the rules fails
+	applied = nil
+
+	if err := installRules("nat", NATChain, r.nat); err != nil {
+		return err
+	}
+
+	if err := installRules("filter", ForwardChain, r.forward); err != nil {
+		return err
+	}
+
+	applied = r
+	return nil
+}
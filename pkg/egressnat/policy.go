@@ -0,0 +1,100 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressnat
+
+import (
+	"fmt"
+	"net"
+
+	k8sConst "github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	k8sUtils "github.com/cilium/cilium/pkg/k8s/utils"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// podNamespaceLabel is the label matching the namespace of an endpoint
+const podNamespaceLabel = labels.LabelSourceK8sKeyPrefix + k8sConst.PodNamespaceLabel
+
+// Policy is the parsed form of a CiliumEgressNATPolicy
+type Policy struct {
+	// Name is the namespaced name of the policy resource
+	Name string
+
+	// Selector selects the endpoints whose traffic is NATed. It is
+	// restricted to the namespace of the policy resource.
+	Selector api.EndpointSelector
+
+	// DestinationCIDRs are the IPv4 destinations the traffic is NATed for
+	DestinationCIDRs []*net.IPNet
+
+	// GatewayNode is the name of the node masquerading the traffic
+	GatewayNode string
+
+	// EgressIP is the IP the traffic is masqueraded with, nil to use the
+	// IP of the gateway node
+	EgressIP net.IP
+}
+
+// PolicyName returns the namespaced name of p
+func PolicyName(p *v2.CiliumEgressNATPolicy) string {
+	return k8sUtils.ExtractNamespace(&p.ObjectMeta) + "/" + p.ObjectMeta.Name
+}
+
+// ParsePolicy validates p and returns its parsed form
+func ParsePolicy(p *v2.CiliumEgressNATPolicy) (*Policy, error) {
+	if p.ObjectMeta.Name == "" {
+		return nil, fmt.Errorf("CiliumEgressNATPolicy must have name")
+	}
+
+	namespace := k8sUtils.ExtractNamespace(&p.ObjectMeta)
+	policy := &Policy{
+		Name:        PolicyName(p),
+		GatewayNode: p.Spec.GatewayNode,
+	}
+
+	if policy.GatewayNode == "" {
+		return nil, fmt.Errorf("CiliumEgressNATPolicy %s must have gatewayNode", policy.Name)
+	}
+
+	if len(p.Spec.DestinationCIDRs) == 0 {
+		return nil, fmt.Errorf("CiliumEgressNATPolicy %s must have destinationCIDRs", policy.Name)
+	}
+
+	for _, c := range p.Spec.DestinationCIDRs {
+		_, cidr, err := net.ParseCIDR(string(c))
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination CIDR %q: %s", c, err)
+		}
+		if cidr.IP.To4() == nil {
+			return nil, fmt.Errorf("destination CIDR %s is not an IPv4 CIDR", c)
+		}
+		policy.DestinationCIDRs = append(policy.DestinationCIDRs, cidr)
+	}
+
+	if p.Spec.EgressIP != "" {
+		policy.EgressIP = net.ParseIP(p.Spec.EgressIP).To4()
+		if policy.EgressIP == nil {
+			return nil, fmt.Errorf("invalid egress IP %q: not an IPv4 address", p.Spec.EgressIP)
+		}
+	}
+
+	// The selector only applies to endpoints in the namespace of the
+	// policy resource.
+	policy.Selector = api.NewESFromK8sLabelSelector("", p.Spec.EndpointSelector.LabelSelector)
+	policy.Selector.AddMatch(podNamespaceLabel, namespace)
+
+	return policy, nil
+}
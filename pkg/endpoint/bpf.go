@@ -183,6 +183,9 @@ func (e *Endpoint) writeHeaderfile(prefix string, owner Owner) error {
 
 	// Endpoint options
 	fw.WriteString(e.Options.GetFmtList())
+	if e.policyAuditRules && !e.Options.IsEnabled(option.PolicyAuditMode) {
+		fw.WriteString("#define POLICY_AUDIT_MODE\n")
+	}
 
 	if e.L3Policy == nil {
 		WriteIPCachePrefixes(fw, nil)
@@ -472,6 +472,11 @@ type Endpoint struct {
 	// is enabled for this endpoint.
 	egressPolicyEnabled bool
 
+	// policyAuditRules specifies whether all policy rules selecting this
+	// endpoint are audit rules, in which case policy is enforced in audit
+	// mode regardless of the PolicyAuditMode option.
+	policyAuditRules bool
+
 	hasBPFProgram chan struct{}
 
 	///////////////////////
@@ -1861,6 +1866,7 @@ func (e *Endpoint) LeaveLocked(owner Owner, proxyWaitGroup *completion.WaitGroup
 	}
 
 	if e.SecurityIdentity != nil {
+		identityPkg.RemoveIdentityUser(e.SecurityIdentity.ID, e.identityUser())
 		err := e.SecurityIdentity.Release()
 		if err != nil {
 			errors = append(errors, fmt.Errorf("unable to release identity: %s", err))
@@ -2275,6 +2281,12 @@ func APICanModify(e *Endpoint) error {
 	return nil
 }
 
+// identityUser returns the name under which the endpoint is recorded as user
+// of its security identity
+func (e *Endpoint) identityUser() string {
+	return "endpoint:" + e.StringID()
+}
+
 func (e *Endpoint) getIDandLabels() string {
 	e.UnconditionalRLock()
 	defer e.RUnlock()
@@ -2552,6 +2564,8 @@ func (e *Endpoint) identityLabelsChanged(owner Owner, myChangeRev int) error {
 
 	e.Unlock()
 
+	owner.EndpointIdentityChanged(e)
+
 	if readyToRegenerate {
 		e.Regenerate(owner, NewRegenerationContext("updated security labels"))
 	}
@@ -56,6 +56,10 @@ type Owner interface {
 
 	// SendNotification is called to emit an agent notification
 	SendNotification(typ monitor.AgentNotification, text string) error
+
+	// EndpointIdentityChanged is called after a new security identity has
+	// been assigned to the endpoint. The endpoint is not locked.
+	EndpointIdentityChanged(e *Endpoint)
 }
 
 // Request is used to create the endpoint's request and send it to the endpoints
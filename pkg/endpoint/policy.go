@@ -534,6 +534,7 @@ func (e *Endpoint) regeneratePolicy(owner Owner) error {
 	// information to short-circuit policy generation if enforcement is
 	// disabled for ingress and / or egress.
 	e.ingressPolicyEnabled, e.egressPolicyEnabled = e.ComputePolicyEnforcement(repo)
+	e.policyAuditRules = e.ComputePolicyAuditRules(repo)
 
 	l4PolicyChanged, err := e.resolveL4Policy(repo)
 	if err != nil {
@@ -639,6 +640,19 @@ func (e *Endpoint) ComputePolicyEnforcement(repo *policy.Repository) (ingress bo
 	}
 }
 
+// ComputePolicyAuditRules returns whether the policy of the endpoint must be
+// enforced in audit mode because all rules selecting it are audit rules. This
+// only applies if policy enforcement is derived from the rules, i.e. with the
+// default enforcement mode.
+//
+// Must be called with endpoint and repo mutexes held for reading.
+func (e *Endpoint) ComputePolicyAuditRules(repo *policy.Repository) bool {
+	if policy.GetPolicyEnabled() != option.DefaultEnforcement || e.IsInit() {
+		return false
+	}
+	return repo.GetRulesMatchingAudit(e.SecurityIdentity.LabelArray)
+}
+
 // Called with e.Mutex UNlocked
 func (e *Endpoint) regenerate(owner Owner, context *RegenerationContext) (retErr error) {
 	var revision uint64
@@ -979,9 +993,11 @@ func (e *Endpoint) SetIdentity(identity *identityPkg.Identity) {
 	oldIdentity := "no identity"
 	if e.SecurityIdentity != nil {
 		oldIdentity = e.SecurityIdentity.StringID()
+		identityPkg.RemoveIdentityUser(e.SecurityIdentity.ID, e.identityUser())
 	}
 
 	e.SecurityIdentity = identity
+	identityPkg.AddIdentityUser(identity.ID, e.identityUser())
 
 	// Sets endpoint state to ready if was waiting for identity
 	if e.GetStateLocked() == StateWaitingForIdentity {
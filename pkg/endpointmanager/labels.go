@@ -0,0 +1,262 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// labelFilterBatchSize is the number of endpoints re-identified at
+	// once after a change of the label filter
+	labelFilterBatchSize = 16
+
+	// labelFilterBatchInterval is the time waited between two batches of
+	// endpoints re-identified after a change of the label filter
+	labelFilterBatchInterval = time.Second
+
+	// labelFilterMutex protects labelFilterStop
+	labelFilterMutex lock.Mutex
+
+	// labelFilterStop is closed to abort the re-identification of
+	// endpoints for a label filter which has been replaced
+	labelFilterStop chan struct{}
+)
+
+// LabelFilterImpact describes how the security identities of the managed
+// endpoints are affected by a change of the label filter.
+type LabelFilterImpact struct {
+	// IdentitiesBefore is the number of distinct identities in use by
+	// endpoints with the current label filter
+	IdentitiesBefore int
+
+	// IdentitiesAfter is the number of distinct identities in use by
+	// endpoints with the new label filter
+	IdentitiesAfter int
+
+	// IdentitiesMerged is the number of new identities which replace
+	// more than one current identity
+	IdentitiesMerged int
+
+	// IdentitiesSplit is the number of current identities which are
+	// replaced by more than one new identity
+	IdentitiesSplit int
+
+	// EndpointsAffected is the number of endpoints which change identity
+	EndpointsAffected int
+}
+
+// endpointLabelChange is the result of re-filtering the labels of a single
+// endpoint with a new label filter.
+type endpointLabelChange struct {
+	ep *endpoint.Endpoint
+
+	// oldLabels and newLabels are the identity labels of the endpoint
+	// before and after the change
+	oldLabels labels.Labels
+	newLabels labels.Labels
+
+	// identityLabels and infoLabels are the orchestration labels of the
+	// endpoint as split by the new label filter
+	identityLabels labels.Labels
+	infoLabels     labels.Labels
+}
+
+func (c *endpointLabelChange) changed() bool {
+	return !c.oldLabels.Equals(c.newLabels)
+}
+
+// evaluateEndpoint re-filters the orchestration labels of ep with filter.
+// Returns nil if the endpoint is being deleted.
+func evaluateEndpoint(ep *endpoint.Endpoint, filter *labels.LabelFilter) *endpointLabelChange {
+	if err := ep.RLockAlive(); err != nil {
+		return nil
+	}
+	orchestration := labels.Labels{}
+	reserved := labels.Labels{}
+	for _, l := range []labels.Labels{ep.OpLabels.OrchestrationIdentity, ep.OpLabels.Disabled, ep.OpLabels.OrchestrationInfo} {
+		for k, v := range l {
+			// Reserved labels are assigned by the agent itself and
+			// never subject to label filtering
+			if v.Source == labels.LabelSourceReserved {
+				reserved[k] = v.DeepCopy()
+			} else {
+				orchestration[k] = v.DeepCopy()
+			}
+		}
+	}
+	change := &endpointLabelChange{
+		ep:        ep,
+		oldLabels: ep.OpLabels.IdentityLabels(),
+		newLabels: ep.OpLabels.Custom.DeepCopy(),
+	}
+	disabled := ep.OpLabels.Disabled.DeepCopy()
+	ep.RUnlock()
+
+	change.identityLabels, change.infoLabels = filter.FilterLabels(orchestration)
+	for k, v := range reserved {
+		change.identityLabels[k] = v
+	}
+
+	if change.newLabels == nil {
+		change.newLabels = labels.Labels{}
+	}
+	for k, v := range change.identityLabels {
+		if disabled[k] == nil {
+			change.newLabels[k] = v
+		}
+	}
+
+	return change
+}
+
+func evaluateEndpoints(filter *labels.LabelFilter) []*endpointLabelChange {
+	eps := GetEndpoints()
+	changes := make([]*endpointLabelChange, 0, len(eps))
+	for _, ep := range eps {
+		if change := evaluateEndpoint(ep, filter); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// labelFilterImpact calculates the identity changes resulting from changes.
+// Identities are compared by their set of labels.
+func labelFilterImpact(changes []*endpointLabelChange) LabelFilterImpact {
+	impact := LabelFilterImpact{}
+	oldToNew := map[string]map[string]struct{}{}
+	newToOld := map[string]map[string]struct{}{}
+
+	for _, c := range changes {
+		oldKey := string(c.oldLabels.SortedList())
+		newKey := string(c.newLabels.SortedList())
+
+		if oldToNew[oldKey] == nil {
+			oldToNew[oldKey] = map[string]struct{}{}
+		}
+		oldToNew[oldKey][newKey] = struct{}{}
+
+		if newToOld[newKey] == nil {
+			newToOld[newKey] = map[string]struct{}{}
+		}
+		newToOld[newKey][oldKey] = struct{}{}
+
+		if c.changed() {
+			impact.EndpointsAffected++
+		}
+	}
+
+	impact.IdentitiesBefore = len(oldToNew)
+	impact.IdentitiesAfter = len(newToOld)
+
+	for _, n := range oldToNew {
+		if len(n) > 1 {
+			impact.IdentitiesSplit++
+		}
+	}
+
+	for _, o := range newToOld {
+		if len(o) > 1 {
+			impact.IdentitiesMerged++
+		}
+	}
+
+	return impact
+}
+
+// EvaluateLabelFilter returns how the identities of all endpoints would
+// change if filter was used to select the identity relevant labels. No
+// changes are performed.
+func EvaluateLabelFilter(filter *labels.LabelFilter) LabelFilterImpact {
+	return labelFilterImpact(evaluateEndpoints(filter))
+}
+
+// applyInBatches calls apply for all changes, labelFilterBatchSize changes
+// at a time with labelFilterBatchInterval in between. Returns early if stop
+// is closed.
+func applyInBatches(changes []*endpointLabelChange, stop <-chan struct{}, apply func(*endpointLabelChange)) {
+	for i, c := range changes {
+		if i > 0 && i%labelFilterBatchSize == 0 {
+			select {
+			case <-stop:
+				return
+			case <-time.After(labelFilterBatchInterval):
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		default:
+			apply(c)
+		}
+	}
+}
+
+// reidentifyEndpoint updates the labels of ep with filter. The labels are
+// filtered again as they may have changed since the impact of the filter was
+// evaluated.
+func reidentifyEndpoint(owner endpoint.Owner, ep *endpoint.Endpoint, filter *labels.LabelFilter) {
+	c := evaluateEndpoint(ep, filter)
+	if c == nil || !c.changed() {
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		logfields.EndpointID:     c.ep.StringID(),
+		logfields.IdentityLabels: c.newLabels.String(),
+	}).Info("Re-identifying endpoint due to label filter change")
+
+	c.ep.UpdateLabels(owner, c.identityLabels, c.infoLabels)
+}
+
+// ApplyLabelFilter installs filter as the label filter and updates the
+// labels of all endpoints whose identity labels change as a result. The
+// endpoints are updated in batches in the background and keep using their
+// current identity until the new identity has been resolved. A subsequent
+// call aborts the updates which are still pending.
+func ApplyLabelFilter(owner endpoint.Owner, filter *labels.LabelFilter) LabelFilterImpact {
+	labels.SetLabelFilter(filter)
+
+	changes := evaluateEndpoints(filter)
+	affected := make([]*endpointLabelChange, 0, len(changes))
+	for _, c := range changes {
+		if c.changed() {
+			affected = append(affected, c)
+		}
+	}
+
+	labelFilterMutex.Lock()
+	if labelFilterStop != nil {
+		close(labelFilterStop)
+	}
+	stop := make(chan struct{})
+	labelFilterStop = stop
+	labelFilterMutex.Unlock()
+
+	go applyInBatches(affected, stop, func(c *endpointLabelChange) {
+		reidentifyEndpoint(owner, c.ep, filter)
+	})
+
+	return labelFilterImpact(changes)
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package endpointmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type EndpointManagerSuite struct{}
+
+var _ = Suite(&EndpointManagerSuite{})
+
+func newLabelChange(oldLabels, newLabels []string) *endpointLabelChange {
+	return &endpointLabelChange{
+		oldLabels: labels.NewLabelsFromModel(oldLabels),
+		newLabels: labels.NewLabelsFromModel(newLabels),
+	}
+}
+
+func (s *EndpointManagerSuite) TestLabelFilterImpact(c *C) {
+	impact := labelFilterImpact(nil)
+	c.Assert(impact, Equals, LabelFilterImpact{})
+
+	impact = labelFilterImpact([]*endpointLabelChange{
+		// Two identities merge into one
+		newLabelChange([]string{"k8s:app=web", "k8s:tier=a"}, []string{"k8s:app=web"}),
+		newLabelChange([]string{"k8s:app=web", "k8s:tier=b"}, []string{"k8s:app=web"}),
+		// One identity splits into two
+		newLabelChange([]string{"k8s:app=db"}, []string{"k8s:app=db", "k8s:zone=1"}),
+		newLabelChange([]string{"k8s:app=db"}, []string{"k8s:app=db", "k8s:zone=2"}),
+		// Unchanged
+		newLabelChange([]string{"k8s:app=cache"}, []string{"k8s:app=cache"}),
+	})
+	c.Assert(impact, Equals, LabelFilterImpact{
+		IdentitiesBefore:  4,
+		IdentitiesAfter:   4,
+		IdentitiesMerged:  1,
+		IdentitiesSplit:   1,
+		EndpointsAffected: 4,
+	})
+}
+
+func (s *EndpointManagerSuite) TestApplyInBatches(c *C) {
+	prevSize, prevInterval := labelFilterBatchSize, labelFilterBatchInterval
+	defer func() {
+		labelFilterBatchSize, labelFilterBatchInterval = prevSize, prevInterval
+	}()
+	labelFilterBatchSize = 2
+	labelFilterBatchInterval = 10 * time.Millisecond
+
+	changes := make([]*endpointLabelChange, 5)
+	for i := range changes {
+		changes[i] = newLabelChange(nil, nil)
+	}
+
+	applied := 0
+	start := time.Now()
+	applyInBatches(changes, make(chan struct{}), func(*endpointLabelChange) { applied++ })
+	c.Assert(applied, Equals, 5)
+	// Three batches with two intervals in between
+	c.Assert(time.Since(start) >= 2*labelFilterBatchInterval, Equals, true)
+
+	// A replaced label filter stops the pending changes
+	stop := make(chan struct{})
+	applied = 0
+	applyInBatches(changes, stop, func(*endpointLabelChange) {
+		applied++
+		if applied == 2 {
+			close(stop)
+		}
+	})
+	c.Assert(applied, Equals, 2)
+}
@@ -115,12 +115,15 @@ func connectivityStatusHealthy(cs *models.ConnectivityStatus) bool {
 	return cs != nil && cs.Status == ""
 }
 
-func formatConnectivityStatus(w io.Writer, cs *models.ConnectivityStatus, path, indent string) {
+func formatConnectivityStatus(w io.Writer, cs *models.ConnectivityStatus, path, indent string, verbose bool) {
 	status := cs.Status
 	if connectivityStatusHealthy(cs) {
 		latency := time.Duration(cs.Latency)
 		status = fmt.Sprintf("OK, RTT=%s", latency)
 	}
+	if verbose && cs.Loss > 0 {
+		status = fmt.Sprintf("%s, Loss=%.0f%%", status, cs.Loss)
+	}
 	fmt.Fprintf(w, "%s%s:\t%s\n", indent, path, status)
 }
 
@@ -135,26 +138,48 @@ func formatPathStatus(w io.Writer, name string, cp *models.PathStatus, indent st
 	indent = fmt.Sprintf("%s  ", indent)
 
 	if cp.Icmp != nil {
-		formatConnectivityStatus(w, cp.Icmp, "ICMP", indent)
+		formatConnectivityStatus(w, cp.Icmp, "ICMP", indent, verbose)
+	}
+	if verbose && cp.TCP != nil {
+		formatConnectivityStatus(w, cp.TCP, "TCP via L3", indent, verbose)
 	}
 	if cp.HTTP != nil {
-		formatConnectivityStatus(w, cp.HTTP, "HTTP via L3", indent)
+		formatConnectivityStatus(w, cp.HTTP, "HTTP via L3", indent, verbose)
+	}
+	if cp.Pmtu != nil && (verbose || cp.Pmtu.Status != "") {
+		formatPathMTUStatus(w, cp.Pmtu, indent)
 	}
 }
 
+func formatPathMTUStatus(w io.Writer, pmtu *models.PathMTUStatus, indent string) {
+	status := pmtu.Status
+	if status == "" {
+		status = fmt.Sprintf("OK, MTU=%d", pmtu.MTU)
+	}
+	fmt.Fprintf(w, "%sPath MTU:\t%s\n", indent, status)
+}
+
+// PathMTUIsBelowConfigured returns true if the path MTU probed for the given
+// path is lower than the MTU configured by Cilium.
+func PathMTUIsBelowConfigured(cp *models.PathStatus) bool {
+	return cp != nil && cp.Pmtu != nil && cp.Pmtu.MTU > 0 &&
+		cp.Pmtu.MTU < cp.Pmtu.Expected
+}
+
 // PathIsHealthy checks whether ICMP and TCP(HTTP) connectivity to the given
-// path is available.
+// path is available. ICMP and raw TCP probes are optional and only taken into
+// account when they were run.
 func PathIsHealthy(cp *models.PathStatus) bool {
-	if cp == nil {
+	if cp == nil || !connectivityStatusHealthy(cp.HTTP) {
 		return false
 	}
 
-	statuses := []*models.ConnectivityStatus{
+	optional := []*models.ConnectivityStatus{
 		cp.Icmp,
-		cp.HTTP,
+		cp.TCP,
 	}
-	for _, status := range statuses {
-		if !connectivityStatusHealthy(status) {
+	for _, status := range optional {
+		if status != nil && !connectivityStatusHealthy(status) {
 			return false
 		}
 	}
@@ -222,13 +247,18 @@ func formatNodeStatus(w io.Writer, node *models.NodeStatus, printAll, succinct,
 // 'maxLines', if nonzero, determines the maximum number of lines to print
 func FormatHealthStatusResponse(w io.Writer, sr *models.HealthStatusResponse, printAll, succinct, verbose bool, maxLines int) {
 	var (
-		healthy   int
-		localhost *models.NodeStatus
+		healthy     int
+		mtuMismatch int
+		localhost   *models.NodeStatus
 	)
 	for _, node := range sr.Nodes {
 		if nodeIsHealthy(node) {
 			healthy++
 		}
+		if PathMTUIsBelowConfigured(GetHostPrimaryAddress(node)) ||
+			PathMTUIsBelowConfigured(node.Endpoint) {
+			mtuMismatch++
+		}
 		if nodeIsLocalhost(node, sr.Local) {
 			localhost = node
 		}
@@ -236,6 +266,10 @@ func FormatHealthStatusResponse(w io.Writer, sr *models.HealthStatusResponse, pr
 	if succinct {
 		fmt.Fprintf(w, "Cluster health:\t%d/%d reachable\t(%s)\n",
 			healthy, len(sr.Nodes), sr.Timestamp)
+		if mtuMismatch > 0 {
+			fmt.Fprintf(w, "Path MTU:\tWarning\t%d/%d nodes below configured MTU\n",
+				mtuMismatch, len(sr.Nodes))
+		}
 		if printAll || healthy < len(sr.Nodes) {
 			fmt.Fprintf(w, "  Name\tIP\tReachable\tEndpoints reachable\n")
 		}
@@ -287,3 +321,47 @@ func GetAndFormatHealthStatus(w io.Writer, succinct, verbose bool, maxLines int)
 	}
 	FormatHealthStatusResponse(w, hr.Payload, verbose, succinct, verbose, maxLines)
 }
+
+// FormatHealthStatusHistory writes the connectivity history of all paths in
+// a HealthStatusResponse as a table to the writer.
+func FormatHealthStatusHistory(w io.Writer, sr *models.HealthStatusResponse) {
+	fmt.Fprintf(w, "Probe time:\t%s\n", sr.Timestamp)
+	fmt.Fprintf(w, "Name\tIP\tProbe\tSamples\tLoss\tMin\tAvg\tMax\tP99\tFlaps\n")
+
+	nodes := sr.Nodes
+	sort.Slice(nodes, func(i, j int) bool {
+		return strings.Compare(nodes[i].Name, nodes[j].Name) < 0
+	})
+	for _, node := range nodes {
+		paths := []*models.PathStatus{GetHostPrimaryAddress(node)}
+		if node.Host != nil {
+			paths = append(paths, node.Host.SecondaryAddresses...)
+		}
+		paths = append(paths, node.Endpoint)
+
+		for _, path := range paths {
+			if path == nil {
+				continue
+			}
+			probes := []struct {
+				name   string
+				status *models.ConnectivityStatus
+			}{
+				{"ICMP", path.Icmp},
+				{"TCP", path.TCP},
+				{"HTTP", path.HTTP},
+			}
+			for _, probe := range probes {
+				if probe.status == nil || probe.status.History == nil {
+					continue
+				}
+				h := probe.status.History
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.1f%%\t%s\t%s\t%s\t%s\t%d\n",
+					node.Name, path.IP, probe.name, h.Samples, h.Loss,
+					time.Duration(h.LatencyMin), time.Duration(h.LatencyAvg),
+					time.Duration(h.LatencyMax), time.Duration(h.LatencyP99),
+					h.Flaps)
+			}
+		}
+	}
+}
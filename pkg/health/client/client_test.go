@@ -17,8 +17,11 @@
 package client
 
 import (
+	"bytes"
 	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/health/models"
 
@@ -67,6 +70,7 @@ func (s *ClientTestSuite) TestFormatNodeStatus(c *C) {
 				pathStatus := &models.PathStatus{
 					HTTP: connectivityStatusHTTP,
 					Icmp: connectivityStatusICMP,
+					TCP:  connectivityStatusICMP,
 					IP:   possibleIP,
 				}
 				possiblePathStatuses = append(possiblePathStatuses, pathStatus)
@@ -118,6 +122,69 @@ func (s *ClientTestSuite) TestFormatNodeStatus(c *C) {
 	}
 }
 
+func (s *ClientTestSuite) TestPathIsHealthy(c *C) {
+	good := &models.ConnectivityStatus{Latency: 1}
+	lossy := &models.ConnectivityStatus{Latency: 1, Loss: 50}
+	bad := &models.ConnectivityStatus{Status: "Connection timed out", Loss: 100}
+
+	c.Assert(PathIsHealthy(nil), Equals, false)
+	c.Assert(PathIsHealthy(&models.PathStatus{}), Equals, false)
+	c.Assert(PathIsHealthy(&models.PathStatus{HTTP: good}), Equals, true)
+	c.Assert(PathIsHealthy(&models.PathStatus{HTTP: bad}), Equals, false)
+	c.Assert(PathIsHealthy(&models.PathStatus{HTTP: good, Icmp: lossy, TCP: good}), Equals, true)
+	c.Assert(PathIsHealthy(&models.PathStatus{HTTP: good, Icmp: bad}), Equals, false)
+	c.Assert(PathIsHealthy(&models.PathStatus{HTTP: good, TCP: bad}), Equals, false)
+}
+
+func (s *ClientTestSuite) TestFormatConnectivityStatus(c *C) {
+	cs := &models.ConnectivityStatus{Latency: int64(time.Millisecond), Loss: 25}
+
+	buf := &bytes.Buffer{}
+	formatConnectivityStatus(buf, cs, "TCP", "", false)
+	c.Assert(buf.String(), Equals, "TCP:\tOK, RTT=1ms\n")
+
+	buf.Reset()
+	formatConnectivityStatus(buf, cs, "TCP", "", true)
+	c.Assert(buf.String(), Equals, "TCP:\tOK, RTT=1ms, Loss=25%\n")
+}
+
+func (s *ClientTestSuite) TestPathMTU(c *C) {
+	good := &models.ConnectivityStatus{Latency: 1}
+	low := &models.PathStatus{
+		IP:   "192.168.1.1",
+		HTTP: good,
+		Pmtu: &models.PathMTUStatus{
+			MTU:      1450,
+			Expected: 1500,
+			Status:   "Path MTU 1450 is below the configured MTU 1500",
+		},
+	}
+	ok := &models.PathStatus{
+		IP:   "192.168.1.2",
+		HTTP: good,
+		Pmtu: &models.PathMTUStatus{MTU: 1500, Expected: 1500},
+	}
+	c.Assert(PathMTUIsBelowConfigured(nil), Equals, false)
+	c.Assert(PathMTUIsBelowConfigured(&models.PathStatus{}), Equals, false)
+	c.Assert(PathMTUIsBelowConfigured(ok), Equals, false)
+	c.Assert(PathMTUIsBelowConfigured(low), Equals, true)
+
+	sr := &models.HealthStatusResponse{
+		Nodes: []*models.NodeStatus{
+			{Name: "node1", Host: &models.HostStatus{PrimaryAddress: low}},
+			{Name: "node2", Host: &models.HostStatus{PrimaryAddress: ok}},
+		},
+	}
+	buf := &bytes.Buffer{}
+	FormatHealthStatusResponse(buf, sr, false, true, false, 0)
+	c.Assert(strings.Contains(buf.String(), "Path MTU:\tWarning\t1/2 nodes below configured MTU"), Equals, true)
+
+	buf.Reset()
+	FormatHealthStatusResponse(buf, sr, true, false, false, 0)
+	c.Assert(strings.Contains(buf.String(), "Path MTU:\tPath MTU 1450 is below the configured MTU 1500"), Equals, true)
+	c.Assert(strings.Contains(buf.String(), "OK, MTU=1500"), Equals, false)
+}
+
 func (s *ClientTestSuite) TestGetHostPrimaryAddress(c *C) {
 	nilHostNS := &models.NodeStatus{
 		Host: nil,
@@ -0,0 +1,174 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/health/models"
+)
+
+// probeSample is the result of a single probe of a path.
+type probeSample struct {
+	healthy bool
+	latency time.Duration
+	loss    float64
+}
+
+// pathHistory is a sliding window over the most recent samples of a path.
+type pathHistory struct {
+	samples []probeSample
+}
+
+// record appends the sample derived from 'cs' to the history, dropping the
+// oldest samples so that at most 'size' samples are kept.
+func (h *pathHistory) record(cs *models.ConnectivityStatus, size int) {
+	sample := probeSample{
+		healthy: cs.Status == "",
+		loss:    cs.Loss,
+	}
+	if sample.healthy {
+		sample.latency = time.Duration(cs.Latency)
+	} else {
+		sample.loss = 100
+	}
+
+	h.samples = append(h.samples, sample)
+	if len(h.samples) > size {
+		h.samples = append(h.samples[:0], h.samples[len(h.samples)-size:]...)
+	}
+}
+
+// summary computes the statistics over all samples in the window.
+func (h *pathHistory) summary() *models.ConnectivityHistory {
+	result := &models.ConnectivityHistory{
+		Samples: int64(len(h.samples)),
+	}
+	if len(h.samples) == 0 {
+		return result
+	}
+
+	var (
+		loss      float64
+		total     time.Duration
+		latencies []time.Duration
+	)
+	for i, s := range h.samples {
+		loss += s.loss
+		if i > 0 && s.healthy != h.samples[i-1].healthy {
+			result.Flaps++
+		}
+		if s.healthy {
+			total += s.latency
+			latencies = append(latencies, s.latency)
+		}
+	}
+	result.Loss = loss / float64(len(h.samples))
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+		// Nearest-rank percentile
+		p99 := (len(latencies)*99+99)/100 - 1
+		result.LatencyMin = latencies[0].Nanoseconds()
+		result.LatencyMax = latencies[len(latencies)-1].Nanoseconds()
+		result.LatencyAvg = (total / time.Duration(len(latencies))).Nanoseconds()
+		result.LatencyP99 = latencies[p99].Nanoseconds()
+	}
+
+	return result
+}
+
+// historyKey identifies the probes of one protocol towards one IP.
+type historyKey struct {
+	ip    ipString
+	probe string
+}
+
+// connectivityHistory keeps a pathHistory for every probed path.
+type connectivityHistory struct {
+	size  int
+	paths map[historyKey]*pathHistory
+}
+
+func newConnectivityHistory(size int) *connectivityHistory {
+	return &connectivityHistory{
+		size:  size,
+		paths: make(map[historyKey]*pathHistory),
+	}
+}
+
+// recordReport records all path statuses in 'report' and annotates them with
+// the statistics over their history. Histories of paths which are no longer
+// part of the report are discarded.
+func (c *connectivityHistory) recordReport(report *healthReport) {
+	seen := make(map[historyKey]struct{}, len(c.paths))
+	for _, node := range report.nodes {
+		for _, path := range nodePaths(node) {
+			c.recordPath(path, seen)
+		}
+	}
+
+	for key := range c.paths {
+		if _, ok := seen[key]; !ok {
+			delete(c.paths, key)
+		}
+	}
+}
+
+func (c *connectivityHistory) recordPath(path *models.PathStatus, seen map[historyKey]struct{}) {
+	probes := map[string]**models.ConnectivityStatus{
+		"icmp": &path.Icmp,
+		"http": &path.HTTP,
+		"tcp":  &path.TCP,
+	}
+	for probe, cs := range probes {
+		if *cs == nil {
+			continue
+		}
+		key := historyKey{ip: ipString(path.IP), probe: probe}
+		h, ok := c.paths[key]
+		if !ok {
+			h = &pathHistory{}
+			c.paths[key] = h
+		}
+		h.record(*cs, c.size)
+		seen[key] = struct{}{}
+
+		// The status may be shared with the prober, so annotate a copy.
+		status := **cs
+		status.History = h.summary()
+		*cs = &status
+	}
+}
+
+// nodePaths returns all non-nil paths of the node status.
+func nodePaths(node *models.NodeStatus) []*models.PathStatus {
+	paths := []*models.PathStatus{node.Endpoint}
+	if node.Host != nil {
+		paths = append(paths, node.Host.PrimaryAddress)
+		paths = append(paths, node.Host.SecondaryAddresses...)
+	}
+
+	result := make([]*models.PathStatus, 0, len(paths))
+	for _, p := range paths {
+		if p != nil {
+			result = append(result, p)
+		}
+	}
+	return result
+}
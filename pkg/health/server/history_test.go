@@ -0,0 +1,84 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package server
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/health/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type HistoryTestSuite struct{}
+
+var _ = Suite(&HistoryTestSuite{})
+
+func (s *HistoryTestSuite) TestPathHistory(c *C) {
+	h := &pathHistory{}
+	c.Assert(h.summary(), DeepEquals, &models.ConnectivityHistory{})
+
+	statuses := []*models.ConnectivityStatus{
+		{Status: "Connection timed out"},
+		{Latency: int64(3 * time.Millisecond)},
+		{Latency: int64(1 * time.Millisecond), Loss: 50},
+		{Status: "Connection timed out"},
+		{Latency: int64(2 * time.Millisecond)},
+	}
+	for _, cs := range statuses {
+		h.record(cs, 4)
+	}
+
+	// The first sample fell out of the window.
+	c.Assert(h.summary(), DeepEquals, &models.ConnectivityHistory{
+		Samples:    4,
+		Loss:       37.5,
+		Flaps:      2,
+		LatencyMin: time.Millisecond.Nanoseconds(),
+		LatencyAvg: (2 * time.Millisecond).Nanoseconds(),
+		LatencyMax: (3 * time.Millisecond).Nanoseconds(),
+		LatencyP99: (3 * time.Millisecond).Nanoseconds(),
+	})
+}
+
+func (s *HistoryTestSuite) TestRecordReport(c *C) {
+	history := newConnectivityHistory(10)
+	report := func(ip string, cs *models.ConnectivityStatus) *healthReport {
+		return &healthReport{
+			nodes: []*models.NodeStatus{{
+				Name: "node1",
+				Host: &models.HostStatus{
+					PrimaryAddress: &models.PathStatus{IP: ip, HTTP: cs},
+				},
+			}},
+		}
+	}
+
+	shared := &models.ConnectivityStatus{Latency: 1}
+	r := report("10.0.0.1", shared)
+	history.recordReport(r)
+	c.Assert(shared.History, IsNil)
+	c.Assert(r.nodes[0].Host.PrimaryAddress.HTTP.History.Samples, Equals, int64(1))
+
+	history.recordReport(report("10.0.0.1", &models.ConnectivityStatus{Latency: 1}))
+	c.Assert(history.paths[historyKey{ip: "10.0.0.1", probe: "http"}].samples, HasLen, 2)
+
+	// Paths which disappear from the report are forgotten.
+	history.recordReport(report("10.0.0.2", &models.ConnectivityStatus{Latency: 1}))
+	c.Assert(history.paths, HasLen, 1)
+	c.Assert(history.paths[historyKey{ip: "10.0.0.2", probe: "http"}].samples, HasLen, 1)
+}
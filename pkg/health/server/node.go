@@ -53,6 +53,16 @@ func (n *healthNode) HealthIP() string {
 	return n.NodeElement.HealthEndpointAddress.IPV6.IP
 }
 
+// isHealthIP returns true if 'ip' is an address of the node's health endpoint.
+func (n *healthNode) isHealthIP(ip string) bool {
+	addr := n.NodeElement.HealthEndpointAddress
+	if addr == nil {
+		return false
+	}
+	return (addr.IPV4 != nil && addr.IPV4.IP == ip) ||
+		(addr.IPV6 != nil && addr.IPV6.IP == ip)
+}
+
 // Addresses returns a map of the node's addresses -> "primary" bool
 func (n *healthNode) Addresses() map[*models.NodeAddressingElement]bool {
 	addresses := map[*models.NodeAddressingElement]bool{}
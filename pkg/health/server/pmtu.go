@@ -0,0 +1,211 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// minMTUv4 is the smallest MTU which is probed for IPv4 paths. Every
+	// IPv4 host must be able to receive datagrams of this size (RFC 791).
+	minMTUv4 = 576
+
+	// minMTUv6 is the minimum link MTU required by IPv6 (RFC 8200).
+	minMTUv6 = 1280
+
+	ipv4HeaderLen = 20
+	ipv6HeaderLen = 40
+	icmpHeaderLen = 8
+
+	protocolICMP     = 1
+	protocolIPv6ICMP = 58
+)
+
+// pmtuProber discovers the path MTU towards peers by sending ICMP echo
+// requests of decreasing size with fragmentation disabled.
+type pmtuProber struct {
+	conn    *net.IPConn
+	ipv6    bool
+	id      int
+	seq     int
+	timeout time.Duration
+}
+
+// newPMTUProber opens a raw ICMP socket which sets the DF bit on all packets
+// and ignores any cached path MTU, so that each probe measures the path.
+func newPMTUProber(ipv6 bool, timeout time.Duration) (*pmtuProber, error) {
+	network, level, opt := "ip4:icmp", unix.IPPROTO_IP, unix.IP_MTU_DISCOVER
+	if ipv6 {
+		network, level, opt = "ip6:ipv6-icmp", unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER
+	}
+
+	conn, err := net.ListenIP(network, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	var sockErr error
+	err = raw.Control(func(fd uintptr) {
+		// IP_PMTUDISC_PROBE and IPV6_PMTUDISC_PROBE share the same value.
+		sockErr = unix.SetsockoptInt(int(fd), level, opt, unix.IP_PMTUDISC_PROBE)
+	})
+	if err == nil {
+		err = sockErr
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to disable fragmentation: %s", err)
+	}
+
+	return &pmtuProber{
+		conn:    conn,
+		ipv6:    ipv6,
+		id:      (os.Getpid() ^ rand.Int()) & 0xffff,
+		timeout: timeout,
+	}, nil
+}
+
+// Close closes the underlying socket.
+func (p *pmtuProber) Close() error {
+	return p.conn.Close()
+}
+
+// echo sends an ICMP echo request of 'size' bytes including the IP header to
+// 'dst' and reports whether a matching reply was received before timing out.
+func (p *pmtuProber) echo(dst *net.IPAddr, size int) (bool, error) {
+	p.seq = (p.seq + 1) & 0xffff
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{
+			ID:   p.id,
+			Seq:  p.seq,
+			Data: make([]byte, size-ipv4HeaderLen-icmpHeaderLen),
+		},
+	}
+	proto := protocolICMP
+	if p.ipv6 {
+		msg.Type = ipv6.ICMPTypeEchoRequest
+		msg.Body.(*icmp.Echo).Data = make([]byte, size-ipv6HeaderLen-icmpHeaderLen)
+		proto = protocolIPv6ICMP
+	}
+
+	// The kernel calculates the ICMPv6 checksum for raw sockets.
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return false, err
+	}
+	if _, err := p.conn.WriteTo(b, dst); err != nil {
+		if opErr, ok := err.(*net.OpError); ok {
+			if sysErr, ok := opErr.Err.(*os.SyscallError); ok && sysErr.Err == syscall.EMSGSIZE {
+				// Larger than the MTU of the local interface.
+				return false, nil
+			}
+		}
+		return false, err
+	}
+
+	buf := make([]byte, size+ipv6HeaderLen)
+	p.conn.SetReadDeadline(time.Now().Add(p.timeout))
+	for {
+		n, peer, err := p.conn.ReadFrom(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return false, nil
+			}
+			return false, err
+		}
+		if peerIP, ok := peer.(*net.IPAddr); !ok || !peerIP.IP.Equal(dst.IP) {
+			continue
+		}
+
+		reply, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil {
+			continue
+		}
+		if reply.Type != ipv4.ICMPTypeEchoReply && reply.Type != ipv6.ICMPTypeEchoReply {
+			continue
+		}
+		if echo, ok := reply.Body.(*icmp.Echo); ok && echo.ID == p.id && echo.Seq == p.seq {
+			return true, nil
+		}
+	}
+}
+
+// probe returns the largest packet size up to 'max' which reaches 'dst'.
+// It first attempts 'max' itself and then narrows down the path MTU using
+// probes of decreasing size, down to the minimum MTU of the address family.
+func (p *pmtuProber) probe(dst *net.IPAddr, max int) (int, error) {
+	return searchMTU(func(size int) (bool, error) {
+		return p.echo(dst, size)
+	}, p.minMTU(), max)
+}
+
+func (p *pmtuProber) minMTU() int {
+	if p.ipv6 {
+		return minMTUv6
+	}
+	return minMTUv4
+}
+
+// searchMTU finds the largest size in [min, max] for which 'probe' succeeds,
+// assuming that all sizes below the path MTU succeed and all sizes above it
+// fail. It returns an error if even 'min' does not succeed.
+func searchMTU(probe func(size int) (bool, error), min, max int) (int, error) {
+	if max < min {
+		min = max
+	}
+
+	ok, err := probe(max)
+	if err != nil || ok {
+		return max, err
+	}
+	if ok, err = probe(min); err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, fmt.Errorf("no reply to probes of %d bytes", min)
+	}
+
+	// Invariant: 'good' reaches the peer, 'bad' does not.
+	good, bad := min, max
+	for bad-good > 1 {
+		size := good + (bad-good)/2
+		ok, err := probe(size)
+		if err != nil {
+			return good, err
+		}
+		if ok {
+			good = size
+		} else {
+			bad = size
+		}
+	}
+	return good, nil
+}
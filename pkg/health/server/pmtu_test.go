@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package server
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+)
+
+type PMTUTestSuite struct{}
+
+var _ = Suite(&PMTUTestSuite{})
+
+func fakePath(mtu int, probed *[]int) func(int) (bool, error) {
+	return func(size int) (bool, error) {
+		*probed = append(*probed, size)
+		return size <= mtu, nil
+	}
+}
+
+func (s *PMTUTestSuite) TestSearchMTU(c *C) {
+	var probed []int
+
+	mtu, err := searchMTU(fakePath(1500, &probed), minMTUv4, 1500)
+	c.Assert(err, IsNil)
+	c.Assert(mtu, Equals, 1500)
+	c.Assert(probed, DeepEquals, []int{1500})
+
+	probed = nil
+	mtu, err = searchMTU(fakePath(1450, &probed), minMTUv4, 1500)
+	c.Assert(err, IsNil)
+	c.Assert(mtu, Equals, 1450)
+	c.Assert(probed[:2], DeepEquals, []int{1500, minMTUv4})
+
+	probed = nil
+	mtu, err = searchMTU(fakePath(minMTUv4, &probed), minMTUv4, 9000)
+	c.Assert(err, IsNil)
+	c.Assert(mtu, Equals, minMTUv4)
+
+	probed = nil
+	_, err = searchMTU(fakePath(0, &probed), minMTUv4, 1500)
+	c.Assert(err, NotNil)
+
+	failing := func(size int) (bool, error) {
+		return false, errors.New("network unreachable")
+	}
+	_, err = searchMTU(failing, minMTUv4, 1500)
+	c.Assert(err, NotNil)
+}
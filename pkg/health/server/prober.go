@@ -17,7 +17,9 @@ package server
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cilium/cilium/api/v1/health/models"
@@ -38,7 +40,6 @@ type healthReport struct {
 }
 
 type prober struct {
-	*fastping.Pinger
 	server *Server
 
 	// 'stop' is closed upon a call to prober.Stop(). When the stopping is
@@ -57,6 +58,9 @@ type prober struct {
 	start   time.Time
 	results map[ipString]*models.PathStatus
 	nodes   nodeMap
+
+	// onCycle is invoked by RunLoop() at the end of each probe cycle.
+	onCycle func()
 }
 
 // copyResultRLocked makes a copy of the path status for the specified IP.
@@ -72,12 +76,14 @@ func (p *prober) copyResultRLocked(ip string) *models.PathStatus {
 	paths := map[**models.ConnectivityStatus]*models.ConnectivityStatus{
 		&result.Icmp: status.Icmp,
 		&result.HTTP: status.HTTP,
+		&result.TCP:  status.TCP,
 	}
 	for res, value := range paths {
 		if value != nil {
 			*res = &*value
 		}
 	}
+	result.Pmtu = status.Pmtu
 	return result
 }
 
@@ -175,20 +181,18 @@ func (p *prober) sweepIPsLocked() {
 	for ip, node := range p.nodes {
 		if node.deletionMark {
 			// Remove deleted nodes from:
-			// * Results (accessed from ICMP pinger or TCP prober)
-			// * ICMP pinger
-			// * TCP prober
+			// * Results (accessed from ICMP or TCP prober)
+			// * ICMP and TCP prober
 			for elem := range node.Addresses() {
 				delete(p.results, ipString(elem.IP))
-				p.RemoveIP(elem.IP) // ICMP pinger
 			}
-			delete(p.nodes, ip) // TCP prober
+			delete(p.nodes, ip)
 		}
 	}
 }
 
-// setNodes sets the list of nodes for the prober, and updates the pinger to
-// start sending pings to all of the nodes.
+// setNodes sets the list of nodes for the prober, subsequent probe cycles
+// send pings to all of the nodes.
 // setNodes will steal references to nodes referenced from 'nodes', so the
 // caller should not modify them after a call to setNodes.
 func (p *prober) setNodes(nodes nodeMap) {
@@ -210,7 +214,6 @@ func (p *prober) setNodes(nodes nodeMap) {
 				result.Status = "Failed to resolve IP"
 			} else {
 				result.Status = "Connection timed out"
-				p.AddIPAddr(addr)
 				p.nodes[ip] = n
 			}
 
@@ -219,13 +222,158 @@ func (p *prober) setNodes(nodes nodeMap) {
 					IP: elem.IP,
 				}
 			}
-			p.results[ip].Icmp = result
+			if p.server.ICMPProbeCount > 0 {
+				p.results[ip].Icmp = result
+			}
 		}
 	}
 
 	p.sweepIPsLocked()
 }
 
+// summarizeProbes converts the round-trip times of 'sent' probes into a
+// connectivity status, reporting the mean latency of the successful probes
+// and the percentage of probes which were lost.
+func summarizeProbes(rtts []time.Duration, sent int) *models.ConnectivityStatus {
+	result := &models.ConnectivityStatus{}
+	if sent <= 0 {
+		return result
+	}
+
+	received := len(rtts)
+	if received > sent {
+		received = sent
+	}
+	result.Loss = float64(sent-received) * 100 / float64(sent)
+	if received == 0 {
+		result.Status = "Connection timed out"
+		return result
+	}
+
+	var total time.Duration
+	for _, rtt := range rtts[:received] {
+		total += rtt
+	}
+	result.Latency = (total / time.Duration(received)).Nanoseconds()
+	return result
+}
+
+// icmpDeadline returns the time to wait for each ICMP echo reply. The
+// deadline is shortened if required so that all ICMPProbeCount echo requests
+// of a probe cycle complete within the probe interval.
+func (p *prober) icmpDeadline(count int) time.Duration {
+	deadline := p.server.ProbeDeadline
+	if max := p.server.ProbeInterval / time.Duration(count); max > 0 && max < deadline {
+		deadline = max
+	}
+	return deadline
+}
+
+// icmpProbe sends 'count' echo requests to the specified IP, and summarizes
+// the round-trip times of the replies.
+func (p *prober) icmpProbe(node string, addr *net.IPAddr, count int) (*models.ConnectivityStatus, error) {
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.NodeName: node,
+		logfields.IPAddr:   addr,
+	})
+
+	rtts := make([]time.Duration, 0, count)
+	pinger := fastping.NewPinger()
+	pinger.MaxRTT = p.icmpDeadline(count)
+	pinger.AddIPAddr(addr)
+	pinger.OnRecv = func(_ *net.IPAddr, rtt time.Duration) {
+		scopedLog.WithField("rtt", rtt).Debug("Probe successful")
+		rtts = append(rtts, rtt)
+	}
+
+	for i := 0; i < count; i++ {
+		if err := pinger.Run(); err != nil {
+			return nil, err
+		}
+	}
+
+	return summarizeProbes(rtts, count), nil
+}
+
+// runICMPProbe sends ICMPProbeCount echo requests to each node IP known to
+// the prober and summarizes the replies into the ICMP results. All IPs are
+// probed concurrently so a probe cycle does not grow with the number of
+// nodes.
+func (p *prober) runICMPProbe() error {
+	count := p.server.ICMPProbeCount
+	if count <= 0 {
+		return nil
+	}
+
+	nodes := make(map[ipString]string)
+	p.RLock()
+	for ip, node := range p.nodes {
+		nodes[ip] = node.Name
+	}
+	p.RUnlock()
+
+	var (
+		wg       sync.WaitGroup
+		errMutex lock.Mutex
+		firstErr error
+	)
+	for ip, name := range nodes {
+		addr, err := net.ResolveIPAddr("ip", string(ip))
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(ip ipString, name string, addr *net.IPAddr) {
+			defer wg.Done()
+
+			status, err := p.icmpProbe(name, addr, count)
+			if err != nil {
+				errMutex.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errMutex.Unlock()
+				return
+			}
+
+			p.Lock()
+			if res, ok := p.results[ip]; ok {
+				res.Icmp = status
+			}
+			p.Unlock()
+		}(ip, name, addr)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// tcpProbe establishes 'count' TCP connections to the health port of the
+// specified IP, and summarizes the time taken to connect.
+func (p *prober) tcpProbe(node string, ip string, port int, count int) *models.ConnectivityStatus {
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.NodeName: node,
+		logfields.IPAddr:   ip,
+		logfields.Port:     port,
+	})
+
+	rtts := make([]time.Duration, 0, count)
+	addr := net.JoinHostPort(ip, strconv.Itoa(port))
+	for i := 0; i < count; i++ {
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", addr, p.server.ProbeDeadline)
+		if err != nil {
+			scopedLog.WithError(err).Debug("TCP connect failed")
+			continue
+		}
+		rtts = append(rtts, time.Since(start))
+		conn.Close()
+	}
+
+	return summarizeProbes(rtts, count)
+}
+
 func (p *prober) httpProbe(node string, ip string, port int) *models.ConnectivityStatus {
 	result := &models.ConnectivityStatus{}
 
@@ -260,11 +408,6 @@ func (p *prober) httpProbe(node string, ip string, port int) *models.Connectivit
 }
 
 func (p *prober) runHTTPProbe() {
-	startTime := time.Now()
-	p.Lock()
-	p.start = startTime
-	p.Unlock()
-
 	// p.nodes is mapped from all known IPs -> nodes in N:M configuration,
 	// so multiple IPs could refer to the same node. To ensure we only
 	// ping each node once, deduplicate nodes into map of nodeName -> []IP.
@@ -305,15 +448,19 @@ func (p *prober) runHTTPProbe() {
 					}).Debugf("Failed to probe: %s", status.HTTP.Status)
 				}
 			}
+			if count := p.server.TCPProbeCount; count > 0 {
+				status.TCP = p.tcpProbe(name, ip.String(), defaults.HTTPPathPort, count)
+			}
 
 			peer := ipString(ip.String())
 			p.Lock()
 			if _, ok := p.results[peer]; ok {
 				p.results[peer].HTTP = status.HTTP
+				p.results[peer].TCP = status.TCP
 			} else {
 				// While we weren't holding the lock, the
-				// pinger's OnIdle() callback fired and updated
-				// the set of nodes to remove this node.
+				// set of nodes was updated to remove this
+				// node.
 				scopedLog.Debug("Node disappeared before result written")
 			}
 			p.Unlock()
@@ -321,6 +468,89 @@ func (p *prober) runHTTPProbe() {
 	}
 }
 
+// runPMTUProbe discovers the path MTU towards every IP known to the prober
+// and compares it against the MTU which Cilium configured for the path.
+func (p *prober) runPMTUProbe() {
+	if !p.server.PMTUProbe {
+		return
+	}
+
+	deviceMTU, routeMTU, err := p.server.getConfiguredMTU()
+	if err != nil {
+		log.WithError(err).Debug("Unable to determine configured MTU")
+		return
+	}
+
+	type target struct {
+		node     string
+		ip       ipString
+		addr     *net.IPAddr
+		expected int
+	}
+	targets := []target{}
+	p.RLock()
+	for ip, node := range p.nodes {
+		addr, err := net.ResolveIPAddr("ip", string(ip))
+		if err != nil {
+			continue
+		}
+		// Traffic towards the health endpoint follows the routes
+		// installed by Cilium, which account for tunnel overhead.
+		expected := deviceMTU
+		if node.isHealthIP(string(ip)) {
+			expected = routeMTU
+		}
+		targets = append(targets, target{node.Name, ip, addr, expected})
+	}
+	p.RUnlock()
+
+	probers := map[bool]*pmtuProber{}
+	defer func() {
+		for _, pp := range probers {
+			pp.Close()
+		}
+	}()
+
+	for _, t := range targets {
+		scopedLog := log.WithFields(logrus.Fields{
+			logfields.NodeName: t.node,
+			logfields.IPAddr:   t.ip,
+		})
+
+		status := &models.PathMTUStatus{Expected: int64(t.expected)}
+		v6 := !isIPv4(string(t.ip))
+		pp, ok := probers[v6]
+		if !ok {
+			if pp, err = newPMTUProber(v6, p.server.ProbeDeadline); err != nil {
+				scopedLog.WithError(err).Warn("Unable to probe path MTU")
+				return
+			}
+			probers[v6] = pp
+		}
+
+		mtu, err := pp.probe(t.addr, t.expected)
+		if err != nil {
+			scopedLog.WithError(err).Debug("Path MTU probe failed")
+			status.Status = err.Error()
+		} else {
+			status.MTU = int64(mtu)
+			if mtu < t.expected {
+				status.Status = fmt.Sprintf("Path MTU %d is below the configured MTU %d", mtu, t.expected)
+				scopedLog.WithFields(logrus.Fields{
+					"mtu":      mtu,
+					"expected": t.expected,
+				}).Warn("Path MTU is below the configured MTU")
+			}
+		}
+
+		p.Lock()
+		if res, ok := p.results[t.ip]; ok {
+			res.Pmtu = status
+		}
+		p.Unlock()
+	}
+}
+
 // Done returns a channel that is closed when RunLoop() is stopped by an error.
 // It must be called after the RunLoop() call.
 func (p *prober) Done() <-chan bool {
@@ -330,15 +560,20 @@ func (p *prober) Done() <-chan bool {
 // Run sends a single probes out to all of the other cilium nodes to gather
 // connectivity status for the cluster.
 func (p *prober) Run() error {
-	err := p.Pinger.Run()
+	startTime := time.Now()
+	p.Lock()
+	p.start = startTime
+	p.Unlock()
+
+	err := p.runICMPProbe()
 	p.runHTTPProbe()
+	p.runPMTUProbe()
 	return err
 }
 
 // Stop disrupts the currently running RunLoop(). This may only be called after
 // a call to RunLoop().
 func (p *prober) Stop() {
-	p.Pinger.Stop()
 	close(p.stop)
 	<-p.proberExited
 	close(p.done)
@@ -351,9 +586,19 @@ func (p *prober) Stop() {
 // stop sending packets, call Stop().
 func (p *prober) RunLoop() {
 	// FIXME: Spread the probes out across the probing interval
-	p.Pinger.RunLoop()
-
 	go func() {
+		runCycle := func() {
+			if err := p.Run(); err != nil {
+				log.WithError(err).Debug("Failed to send ICMP probes")
+			}
+			if p.onCycle != nil {
+				p.onCycle()
+			}
+		}
+
+		// Run it once at the start so we get some initial status
+		runCycle()
+
 		tick := time.NewTicker(p.server.ProbeInterval)
 	loop:
 		for {
@@ -361,7 +606,7 @@ func (p *prober) RunLoop() {
 			case <-p.stop:
 				break loop
 			case <-tick.C:
-				p.runHTTPProbe()
+				runCycle()
 				continue
 			}
 		}
@@ -374,7 +619,6 @@ func (p *prober) RunLoop() {
 // the prober to populate its 'results' map.
 func newProber(s *Server, nodes nodeMap) *prober {
 	prober := &prober{
-		Pinger:       fastping.NewPinger(),
 		server:       s,
 		done:         make(chan bool),
 		proberExited: make(chan bool),
@@ -382,31 +626,7 @@ func newProber(s *Server, nodes nodeMap) *prober {
 		results:      make(map[ipString]*models.PathStatus),
 		nodes:        make(nodeMap),
 	}
-	prober.MaxRTT = s.ProbeDeadline
 
 	prober.setNodes(nodes)
-	prober.OnRecv = func(addr *net.IPAddr, rtt time.Duration) {
-		prober.Lock()
-		defer prober.Unlock()
-		node, exists := prober.nodes[ipString(addr.String())]
-
-		scopedLog := log.WithFields(logrus.Fields{
-			logfields.IPAddr: addr,
-			"rtt":            rtt,
-		})
-		if !exists {
-			scopedLog.Debugf("Node disappeared, skip result")
-			return
-		}
-
-		prober.results[ipString(addr.String())].Icmp = &models.ConnectivityStatus{
-			Latency: rtt.Nanoseconds(),
-			Status:  "",
-		}
-		scopedLog.WithFields(logrus.Fields{
-			logfields.NodeName: node.Name,
-		}).Debugf("Probe successful")
-	}
-
 	return prober
 }
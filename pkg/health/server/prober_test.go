@@ -0,0 +1,65 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ProberTestSuite struct{}
+
+var _ = Suite(&ProberTestSuite{})
+
+func (s *ProberTestSuite) TestSummarizeProbes(c *C) {
+	res := summarizeProbes(nil, 0)
+	c.Assert(res.Status, Equals, "")
+	c.Assert(res.Loss, Equals, float64(0))
+
+	res = summarizeProbes(nil, 4)
+	c.Assert(res.Status, Equals, "Connection timed out")
+	c.Assert(res.Loss, Equals, float64(100))
+
+	res = summarizeProbes([]time.Duration{time.Millisecond, 3 * time.Millisecond}, 4)
+	c.Assert(res.Status, Equals, "")
+	c.Assert(res.Loss, Equals, float64(50))
+	c.Assert(res.Latency, Equals, (2 * time.Millisecond).Nanoseconds())
+
+	// Duplicate replies must not result in negative loss.
+	res = summarizeProbes([]time.Duration{time.Millisecond, time.Millisecond}, 1)
+	c.Assert(res.Loss, Equals, float64(0))
+	c.Assert(res.Latency, Equals, time.Millisecond.Nanoseconds())
+}
+
+func (s *ProberTestSuite) TestICMPDeadline(c *C) {
+	p := &prober{server: &Server{Config: Config{
+		ProbeInterval: time.Minute,
+		ProbeDeadline: time.Second,
+	}}}
+	c.Assert(p.icmpDeadline(3), Equals, time.Second)
+
+	// All echo requests of a cycle must complete within the interval
+	p.server.ProbeInterval = 2 * time.Second
+	c.Assert(p.icmpDeadline(4), Equals, 500*time.Millisecond)
+}
@@ -68,6 +68,21 @@ type Config struct {
 	CiliumURI     string
 	ProbeInterval time.Duration
 	ProbeDeadline time.Duration
+
+	// ICMPProbeCount is the number of ICMP echo requests sent to each
+	// IP per probe cycle. Zero disables ICMP probing.
+	ICMPProbeCount int
+
+	// TCPProbeCount is the number of TCP connections made to the health
+	// port of each IP per probe cycle. Zero disables TCP probing.
+	TCPProbeCount int
+
+	// PMTUProbe enables probing of the path MTU towards each IP.
+	PMTUProbe bool
+
+	// HistorySize is the number of probe results kept per path to compute
+	// the connectivity history. Zero disables the history.
+	HistorySize int
 }
 
 // ipString is an IP address used as a more descriptive type name in maps.
@@ -94,6 +109,7 @@ type Server struct {
 	lock.RWMutex
 	connectivity *healthReport
 	localStatus  *healthModels.SelfStatus
+	history      *connectivityHistory
 }
 
 // DumpUptime returns the time that this server has been running.
@@ -151,6 +167,21 @@ func (s *Server) getNodes() (nodeMap, error) {
 	return nodes, nil
 }
 
+// getConfiguredMTU fetches the device and route MTU configured by the Cilium
+// daemon.
+func (s *Server) getConfiguredMTU() (deviceMTU, routeMTU int, err error) {
+	resp, err := s.Daemon.GetConfig(nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to get agent configuration: %s", err)
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Status == nil {
+		return 0, 0, fmt.Errorf("received nil configuration response")
+	}
+
+	status := resp.Payload.Status
+	return int(status.DeviceMTU), int(status.RouteMTU), nil
+}
+
 // updateCluster makes the specified health report visible to the API.
 //
 // It only updates the server's API-visible health report if the provided
@@ -160,6 +191,9 @@ func (s *Server) updateCluster(report *healthReport) {
 	defer s.Unlock()
 
 	if s.connectivity.startTime.Before(report.startTime) {
+		if s.history != nil {
+			s.history.recordReport(report)
+		}
 		s.connectivity = report
 	}
 }
@@ -214,8 +248,7 @@ func (s *Server) runActiveServices() error {
 
 	nodes, _ := s.getNodes()
 	prober := newProber(s, nodes)
-	prober.MaxRTT = s.ProbeInterval
-	prober.OnIdle = func() {
+	prober.onCycle = func() {
 		// Fetch results and update set of nodes to probe every
 		// ProbeInterval
 		s.updateCluster(prober.getResults())
@@ -316,6 +349,9 @@ func NewServer(config Config) (*Server, error) {
 		tcpServers:   []*healthApi.Server{},
 		connectivity: &healthReport{},
 	}
+	if config.HistorySize > 0 {
+		server.history = newConnectivityHistory(config.HistorySize)
+	}
 
 	swaggerSpec, err := loads.Analyzed(healthApi.SwaggerJSON, "")
 	if err != nil {
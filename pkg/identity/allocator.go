@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/cilium/cilium/pkg/idpool"
+	clientset "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned"
 	"github.com/cilium/cilium/pkg/kvstore"
 	"github.com/cilium/cilium/pkg/kvstore/allocator"
 	"github.com/cilium/cilium/pkg/labels"
@@ -49,9 +50,22 @@ func (gi globalIdentity) PutKey(v string) (allocator.AllocatorKey, error) {
 	return globalIdentity{labels.NewLabelsFromSortedList(string(b))}, nil
 }
 
+// identityBackend is the interface implemented by the backends storing
+// security identities
+type identityBackend interface {
+	Allocate(key allocator.AllocatorKey) (idpool.ID, bool, error)
+	Release(key allocator.AllocatorKey) error
+	Get(key allocator.AllocatorKey) (idpool.ID, error)
+	GetByID(id idpool.ID) (allocator.AllocatorKey, error)
+	ForeachCache(cb allocator.RangeFunc)
+	WaitForInitialSync()
+	LocalRefCount(id idpool.ID) uint64
+	GetNodeUsage(key allocator.AllocatorKey) ([]string, error)
+}
+
 var (
 	setupOnce         sync.Once
-	identityAllocator *allocator.Allocator
+	identityAllocator identityBackend
 
 	// IdentitiesPath is the path to where identities are stored in the key-value
 	// store.
@@ -69,9 +83,10 @@ type IdentityAllocatorOwner interface {
 	GetNodeSuffix() string
 }
 
-// InitIdentityAllocator creates the the identity allocator. Only the first
-// invocation of this function will have an effect.
-func InitIdentityAllocator(owner IdentityAllocatorOwner) {
+// initIdentityAllocator creates the identity allocator using the backend
+// returned by newBackend. Only the first invocation of this function will
+// have an effect.
+func initIdentityAllocator(owner IdentityAllocatorOwner, newBackend func(minID, maxID, prefixMask idpool.ID, events allocator.AllocatorEventChan) (identityBackend, error)) {
 	initWellKnownIdentities()
 
 	setupOnce.Do(func() {
@@ -79,19 +94,15 @@ func InitIdentityAllocator(owner IdentityAllocatorOwner) {
 
 		minID := idpool.ID(MinimalNumericIdentity)
 		maxID := idpool.ID(^uint16(0))
+		prefixMask := idpool.ID(option.Config.ClusterID << option.ClusterIDShift)
 		events := make(allocator.AllocatorEventChan, 65536)
 
-		// It is important to start listening for events before calling
-		// NewAllocator() as it will emit events while filling the
-		// initial cache
+		// It is important to start listening for events before
+		// creating the backend as it will emit events while filling
+		// the initial cache
 		go identityWatcher(owner, events)
 
-		a, err := allocator.NewAllocator(IdentitiesPath, globalIdentity{},
-			allocator.WithMax(maxID), allocator.WithMin(minID),
-			allocator.WithSuffix(owner.GetNodeSuffix()),
-			allocator.WithEvents(events),
-			allocator.WithMasterKeyProtection(),
-			allocator.WithPrefixMask(idpool.ID(option.Config.ClusterID<<option.ClusterIDShift)))
+		a, err := newBackend(minID, maxID, prefixMask, events)
 		if err != nil {
 			log.WithError(err).Fatal("Unable to initialize identity allocator")
 		}
@@ -100,6 +111,33 @@ func InitIdentityAllocator(owner IdentityAllocatorOwner) {
 	})
 }
 
+// InitIdentityAllocator creates the the identity allocator storing identities
+// in the kvstore. Only the first invocation of this function or of
+// InitCRDIdentityAllocator will have an effect.
+func InitIdentityAllocator(owner IdentityAllocatorOwner) {
+	initIdentityAllocator(owner, func(minID, maxID, prefixMask idpool.ID, events allocator.AllocatorEventChan) (identityBackend, error) {
+		return allocator.NewAllocator(IdentitiesPath, globalIdentity{},
+			allocator.WithMax(maxID), allocator.WithMin(minID),
+			allocator.WithSuffix(owner.GetNodeSuffix()),
+			allocator.WithEvents(events),
+			allocator.WithMasterKeyProtection(),
+			allocator.WithPrefixMask(prefixMask))
+	})
+}
+
+// InitCRDIdentityAllocator creates the identity allocator storing identities
+// as CiliumIdentity custom resources using the given client. The
+// CiliumIdentity CRD must have been created already. Only the first
+// invocation of this function or of InitIdentityAllocator will have an
+// effect.
+func InitCRDIdentityAllocator(owner IdentityAllocatorOwner, client clientset.Interface) {
+	initIdentityAllocator(owner, func(minID, maxID, prefixMask idpool.ID, events allocator.AllocatorEventChan) (identityBackend, error) {
+		b := newCRDBackend(client, owner.GetNodeSuffix(), minID, maxID, prefixMask, events)
+		b.start()
+		return b, nil
+	})
+}
+
 // WaitForInitialIdentities waits for the initial set of security identities to
 // have been received and populated into the allocator cache
 func WaitForInitialIdentities() {
@@ -121,7 +159,7 @@ func IdentityAllocationIsLocal(lbls labels.Labels) bool {
 // AllocateIdentity allocates an identity described by the specified labels. If
 // an identity for the specified set of labels already exist, the identity is
 // re-used and reference counting is performed, otherwise a new identity is
-// allocated via the kvstore.
+// allocated via the kvstore or as CiliumIdentity resource.
 func AllocateIdentity(lbls labels.Labels) (*Identity, bool, error) {
 	log.WithFields(logrus.Fields{
 		logfields.IdentityLabels: lbls.String(),
@@ -146,6 +184,7 @@ func AllocateIdentity(lbls labels.Labels) (*Identity, bool, error) {
 	if err != nil {
 		return nil, false, err
 	}
+	tracker.allocated(NumericIdentity(id))
 
 	log.WithFields(logrus.Fields{
 		logfields.Identity:       id,
@@ -169,7 +208,12 @@ func (id *Identity) Release() error {
 		return fmt.Errorf("allocator not initialized")
 	}
 
-	return identityAllocator.Release(globalIdentity{id.Labels})
+	if err := identityAllocator.Release(globalIdentity{id.Labels}); err != nil {
+		return err
+	}
+
+	tracker.released(id.ID, identityAllocator.LocalRefCount(idpool.ID(id.ID)))
+	return nil
 }
 
 // ReleaseSlice attempts to release a set of identities. It is a helper
@@ -194,6 +238,13 @@ func ReleaseSlice(identities []*Identity) error {
 
 // WatchRemoteIdentities starts watching for identities in another kvstore and
 // syncs all identities to the local identity cache.
+// Returns nil if identities are not allocated in the kvstore.
 func WatchRemoteIdentities(backend kvstore.BackendOperations) *allocator.RemoteCache {
-	return identityAllocator.WatchRemoteKVStore(backend, IdentitiesPath)
+	a, ok := identityAllocator.(*allocator.Allocator)
+	if !ok {
+		log.Warning("Identities of remote clusters can only be watched if identities are allocated in the kvstore")
+		return nil
+	}
+
+	return a.WatchRemoteKVStore(backend, IdentitiesPath)
 }
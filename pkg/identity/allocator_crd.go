@@ -0,0 +1,599 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cilium/cilium/pkg/idpool"
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	clientset "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned"
+	informer "github.com/cilium/cilium/pkg/k8s/client/informers/externalversions/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// crdHeartbeatInterval is the interval in which a node confirms the
+	// use of all identities it is using
+	crdHeartbeatInterval = 5 * time.Minute
+
+	// crdHeartbeatTimeout is the duration after which the use of an
+	// identity by a node expires if it has not been confirmed again
+	crdHeartbeatTimeout = 3 * crdHeartbeatInterval
+
+	// crdGCInterval is the interval in which unused identities are
+	// garbage collected
+	crdGCInterval = 5 * time.Minute
+
+	// crdMaxAllocAttempts is the number of attempts to create a new
+	// identity on the apiserver before giving up
+	crdMaxAllocAttempts = 16
+
+	// byLabelsIndex is the name of the informer index mapping the
+	// allocator key of the labels to the identities
+	byLabelsIndex = "by-labels"
+)
+
+// crdLocalKey is an identity in local use
+type crdLocalKey struct {
+	id     idpool.ID
+	key    allocator.AllocatorKey
+	refcnt uint64
+}
+
+// crdBackend is an identity allocator storing each identity as a
+// CiliumIdentity custom resource. The name of the resource is the numeric
+// identity, the labels are stored in the resource itself. The IDs probed for
+// a new identity are derived from its labels so that nodes racing to create
+// the same identity converge on the same resource. Every node using an
+// identity records its use in the status of the resource and periodically
+// confirms it. Identities which are no longer used by any node are garbage
+// collected.
+type crdBackend struct {
+	client clientset.Interface
+
+	// node is the name under which the use of identities by this node is
+	// recorded
+	node string
+
+	// events receives an event for each identity added, modified or
+	// removed
+	events allocator.AllocatorEventChan
+
+	// prefixMask is ORed to all selected IDs prior to allocation
+	prefixMask idpool.ID
+
+	// minID and maxID are the bounds of the IDs selected for allocation
+	minID idpool.ID
+	maxID idpool.ID
+
+	informer cache.SharedIndexInformer
+
+	// mutex protects localKeys and serializes allocations
+	mutex lock.Mutex
+
+	// localKeys are all identities in local use indexed by crdKey()
+	localKeys map[string]*crdLocalKey
+
+	stop chan struct{}
+}
+
+// securityLabels returns the labels in the representation stored in a
+// CiliumIdentity
+func securityLabels(lbls labels.Labels) map[string]string {
+	m := make(map[string]string, len(lbls))
+	for _, l := range lbls {
+		m[l.Source+":"+l.Key] = l.Value
+	}
+	return m
+}
+
+// identityKey returns the allocator key of a CiliumIdentity
+func identityKey(ci *cilium_v2.CiliumIdentity) globalIdentity {
+	return globalIdentity{labels.Map2Labels(ci.SecurityLabels, "")}
+}
+
+// identityID returns the numeric identity of a CiliumIdentity
+func identityID(ci *cilium_v2.CiliumIdentity) (idpool.ID, error) {
+	id, err := strconv.ParseUint(ci.Name, 10, 64)
+	if err != nil {
+		return idpool.NoID, fmt.Errorf("invalid identity name '%s': %s", ci.Name, err)
+	}
+	return idpool.ID(id), nil
+}
+
+// crdKey returns the string representation of an allocator key used to
+// index identities. Unlike GetKey(), it does not depend on the kvstore.
+func crdKey(key allocator.AllocatorKey) string {
+	return string(key.(globalIdentity).SortedList())
+}
+
+func byLabelsIndexFunc(obj interface{}) ([]string, error) {
+	ci, ok := obj.(*cilium_v2.CiliumIdentity)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+	return []string{crdKey(identityKey(ci))}, nil
+}
+
+func newCRDBackend(client clientset.Interface, node string, minID, maxID, prefixMask idpool.ID, events allocator.AllocatorEventChan) *crdBackend {
+	b := &crdBackend{
+		client:     client,
+		node:       node,
+		events:     events,
+		prefixMask: prefixMask,
+		minID:      minID,
+		maxID:      maxID,
+		localKeys:  map[string]*crdLocalKey{},
+		stop:       make(chan struct{}),
+	}
+
+	b.informer = informer.NewCiliumIdentityInformer(client, 0,
+		cache.Indexers{byLabelsIndex: byLabelsIndexFunc})
+	b.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			b.onUpsert(kvstore.EventTypeCreate, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			b.onUpsert(kvstore.EventTypeModify, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = deleted.Obj
+			}
+			b.onDelete(obj)
+		},
+	})
+
+	return b
+}
+
+// start starts watching for identities as well as the heartbeat and garbage
+// collector routines
+func (b *crdBackend) start() {
+	go b.informer.Run(b.stop)
+	go b.runPeriodically("heartbeat", crdHeartbeatInterval, b.heartbeat)
+	go b.runPeriodically("garbage collector", crdGCInterval, b.runGC)
+}
+
+// close stops all routines started by start()
+func (b *crdBackend) close() {
+	close(b.stop)
+}
+
+func (b *crdBackend) runPeriodically(name string, interval time.Duration, f func()) {
+	for {
+		select {
+		case <-b.stop:
+			log.Debugf("Stopped identity %s", name)
+			return
+		case <-time.After(interval):
+			f()
+		}
+	}
+}
+
+func (b *crdBackend) onUpsert(typ kvstore.EventType, obj interface{}) {
+	ci, ok := obj.(*cilium_v2.CiliumIdentity)
+	if !ok {
+		return
+	}
+
+	id, err := identityID(ci)
+	if err != nil {
+		log.WithError(err).Warning("Ignoring CiliumIdentity")
+		return
+	}
+
+	if b.events != nil {
+		b.events <- allocator.AllocatorEvent{Typ: typ, ID: id, Key: identityKey(ci)}
+	}
+}
+
+func (b *crdBackend) onDelete(obj interface{}) {
+	ci, ok := obj.(*cilium_v2.CiliumIdentity)
+	if !ok {
+		return
+	}
+
+	id, err := identityID(ci)
+	if err != nil {
+		return
+	}
+
+	if b.events != nil {
+		b.events <- allocator.AllocatorEvent{Typ: kvstore.EventTypeDelete, ID: id, Key: identityKey(ci)}
+	}
+}
+
+// WaitForInitialSync waits until the initial list of identities has been
+// received
+func (b *crdBackend) WaitForInitialSync() {
+	cache.WaitForCacheSync(b.stop, b.informer.HasSynced)
+}
+
+// lookup returns the ID of the identity with the given key as known to the
+// local cache. If several identities exist for the same key because nodes
+// raced to create it, the lowest ID is returned so that all nodes agree.
+func (b *crdBackend) lookup(k string) idpool.ID {
+	objs, err := b.informer.GetIndexer().ByIndex(byLabelsIndex, k)
+	if err != nil || len(objs) == 0 {
+		return idpool.NoID
+	}
+
+	ids := make([]idpool.ID, 0, len(objs))
+	for _, obj := range objs {
+		if id, err := identityID(obj.(*cilium_v2.CiliumIdentity)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return idpool.NoID
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids[0]
+}
+
+// updateUse records or withdraws the use of the identity by this node
+func (b *crdBackend) updateUse(id idpool.ID, inUse bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ci, err := b.client.CiliumV2().CiliumIdentities().Get(id.String(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if inUse {
+			if ci.Status.Nodes == nil {
+				ci.Status.Nodes = map[string]metav1.Time{}
+			}
+			ci.Status.Nodes[b.node] = metav1.Now()
+		} else {
+			if _, ok := ci.Status.Nodes[b.node]; !ok {
+				return nil
+			}
+			delete(ci.Status.Nodes, b.node)
+		}
+
+		_, err = b.client.CiliumV2().CiliumIdentities().Update(ci)
+		return err
+	})
+}
+
+// create creates the CiliumIdentity for the given ID and key with this node
+// recorded as user
+func (b *crdBackend) create(id idpool.ID, key allocator.AllocatorKey) error {
+	ci := &cilium_v2.CiliumIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: id.String(),
+		},
+		SecurityLabels: securityLabels(key.(globalIdentity).Labels),
+		Status: cilium_v2.IdentityStatus{
+			Nodes: map[string]metav1.Time{b.node: metav1.Now()},
+		},
+	}
+
+	_, err := b.client.CiliumV2().CiliumIdentities().Create(ci)
+	return err
+}
+
+// candidateID returns the n-th ID probed when creating a new identity for
+// the key k. All nodes probe the same sequence of IDs for a key.
+func (b *crdBackend) candidateID(k string, n int) idpool.ID {
+	h := fnv.New64a()
+	h.Write([]byte(k))
+	size := uint64(b.maxID-b.minID) + 1
+	return (b.minID + idpool.ID((h.Sum64()+uint64(n))%size)) | b.prefixMask
+}
+
+// usedByOtherKey returns true if the local cache knows the ID to be in use
+// by an identity with a key other than k
+func (b *crdBackend) usedByOtherKey(id idpool.ID, k string) bool {
+	obj, exists, err := b.informer.GetIndexer().GetByKey(id.String())
+	if err != nil || !exists {
+		return false
+	}
+	return crdKey(identityKey(obj.(*cilium_v2.CiliumIdentity))) != k
+}
+
+// allocateNew creates a new identity for the key at the first free ID of
+// the sequence of candidate IDs of the key. If another node has created the
+// identity for the same key in the meantime, its ID is adopted and false is
+// returned.
+func (b *crdBackend) allocateNew(key allocator.AllocatorKey) (idpool.ID, bool, error) {
+	k := crdKey(key)
+	size := int(b.maxID-b.minID) + 1
+	attempts := 0
+
+	var err error
+	for n := 0; n < size && attempts < crdMaxAllocAttempts; n++ {
+		id := b.candidateID(k, n)
+		if b.usedByOtherKey(id, k) {
+			continue
+		}
+
+		attempts++
+		err = b.create(id, key)
+		if err == nil {
+			return id, true, nil
+		}
+		if !k8serrors.IsAlreadyExists(err) {
+			return idpool.NoID, false, err
+		}
+
+		ci, getErr := b.client.CiliumV2().CiliumIdentities().Get(id.String(), metav1.GetOptions{})
+		switch {
+		case getErr != nil:
+			// Deleted in the meantime, the ID is probed again
+			// in the next attempt
+			if k8serrors.IsNotFound(getErr) {
+				n--
+				continue
+			}
+			return idpool.NoID, false, getErr
+		case crdKey(identityKey(ci)) != k:
+			continue
+		}
+
+		// Another node created the identity for the same key
+		err = b.updateUse(id, true)
+		switch {
+		case err == nil:
+			return id, false, nil
+		case k8serrors.IsNotFound(err):
+			n--
+		default:
+			return idpool.NoID, false, fmt.Errorf("unable to record use of identity %s: %s", id, err)
+		}
+	}
+
+	if err == nil {
+		return idpool.NoID, false, fmt.Errorf("no more available IDs in configured space")
+	}
+	return idpool.NoID, false, fmt.Errorf("unable to allocate identity after %d attempts: %s", attempts, err)
+}
+
+// Allocate returns the ID of the identity with the given key and records
+// the use of the identity by this node. If no identity exists for the key
+// yet, a new identity is created and true is returned.
+func (b *crdBackend) Allocate(key allocator.AllocatorKey) (idpool.ID, bool, error) {
+	k := crdKey(key)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if lk, ok := b.localKeys[k]; ok {
+		lk.refcnt++
+		return lk.id, false, nil
+	}
+
+	// Selecting a new ID requires complete knowledge of all identities
+	// in use
+	b.WaitForInitialSync()
+
+	id, isNew := b.lookup(k), false
+	if id != idpool.NoID {
+		err := b.updateUse(id, true)
+		switch {
+		case k8serrors.IsNotFound(err):
+			// The identity has been removed in the meantime
+			id = idpool.NoID
+		case err != nil:
+			return idpool.NoID, false, fmt.Errorf("unable to record use of identity %s: %s", id, err)
+		}
+	}
+
+	if id == idpool.NoID {
+		var err error
+		if id, isNew, err = b.allocateNew(key); err != nil {
+			return idpool.NoID, false, err
+		}
+	}
+
+	b.localKeys[k] = &crdLocalKey{id: id, key: key, refcnt: 1}
+
+	return id, isNew, nil
+}
+
+// Release releases the use of the identity with the given key. After the
+// last local user has released the identity, the use of the identity by this
+// node is withdrawn.
+func (b *crdBackend) Release(key allocator.AllocatorKey) error {
+	k := crdKey(key)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	lk, ok := b.localKeys[k]
+	if !ok {
+		return fmt.Errorf("unable to find key in local cache")
+	}
+
+	lk.refcnt--
+	if lk.refcnt > 0 {
+		return nil
+	}
+	delete(b.localKeys, k)
+
+	if err := b.updateUse(lk.id, false); err != nil && !k8serrors.IsNotFound(err) {
+		log.WithError(err).WithField(logfields.Identity, lk.id).
+			Warning("Unable to withdraw use of identity, garbage collector will release it")
+	}
+
+	return nil
+}
+
+// Get returns the ID of the identity with the given key or NoID if no such
+// identity exists
+func (b *crdBackend) Get(key allocator.AllocatorKey) (idpool.ID, error) {
+	k := crdKey(key)
+
+	b.mutex.Lock()
+	lk, ok := b.localKeys[k]
+	b.mutex.Unlock()
+	if ok {
+		return lk.id, nil
+	}
+
+	return b.lookup(k), nil
+}
+
+// GetByID returns the key of the identity with the given ID. Returns nil if
+// no such identity exists.
+func (b *crdBackend) GetByID(id idpool.ID) (allocator.AllocatorKey, error) {
+	obj, exists, err := b.informer.GetIndexer().GetByKey(id.String())
+	if err == nil && exists {
+		return identityKey(obj.(*cilium_v2.CiliumIdentity)), nil
+	}
+
+	ci, err := b.client.CiliumV2().CiliumIdentities().Get(id.String(), metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return identityKey(ci), nil
+}
+
+// ForeachCache iterates over all identities in the local cache
+func (b *crdBackend) ForeachCache(cb allocator.RangeFunc) {
+	for _, obj := range b.informer.GetIndexer().List() {
+		ci := obj.(*cilium_v2.CiliumIdentity)
+		if id, err := identityID(ci); err == nil {
+			cb(id, identityKey(ci))
+		}
+	}
+}
+
+// LocalRefCount returns the number of local references to the identity
+// with the given ID
+func (b *crdBackend) LocalRefCount(id idpool.ID) uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, lk := range b.localKeys {
+		if lk.id == id {
+			return lk.refcnt
+		}
+	}
+
+	return 0
+}
+
+// GetNodeUsage returns the names of all nodes which have recorded the use of
+// the identity with the given key
+func (b *crdBackend) GetNodeUsage(key allocator.AllocatorKey) ([]string, error) {
+	id, err := b.Get(key)
+	if err != nil || id == idpool.NoID {
+		return nil, err
+	}
+
+	obj, exists, err := b.informer.GetIndexer().GetByKey(id.String())
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	ci := obj.(*cilium_v2.CiliumIdentity)
+	nodes := make([]string, 0, len(ci.Status.Nodes))
+	for node := range ci.Status.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	return nodes, nil
+}
+
+// heartbeat confirms the use of all identities in local use. Identities
+// which have been removed while still in use are re-created.
+func (b *crdBackend) heartbeat() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, lk := range b.localKeys {
+		scopedLog := log.WithField(logfields.Identity, lk.id)
+
+		err := b.updateUse(lk.id, true)
+		if k8serrors.IsNotFound(err) {
+			err = b.create(lk.id, lk.key)
+			if err == nil {
+				scopedLog.Warning("Re-created identity still in local use")
+			}
+		}
+		if err != nil {
+			scopedLog.WithError(err).Warning("Unable to confirm use of identity")
+		}
+	}
+}
+
+// isUnused returns true if no node has confirmed the use of the identity
+// within crdHeartbeatTimeout
+func isUnused(ci *cilium_v2.CiliumIdentity, now time.Time) bool {
+	expired := now.Add(-crdHeartbeatTimeout)
+
+	if ci.CreationTimestamp.Time.After(expired) {
+		return false
+	}
+
+	for _, lastSeen := range ci.Status.Nodes {
+		if lastSeen.Time.After(expired) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runGC deletes all identities which are no longer used by any node
+func (b *crdBackend) runGC() {
+	now := time.Now()
+
+	for _, obj := range b.informer.GetIndexer().List() {
+		ci := obj.(*cilium_v2.CiliumIdentity)
+		if !isUnused(ci, now) {
+			continue
+		}
+
+		scopedLog := log.WithFields(logrus.Fields{
+			logfields.Identity:       ci.Name,
+			logfields.IdentityLabels: identityKey(ci).String(),
+		})
+
+		uid := ci.UID
+		err := b.client.CiliumV2().CiliumIdentities().Delete(ci.Name,
+			&metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}})
+		switch {
+		case err == nil:
+			scopedLog.Info("Deleted unused identity")
+		case !k8serrors.IsNotFound(err):
+			scopedLog.WithError(err).Warning("Unable to delete unused identity")
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package identity
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/idpool"
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/testutils"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CRDAllocatorSuite struct{}
+
+var _ = Suite(&CRDAllocatorSuite{})
+
+func newTestCRDBackend(client *fake.Clientset, node string) *crdBackend {
+	b := newCRDBackend(client, node, idpool.ID(256), idpool.ID(512), idpool.ID(0), nil)
+	b.start()
+	b.WaitForInitialSync()
+	return b
+}
+
+func getIdentity(c *C, client *fake.Clientset, id idpool.ID) *cilium_v2.CiliumIdentity {
+	ci, err := client.CiliumV2().CiliumIdentities().Get(id.String(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	return ci
+}
+
+func (s *CRDAllocatorSuite) TestAllocateRelease(c *C) {
+	client := fake.NewSimpleClientset()
+	lbls := labels.NewLabelsFromModel([]string{"k8s:id=foo", "k8s:user=anna", "cidr:f00d::/64"})
+	key := globalIdentity{lbls}
+
+	b1 := newTestCRDBackend(client, "node1")
+	defer b1.close()
+
+	id, isNew, err := b1.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, true)
+	c.Assert(id >= idpool.ID(256) && id <= idpool.ID(512), Equals, true)
+
+	ci := getIdentity(c, client, id)
+	c.Assert(ci.SecurityLabels, DeepEquals, map[string]string{
+		"k8s:id":         "foo",
+		"k8s:user":       "anna",
+		"cidr:f00d::/64": "",
+	})
+	c.Assert(crdKey(identityKey(ci)), Equals, crdKey(key))
+	_, ok := ci.Status.Nodes["node1"]
+	c.Assert(ok, Equals, true)
+
+	// Local reuse
+	id2, isNew, err := b1.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(id2, Equals, id)
+
+	// Another node must find the existing identity
+	b2 := newTestCRDBackend(client, "node2")
+	defer b2.close()
+	c.Assert(testutils.WaitUntil(func() bool {
+		id, _ := b2.Get(key)
+		return id != idpool.NoID
+	}, 5*time.Second), IsNil)
+
+	id3, isNew, err := b2.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(id3, Equals, id)
+	c.Assert(getIdentity(c, client, id).Status.Nodes, HasLen, 2)
+	c.Assert(b1.LocalRefCount(id), Equals, uint64(2))
+	c.Assert(b2.LocalRefCount(id), Equals, uint64(1))
+	c.Assert(testutils.WaitUntil(func() bool {
+		nodes, err := b2.GetNodeUsage(key)
+		return err == nil && len(nodes) == 2 && nodes[0] == "node1" && nodes[1] == "node2"
+	}, 5*time.Second), IsNil)
+
+	k, err := b2.GetByID(id)
+	c.Assert(err, IsNil)
+	c.Assert(crdKey(k), Equals, crdKey(key))
+
+	k, err = b2.GetByID(idpool.ID(1000))
+	c.Assert(err, IsNil)
+	c.Assert(k, IsNil)
+
+	found := false
+	b2.ForeachCache(func(i idpool.ID, k allocator.AllocatorKey) {
+		found = found || (i == id && crdKey(k) == crdKey(key))
+	})
+	c.Assert(found, Equals, true)
+
+	// The use is only withdrawn after the last local release
+	c.Assert(b1.Release(key), IsNil)
+	c.Assert(getIdentity(c, client, id).Status.Nodes, HasLen, 2)
+	c.Assert(b1.Release(key), IsNil)
+	nodes := getIdentity(c, client, id).Status.Nodes
+	c.Assert(nodes, HasLen, 1)
+	_, ok = nodes["node2"]
+	c.Assert(ok, Equals, true)
+
+	c.Assert(b1.Release(key), Not(IsNil))
+}
+
+func (s *CRDAllocatorSuite) TestAllocateRace(c *C) {
+	client := fake.NewSimpleClientset()
+	key := globalIdentity{labels.NewLabelsFromModel([]string{"k8s:id=race"})}
+
+	b1 := newTestCRDBackend(client, "node1")
+	defer b1.close()
+	b2 := newTestCRDBackend(client, "node2")
+	defer b2.close()
+
+	// Both nodes create the identity concurrently
+	ids := make(chan idpool.ID, 2)
+	for _, b := range []*crdBackend{b1, b2} {
+		go func(b *crdBackend) {
+			id, _, err := b.Allocate(key)
+			c.Check(err, IsNil)
+			ids <- id
+		}(b)
+	}
+	id1, id2 := <-ids, <-ids
+	c.Assert(id1, Equals, id2)
+
+	list, err := client.CiliumV2().CiliumIdentities().List(metav1.ListOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(list.Items, HasLen, 1)
+	c.Assert(list.Items[0].Status.Nodes, HasLen, 2)
+
+	// A node which has not seen the identity yet adopts it instead of
+	// creating a second one
+	b3 := newTestCRDBackend(client, "node3")
+	defer b3.close()
+	id3, isNew, err := b3.allocateNew(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(id3, Equals, id1)
+	c.Assert(getIdentity(c, client, id1).Status.Nodes, HasLen, 3)
+}
+
+func (s *CRDAllocatorSuite) TestAllocateCollision(c *C) {
+	client := fake.NewSimpleClientset()
+	key := globalIdentity{labels.NewLabelsFromModel([]string{"k8s:id=foo"})}
+
+	b := newTestCRDBackend(client, "node1")
+	defer b.close()
+
+	// The first candidate ID is taken by an identity with other labels
+	first := b.candidateID(crdKey(key), 0)
+	_, err := client.CiliumV2().CiliumIdentities().Create(&cilium_v2.CiliumIdentity{
+		ObjectMeta:     metav1.ObjectMeta{Name: first.String()},
+		SecurityLabels: map[string]string{"k8s:id": "other"},
+	})
+	c.Assert(err, IsNil)
+
+	id, isNew, err := b.allocateNew(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, true)
+	c.Assert(id, Equals, b.candidateID(crdKey(key), 1))
+}
+
+func (s *CRDAllocatorSuite) TestGC(c *C) {
+	client := fake.NewSimpleClientset()
+	expired := metav1.NewTime(time.Now().Add(-2 * crdHeartbeatTimeout))
+
+	_, err := client.CiliumV2().CiliumIdentities().Create(&cilium_v2.CiliumIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "300",
+			CreationTimestamp: expired,
+		},
+		SecurityLabels: map[string]string{"k8s:id": "stale"},
+		Status: cilium_v2.IdentityStatus{
+			Nodes: map[string]metav1.Time{"node1": expired},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	b := newTestCRDBackend(client, "node1")
+	defer b.close()
+
+	key := globalIdentity{labels.NewLabelsFromModel([]string{"k8s:id=used"})}
+	id, _, err := b.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(testutils.WaitUntil(func() bool {
+		obj, _, _ := b.informer.GetIndexer().GetByKey(id.String())
+		return obj != nil
+	}, 5*time.Second), IsNil)
+
+	b.runGC()
+
+	_, err = client.CiliumV2().CiliumIdentities().Get("300", metav1.GetOptions{})
+	c.Assert(err, Not(IsNil))
+	getIdentity(c, client, id)
+
+	// An identity deleted while still in local use is re-created
+	c.Assert(client.CiliumV2().CiliumIdentities().Delete(id.String(), nil), IsNil)
+	b.heartbeat()
+	ci := getIdentity(c, client, id)
+	c.Assert(crdKey(identityKey(ci)), Equals, crdKey(key))
+}
+
+func (s *CRDAllocatorSuite) TestIsUnused(c *C) {
+	now := time.Now()
+	expired := metav1.NewTime(now.Add(-2 * crdHeartbeatTimeout))
+	recent := metav1.NewTime(now.Add(-crdHeartbeatInterval))
+
+	ci := &cilium_v2.CiliumIdentity{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: recent}}
+	c.Assert(isUnused(ci, now), Equals, false)
+
+	ci.CreationTimestamp = expired
+	c.Assert(isUnused(ci, now), Equals, true)
+
+	ci.Status.Nodes = map[string]metav1.Time{"node1": expired}
+	c.Assert(isUnused(ci, now), Equals, true)
+
+	ci.Status.Nodes["node2"] = recent
+	c.Assert(isUnused(ci, now), Equals, false)
+}
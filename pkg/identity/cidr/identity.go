@@ -29,6 +29,12 @@ var (
 	log = logging.DefaultLogger.WithField(logfields.LogSubsys, "identity-cidr")
 )
 
+// identityUser returns the name under which prefix is recorded as user of its
+// identity
+func identityUser(prefix *net.IPNet) string {
+	return "cidr:" + prefix.String()
+}
+
 // AllocateCIDRIdentities allocates identities for each of the specified CIDR
 // prefixes so they will be available later during policy resolution.
 //
@@ -64,6 +70,12 @@ func AllocateCIDRIdentities(prefixes []*net.IPNet) (res []*identity.Identity, er
 		res[i] = id
 	}
 
+	for i, id := range res {
+		if id != nil {
+			identity.AddIdentityUser(id.ID, identityUser(prefixes[i]))
+		}
+	}
+
 	if err == nil {
 		log.Debugf("Allocated identities for %d prefixes", len(res))
 	} else {
@@ -96,3 +108,16 @@ func LookupCIDRIdentities(prefixes []*net.IPNet) (res []*identity.Identity, err
 
 	return res, err
 }
+
+// ReleaseCIDRIdentities releases the identities previously allocated for
+// the specified prefixes with AllocateCIDRIdentities. The identities must have
+// a 1-to-1 correspondence with the prefixes, nil identities are skipped.
+func ReleaseCIDRIdentities(prefixes []*net.IPNet, identities []*identity.Identity) error {
+	for i, id := range identities {
+		if id != nil && i < len(prefixes) {
+			identity.RemoveIdentityUser(id.ID, identityUser(prefixes[i]))
+		}
+	}
+
+	return identity.ReleaseSlice(identities)
+}
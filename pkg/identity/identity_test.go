@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
 	"github.com/cilium/cilium/pkg/labels"
 
 	. "gopkg.in/check.v1"
@@ -61,6 +62,14 @@ func (s *IdentityTestSuite) TestReservedID(c *C) {
 	c.Assert(i, Equals, NumericIdentity(3))
 	c.Assert(i.String(), Equals, "unmanaged")
 
+	i = GetReservedID("remote-node")
+	c.Assert(i, Equals, NumericIdentity(6))
+	c.Assert(i.String(), Equals, "remote-node")
+
+	i = GetReservedID("kube-apiserver")
+	c.Assert(i, Equals, NumericIdentity(7))
+	c.Assert(i.String(), Equals, "kube-apiserver")
+
 	c.Assert(GetReservedID("unknown"), Equals, IdentityUnknown)
 	unknown := NumericIdentity(700)
 	c.Assert(unknown.String(), Equals, "700")
@@ -163,7 +172,7 @@ func (d dummyOwner) GetNodeSuffix() string {
 
 func (ias *IdentityAllocatorSuite) TestGetIdentityCache(c *C) {
 	InitIdentityAllocator(dummyOwner{})
-	defer identityAllocator.DeleteAllKeys()
+	defer identityAllocator.(*allocator.Allocator).DeleteAllKeys()
 
 	cache := GetIdentityCache()
 	_, ok := cache[ReservedCiliumKVStore]
@@ -176,7 +185,7 @@ func (ias *IdentityAllocatorSuite) TestAllocator(c *C) {
 	lbls3 := labels.NewLabelsFromSortedList("id=bar;user=susan")
 
 	InitIdentityAllocator(dummyOwner{})
-	defer identityAllocator.DeleteAllKeys()
+	defer identityAllocator.(*allocator.Allocator).DeleteAllKeys()
 
 	id1a, isNew, err := AllocateIdentity(lbls1)
 	c.Assert(id1a, Not(IsNil))
@@ -58,6 +58,14 @@ const (
 	// received any labels yet.
 	ReservedIdentityInit
 
+	// ReservedIdentityRemoteNode is the identity given to all nodes in
+	// local and remote clusters except for the local node.
+	ReservedIdentityRemoteNode
+
+	// ReservedIdentityKubeAPIServer is the identity given to the IPs
+	// backing the "kubernetes" service in the "default" namespace.
+	ReservedIdentityKubeAPIServer
+
 	// --------------------------------------------------------------
 	// Special identities for well-known cluster components
 
@@ -192,18 +200,22 @@ func initWellKnownIdentities() {
 
 var (
 	reservedIdentities = map[string]NumericIdentity{
-		labels.IDNameHost:      ReservedIdentityHost,
-		labels.IDNameWorld:     ReservedIdentityWorld,
-		labels.IDNameUnmanaged: ReservedIdentityUnmanaged,
-		labels.IDNameHealth:    ReservedIdentityHealth,
-		labels.IDNameInit:      ReservedIdentityInit,
+		labels.IDNameHost:          ReservedIdentityHost,
+		labels.IDNameWorld:         ReservedIdentityWorld,
+		labels.IDNameUnmanaged:     ReservedIdentityUnmanaged,
+		labels.IDNameHealth:        ReservedIdentityHealth,
+		labels.IDNameInit:          ReservedIdentityInit,
+		labels.IDNameRemoteNode:    ReservedIdentityRemoteNode,
+		labels.IDNameKubeAPIServer: ReservedIdentityKubeAPIServer,
 	}
 	reservedIdentityNames = map[NumericIdentity]string{
-		ReservedIdentityHost:      labels.IDNameHost,
-		ReservedIdentityWorld:     labels.IDNameWorld,
-		ReservedIdentityUnmanaged: labels.IDNameUnmanaged,
-		ReservedIdentityHealth:    labels.IDNameHealth,
-		ReservedIdentityInit:      labels.IDNameInit,
+		ReservedIdentityHost:          labels.IDNameHost,
+		ReservedIdentityWorld:         labels.IDNameWorld,
+		ReservedIdentityUnmanaged:     labels.IDNameUnmanaged,
+		ReservedIdentityHealth:        labels.IDNameHealth,
+		ReservedIdentityInit:          labels.IDNameInit,
+		ReservedIdentityRemoteNode:    labels.IDNameRemoteNode,
+		ReservedIdentityKubeAPIServer: labels.IDNameKubeAPIServer,
 	}
 
 	wellKnown = wellKnownIdentities{}
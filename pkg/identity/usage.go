@@ -0,0 +1,255 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/idpool"
+	"github.com/cilium/cilium/pkg/lock"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// IdentityUsage describes by whom a security identity is used
+type IdentityUsage struct {
+	// Identity is the identity being described
+	Identity *Identity
+
+	// LocalReferences is the number of references to the identity held
+	// by the identity allocator of this node
+	LocalReferences uint64
+
+	// Users is the sorted list of local users of the identity, e.g.
+	// endpoints and CIDR prefixes
+	Users []string
+
+	// Nodes is the sorted list of nodes in the cluster which use the
+	// identity
+	Nodes []string
+
+	// UnusedSince is the time since when the identity has had no local
+	// users. It is zero if the identity has local users.
+	UnusedSince time.Time
+}
+
+// GetModel returns the API model representation of the identity usage
+func (u *IdentityUsage) GetModel() *models.IdentityUsage {
+	if u == nil {
+		return nil
+	}
+
+	ret := &models.IdentityUsage{
+		LocalReferences: int64(u.LocalReferences),
+		Users:           u.Users,
+		Nodes:           u.Nodes,
+	}
+
+	if id := u.Identity.GetModel(); id != nil {
+		ret.ID = id.ID
+		ret.Labels = id.Labels
+	}
+
+	if !u.UnusedSince.IsZero() {
+		ret.UnusedSince = strfmt.DateTime(u.UnusedSince)
+	}
+
+	return ret
+}
+
+// localUsage is the local usage of a single identity
+type localUsage struct {
+	// users maps each user to the number of times it uses the identity
+	users map[string]int
+
+	// unusedSince is the time when the last user was removed or, if
+	// the identity has never had any users, when it was allocated
+	unusedSince time.Time
+}
+
+type usageTracker struct {
+	mutex lock.Mutex
+	usage map[NumericIdentity]*localUsage
+}
+
+var tracker = usageTracker{usage: map[NumericIdentity]*localUsage{}}
+
+func (t *usageTracker) get(id NumericIdentity) *localUsage {
+	u, ok := t.usage[id]
+	if !ok {
+		u = &localUsage{
+			users:       map[string]int{},
+			unusedSince: time.Now(),
+		}
+		t.usage[id] = u
+	}
+	return u
+}
+
+// allocated records the allocation of id
+func (t *usageTracker) allocated(id NumericIdentity) {
+	t.mutex.Lock()
+	t.get(id)
+	t.mutex.Unlock()
+}
+
+// released forgets about id if it is no longer referenced
+func (t *usageTracker) released(id NumericIdentity, refcnt uint64) {
+	t.mutex.Lock()
+	if u, ok := t.usage[id]; ok && refcnt == 0 && len(u.users) == 0 {
+		delete(t.usage, id)
+	}
+	t.mutex.Unlock()
+}
+
+func (t *usageTracker) addUser(id NumericIdentity, user string) {
+	t.mutex.Lock()
+	u := t.get(id)
+	u.users[user]++
+	u.unusedSince = time.Time{}
+	t.mutex.Unlock()
+}
+
+func (t *usageTracker) removeUser(id NumericIdentity, user string) {
+	t.mutex.Lock()
+	if u, ok := t.usage[id]; ok && u.users[user] > 0 {
+		u.users[user]--
+		if u.users[user] == 0 {
+			delete(u.users, user)
+		}
+		if len(u.users) == 0 {
+			u.unusedSince = time.Now()
+		}
+	}
+	t.mutex.Unlock()
+}
+
+// snapshot returns the users of id and the time since when it is unused
+func (t *usageTracker) snapshot(id NumericIdentity) ([]string, time.Time, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	u, ok := t.usage[id]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	users := make([]string, 0, len(u.users))
+	for user := range u.users {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	return users, u.unusedSince, true
+}
+
+func (t *usageTracker) ids() []NumericIdentity {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ids := make([]NumericIdentity, 0, len(t.usage))
+	for id := range t.usage {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// AddIdentityUser records that user, e.g. an endpoint or CIDR prefix, uses
+// the identity id. A user may be added several times and must be removed
+// equally often.
+func AddIdentityUser(id NumericIdentity, user string) {
+	tracker.addUser(id, user)
+}
+
+// RemoveIdentityUser removes one use of the identity id by user. Removing
+// a user which has not been added has no effect.
+func RemoveIdentityUser(id NumericIdentity, user string) {
+	tracker.removeUser(id, user)
+}
+
+func localRefCount(id NumericIdentity) uint64 {
+	if identityAllocator == nil || LookupReservedIdentity(id) != nil {
+		return 0
+	}
+	return identityAllocator.LocalRefCount(idpool.ID(id))
+}
+
+func getLocalUsage(identity *Identity) *IdentityUsage {
+	users, unusedSince, _ := tracker.snapshot(identity.ID)
+	return &IdentityUsage{
+		Identity:        identity,
+		LocalReferences: localRefCount(identity.ID),
+		Users:           users,
+		UnusedSince:     unusedSince,
+	}
+}
+
+// GetIdentityUsage returns the local and cluster-wide usage of the identity
+// id. Returns nil if the identity is unknown. The cluster-wide usage may
+// require accessing the kvstore or the Kubernetes apiserver.
+func GetIdentityUsage(id NumericIdentity) (*IdentityUsage, error) {
+	identity := LookupIdentityByID(id)
+	if identity == nil {
+		return nil, nil
+	}
+
+	usage := getLocalUsage(identity)
+	if identity.IsReserved() || identityAllocator == nil {
+		return usage, nil
+	}
+
+	nodes, err := identityAllocator.GetNodeUsage(globalIdentity{identity.Labels})
+	if err != nil {
+		return nil, err
+	}
+	usage.Nodes = nodes
+
+	return usage, nil
+}
+
+// GetStaleIdentities returns the identities which are still referenced by
+// the identity allocator of this node but have had no local users for at
+// least minAge, sorted by ID. Such identities are typically leaked and will
+// not be released.
+func GetStaleIdentities(minAge time.Duration) []*IdentityUsage {
+	now := time.Now()
+	stale := []*IdentityUsage{}
+
+	for _, id := range tracker.ids() {
+		users, unusedSince, ok := tracker.snapshot(id)
+		if !ok || len(users) > 0 || now.Sub(unusedSince) < minAge {
+			continue
+		}
+
+		if localRefCount(id) == 0 {
+			continue
+		}
+
+		identity := LookupIdentityByID(id)
+		if identity == nil {
+			continue
+		}
+
+		stale = append(stale, getLocalUsage(identity))
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Identity.ID < stale[j].Identity.ID
+	})
+
+	return stale
+}
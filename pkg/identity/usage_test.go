@@ -0,0 +1,117 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package identity
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+type UsageSuite struct{}
+
+var _ = Suite(&UsageSuite{})
+
+func (s *UsageSuite) TestUsageTracker(c *C) {
+	t := usageTracker{usage: map[NumericIdentity]*localUsage{}}
+
+	t.allocated(1000)
+	users, unusedSince, ok := t.snapshot(1000)
+	c.Assert(ok, Equals, true)
+	c.Assert(users, HasLen, 0)
+	c.Assert(unusedSince.IsZero(), Equals, false)
+
+	t.addUser(1000, "endpoint:1")
+	t.addUser(1000, "endpoint:1")
+	t.addUser(1000, "cidr:10.0.0.0/8")
+	users, unusedSince, _ = t.snapshot(1000)
+	c.Assert(users, DeepEquals, []string{"cidr:10.0.0.0/8", "endpoint:1"})
+	c.Assert(unusedSince.IsZero(), Equals, true)
+
+	// Users must be removed as often as they were added
+	t.removeUser(1000, "endpoint:1")
+	t.removeUser(1000, "cidr:10.0.0.0/8")
+	users, _, _ = t.snapshot(1000)
+	c.Assert(users, DeepEquals, []string{"endpoint:1"})
+
+	// Identities with users are not forgotten on release
+	t.released(1000, 0)
+	_, _, ok = t.snapshot(1000)
+	c.Assert(ok, Equals, true)
+
+	t.removeUser(1000, "endpoint:1")
+	t.removeUser(1000, "endpoint:2")
+	users, unusedSince, _ = t.snapshot(1000)
+	c.Assert(users, HasLen, 0)
+	c.Assert(unusedSince.IsZero(), Equals, false)
+
+	// Still referenced by the allocator
+	t.released(1000, 1)
+	c.Assert(t.ids(), DeepEquals, []NumericIdentity{1000})
+
+	t.released(1000, 0)
+	c.Assert(t.ids(), HasLen, 0)
+}
+
+func (s *UsageSuite) TestStaleIdentities(c *C) {
+	client := fake.NewSimpleClientset()
+	b := newTestCRDBackend(client, "node1")
+	defer b.close()
+
+	oldAllocator, oldTracker := identityAllocator, tracker.usage
+	identityAllocator = b
+	tracker.usage = map[NumericIdentity]*localUsage{}
+	defer func() {
+		identityAllocator = oldAllocator
+		tracker.usage = oldTracker
+	}()
+
+	allocate := func(lbls ...string) NumericIdentity {
+		id, _, err := b.Allocate(globalIdentity{labels.NewLabelsFromModel(lbls)})
+		c.Assert(err, IsNil)
+		tracker.allocated(NumericIdentity(id))
+		return NumericIdentity(id)
+	}
+
+	used := allocate("k8s:app=used")
+	AddIdentityUser(used, "endpoint:1")
+	leaked := allocate("k8s:app=leaked")
+
+	usage, err := GetIdentityUsage(used)
+	c.Assert(err, IsNil)
+	c.Assert(usage.LocalReferences, Equals, uint64(1))
+	c.Assert(usage.Users, DeepEquals, []string{"endpoint:1"})
+	c.Assert(usage.Nodes, DeepEquals, []string{"node1"})
+
+	usage, err = GetIdentityUsage(NumericIdentity(1000))
+	c.Assert(err, IsNil)
+	c.Assert(usage, IsNil)
+
+	stale := GetStaleIdentities(0)
+	c.Assert(stale, HasLen, 1)
+	c.Assert(stale[0].Identity.ID, Equals, leaked)
+	c.Assert(stale[0].LocalReferences, Equals, uint64(1))
+	c.Assert(GetStaleIdentities(time.Hour), HasLen, 0)
+
+	RemoveIdentityUser(used, "endpoint:1")
+	stale = GetStaleIdentities(0)
+	c.Assert(stale, HasLen, 2)
+	c.Assert(stale[0].Identity.ID < stale[1].Identity.ID, Equals, true)
+}
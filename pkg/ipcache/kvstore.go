@@ -310,6 +310,12 @@ type IPIdentityWatcher struct {
 	backend  kvstore.BackendOperations
 	stop     chan struct{}
 	stopOnce sync.Once
+
+	// mutex protects synced
+	mutex lock.RWMutex
+
+	// synced is the set of kvstore keys received from the watched kvstore
+	synced map[string]struct{}
 }
 
 // NewIPIdentityWatcher creates a new IPIdentityWatcher using the specified
@@ -318,6 +324,7 @@ func NewIPIdentityWatcher(backend kvstore.BackendOperations) *IPIdentityWatcher
 	watcher := &IPIdentityWatcher{
 		backend: backend,
 		stop:    make(chan struct{}),
+		synced:  map[string]struct{}{},
 	}
 
 	return watcher
@@ -382,7 +389,15 @@ restart:
 					Source: FromKVStore,
 				})
 
+				iw.mutex.Lock()
+				iw.synced[event.Key] = struct{}{}
+				iw.mutex.Unlock()
+
 			case kvstore.EventTypeDelete:
+				iw.mutex.Lock()
+				delete(iw.synced, event.Key)
+				iw.mutex.Unlock()
+
 				// Value is not present in deletion event;
 				// need to convert kvstore key to IP.
 				ipnet, isHost, err := keyToIPNet(event.Key)
@@ -423,6 +438,15 @@ restart:
 	}
 }
 
+// NumEntries returns the number of IP<->identity mappings received from the
+// kvstore
+func (iw *IPIdentityWatcher) NumEntries() int {
+	iw.mutex.RLock()
+	defer iw.mutex.RUnlock()
+
+	return len(iw.synced)
+}
+
 // Close stops the IPIdentityWatcher and causes Watch() to return
 func (iw *IPIdentityWatcher) Close() {
 	iw.stopOnce.Do(func() {
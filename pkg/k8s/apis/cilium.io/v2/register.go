@@ -41,7 +41,7 @@ const (
 
 	// CustomResourceDefinitionSchemaVersion is semver-conformant version of CRD schema
 	// Used to determine if CRD needs to be updated in cluster
-	CustomResourceDefinitionSchemaVersion = "1.10"
+	CustomResourceDefinitionSchemaVersion = "1.12"
 
 	// CustomResourceDefinitionSchemaVersionKey is key to label which holds the CRD schema version
 	CustomResourceDefinitionSchemaVersionKey = "io.cilium.k8s.crd.schema.version"
@@ -96,6 +96,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CiliumNetworkPolicy{},
 		&CiliumNetworkPolicyList{},
 		&CiliumEndpoint{},
+		&CiliumIdentity{},
+		&CiliumIdentityList{},
+		&CiliumNode{},
+		&CiliumNodeList{},
+		&CiliumEgressNATPolicy{},
+		&CiliumEgressNATPolicyList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -112,6 +118,18 @@ func CreateCustomResourceDefinitions(clientset apiextensionsclient.Interface) er
 		return err
 	}
 
+	if err := createIdentityCRD(clientset); err != nil {
+		return err
+	}
+
+	if err := createNodeCRD(clientset); err != nil {
+		return err
+	}
+
+	if err := createEgressNATPolicyCRD(clientset); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -242,6 +260,136 @@ func createCEPCRD(clientset apiextensionsclient.Interface) error {
 	return createUpdateCRD(clientset, "v2.CiliumEndpoint", res)
 }
 
+// createIdentityCRD creates and updates the CiliumIdentity CRD. It should be
+// called on agent startup but is idempotent and safe to call again.
+func createIdentityCRD(clientset apiextensionsclient.Interface) error {
+	var (
+		// CustomResourceDefinitionSingularName is the singular name of custom resource definition
+		CustomResourceDefinitionSingularName = "ciliumidentity"
+
+		// CustomResourceDefinitionPluralName is the plural name of custom resource definition
+		CustomResourceDefinitionPluralName = "ciliumidentities"
+
+		// CustomResourceDefinitionShortNames are the abbreviated names to refer to this CRD's instances
+		CustomResourceDefinitionShortNames = []string{"ciliumid"}
+
+		// CustomResourceDefinitionKind is the Kind name of custom resource definition
+		CustomResourceDefinitionKind = "CiliumIdentity"
+
+		CRDName = CustomResourceDefinitionPluralName + "." + SchemeGroupVersion.Group
+	)
+
+	res := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CRDName,
+			Labels: map[string]string{
+				CustomResourceDefinitionSchemaVersionKey: CustomResourceDefinitionSchemaVersion,
+			},
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:   SchemeGroupVersion.Group,
+			Version: SchemeGroupVersion.Version,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:     CustomResourceDefinitionPluralName,
+				Singular:   CustomResourceDefinitionSingularName,
+				ShortNames: CustomResourceDefinitionShortNames,
+				Kind:       CustomResourceDefinitionKind,
+			},
+			Scope:      apiextensionsv1beta1.ClusterScoped,
+			Validation: &identityCRV,
+		},
+	}
+
+	return createUpdateCRD(clientset, "v2.CiliumIdentity", res)
+}
+
+// createNodeCRD creates and updates the CiliumNode CRD. It should be called
+// on agent startup but is idempotent and safe to call again.
+func createNodeCRD(clientset apiextensionsclient.Interface) error {
+	var (
+		// CustomResourceDefinitionSingularName is the singular name of custom resource definition
+		CustomResourceDefinitionSingularName = "ciliumnode"
+
+		// CustomResourceDefinitionPluralName is the plural name of custom resource definition
+		CustomResourceDefinitionPluralName = "ciliumnodes"
+
+		// CustomResourceDefinitionShortNames are the abbreviated names to refer to this CRD's instances
+		CustomResourceDefinitionShortNames = []string{"cn"}
+
+		// CustomResourceDefinitionKind is the Kind name of custom resource definition
+		CustomResourceDefinitionKind = "CiliumNode"
+
+		CRDName = CustomResourceDefinitionPluralName + "." + SchemeGroupVersion.Group
+	)
+
+	res := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CRDName,
+			Labels: map[string]string{
+				CustomResourceDefinitionSchemaVersionKey: CustomResourceDefinitionSchemaVersion,
+			},
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:   SchemeGroupVersion.Group,
+			Version: SchemeGroupVersion.Version,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:     CustomResourceDefinitionPluralName,
+				Singular:   CustomResourceDefinitionSingularName,
+				ShortNames: CustomResourceDefinitionShortNames,
+				Kind:       CustomResourceDefinitionKind,
+			},
+			Scope:      apiextensionsv1beta1.ClusterScoped,
+			Validation: &nodeCRV,
+		},
+	}
+
+	return createUpdateCRD(clientset, "v2.CiliumNode", res)
+}
+
+// createEgressNATPolicyCRD creates and updates the CiliumEgressNATPolicy CRD.
+// It should be called on agent startup but is idempotent and safe to call
+// again.
+func createEgressNATPolicyCRD(clientset apiextensionsclient.Interface) error {
+	var (
+		// CustomResourceDefinitionSingularName is the singular name of custom resource definition
+		CustomResourceDefinitionSingularName = "ciliumegressnatpolicy"
+
+		// CustomResourceDefinitionPluralName is the plural name of custom resource definition
+		CustomResourceDefinitionPluralName = "ciliumegressnatpolicies"
+
+		// CustomResourceDefinitionShortNames are the abbreviated names to refer to this CRD's instances
+		CustomResourceDefinitionShortNames = []string{"cenp"}
+
+		// CustomResourceDefinitionKind is the Kind name of custom resource definition
+		CustomResourceDefinitionKind = "CiliumEgressNATPolicy"
+
+		CRDName = CustomResourceDefinitionPluralName + "." + SchemeGroupVersion.Group
+	)
+
+	res := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CRDName,
+			Labels: map[string]string{
+				CustomResourceDefinitionSchemaVersionKey: CustomResourceDefinitionSchemaVersion,
+			},
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:   SchemeGroupVersion.Group,
+			Version: SchemeGroupVersion.Version,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:     CustomResourceDefinitionPluralName,
+				Singular:   CustomResourceDefinitionSingularName,
+				ShortNames: CustomResourceDefinitionShortNames,
+				Kind:       CustomResourceDefinitionKind,
+			},
+			Scope:      apiextensionsv1beta1.NamespaceScoped,
+			Validation: &egressNATPolicyCRV,
+		},
+	}
+
+	return createUpdateCRD(clientset, "v2.CiliumEgressNATPolicy", res)
+}
+
 // createUpdateCRD ensures the CRD object is installed into the k8s cluster. It
 // will create or update the CRD and it's validation when needed
 func createUpdateCRD(clientset apiextensionsclient.Interface, CRDName string, crd *apiextensionsv1beta1.CustomResourceDefinition) error {
@@ -365,6 +513,59 @@ var (
 		OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{},
 	}
 
+	// identityCRV is a minimal validation for CiliumIdentity objects, the
+	// objects are only ever created by the agent.
+	identityCRV = apiextensionsv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{},
+	}
+
+	// nodeCRV is a minimal validation for CiliumNode objects, the objects
+	// are only ever created by the agent.
+	nodeCRV = apiextensionsv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{},
+	}
+
+	egressNATPolicyCRV = apiextensionsv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{
+			Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+				"spec": egressNATPolicySpec,
+			},
+		},
+	}
+
+	egressNATPolicySpec = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "Spec is the specification of the egress NAT policy.",
+		Type:        "object",
+		Required: []string{
+			"endpointSelector",
+			"destinationCIDRs",
+			"gatewayNode",
+		},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"endpointSelector": EndpointSelector,
+			"destinationCIDRs": {
+				Description: "DestinationCIDRs is the list of destinations the traffic " +
+					"is NATed for.",
+				Type:     "array",
+				MinItems: getInt64(1),
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &CIDR,
+				},
+			},
+			"gatewayNode": {
+				Description: "GatewayNode is the name of the node the traffic leaves " +
+					"the cluster through.",
+				Type: "string",
+			},
+			"egressIP": {
+				Description: "EgressIP is the IP the traffic is NATed to. It must be " +
+					"assigned to the gateway node. Defaults to the IP of the gateway node.",
+				Type:   "string",
+				Format: "ipv4",
+			},
+		},
+	}
+
 	cnpCRV = apiextensionsv1beta1.CustomResourceValidation{
 		OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{
 			Properties: properties,
@@ -974,6 +1175,14 @@ var (
 					"rule. Rules cannot be identified by comment.",
 				Type: "string",
 			},
+			"audit": {
+				Description: "Audit marks the rule as an audit rule. Endpoints which are only " +
+					"selected by audit rules have their policy computed as usual, but packets " +
+					"which would be dropped by policy are allowed instead and accounted in " +
+					"the policy_audit_total metric. The first packet of each audited flow is " +
+					"reported as an audited policy verdict notification.",
+				Type: "boolean",
+			},
 			"egress": {
 				Description: "Egress is a list of EgressRule which are enforced at egress. If " +
 					"omitted or empty, this rule does not apply at egress.",
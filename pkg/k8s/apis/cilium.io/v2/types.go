@@ -281,3 +281,161 @@ type CiliumEndpointList struct {
 	// Items is a list of CiliumEndpoint
 	Items []CiliumEndpoint `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumIdentity is a security identity allocated in the cluster. The name of
+// the object is the numeric identity, the labels it represents are stored in
+// SecurityLabels.
+// +k8s:openapi-gen=false
+type CiliumIdentity struct {
+	// +k8s:openapi-gen=false
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// SecurityLabels is the set of labels of the identity, indexed by
+	// "source:key"
+	SecurityLabels map[string]string `json:"security-labels"`
+
+	// Status is the usage status of the identity
+	Status IdentityStatus `json:"status"`
+}
+
+// IdentityStatus is the usage status of a CiliumIdentity
+type IdentityStatus struct {
+	// Nodes maps each node using the identity to the last time the node
+	// has confirmed its use of the identity
+	Nodes map[string]metav1.Time `json:"nodes,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumIdentityList is a list of CiliumIdentity objects
+// +k8s:openapi-gen=false
+type CiliumIdentityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items is a list of CiliumIdentity
+	Items []CiliumIdentity `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumNode is a node managed by Cilium. The object is created and
+// maintained by the agent running on the node, its name is the name of the
+// node. It contains all information other nodes require to reach the node
+// and its endpoints.
+// +k8s:openapi-gen=false
+type CiliumNode struct {
+	// +k8s:openapi-gen=false
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec is the specification of the node
+	Spec NodeSpec `json:"spec"`
+}
+
+// NodeSpec is the specification of a CiliumNode
+type NodeSpec struct {
+	// Addresses is the list of all addresses of the node
+	Addresses []NodeAddress `json:"addresses,omitempty"`
+
+	// IPv4AllocCIDR is the IPv4 prefix out of which the node allocates
+	// addresses for its endpoints
+	IPv4AllocCIDR string `json:"ipv4-alloc-cidr,omitempty"`
+
+	// IPv6AllocCIDR is the IPv6 prefix out of which the node allocates
+	// addresses for its endpoints
+	IPv6AllocCIDR string `json:"ipv6-alloc-cidr,omitempty"`
+
+	// IPv4HealthIP is the IPv4 address of the cilium-health endpoint of
+	// the node
+	IPv4HealthIP string `json:"ipv4-health-ip,omitempty"`
+
+	// IPv6HealthIP is the IPv6 address of the cilium-health endpoint of
+	// the node
+	IPv6HealthIP string `json:"ipv6-health-ip,omitempty"`
+
+	// EncryptionKey is the index of the key the node uses to encrypt
+	// traffic, 0 if encryption is disabled
+	EncryptionKey uint8 `json:"encryption-key,omitempty"`
+}
+
+// NodeAddress is an address of a CiliumNode
+type NodeAddress struct {
+	// Type is the type of the address, e.g. InternalIP or ExternalIP
+	Type string `json:"type"`
+
+	// IP is the IP address
+	IP string `json:"ip"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumNodeList is a list of CiliumNode objects
+// +k8s:openapi-gen=false
+type CiliumNodeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items is a list of CiliumNode
+	Items []CiliumNode `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumEgressNATPolicy steers the traffic of the selected endpoints to the
+// destination CIDRs via a gateway node which NATs the traffic to the egress
+// IP before it leaves the cluster.
+// +k8s:openapi-gen=false
+type CiliumEgressNATPolicy struct {
+	// +k8s:openapi-gen=false
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec is the specification of the egress NAT policy
+	Spec EgressNATPolicySpec `json:"spec"`
+}
+
+// EgressNATPolicySpec is the specification of a CiliumEgressNATPolicy
+type EgressNATPolicySpec struct {
+	// EndpointSelector selects the endpoints in the namespace of the
+	// policy whose traffic is NATed
+	EndpointSelector api.EndpointSelector `json:"endpointSelector"`
+
+	// DestinationCIDRs is the list of destinations the traffic is NATed
+	// for
+	DestinationCIDRs []api.CIDR `json:"destinationCIDRs"`
+
+	// GatewayNode is the name of the node the traffic leaves the cluster
+	// through
+	GatewayNode string `json:"gatewayNode"`
+
+	// EgressIP is the IP the traffic is NATed to. It must be assigned to
+	// the gateway node. Defaults to the IP of the gateway node.
+	EgressIP string `json:"egressIP,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumEgressNATPolicyList is a list of CiliumEgressNATPolicy objects
+// +k8s:openapi-gen=false
+type CiliumEgressNATPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items is a list of CiliumEgressNATPolicy
+	Items []CiliumEgressNATPolicy `json:"items"`
+}
@@ -0,0 +1,199 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"net"
+	"reflect"
+
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	versionedClient "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParseCiliumNode parses a CiliumNode to a cilium node
+func ParseCiliumNode(cn *cilium_v2.CiliumNode, source node.Source) *node.Node {
+	scopedLog := log.WithField(logfields.NodeName, cn.Name)
+
+	n := &node.Node{
+		Name:          cn.Name,
+		Cluster:       option.Config.ClusterName,
+		ClusterID:     option.Config.ClusterID,
+		IPAddresses:   []node.Address{},
+		EncryptionKey: cn.Spec.EncryptionKey,
+		Source:        source,
+	}
+
+	for _, addr := range cn.Spec.Addresses {
+		ip := net.ParseIP(addr.IP)
+		if ip == nil {
+			scopedLog.WithFields(logrus.Fields{
+				logfields.IPAddr: addr.IP,
+				"type":           addr.Type,
+			}).Warn("Ignoring invalid node IP")
+			continue
+		}
+		n.IPAddresses = append(n.IPAddresses, node.Address{
+			AddressType: v1.NodeAddressType(addr.Type),
+			IP:          ip,
+		})
+	}
+
+	if cidr := cn.Spec.IPv4AllocCIDR; cidr != "" {
+		if _, ipnet, err := net.ParseCIDR(cidr); err != nil {
+			scopedLog.WithError(err).WithField(logfields.V4Prefix, cidr).Warn("Invalid IPv4 allocation CIDR in CiliumNode")
+		} else {
+			n.IPv4AllocCIDR = ipnet
+		}
+	}
+
+	if cidr := cn.Spec.IPv6AllocCIDR; cidr != "" {
+		if _, ipnet, err := net.ParseCIDR(cidr); err != nil {
+			scopedLog.WithError(err).WithField(logfields.V6Prefix, cidr).Warn("Invalid IPv6 allocation CIDR in CiliumNode")
+		} else {
+			n.IPv6AllocCIDR = ipnet
+		}
+	}
+
+	if healthIP := cn.Spec.IPv4HealthIP; healthIP != "" {
+		if ip := net.ParseIP(healthIP); ip == nil {
+			scopedLog.WithField(logfields.V4HealthIP, healthIP).Warn("Invalid IPv4 health endpoint IP in CiliumNode")
+		} else {
+			n.IPv4HealthIP = ip
+		}
+	}
+
+	if healthIP := cn.Spec.IPv6HealthIP; healthIP != "" {
+		if ip := net.ParseIP(healthIP); ip == nil {
+			scopedLog.WithField(logfields.V6HealthIP, healthIP).Warn("Invalid IPv6 health endpoint IP in CiliumNode")
+		} else {
+			n.IPv6HealthIP = ip
+		}
+	}
+
+	return n
+}
+
+// NewCiliumNodeSpec returns the CiliumNode specification of a cilium node
+func NewCiliumNodeSpec(n *node.Node) cilium_v2.NodeSpec {
+	spec := cilium_v2.NodeSpec{
+		EncryptionKey: n.EncryptionKey,
+	}
+
+	for _, addr := range n.IPAddresses {
+		spec.Addresses = append(spec.Addresses, cilium_v2.NodeAddress{
+			Type: string(addr.AddressType),
+			IP:   addr.IP.String(),
+		})
+	}
+
+	if n.IPv4AllocCIDR != nil {
+		spec.IPv4AllocCIDR = n.IPv4AllocCIDR.String()
+	}
+	if n.IPv6AllocCIDR != nil {
+		spec.IPv6AllocCIDR = n.IPv6AllocCIDR.String()
+	}
+	if n.IPv4HealthIP != nil {
+		spec.IPv4HealthIP = n.IPv4HealthIP.String()
+	}
+	if n.IPv6HealthIP != nil {
+		spec.IPv6HealthIP = n.IPv6HealthIP.String()
+	}
+
+	return spec
+}
+
+// newCiliumNodeOwnerReferences returns the owner references which tie the
+// lifecycle of a CiliumNode to its kubernetes node. The CiliumNode is garbage
+// collected by kubernetes once the node has been removed from the cluster.
+func newCiliumNodeOwnerReferences(owner *v1.Node) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       owner.Name,
+			UID:        owner.UID,
+		},
+	}
+}
+
+// UpdateCiliumNode creates the CiliumNode resource of the node n, owned by
+// the kubernetes node owner, or updates it if its specification or owner has
+// changed
+func UpdateCiliumNode(c versionedClient.Interface, owner *v1.Node, n *node.Node) error {
+	spec := NewCiliumNodeSpec(n)
+	ownerReferences := newCiliumNodeOwnerReferences(owner)
+
+	cn, err := c.CiliumV2().CiliumNodes().Get(n.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = c.CiliumV2().CiliumNodes().Create(&cilium_v2.CiliumNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            n.Name,
+				OwnerReferences: ownerReferences,
+			},
+			Spec: spec,
+		})
+		return err
+	case err != nil:
+		return err
+	}
+
+	// CiliumNodes created before owner references were set are updated as
+	// well so that they are garbage collected together with the node
+	if reflect.DeepEqual(cn.Spec, spec) && reflect.DeepEqual(cn.OwnerReferences, ownerReferences) {
+		return nil
+	}
+
+	cn.Spec = spec
+	cn.OwnerReferences = ownerReferences
+	_, err = c.CiliumV2().CiliumNodes().Update(cn)
+	return err
+}
+
+// GetCiliumNode returns the CiliumNode resource of nodeName from the
+// kubernetes api server
+func GetCiliumNode(c versionedClient.Interface, nodeName string) (*cilium_v2.CiliumNode, error) {
+	return c.CiliumV2().CiliumNodes().Get(nodeName, metav1.GetOptions{})
+}
+
+// restoreCiliumNodeCIDRs fills in the allocation CIDRs of n which are not set
+// yet from the CiliumNode resource of the node, if it exists
+func restoreCiliumNodeCIDRs(c versionedClient.Interface, n *node.Node) {
+	scopedLog := log.WithField(logfields.NodeName, n.Name)
+
+	cn, err := GetCiliumNode(c, n.Name)
+	if err != nil {
+		// The resource or its definition do not exist yet when the
+		// agent is started for the first time on the node
+		scopedLog.WithError(err).Debug("Unable to retrieve CiliumNode resource")
+		return
+	}
+
+	restored := ParseCiliumNode(cn, n.Source)
+	if n.IPv4AllocCIDR == nil {
+		n.IPv4AllocCIDR = restored.IPv4AllocCIDR
+	}
+	if n.IPv6AllocCIDR == nil {
+		n.IPv6AllocCIDR = restored.IPv6AllocCIDR
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package k8s
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/checker"
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/node"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (s *K8sSuite) TestParseCiliumNode(c *C) {
+	cn := &cilium_v2.CiliumNode{
+		Spec: cilium_v2.NodeSpec{
+			Addresses: []cilium_v2.NodeAddress{
+				{Type: string(v1.NodeInternalIP), IP: "192.168.0.1"},
+				{Type: string(node.NodeCiliumInternalIP), IP: "10.1.0.1"},
+				{Type: string(v1.NodeExternalIP), IP: "invalid"},
+			},
+			IPv4AllocCIDR: "10.1.0.0/16",
+			IPv6AllocCIDR: "invalid",
+			IPv4HealthIP:  "10.1.0.2",
+			IPv6HealthIP:  "f00d::1",
+			EncryptionKey: 3,
+		},
+	}
+	cn.Name = "node1"
+
+	n := ParseCiliumNode(cn, node.FromKubernetes)
+	c.Assert(n.Name, Equals, "node1")
+	c.Assert(n.Source, Equals, node.FromKubernetes)
+	c.Assert(n.IPAddresses, checker.DeepEquals, []node.Address{
+		{AddressType: v1.NodeInternalIP, IP: net.ParseIP("192.168.0.1")},
+		{AddressType: node.NodeCiliumInternalIP, IP: net.ParseIP("10.1.0.1")},
+	})
+	c.Assert(n.IPv4AllocCIDR.String(), Equals, "10.1.0.0/16")
+	c.Assert(n.IPv6AllocCIDR, IsNil)
+	c.Assert(n.IPv4HealthIP.String(), Equals, "10.1.0.2")
+	c.Assert(n.IPv6HealthIP.String(), Equals, "f00d::1")
+	c.Assert(n.EncryptionKey, Equals, uint8(3))
+	c.Assert(n.GetNodeIP(false).String(), Equals, "192.168.0.1")
+}
+
+func (s *K8sSuite) TestUpdateCiliumNode(c *C) {
+	_, v4CIDR, _ := net.ParseCIDR("10.1.0.0/16")
+	n := &node.Node{
+		Name: "node1",
+		IPAddresses: []node.Address{
+			{AddressType: v1.NodeInternalIP, IP: net.ParseIP("192.168.0.1")},
+			{AddressType: node.NodeCiliumInternalIP, IP: net.ParseIP("10.1.0.1")},
+		},
+		IPv4AllocCIDR: v4CIDR,
+		EncryptionKey: 1,
+	}
+
+	owner := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			UID:  types.UID("a1b2c3"),
+		},
+	}
+	ownerReferences := []metav1.OwnerReference{
+		{APIVersion: "v1", Kind: "Node", Name: "node1", UID: types.UID("a1b2c3")},
+	}
+
+	// A resource created by an agent which did not set the owner yet
+	client := fake.NewSimpleClientset(&cilium_v2.CiliumNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+		},
+	})
+	err := UpdateCiliumNode(client, owner, n)
+	c.Assert(err, IsNil)
+
+	cn, err := GetCiliumNode(client, "node1")
+	c.Assert(err, IsNil)
+	c.Assert(cn.OwnerReferences, checker.DeepEquals, ownerReferences)
+
+	// The resource is created if it does not exist yet
+	client = fake.NewSimpleClientset()
+	err = UpdateCiliumNode(client, owner, n)
+	c.Assert(err, IsNil)
+
+	cn, err = GetCiliumNode(client, "node1")
+	c.Assert(err, IsNil)
+	c.Assert(cn.OwnerReferences, checker.DeepEquals, ownerReferences)
+	c.Assert(cn.Spec, checker.DeepEquals, cilium_v2.NodeSpec{
+		Addresses: []cilium_v2.NodeAddress{
+			{Type: string(v1.NodeInternalIP), IP: "192.168.0.1"},
+			{Type: string(node.NodeCiliumInternalIP), IP: "10.1.0.1"},
+		},
+		IPv4AllocCIDR: "10.1.0.0/16",
+		EncryptionKey: 1,
+	})
+
+	// The resource is updated once the node has changed
+	n.IPv4HealthIP = net.ParseIP("10.1.0.2")
+	err = UpdateCiliumNode(client, owner, n)
+	c.Assert(err, IsNil)
+
+	cn, err = GetCiliumNode(client, "node1")
+	c.Assert(err, IsNil)
+	c.Assert(cn.Spec.IPv4HealthIP, Equals, "10.1.0.2")
+
+	parsed := ParseCiliumNode(cn, node.FromKubernetes)
+	n.Source = node.FromKubernetes
+	c.Assert(parsed.PublicAttrEquals(n), Equals, true)
+}
+
+func (s *K8sSuite) TestRestoreCiliumNodeCIDRs(c *C) {
+	client := fake.NewSimpleClientset()
+
+	// Nothing is restored if the resource does not exist yet
+	n := &node.Node{Name: "node1"}
+	restoreCiliumNodeCIDRs(client, n)
+	c.Assert(n.IPv4AllocCIDR, IsNil)
+	c.Assert(n.IPv6AllocCIDR, IsNil)
+
+	client = fake.NewSimpleClientset(&cilium_v2.CiliumNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+		},
+		Spec: cilium_v2.NodeSpec{
+			IPv4AllocCIDR: "10.1.0.0/16",
+			IPv6AllocCIDR: "f00d::a0b:0:0:0/96",
+		},
+	})
+
+	// CIDRs provided by the node resource take precedence
+	_, v4CIDR, _ := net.ParseCIDR("10.2.0.0/16")
+	n = &node.Node{Name: "node1", IPv4AllocCIDR: v4CIDR}
+	restoreCiliumNodeCIDRs(client, n)
+	c.Assert(n.IPv4AllocCIDR.String(), Equals, "10.2.0.0/16")
+	c.Assert(n.IPv6AllocCIDR.String(), Equals, "f00d::a0b:0:0:0/96")
+}
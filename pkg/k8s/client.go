@@ -23,6 +23,7 @@ import (
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/annotation"
+	versionedClient "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 
 	go_version "github.com/hashicorp/go-version"
@@ -43,6 +44,10 @@ var (
 	// client is the object through which interactions with Kubernetes are
 	// performed.
 	client kubernetes.Interface
+
+	// ciliumClient is the object through which interactions with the
+	// Cilium custom resources are performed.
+	ciliumClient versionedClient.Interface
 )
 
 // CreateConfig creates a rest.Config for a given endpoint using a kubeconfig file.
@@ -228,6 +233,11 @@ func Client() kubernetes.Interface {
 	return client
 }
 
+// CiliumClient returns the default client for the Cilium custom resources
+func CiliumClient() versionedClient.Interface {
+	return ciliumClient
+}
+
 func createDefaultClient() error {
 	restConfig, err := CreateConfig()
 	if err != nil {
@@ -239,7 +249,13 @@ func createDefaultClient() error {
 		return fmt.Errorf("unable to create k8s client: %s", err)
 	}
 
+	ciliumK8sClient, err := versionedClient.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create cilium k8s client: %s", err)
+	}
+
 	client = k8sClient
+	ciliumClient = ciliumK8sClient
 
 	return nil
 }
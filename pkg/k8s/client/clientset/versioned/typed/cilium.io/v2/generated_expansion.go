@@ -16,6 +16,12 @@
 
 package v2
 
+type CiliumEgressNATPolicyExpansion interface{}
+
 type CiliumEndpointExpansion interface{}
 
+type CiliumIdentityExpansion interface{}
+
 type CiliumNetworkPolicyExpansion interface{}
+
+type CiliumNodeExpansion interface{}
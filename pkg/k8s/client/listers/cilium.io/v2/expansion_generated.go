@@ -16,6 +16,14 @@
 
 package v2
 
+// CiliumEgressNATPolicyListerExpansion allows custom methods to be added to
+// CiliumEgressNATPolicyLister.
+type CiliumEgressNATPolicyListerExpansion interface{}
+
+// CiliumEgressNATPolicyNamespaceListerExpansion allows custom methods to be added to
+// CiliumEgressNATPolicyNamespaceLister.
+type CiliumEgressNATPolicyNamespaceListerExpansion interface{}
+
 // CiliumEndpointListerExpansion allows custom methods to be added to
 // CiliumEndpointLister.
 type CiliumEndpointListerExpansion interface{}
@@ -24,6 +32,10 @@ type CiliumEndpointListerExpansion interface{}
 // CiliumEndpointNamespaceLister.
 type CiliumEndpointNamespaceListerExpansion interface{}
 
+// CiliumIdentityListerExpansion allows custom methods to be added to
+// CiliumIdentityLister.
+type CiliumIdentityListerExpansion interface{}
+
 // CiliumNetworkPolicyListerExpansion allows custom methods to be added to
 // CiliumNetworkPolicyLister.
 type CiliumNetworkPolicyListerExpansion interface{}
@@ -31,3 +43,7 @@ type CiliumNetworkPolicyListerExpansion interface{}
 // CiliumNetworkPolicyNamespaceListerExpansion allows custom methods to be added to
 // CiliumNetworkPolicyNamespaceLister.
 type CiliumNetworkPolicyNamespaceListerExpansion interface{}
+
+// CiliumNodeListerExpansion allows custom methods to be added to
+// CiliumNodeLister.
+type CiliumNodeListerExpansion interface{}
@@ -92,6 +92,14 @@ func init() {
 		equalV1Node,
 	)
 
+	utils.RegisterObject(
+		&cilium_v2.CiliumNode{},
+		"ciliumnodes",
+		copyObjToV2CiliumNode,
+		listV2CiliumNode,
+		equalV2CiliumNode,
+	)
+
 	utils.RegisterObject(
 		&v1.Namespace{},
 		"namespaces",
@@ -171,6 +179,16 @@ func copyObjToV1Node(obj interface{}) meta_v1.Object {
 	return node.DeepCopy()
 }
 
+func copyObjToV2CiliumNode(obj interface{}) meta_v1.Object {
+	cn, ok := obj.(*cilium_v2.CiliumNode)
+	if !ok {
+		log.WithField(logfields.Object, logfields.Repr(obj)).
+			Warn("Ignoring invalid k8s v2 CiliumNode")
+		return nil
+	}
+	return cn.DeepCopy()
+}
+
 func copyObjToV1Namespace(obj interface{}) meta_v1.Object {
 	ns, ok := obj.(*v1.Namespace)
 	if !ok {
@@ -363,6 +381,32 @@ func listV1Node(client interface{}) func() (versioned.Map, error) {
 	}
 }
 
+func listV2CiliumNode(client interface{}) func() (versioned.Map, error) {
+	k8sClient, ok := client.(versionedClient.Interface)
+	if !ok {
+		log.Panicf("Invalid resource type %s: expecting 'versionedClient.Interface'", reflect.TypeOf(client))
+	}
+	return func() (versioned.Map, error) {
+		m := versioned.NewMap()
+		// Limit the number of elements to avoid network congestion every N minutes
+		lo := meta_v1.ListOptions{Limit: 50}
+		for {
+			list, err := k8sClient.CiliumV2().CiliumNodes().List(lo)
+			if err != nil {
+				return nil, err
+			}
+			lo.Continue = list.Continue
+			for i := range list.Items {
+				m.Add(utils.GetVerStructFrom(&list.Items[i]))
+			}
+			if lo.Continue == "" {
+				break
+			}
+		}
+		return m, nil
+	}
+}
+
 func listV1Namespace(client interface{}) func() (versioned.Map, error) {
 	k8sClient, ok := client.(kubernetes.Interface)
 	if !ok {
@@ -426,6 +470,7 @@ func equalV1Services(o1, o2 interface{}) bool {
 		headless = true
 	}
 	si1 := loadbalancer.NewK8sServiceInfo(clusterIP, headless, svc1.Labels, svc1.Spec.Selector)
+	si1.IsGlobal = IsGlobalService(svc1)
 
 	clusterIP = net.ParseIP(svc2.Spec.ClusterIP)
 	headless = false
@@ -433,6 +478,7 @@ func equalV1Services(o1, o2 interface{}) bool {
 		headless = true
 	}
 	si2 := loadbalancer.NewK8sServiceInfo(clusterIP, headless, svc2.Labels, svc2.Spec.Selector)
+	si2.IsGlobal = IsGlobalService(svc2)
 
 	// Please write all the equalness logic inside the K8sServiceInfo.Equals()
 	// method.
@@ -541,6 +587,21 @@ func equalV1Node(o1, o2 interface{}) bool {
 		node1.GetAnnotations()[annotation.CiliumHostIP] == node2.GetAnnotations()[annotation.CiliumHostIP]
 }
 
+func equalV2CiliumNode(o1, o2 interface{}) bool {
+	cn1, ok := o1.(*cilium_v2.CiliumNode)
+	if !ok {
+		log.Panicf("Invalid resource type %q, expecting *cilium_v2.CiliumNode", reflect.TypeOf(o1))
+		return false
+	}
+	cn2, ok := o2.(*cilium_v2.CiliumNode)
+	if !ok {
+		log.Panicf("Invalid resource type %q, expecting *cilium_v2.CiliumNode", reflect.TypeOf(o2))
+		return false
+	}
+	return cn1.Name == cn2.Name &&
+		reflect.DeepEqual(cn1.Spec, cn2.Spec)
+}
+
 func equalV1Namespace(o1, o2 interface{}) bool {
 	ns1, ok := o1.(*v1.Namespace)
 	if !ok {
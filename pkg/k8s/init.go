@@ -73,6 +73,12 @@ func retrieveNodeInformation(nodeName string) (*node.Node, error) {
 	n := ParseNode(k8sNode, node.FromAgentLocal)
 	log.WithField(logfields.NodeName, n.Name).Info("Retrieved node information from kubernetes")
 
+	// Restore allocation CIDRs not provided by the node resource from the
+	// CiliumNode resource written by a previous run of the agent
+	if n.IPv4AllocCIDR == nil || n.IPv6AllocCIDR == nil {
+		restoreCiliumNodeCIDRs(CiliumClient(), n)
+	}
+
 	if requireIPv4CIDR && n.IPv4AllocCIDR == nil {
 		return nil, fmt.Errorf("Required IPv4 pod CIDR not present in node resource")
 	}
@@ -128,6 +128,34 @@ func ParseNode(k8sNode *v1.Node, source node.Source) *node.Node {
 	return node
 }
 
+// ParseLegacyNode parses a kubernetes node annotated by a cilium agent which
+// does not register its node as CiliumNode yet. The IP of the cilium_host
+// interface is taken from the node annotation. Nodes which have not been
+// annotated by a cilium agent are ignored and nil is returned.
+func ParseLegacyNode(k8sNode *v1.Node, source node.Source) *node.Node {
+	ciliumHostIP, ok := k8sNode.Annotations[annotation.CiliumHostIP]
+	if !ok {
+		return nil
+	}
+
+	ip := net.ParseIP(ciliumHostIP)
+	if ip == nil {
+		log.WithFields(logrus.Fields{
+			logfields.NodeName:       k8sNode.Name,
+			logfields.V4CiliumHostIP: ciliumHostIP,
+		}).Warn("Ignoring node with invalid cilium host IP annotation")
+		return nil
+	}
+
+	n := ParseNode(k8sNode, source)
+	n.IPAddresses = append(n.IPAddresses, node.Address{
+		AddressType: node.NodeCiliumInternalIP,
+		IP:          ip,
+	})
+
+	return n
+}
+
 // GetNode returns the kubernetes nodeName's node information from the
 // kubernetes api server
 func GetNode(c kubernetes.Interface, nodeName string) (*v1.Node, error) {
@@ -17,7 +17,10 @@
 package k8s
 
 import (
+	"net"
+
 	"github.com/cilium/cilium/pkg/annotation"
+	"github.com/cilium/cilium/pkg/checker"
 	"github.com/cilium/cilium/pkg/node"
 
 	. "gopkg.in/check.v1"
@@ -86,3 +89,37 @@ func (s *K8sSuite) TestParseNode(c *C) {
 	c.Assert(n.IPv6AllocCIDR, NotNil)
 	c.Assert(n.IPv6AllocCIDR.String(), Equals, "f00d:aaaa:bbbb:cccc:dddd:eeee::/112")
 }
+
+func (s *K8sSuite) TestParseLegacyNode(c *C) {
+	k8sNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			Annotations: map[string]string{
+				annotation.V4CIDRName:   "10.254.0.0/16",
+				annotation.CiliumHostIP: "10.254.0.1",
+			},
+		},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "192.168.0.1"},
+			},
+		},
+	}
+
+	n := ParseLegacyNode(k8sNode, node.FromKubernetes)
+	c.Assert(n, NotNil)
+	c.Assert(n.Name, Equals, "node1")
+	c.Assert(n.IPv4AllocCIDR.String(), Equals, "10.254.0.0/16")
+	c.Assert(n.IPAddresses, checker.DeepEquals, []node.Address{
+		{AddressType: v1.NodeInternalIP, IP: net.ParseIP("192.168.0.1")},
+		{AddressType: node.NodeCiliumInternalIP, IP: net.ParseIP("10.254.0.1")},
+	})
+
+	// Invalid cilium host IP annotation
+	k8sNode.Annotations[annotation.CiliumHostIP] = "invalid"
+	c.Assert(ParseLegacyNode(k8sNode, node.FromKubernetes), IsNil)
+
+	// Node not annotated by a cilium agent
+	delete(k8sNode.Annotations, annotation.CiliumHostIP)
+	c.Assert(ParseLegacyNode(k8sNode, node.FromKubernetes), IsNil)
+}
@@ -0,0 +1,30 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"strings"
+
+	"github.com/cilium/cilium/pkg/annotation"
+
+	"k8s.io/api/core/v1"
+)
+
+// IsGlobalService returns true if the service has been annotated to be a
+// global service shared with all clusters of a ClusterMesh
+func IsGlobalService(svc *v1.Service) bool {
+	value, ok := svc.ObjectMeta.Annotations[annotation.GlobalService]
+	return ok && strings.ToLower(value) == "true"
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -622,6 +623,30 @@ func (a *Allocator) GetByID(id idpool.ID) (AllocatorKey, error) {
 	return a.keyType.PutKey(string(v))
 }
 
+// LocalRefCount returns the number of references to id held by this
+// allocator. Returns 0 if the ID is not in local use.
+func (a *Allocator) LocalRefCount(id idpool.ID) uint64 {
+	return a.localKeys.refcnt(id)
+}
+
+// GetNodeUsage returns the suffixes of all slave keys of key, i.e. the list
+// of nodes which use the ID allocated to key.
+func (a *Allocator) GetNodeUsage(key AllocatorKey) ([]string, error) {
+	prefix := path.Join(a.valuePrefix, key.GetKey()) + "/"
+	uses, err := kvstore.ListPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, 0, len(uses))
+	for k := range uses {
+		nodes = append(nodes, strings.TrimPrefix(k, prefix))
+	}
+	sort.Strings(nodes)
+
+	return nodes, nil
+}
+
 // Release releases the use of an ID associated with the provided key. After
 // the last user has released the ID, the key is removed in the KVstore and
 // the returned lastUse value is true.
@@ -815,6 +840,11 @@ func (a *Allocator) WatchRemoteKVStore(backend kvstore.BackendOperations, prefix
 	return rc
 }
 
+// NumEntries returns the number of identities in the remote cache
+func (rc *RemoteCache) NumEntries() int {
+	return rc.cache.numEntries()
+}
+
 // Close stops watching for identities in the kvstore associated with the
 // remote cache and will clear the local cache.
 func (rc *RemoteCache) Close() {
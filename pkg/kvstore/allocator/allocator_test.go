@@ -203,6 +203,12 @@ func testAllocator(c *C, maxID idpool.ID, allocatorName string, suffix string) {
 
 		// refcnt in the 2nd allocator is 1
 		c.Assert(localKey.refcnt, Equals, uint64(1))
+		c.Assert(allocator2.LocalRefCount(id), Equals, uint64(1))
+
+		// both allocators use the key
+		nodes, err := allocator2.GetNodeUsage(key)
+		c.Assert(err, IsNil)
+		c.Assert(nodes, DeepEquals, []string{suffix, "b"})
 
 		allocator2.Release(key)
 	}
@@ -289,6 +289,12 @@ func (c *cache) foreach(cb RangeFunc) {
 	c.mutex.RUnlock()
 }
 
+func (c *cache) numEntries() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.cache)
+}
+
 func (c *cache) insert(key AllocatorKey, val idpool.ID) {
 	c.mutex.Lock()
 	c.nextCache[val] = key
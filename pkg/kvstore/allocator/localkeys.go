@@ -97,6 +97,19 @@ func (lk *localKeys) lookupID(id idpool.ID) string {
 	return ""
 }
 
+// refcnt returns the number of local references to the key associated with
+// id
+func (lk *localKeys) refcnt(id idpool.ID) uint64 {
+	lk.RLock()
+	defer lk.RUnlock()
+
+	if k, ok := lk.ids[id]; ok {
+		return k.refcnt
+	}
+
+	return 0
+}
+
 // use increments the refcnt of the key and returns its value
 func (lk *localKeys) use(key string) idpool.ID {
 	lk.Lock()
@@ -43,6 +43,7 @@ func (s *AllocatorSuite) TestLocalKeys(c *C) {
 	v, err = k.allocate(key, val) // refcnt=2
 	c.Assert(err, IsNil)
 	c.Assert(v, Equals, val)
+	c.Assert(k.refcnt(val), Equals, uint64(2))
 
 	v, err = k.allocate(key2, val2) // refcnt=1
 	c.Assert(err, IsNil)
@@ -68,4 +69,5 @@ func (s *AllocatorSuite) TestLocalKeys(c *C) {
 	k.release(key2) // refcnt=0
 	v = k.use(key2)
 	c.Assert(v, Equals, idpool.NoID)
+	c.Assert(k.refcnt(val2), Equals, uint64(0))
 }
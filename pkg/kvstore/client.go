@@ -22,7 +22,7 @@ var (
 )
 
 func initClient(module backendModule) error {
-	c, err := module.newClient()
+	c, err := module.newClient(nil)
 	if err != nil {
 		return err
 	}
@@ -38,8 +38,24 @@ func Client() BackendOperations {
 	return defaultClient
 }
 
-// NewClient returns a new kvstore client based on the configuration
-func NewClient(selectedBackend string, opts map[string]string) (BackendOperations, error) {
+// ExtraOptions represents the options of a kvstore client which can not be
+// represented in a textual format and need to be set programmatically.
+type ExtraOptions struct {
+	// WatchErrorHandler is called when a watcher of the client fails to
+	// list or watch its prefix. The watcher retries on its own.
+	WatchErrorHandler func(err error)
+}
+
+// watchError calls the WatchErrorHandler, if any
+func (e *ExtraOptions) watchError(err error) {
+	if e != nil && e.WatchErrorHandler != nil {
+		e.WatchErrorHandler(err)
+	}
+}
+
+// NewClient returns a new kvstore client based on the configuration. opts
+// may be nil.
+func NewClient(selectedBackend string, opts map[string]string, options *ExtraOptions) (BackendOperations, error) {
 	module := getBackend(selectedBackend)
 	if module == nil {
 		return nil, fmt.Errorf("unknown key-value store type %q. See cilium.link/err-kvstore for details", selectedBackend)
@@ -49,7 +65,7 @@ func NewClient(selectedBackend string, opts map[string]string) (BackendOperation
 		return nil, err
 	}
 
-	c, err := module.newClient()
+	c, err := module.newClient(options)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func (c *consulModule) getConfig() map[string]string {
 	return getOpts(c.opts)
 }
 
-func (c *consulModule) newClient() (BackendOperations, error) {
+func (c *consulModule) newClient(opts *ExtraOptions) (BackendOperations, error) {
 	if c.config == nil {
 		consulAddr, ok := c.opts[optAddress]
 		if !ok {
@@ -104,7 +104,7 @@ func (c *consulModule) newClient() (BackendOperations, error) {
 		c.config.Address = addr
 	}
 
-	client, err := newConsulClient(c.config)
+	client, err := newConsulClient(c.config, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -120,9 +120,12 @@ type consulClient struct {
 	*consulAPI.Client
 	lease       string
 	controllers *controller.Manager
+
+	// extraOptions is the optional configuration passed to NewClient
+	extraOptions *ExtraOptions
 }
 
-func newConsulClient(config *consulAPI.Config) (BackendOperations, error) {
+func newConsulClient(config *consulAPI.Config, opts *ExtraOptions) (BackendOperations, error) {
 	var (
 		c   *consulAPI.Client
 		err error
@@ -170,9 +173,10 @@ func newConsulClient(config *consulAPI.Config) (BackendOperations, error) {
 	}
 
 	client := &consulClient{
-		Client:      c,
-		lease:       lease,
-		controllers: controller.NewManager(),
+		Client:       c,
+		lease:        lease,
+		controllers:  controller.NewManager(),
+		extraOptions: opts,
 	}
 
 	client.controllers.UpdateController(fmt.Sprintf("consul-lease-keepalive-%p", c),
@@ -229,6 +233,7 @@ func (c *consulClient) Watch(w *Watcher) {
 		if err != nil {
 			sleepTime = 5 * time.Second
 			Trace("List of Watch failed", err, logrus.Fields{fieldPrefix: w.prefix, fieldWatcher: w.name})
+			c.extraOptions.watchError(err)
 		}
 
 		if q != nil {
@@ -110,7 +110,7 @@ func (e *etcdModule) getConfig() map[string]string {
 	return getOpts(e.opts)
 }
 
-func (e *etcdModule) newClient() (BackendOperations, error) {
+func (e *etcdModule) newClient(opts *ExtraOptions) (BackendOperations, error) {
 	endpointsOpt, endpointsSet := e.opts[addrOption]
 	configPathOpt, configSet := e.opts[EtcdOptionConfig]
 	configPath := ""
@@ -132,7 +132,7 @@ func (e *etcdModule) newClient() (BackendOperations, error) {
 		}
 	}
 
-	return newEtcdClient(e.config, configPath)
+	return newEtcdClient(e.config, configPath, opts)
 }
 
 func init() {
@@ -162,6 +162,9 @@ type etcdClient struct {
 
 	// latestErrorStatus is the latest error condition of the etcd connection
 	latestErrorStatus error
+
+	// extraOptions is the optional configuration passed to NewClient
+	extraOptions *ExtraOptions
 }
 
 type etcdMutex struct {
@@ -200,7 +203,7 @@ func (e *etcdClient) renewSession() error {
 	return nil
 }
 
-func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, error) {
+func newEtcdClient(config *client.Config, cfgPath string, opts *ExtraOptions) (BackendOperations, error) {
 	if cfgPath != "" {
 		cfg, err := clientyaml.NewConfig(cfgPath)
 		if err != nil {
@@ -243,6 +246,7 @@ func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, er
 		firstSession:         firstSession,
 		controllers:          controller.NewManager(),
 		latestStatusSnapshot: "No connection to etcd",
+		extraOptions:         opts,
 	}
 
 	// wait for session to be created also in parallel
@@ -380,6 +384,7 @@ reList:
 			client.WithSerializable())
 		if err != nil {
 			scopedLog.WithError(err).Warn("Unable to list keys before starting watcher")
+			e.extraOptions.watchError(err)
 			continue
 		}
 
@@ -454,6 +459,8 @@ reList:
 					// watch on the next possible revision
 					if err == v3rpcErrors.ErrCompacted {
 						scopedLog.WithError(err).Debug("Tried watching on compacted revision")
+					} else {
+						e.extraOptions.watchError(err)
 					}
 
 					// mark all local keys in state for
@@ -31,6 +31,8 @@ var (
 	log                  = logging.DefaultLogger.WithField(logfields.LogSubsys, "labels-filter")
 	validLabelPrefixesMU lock.RWMutex
 	validLabelPrefixes   *labelPrefixCfg // Label prefixes used to filter from all labels
+	baseLabelPrefixes    *labelPrefixCfg // Label prefixes read from file or defaults
+	customLabelPrefixes  []string        // Label prefixes configured by the user
 )
 
 const (
@@ -93,6 +95,10 @@ func parseLabelPrefix(label string) (*LabelPrefix, error) {
 		labelPrefix.Prefix = label
 	}
 
+	if labelPrefix.Prefix == "" {
+		return nil, fmt.Errorf("invalid label prefix %q: prefix was empty", label)
+	}
+
 	if labelPrefix.Prefix[0] == '!' {
 		labelPrefix.Ignore = true
 		labelPrefix.Prefix = labelPrefix.Prefix[1:]
@@ -111,28 +117,24 @@ func parseLabelPrefix(label string) (*LabelPrefix, error) {
 // of valid prefixes. Both are optional. If both are provided, both list are
 // appended together.
 func ParseLabelPrefixCfg(prefixes []string, file string) error {
-	cfg, err := readLabelPrefixCfgFrom(file)
+	base, err := readLabelPrefixCfgFrom(file)
 	if err != nil {
 		return fmt.Errorf("Unable to read label prefix file: %s", err)
 	}
 
-	for _, label := range prefixes {
-		p, err := parseLabelPrefix(label)
-		if err != nil {
-			return err
-		}
-
-		if !p.Ignore {
-			cfg.whitelist = true
-		}
-
-		cfg.LabelPrefixes = append(cfg.LabelPrefixes, p)
+	cfg, err := base.withPrefixes(prefixes)
+	if err != nil {
+		return err
 	}
 
+	validLabelPrefixesMU.Lock()
+	baseLabelPrefixes = base
 	validLabelPrefixes = cfg
+	customLabelPrefixes = prefixes
+	validLabelPrefixesMU.Unlock()
 
 	log.Info("Valid label prefix configuration:")
-	for _, l := range validLabelPrefixes.LabelPrefixes {
+	for _, l := range cfg.LabelPrefixes {
 		log.Infof(" - %s", l)
 	}
 
@@ -150,6 +152,31 @@ type labelPrefixCfg struct {
 	whitelist bool
 }
 
+// withPrefixes returns a copy of cfg with the given prefixes appended to it.
+func (cfg *labelPrefixCfg) withPrefixes(prefixes []string) (*labelPrefixCfg, error) {
+	n := &labelPrefixCfg{
+		Version:       cfg.Version,
+		LabelPrefixes: make([]*LabelPrefix, 0, len(cfg.LabelPrefixes)+len(prefixes)),
+		whitelist:     cfg.whitelist,
+	}
+	n.LabelPrefixes = append(n.LabelPrefixes, cfg.LabelPrefixes...)
+
+	for _, label := range prefixes {
+		p, err := parseLabelPrefix(label)
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.Ignore {
+			n.whitelist = true
+		}
+
+		n.LabelPrefixes = append(n.LabelPrefixes, p)
+	}
+
+	return n, nil
+}
+
 // defaultLabelPrefixCfg returns a default LabelPrefixCfg using the latest
 // LPCfgFileVersion
 func defaultLabelPrefixCfg() *labelPrefixCfg {
@@ -225,9 +252,6 @@ func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informatio
 		return nil, nil
 	}
 
-	validLabelPrefixesMU.RLock()
-	defer validLabelPrefixesMU.RUnlock()
-
 	identityLabels = Labels{}
 	informationLabels = Labels{}
 	for k, v := range lbls {
@@ -274,5 +298,77 @@ func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informatio
 // same prefix as one of lpc valid prefixes, as well as labels that do not match
 // the aforementioned filtering criteria.
 func FilterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
-	return validLabelPrefixes.filterLabels(lbls)
+	return GetLabelFilter().FilterLabels(lbls)
+}
+
+// LabelFilter is an immutable label prefix configuration which can be
+// evaluated before it is installed with SetLabelFilter.
+type LabelFilter struct {
+	cfg    *labelPrefixCfg
+	custom []string
+}
+
+// FilterLabels splits lbls into identity relevant and information labels
+// according to the filter.
+func (f *LabelFilter) FilterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
+	return f.cfg.filterLabels(lbls)
+}
+
+// CustomPrefixes returns the user configured label prefixes of the filter in
+// the format accepted by NewLabelFilter.
+func (f *LabelFilter) CustomPrefixes() []string {
+	return append([]string{}, f.custom...)
+}
+
+// Prefixes returns all label prefixes evaluated by the filter, including the
+// ones read from the label prefix file or the defaults.
+func (f *LabelFilter) Prefixes() []string {
+	prefixes := make([]string, 0, len(f.cfg.LabelPrefixes))
+	for _, p := range f.cfg.LabelPrefixes {
+		prefixes = append(prefixes, p.String())
+	}
+	return prefixes
+}
+
+// GetLabelFilter returns the label filter currently in use.
+func GetLabelFilter() *LabelFilter {
+	validLabelPrefixesMU.RLock()
+	defer validLabelPrefixesMU.RUnlock()
+
+	return &LabelFilter{cfg: validLabelPrefixes, custom: customLabelPrefixes}
+}
+
+// NewLabelFilter returns a label filter which replaces the user configured
+// label prefixes with prefixes. The prefixes read from the label prefix file
+// or the defaults are retained. The returned filter is not used until it is
+// passed to SetLabelFilter.
+func NewLabelFilter(prefixes []string) (*LabelFilter, error) {
+	validLabelPrefixesMU.RLock()
+	base := baseLabelPrefixes
+	validLabelPrefixesMU.RUnlock()
+
+	if base == nil {
+		base = defaultLabelPrefixCfg()
+	}
+
+	cfg, err := base.withPrefixes(prefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelFilter{cfg: cfg, custom: append([]string{}, prefixes...)}, nil
+}
+
+// SetLabelFilter replaces the label filter used by FilterLabels. Labels
+// which have already been filtered are not affected.
+func SetLabelFilter(f *LabelFilter) {
+	validLabelPrefixesMU.Lock()
+	validLabelPrefixes = f.cfg
+	customLabelPrefixes = f.custom
+	validLabelPrefixesMU.Unlock()
+
+	log.Info("Valid label prefix configuration:")
+	for _, l := range f.cfg.LabelPrefixes {
+		log.Infof(" - %s", l)
+	}
 }
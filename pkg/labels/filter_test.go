@@ -71,3 +71,40 @@ func (s *LabelsPrefCfgSuite) TestFilterLabels(c *C) {
 	allLabels["id.lizards"].Source = "I can change this and doesn't affect any one"
 	c.Assert(filtered, checker.DeepEquals, wanted)
 }
+
+func (s *LabelsPrefCfgSuite) TestLabelFilter(c *C) {
+	c.Assert(ParseLabelPrefixCfg([]string{"k8s:app"}, ""), IsNil)
+
+	lbls := Map2Labels(map[string]string{
+		"app":  "web",
+		"tier": "frontend",
+	}, LabelSourceK8s)
+
+	idLbls, infoLbls := FilterLabels(lbls)
+	c.Assert(idLbls, HasLen, 1)
+	c.Assert(idLbls["app"], Not(IsNil))
+	c.Assert(infoLbls, HasLen, 1)
+	c.Assert(GetLabelFilter().CustomPrefixes(), checker.DeepEquals, []string{"k8s:app"})
+
+	f, err := NewLabelFilter([]string{"k8s:app", "k8s:tier"})
+	c.Assert(err, IsNil)
+	c.Assert(f.Prefixes(), HasLen, len(defaultLabelPrefixCfg().LabelPrefixes)+2)
+
+	// The new filter is not used until it has been set
+	idLbls, _ = FilterLabels(lbls)
+	c.Assert(idLbls, HasLen, 1)
+	idLbls, _ = f.FilterLabels(lbls)
+	c.Assert(idLbls, HasLen, 2)
+
+	SetLabelFilter(f)
+	idLbls, _ = FilterLabels(lbls)
+	c.Assert(idLbls, HasLen, 2)
+	c.Assert(GetLabelFilter().CustomPrefixes(), checker.DeepEquals, []string{"k8s:app", "k8s:tier"})
+
+	_, err = NewLabelFilter([]string{"k8s:"})
+	c.Assert(err, Not(IsNil))
+	_, err = NewLabelFilter([]string{"k8s:[a-"})
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(ParseLabelPrefixCfg(nil, ""), IsNil)
+}
@@ -34,6 +34,14 @@ const (
 	// IDNameHost is the label used for the hostname ID.
 	IDNameHost = "host"
 
+	// IDNameRemoteNode is the label used to describe the
+	// ReservedIdentityRemoteNode
+	IDNameRemoteNode = "remote-node"
+
+	// IDNameKubeAPIServer is the label used to describe the
+	// ReservedIdentityKubeAPIServer
+	IDNameKubeAPIServer = "kube-apiserver"
+
 	// IDNameWorld is the label used for the world ID.
 	IDNameWorld = "world"
 
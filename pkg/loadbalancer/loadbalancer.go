@@ -18,6 +18,7 @@ import (
 	"crypto/sha512"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/cilium/cilium/api/v1/models"
@@ -119,6 +120,10 @@ type LoadBalancer struct {
 	K8sServices  map[K8sServiceNamespace]*K8sServiceInfo
 	K8sEndpoints map[K8sServiceNamespace]*K8sServiceEndpoint
 	K8sIngress   map[K8sServiceNamespace]*K8sServiceInfo
+
+	// K8sExternalEndpoints contains the endpoints of global services
+	// learned from remote clusters, indexed by the name of the cluster
+	K8sExternalEndpoints map[K8sServiceNamespace]map[string]*K8sServiceEndpoint
 }
 
 // AddService adds a service to list of loadbalancers and returns true if created.
@@ -168,12 +173,13 @@ func NewL4Type(name string) (L4Type, error) {
 // NewLoadBalancer returns a LoadBalancer with all maps initialized.
 func NewLoadBalancer() *LoadBalancer {
 	return &LoadBalancer{
-		SVCMap:       SVCMap{},
-		SVCMapID:     SVCMapID{},
-		RevNATMap:    RevNATMap{},
-		K8sServices:  map[K8sServiceNamespace]*K8sServiceInfo{},
-		K8sEndpoints: map[K8sServiceNamespace]*K8sServiceEndpoint{},
-		K8sIngress:   map[K8sServiceNamespace]*K8sServiceInfo{},
+		SVCMap:               SVCMap{},
+		SVCMapID:             SVCMapID{},
+		RevNATMap:            RevNATMap{},
+		K8sServices:          map[K8sServiceNamespace]*K8sServiceInfo{},
+		K8sEndpoints:         map[K8sServiceNamespace]*K8sServiceEndpoint{},
+		K8sIngress:           map[K8sServiceNamespace]*K8sServiceInfo{},
+		K8sExternalEndpoints: map[K8sServiceNamespace]map[string]*K8sServiceEndpoint{},
 	}
 }
 
@@ -191,6 +197,10 @@ type K8sServiceInfo struct {
 	Ports      map[FEPortName]*FEPort
 	Labels     map[string]string
 	Selector   map[string]string
+
+	// IsGlobal is true if the backends of the service are shared with
+	// and merged from other clusters in the ClusterMesh
+	IsGlobal bool
 }
 
 // IsExternal returns true if the service is expected to serve out-of-cluster endpoints:
@@ -210,6 +220,7 @@ func (si *K8sServiceInfo) Equals(o *K8sServiceInfo) bool {
 		return true
 	}
 	if si.IsHeadless == o.IsHeadless &&
+		si.IsGlobal == o.IsGlobal &&
 		si.FEIP.Equal(o.FEIP) &&
 		comparator.MapStringEquals(si.Labels, o.Labels) &&
 		comparator.MapStringEquals(si.Selector, o.Selector) {
@@ -284,6 +295,56 @@ func (e *K8sServiceEndpoint) DeepEqual(o *K8sServiceEndpoint) bool {
 	return true
 }
 
+// Backends returns the backends of the endpoint for the port with the given
+// name. Backends with an IP family different from the frontend IP feIP are
+// ignored.
+func (e *K8sServiceEndpoint) Backends(portName FEPortName, feIP net.IP) []LBBackEnd {
+	port, ok := e.Ports[portName]
+	if !ok || port == nil {
+		return nil
+	}
+
+	isFEIPv4 := feIP.To4() != nil
+	backends := []LBBackEnd{}
+	for beIP := range e.BEIPs {
+		ip := net.ParseIP(beIP)
+		if ip == nil || (ip.To4() != nil) != isFEIPv4 {
+			continue
+		}
+		backends = append(backends, LBBackEnd{
+			L3n4Addr: L3n4Addr{IP: ip, L4Addr: *port},
+			Weight:   0,
+		})
+	}
+
+	return backends
+}
+
+// GlobalBackends returns the backends for the port with the given name of a
+// global service. Local backends are always preferred, the backends of the
+// external endpoints of all remote clusters are only returned if no local
+// backend is available.
+func GlobalBackends(local *K8sServiceEndpoint, external map[string]*K8sServiceEndpoint, portName FEPortName, feIP net.IP) []LBBackEnd {
+	if local != nil {
+		if backends := local.Backends(portName, feIP); len(backends) > 0 {
+			return backends
+		}
+	}
+
+	clusters := make([]string, 0, len(external))
+	for cluster := range external {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	backends := []LBBackEnd{}
+	for _, cluster := range clusters {
+		backends = append(backends, external[cluster].Backends(portName, feIP)...)
+	}
+
+	return backends
+}
+
 // CIDRPrefixes returns the endpoint's backends as a slice of IPNets.
 func (e *K8sServiceEndpoint) CIDRPrefixes() ([]*net.IPNet, error) {
 	prefixes := make([]string, 0, len(e.BEIPs))
@@ -41,6 +41,50 @@ func (s *TypesSuite) TestIsK8ServiceExternal(c *check.C) {
 	c.Assert(si.IsExternal(), check.Equals, false)
 }
 
+func (s *TypesSuite) TestGlobalBackends(c *check.C) {
+	feIP := net.ParseIP("10.0.0.1")
+	port := NewL4Addr(TCP, 8080)
+
+	local := NewK8sServiceEndpoint()
+	local.Ports["http"] = port
+
+	remote1 := NewK8sServiceEndpoint()
+	remote1.BEIPs["10.1.0.1"] = true
+	remote1.BEIPs["f00d::1"] = true
+	remote1.Ports["http"] = NewL4Addr(TCP, 80)
+
+	remote2 := NewK8sServiceEndpoint()
+	remote2.BEIPs["10.2.0.1"] = true
+	remote2.Ports["http"] = port
+
+	external := map[string]*K8sServiceEndpoint{
+		"cluster2": remote2,
+		"cluster1": remote1,
+	}
+
+	// fail over to remote backends if no local backend is available
+	backends := GlobalBackends(local, external, "http", feIP)
+	c.Assert(backends, check.HasLen, 2)
+	c.Assert(backends[0].IP.String(), check.Equals, "10.1.0.1")
+	c.Assert(backends[0].Port, check.Equals, uint16(80))
+	c.Assert(backends[1].IP.String(), check.Equals, "10.2.0.1")
+	c.Assert(backends[1].Port, check.Equals, uint16(8080))
+
+	c.Assert(GlobalBackends(nil, external, "http", feIP), check.HasLen, 2)
+	c.Assert(GlobalBackends(local, external, "https", feIP), check.HasLen, 0)
+
+	// local backends are preferred
+	local.BEIPs["10.0.1.1"] = true
+	backends = GlobalBackends(local, external, "http", feIP)
+	c.Assert(backends, check.HasLen, 1)
+	c.Assert(backends[0].IP.String(), check.Equals, "10.0.1.1")
+
+	// backends of another address family are ignored
+	backends = GlobalBackends(nil, external, "http", net.ParseIP("f00d::10"))
+	c.Assert(backends, check.HasLen, 1)
+	c.Assert(backends[0].IP.String(), check.Equals, "f00d::1")
+}
+
 func TestL4Addr_Equals(t *testing.T) {
 	type args struct {
 		o *L4Addr
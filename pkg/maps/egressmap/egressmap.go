@@ -0,0 +1,137 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressmap
+
+import (
+	"fmt"
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "map-egress")
+
+const (
+	// MapName is the canonical name of the egress NAT map on the
+	// filesystem.
+	MapName = "cilium_egress_v4"
+
+	// MaxEntries is the maximum number of source/destination pairs that
+	// can be steered towards an egress gateway.
+	MaxEntries = 16384
+)
+
+var (
+	// EgressMap represents the BPF map steering traffic of selected
+	// endpoints towards an egress NAT gateway.
+	EgressMap = bpf.NewMap(MapName,
+		bpf.BPF_MAP_TYPE_LPM_TRIE,
+		int(unsafe.Sizeof(Key{})),
+		int(unsafe.Sizeof(Value{})),
+		MaxEntries,
+		bpf.BPF_F_NO_PREALLOC, 0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			k, v := Key{}, Value{}
+
+			if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+				return nil, nil, err
+			}
+
+			return &k, &v, nil
+		}).WithCache()
+)
+
+// Key implements the bpf.MapKey interface.
+//
+// Must be in sync with struct egress_key in <bpf/lib/maps.h>
+type Key struct {
+	Prefixlen uint32
+	SourceIP  types.IPv4
+	DestIP    types.IPv4
+}
+
+// GetKeyPtr returns the unsafe pointer to the BPF key
+func (k *Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// NewValue returns a new empty instance of the structure representing the BPF
+// map value
+func (k Key) NewValue() bpf.MapValue { return &Value{} }
+
+// staticPrefixBits is the number of bits of the key which must always match,
+// i.e. the full source address.
+const staticPrefixBits = uint32(unsafe.Sizeof(types.IPv4{})) * 8
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s %s/%d", k.SourceIP, k.DestIP, k.Prefixlen-staticPrefixBits)
+}
+
+// NewKey returns a Key matching traffic from the IPv4 address source towards
+// any address within the IPv4 prefix dest.
+func NewKey(source net.IP, dest *net.IPNet) Key {
+	result := Key{}
+
+	ones, _ := dest.Mask.Size()
+	result.Prefixlen = staticPrefixBits + uint32(ones)
+	copy(result.SourceIP[:], source.To4())
+	copy(result.DestIP[:], dest.IP.To4())
+
+	return result
+}
+
+// Value implements the bpf.MapValue interface. It contains the node IP of the
+// gateway the traffic is steered to. The gateway masquerades the traffic.
+//
+// Must be in sync with struct egress_info in <bpf/lib/maps.h>
+type Value struct {
+	GatewayIP types.IPv4
+}
+
+// NewValue returns a Value for the given gateway IPv4 address.
+func NewValue(gatewayIP net.IP) Value {
+	result := Value{}
+
+	copy(result.GatewayIP[:], gatewayIP.To4())
+
+	return result
+}
+
+// GetValuePtr returns the unsafe pointer to the BPF value.
+func (v *Value) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+
+func (v Value) String() string {
+	return v.GatewayIP.String()
+}
+
+// Update adds or replaces the entry for the given key
+func Update(key Key, value Value) error {
+	log.WithFields(logrus.Fields{
+		logfields.BPFMapKey:   key,
+		logfields.BPFMapValue: value,
+	}).Debug("Updating egress map entry")
+
+	return EgressMap.Update(&key, &value)
+}
+
+// Delete removes the entry for the given key
+func Delete(key Key) error {
+	log.WithField(logfields.BPFMapKey, key).Debug("Deleting egress map entry")
+	return EgressMap.Delete(&key)
+}
@@ -49,6 +49,11 @@ const (
 	dirEgress  = 2
 	dirUnknown = 0
 
+	// reasonForwarded and reasonPolicyAudit values should match with
+	// REASON_FORWARDED and REASON_POLICY_AUDIT in bpf/lib/common.h
+	reasonForwarded   = 0
+	reasonPolicyAudit = 1
+
 	// possibleCPUsFileLength matches the buffer size for CPUs.
 	// Reference bpf_num_possible_cpus from
 	// https://git.kernel.org/pub/scm/linux/kernel/git/bpf/bpf.git/tree/tools/testing/selftests/bpf/bpf_util.h
@@ -119,7 +124,13 @@ func (v *Value) RequestBytes() string {
 
 // IsDrop checks if the reason is drop or not.
 func (k *Key) IsDrop() bool {
-	return k.Reason != 0
+	return k.Reason != reasonForwarded && k.Reason != reasonPolicyAudit
+}
+
+// IsPolicyAudit checks if the reason is a packet which was forwarded because
+// of policy audit mode.
+func (k *Key) IsPolicyAudit() bool {
+	return k.Reason == reasonPolicyAudit
 }
 
 // CountFloat converts the request count to float
@@ -142,9 +153,12 @@ func (v *Value) GetValuePtr() unsafe.Pointer {
 func updatePrometheusMetrics(key *Key, val *Value) {
 	var counter prometheus.Counter
 	var err error
-	if key.IsDrop() {
+	switch {
+	case key.IsDrop():
 		counter, err = metrics.DropCount.GetMetricWithLabelValues(key.DropForwardReason(), key.Direction())
-	} else {
+	case key.IsPolicyAudit():
+		counter, err = metrics.PolicyAuditCount.GetMetricWithLabelValues(key.Direction())
+	default:
 		counter, err = metrics.ForwardCount.GetMetricWithLabelValues(key.Direction())
 	}
 	if err != nil {
@@ -156,9 +170,12 @@ func updatePrometheusMetrics(key *Key, val *Value) {
 	// Check if metrics have changed since the last poll.
 	// If yes, we need to add only the delta.
 	if newValue > oldValue {
-		if key.IsDrop() {
+		switch {
+		case key.IsDrop():
 			metrics.DropCount.WithLabelValues(key.DropForwardReason(), key.Direction()).Add((newValue - oldValue))
-		} else {
+		case key.IsPolicyAudit():
+			metrics.PolicyAuditCount.WithLabelValues(key.Direction()).Add((newValue - oldValue))
+		default:
 			metrics.ForwardCount.WithLabelValues(key.Direction()).Add((newValue - oldValue))
 		}
 	}
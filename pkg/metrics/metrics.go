@@ -67,6 +67,10 @@ var (
 	// LabelValueOutcomeFail is used as an unsuccessful outcome of an operation
 	LabelValueOutcomeFail = "fail"
 
+	// LabelValueOutcomeDropped is used as the outcome of an operation which
+	// was not attempted, e.g. because a queue was full
+	LabelValueOutcomeDropped = "dropped"
+
 	// LabelEventSourceAPI marks event-related metrics that come from the API
 	LabelEventSourceAPI = "api"
 
@@ -117,6 +121,9 @@ var (
 	// started by cilium (Envoy, monitor, etc..)
 	LabelSubsystem = "subsystem"
 
+	// LabelAccessLogSink is the label used to refer to an access log sink
+	LabelAccessLogSink = "sink"
+
 	// Endpoint
 
 	// EndpointCount is a function used to collect this metric.
@@ -294,6 +301,22 @@ var (
 		Help:      "Number of total L7 received requests/responses",
 	})
 
+	// AccessLogSinkRecords is the number of access log records handled by
+	// the access log sinks, labeled by sink and outcome
+	AccessLogSinkRecords = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "proxy_accesslog_sink_records_total",
+		Help:      "Number of access log records exported to sinks, labeled by sink and outcome (success, fail, dropped)",
+	}, []string{LabelAccessLogSink, "outcome"})
+
+	// AccessLogSinkQueueLength is the number of access log records buffered
+	// for export, labeled by sink
+	AccessLogSinkQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "proxy_accesslog_sink_queue_length",
+		Help:      "Number of access log records buffered for export, labeled by sink",
+	}, []string{LabelAccessLogSink})
+
 	// L3-L4 statistics
 
 	// DropCount is the total drop requests,
@@ -314,6 +337,16 @@ var (
 	},
 		[]string{"direction"})
 
+	// PolicyAuditCount is the total number of packets which would have
+	// been dropped by policy but were allowed because of policy audit mode,
+	// tagged by ingress/egress direction
+	PolicyAuditCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "policy_audit_total",
+		Help:      "Total packets allowed by policy audit mode which would have been dropped, tagged by ingress/egress direction",
+	},
+		[]string{"direction"})
+
 	// Datapath statistics
 
 	// DatapathErrors is the number of errors managing datapath components
@@ -458,9 +491,12 @@ func init() {
 	MustRegister(ProxyForwarded)
 	MustRegister(ProxyDenied)
 	MustRegister(ProxyReceived)
+	MustRegister(AccessLogSinkRecords)
+	MustRegister(AccessLogSinkQueueLength)
 
 	MustRegister(DropCount)
 	MustRegister(ForwardCount)
+	MustRegister(PolicyAuditCount)
 
 	MustRegister(newStatusCollector())
 
@@ -17,6 +17,8 @@ package metrics
 import (
 	"time"
 
+	healthModels "github.com/cilium/cilium/api/v1/health/models"
+	"github.com/cilium/cilium/api/v1/models"
 	clientPkg "github.com/cilium/cilium/pkg/client"
 	healthClientPkg "github.com/cilium/cilium/pkg/health/client"
 
@@ -36,6 +38,14 @@ type statusCollector struct {
 	ipAddressesDesc                *prometheus.Desc
 	unreachableNodesDesc           *prometheus.Desc
 	unreachableHealthEndpointsDesc *prometheus.Desc
+	connectivityLossDesc           *prometheus.Desc
+	connectivityLatencyDesc        *prometheus.Desc
+	connectivityFlapsDesc          *prometheus.Desc
+	remoteClusterReadyDesc         *prometheus.Desc
+	remoteClusterFailuresDesc      *prometheus.Desc
+	remoteClusterLastFailureDesc   *prometheus.Desc
+	remoteClusterEntriesDesc       *prometheus.Desc
+	globalServicesDesc             *prometheus.Desc
 }
 
 func newStatusCollector() *statusCollector {
@@ -72,6 +82,46 @@ func newStatusCollector() *statusCollector {
 			"Number of health endpoints that cannot be reached",
 			nil, nil,
 		),
+		connectivityLossDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "node_connectivity_loss_percent"),
+			"Percentage of connectivity probes lost over the recent probe history",
+			[]string{"target_node", "target_type", "protocol"}, nil,
+		),
+		connectivityLatencyDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "node_connectivity_latency_seconds"),
+			"Round trip time of connectivity probes over the recent probe history",
+			[]string{"target_node", "target_type", "protocol", "stat"}, nil,
+		),
+		connectivityFlapsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "node_connectivity_flaps"),
+			"Number of reachability transitions over the recent probe history",
+			[]string{"target_node", "target_type", "protocol"}, nil,
+		),
+		remoteClusterReadyDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "clustermesh", "remote_cluster_ready"),
+			"Readiness of the connection to a remote cluster (1 = ready)",
+			[]string{"target_cluster"}, nil,
+		),
+		remoteClusterFailuresDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "clustermesh", "remote_cluster_failures"),
+			"Number of failures to connect to, watch or synchronize with a remote cluster",
+			[]string{"target_cluster"}, nil,
+		),
+		remoteClusterLastFailureDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "clustermesh", "remote_cluster_last_failure_ts"),
+			"Timestamp of the last failure to connect to, watch or synchronize with a remote cluster",
+			[]string{"target_cluster"}, nil,
+		),
+		remoteClusterEntriesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "clustermesh", "remote_cluster_entries"),
+			"Number of entries synchronized from a remote cluster",
+			[]string{"target_cluster", "type"}, nil,
+		),
+		globalServicesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "clustermesh", "global_services"),
+			"Number of global services learned from remote clusters",
+			nil, nil,
+		),
 	}
 }
 
@@ -80,6 +130,14 @@ func (s *statusCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- s.ipAddressesDesc
 	ch <- s.unreachableNodesDesc
 	ch <- s.unreachableHealthEndpointsDesc
+	ch <- s.connectivityLossDesc
+	ch <- s.connectivityLatencyDesc
+	ch <- s.connectivityFlapsDesc
+	ch <- s.remoteClusterReadyDesc
+	ch <- s.remoteClusterFailuresDesc
+	ch <- s.remoteClusterLastFailureDesc
+	ch <- s.remoteClusterEntriesDesc
+	ch <- s.globalServicesDesc
 }
 
 func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
@@ -128,6 +186,10 @@ func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
 		)
 	}
 
+	if statusResponse.Payload.ClusterMesh != nil {
+		s.collectClusterMesh(ch, statusResponse.Payload.ClusterMesh)
+	}
+
 	healthStatusResponse, err := s.healthClient.Connectivity.GetStatus(nil)
 	if err != nil {
 		log.WithError(err).Error("Error while getting cilium-health status")
@@ -151,6 +213,9 @@ func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
 		if nodeStatus.Endpoint != nil && !healthClientPkg.PathIsHealthy(nodeStatus.Endpoint) {
 			unreachableEndpoints++
 		}
+
+		s.collectPathHistory(ch, nodeStatus.Name, "host", healthClientPkg.GetHostPrimaryAddress(nodeStatus))
+		s.collectPathHistory(ch, nodeStatus.Name, "endpoint", nodeStatus.Endpoint)
 	}
 
 	ch <- prometheus.MustNewConstMetric(
@@ -165,3 +230,107 @@ func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
 		float64(unreachableEndpoints),
 	)
 }
+
+// collectClusterMesh exposes the status of the connection to every remote
+// cluster.
+func (s *statusCollector) collectClusterMesh(ch chan<- prometheus.Metric, cm *models.ClusterMeshStatus) {
+	ch <- prometheus.MustNewConstMetric(
+		s.globalServicesDesc,
+		prometheus.GaugeValue,
+		float64(cm.NumGlobalServices),
+	)
+
+	for _, cluster := range cm.Clusters {
+		ready := 0.0
+		if cluster.Ready {
+			ready = 1.0
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			s.remoteClusterReadyDesc,
+			prometheus.GaugeValue,
+			ready,
+			cluster.Name,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			s.remoteClusterFailuresDesc,
+			prometheus.CounterValue,
+			float64(cluster.NumFailures),
+			cluster.Name,
+		)
+
+		if lastFailure := time.Time(cluster.LastFailure); !lastFailure.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				s.remoteClusterLastFailureDesc,
+				prometheus.GaugeValue,
+				float64(lastFailure.Unix()),
+				cluster.Name,
+			)
+		}
+
+		entries := map[string]int64{
+			"nodes":      cluster.NumNodes,
+			"identities": cluster.NumIdentities,
+			"ipcache":    cluster.NumIpcacheEntries,
+			"services":   cluster.NumSharedServices,
+		}
+		for typ, n := range entries {
+			ch <- prometheus.MustNewConstMetric(
+				s.remoteClusterEntriesDesc,
+				prometheus.GaugeValue,
+				float64(n),
+				cluster.Name, typ,
+			)
+		}
+	}
+}
+
+// collectPathHistory exposes the connectivity history of every probe of the
+// path towards 'node'.
+func (s *statusCollector) collectPathHistory(ch chan<- prometheus.Metric, node, targetType string, path *healthModels.PathStatus) {
+	if path == nil {
+		return
+	}
+
+	probes := map[string]*healthModels.ConnectivityStatus{
+		"icmp": path.Icmp,
+		"http": path.HTTP,
+		"tcp":  path.TCP,
+	}
+	for protocol, status := range probes {
+		if status == nil || status.History == nil {
+			continue
+		}
+		h := status.History
+
+		ch <- prometheus.MustNewConstMetric(
+			s.connectivityLossDesc,
+			prometheus.GaugeValue,
+			h.Loss,
+			node, targetType, protocol,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			s.connectivityFlapsDesc,
+			prometheus.GaugeValue,
+			float64(h.Flaps),
+			node, targetType, protocol,
+		)
+
+		latencies := map[string]int64{
+			"min": h.LatencyMin,
+			"avg": h.LatencyAvg,
+			"max": h.LatencyMax,
+			"p99": h.LatencyP99,
+		}
+		for stat, latency := range latencies {
+			ch <- prometheus.MustNewConstMetric(
+				s.connectivityLatencyDesc,
+				prometheus.GaugeValue,
+				time.Duration(latency).Seconds(),
+				node, targetType, protocol, stat,
+			)
+		}
+	}
+}
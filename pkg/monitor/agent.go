@@ -69,6 +69,17 @@ func (n *AgentNotify) getJSON() string {
 	return fmt.Sprintf(`{"type":"agent","subtype":"%s","message":%s}`, resolveAgentType(n.Type), n.Text)
 }
 
+func (n *AgentNotify) getVerbose() AgentNotifyVerbose {
+	msg := json.RawMessage(n.Text)
+	if !json.Valid(msg) {
+		msg, _ = json.Marshal(n.Text)
+	}
+	return AgentNotifyVerbose{
+		Subtype: resolveAgentType(n.Type),
+		Message: msg,
+	}
+}
+
 // DumpJSON prints notification in json format
 func (n *AgentNotify) DumpJSON() {
 	fmt.Println(n.getJSON())
@@ -442,13 +442,15 @@ func (n *DebugCapture) subTypeString() string {
 	}
 }
 
-func (n *DebugCapture) getJSON(data []byte, cpuPrefix string) (string, error) {
-
+func (n *DebugCapture) getVerbose(data []byte, cpuPrefix string) DebugCaptureVerbose {
 	v := DebugCaptureToVerbose(n)
 	v.CPUPrefix = cpuPrefix
 	v.Summary = GetConnectionSummary(data[DebugCaptureLen:])
+	return v
+}
 
-	ret, err := json.Marshal(v)
+func (n *DebugCapture) getJSON(data []byte, cpuPrefix string) (string, error) {
+	ret, err := json.Marshal(n.getVerbose(data, cpuPrefix))
 	return string(ret), err
 }
 
@@ -114,15 +114,17 @@ func (n *DropNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
 	}
 }
 
-func (n *DropNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
-
+func (n *DropNotify) getVerbose(data []byte, cpuPrefix string) DropNotifyVerbose {
 	v := DropNotifyToVerbose(n)
 	v.CPUPrefix = cpuPrefix
 	if n.CapLen > 0 && len(data) > DropNotifyLen {
 		v.Summary = GetDissectSummary(data[DropNotifyLen:])
 	}
+	return v
+}
 
-	ret, err := json.Marshal(v)
+func (n *DropNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
+	ret, err := json.Marshal(n.getVerbose(data, cpuPrefix))
 	return string(ret), err
 }
 
@@ -0,0 +1,181 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+const (
+	// PolicyVerdictNotifyLen is the amount of packet data provided in a
+	// policy verdict notification
+	PolicyVerdictNotifyLen = 32
+)
+
+// Policy verdict flags, must be synchronized with <bpf/lib/policy_log.h>
+const (
+	// PolicyVerdictFlagDirMask masks the direction of the policy decision
+	PolicyVerdictFlagDirMask = 0x3
+	// PolicyVerdictFlagAudited is set if the packet would have been
+	// dropped but was allowed because of policy audit mode
+	PolicyVerdictFlagAudited = 0x4
+)
+
+// Directions of a policy decision, must be synchronized with METRIC_INGRESS
+// and METRIC_EGRESS in <bpf/lib/common.h>
+const (
+	PolicyIngress = 1
+	PolicyEgress  = 2
+)
+
+// PolicyVerdictNotify is the message format of a policy verdict notification
+// in the BPF ring buffer
+type PolicyVerdictNotify struct {
+	Type     uint8
+	SubType  uint8
+	Source   uint16
+	Hash     uint32
+	OrigLen  uint32
+	CapLen   uint32
+	SrcLabel uint32
+	DstLabel uint32
+	Verdict  int32
+	DstPort  uint16
+	Proto    uint8
+	Flags    uint8
+	// data
+}
+
+// IsAudited returns true if the policy verdict was overridden by audit mode
+func (n *PolicyVerdictNotify) IsAudited() bool {
+	return n.Flags&PolicyVerdictFlagAudited != 0
+}
+
+// Direction returns the direction of the policy decision as string
+func (n *PolicyVerdictNotify) Direction() string {
+	switch n.Flags & PolicyVerdictFlagDirMask {
+	case PolicyIngress:
+		return "ingress"
+	case PolicyEgress:
+		return "egress"
+	}
+	return "unknown"
+}
+
+// Port returns the destination port in host byte order
+func (n *PolicyVerdictNotify) Port() uint16 {
+	return byteorder.NetworkToHost(n.DstPort).(uint16)
+}
+
+// Action returns a human readable string of the policy verdict
+func (n *PolicyVerdictNotify) Action() string {
+	switch {
+	case n.Verdict < 0 && n.IsAudited():
+		return fmt.Sprintf("audit (%s)", DropReason(uint8(-n.Verdict)))
+	case n.Verdict < 0:
+		return fmt.Sprintf("deny (%s)", DropReason(uint8(-n.Verdict)))
+	case n.Verdict > 0:
+		return fmt.Sprintf("redirect to proxy port %d", n.Verdict)
+	}
+	return "allow"
+}
+
+// DumpInfo prints a summary of the policy verdict messages.
+func (n *PolicyVerdictNotify) DumpInfo(data []byte) {
+	summary := ""
+	if len(data) > PolicyVerdictNotifyLen {
+		summary = GetConnectionSummary(data[PolicyVerdictNotifyLen:])
+	}
+
+	fmt.Printf("-> policy-verdict %s flow %#x %s %s/%d, identity %d->%d: %s\n",
+		n.Action(), n.Hash, n.Direction(), u8proto.U8proto(n.Proto), n.Port(),
+		n.SrcLabel, n.DstLabel, summary)
+}
+
+// DumpVerbose prints the policy verdict notification in human readable form
+func (n *PolicyVerdictNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
+	fmt.Printf("%s MARK %#x FROM %d POLICY-VERDICT: %d bytes, %s %s/%d, identity %d->%d, action %s\n",
+		prefix, n.Hash, n.Source, n.OrigLen, n.Direction(), u8proto.U8proto(n.Proto),
+		n.Port(), n.SrcLabel, n.DstLabel, n.Action())
+
+	if n.CapLen > 0 && len(data) > PolicyVerdictNotifyLen {
+		Dissect(dissect, data[PolicyVerdictNotifyLen:])
+	}
+}
+
+func (n *PolicyVerdictNotify) getVerbose(data []byte, cpuPrefix string) PolicyVerdictNotifyVerbose {
+	v := PolicyVerdictNotifyToVerbose(n)
+	v.CPUPrefix = cpuPrefix
+	if n.CapLen > 0 && len(data) > PolicyVerdictNotifyLen {
+		v.Summary = GetDissectSummary(data[PolicyVerdictNotifyLen:])
+	}
+	return v
+}
+
+func (n *PolicyVerdictNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
+	ret, err := json.Marshal(n.getVerbose(data, cpuPrefix))
+	return string(ret), err
+}
+
+// DumpJSON prints notification in json format
+func (n *PolicyVerdictNotify) DumpJSON(data []byte, cpuPrefix string) {
+	resp, err := n.getJSON(data, cpuPrefix)
+	if err == nil {
+		fmt.Println(resp)
+	}
+}
+
+// PolicyVerdictNotifyVerbose represents a json notification printed by monitor
+type PolicyVerdictNotifyVerbose struct {
+	CPUPrefix string `json:"cpu,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Mark      string `json:"mark,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+
+	Source   uint16 `json:"source"`
+	Bytes    uint32 `json:"bytes"`
+	SrcLabel uint32 `json:"srcLabel"`
+	DstLabel uint32 `json:"dstLabel"`
+	DstPort  uint16 `json:"dstPort"`
+	Verdict  int32  `json:"verdict"`
+	Audited  bool   `json:"audited"`
+
+	Summary *DissectSummary `json:"summary,omitempty"`
+}
+
+// PolicyVerdictNotifyToVerbose creates verbose notification from
+// PolicyVerdictNotify
+func PolicyVerdictNotifyToVerbose(n *PolicyVerdictNotify) PolicyVerdictNotifyVerbose {
+	return PolicyVerdictNotifyVerbose{
+		Type:      "policy-verdict",
+		Mark:      fmt.Sprintf("%#x", n.Hash),
+		Direction: n.Direction(),
+		Action:    n.Action(),
+		Protocol:  u8proto.U8proto(n.Proto).String(),
+		Source:    n.Source,
+		Bytes:     n.OrigLen,
+		SrcLabel:  n.SrcLabel,
+		DstLabel:  n.DstLabel,
+		DstPort:   n.Port(),
+		Verdict:   n.Verdict,
+		Audited:   n.IsAudited(),
+	}
+}
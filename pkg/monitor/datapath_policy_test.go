@@ -0,0 +1,70 @@
+// Copyright 2016-2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MonitorSuite) TestPolicyVerdictNotify(c *C) {
+	c.Assert(binary.Size(PolicyVerdictNotify{}), Equals, PolicyVerdictNotifyLen)
+
+	in := PolicyVerdictNotify{
+		Type:     MessageTypePolicyVerdict,
+		Source:   10,
+		SrcLabel: 100,
+		DstLabel: 200,
+		Verdict:  -133,
+		DstPort:  byteorder.HostToNetwork(uint16(80)).(uint16),
+		Proto:    6,
+		Flags:    PolicyIngress | PolicyVerdictFlagAudited,
+	}
+	buf := new(bytes.Buffer)
+	c.Assert(binary.Write(buf, byteorder.Native, in), IsNil)
+
+	n := PolicyVerdictNotify{}
+	c.Assert(binary.Read(bytes.NewReader(buf.Bytes()), byteorder.Native, &n), IsNil)
+	c.Assert(n, Equals, in)
+	c.Assert(n.IsAudited(), Equals, true)
+	c.Assert(n.Direction(), Equals, "ingress")
+	c.Assert(n.Port(), Equals, uint16(80))
+	c.Assert(n.Action(), Equals, "audit (Policy denied (L3))")
+
+	v := PolicyVerdictNotifyToVerbose(&n)
+	c.Assert(v.Type, Equals, "policy-verdict")
+	c.Assert(v.Protocol, Equals, "TCP")
+	c.Assert(v.Audited, Equals, true)
+
+	// notifications without captured packet must not be sliced beyond
+	// their length
+	n.DumpInfo(buf.Bytes())
+	n.DumpInfo(buf.Bytes()[:4])
+
+	n.Flags = PolicyEgress
+	n.Verdict = 0
+	c.Assert(n.IsAudited(), Equals, false)
+	c.Assert(n.Direction(), Equals, "egress")
+	c.Assert(n.Action(), Equals, "allow")
+
+	n.Verdict = 10000
+	c.Assert(n.Action(), Equals, "redirect to proxy port 10000")
+}
@@ -155,14 +155,17 @@ func (n *TraceNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
 	}
 }
 
-func (n *TraceNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
+func (n *TraceNotify) getVerbose(data []byte, cpuPrefix string) TraceNotifyVerbose {
 	v := TraceNotifyToVerbose(n)
 	v.CPUPrefix = cpuPrefix
 	if n.CapLen > 0 && len(data) > TraceNotifyLen {
 		v.Summary = GetDissectSummary(data[TraceNotifyLen:])
 	}
+	return v
+}
 
-	ret, err := json.Marshal(v)
+func (n *TraceNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
+	ret, err := json.Marshal(n.getVerbose(data, cpuPrefix))
 	return string(ret), err
 }
 
@@ -228,3 +228,26 @@ func GetDissectSummary(data []byte) *DissectSummary {
 	}
 	return ret
 }
+
+// getPacketEndpoints decodes the data into layers and returns the source and
+// destination IP addresses and L4 ports found in the packet, if any.
+func getPacketEndpoints(data []byte) (ips []net.IP, ports []uint16) {
+	dissectLock.Lock()
+	defer dissectLock.Unlock()
+
+	parser.DecodeLayers(data, &decoded)
+
+	for _, typ := range decoded {
+		switch typ {
+		case layers.LayerTypeIPv4:
+			ips = append(ips, ip4.SrcIP, ip4.DstIP)
+		case layers.LayerTypeIPv6:
+			ips = append(ips, ip6.SrcIP, ip6.DstIP)
+		case layers.LayerTypeTCP:
+			ports = append(ports, uint16(tcp.SrcPort), uint16(tcp.DstPort))
+		case layers.LayerTypeUDP:
+			ports = append(ports, uint16(udp.SrcPort), uint16(udp.DstPort))
+		}
+	}
+	return ips, ports
+}
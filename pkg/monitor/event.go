@@ -0,0 +1,136 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// EventTypeLost is the MonitorEvent type of lost records
+const EventTypeLost = models.MonitorEventTypeLost
+
+// DebugMsgVerbose represents a json debug message
+type DebugMsgVerbose struct {
+	Source  uint16 `json:"source"`
+	Hash    uint32 `json:"hash"`
+	Message string `json:"message"`
+}
+
+// AgentNotifyVerbose represents a json agent notification
+type AgentNotifyVerbose struct {
+	Subtype string `json:"subtype"`
+	// Message is the JSON representation of the notification, or a JSON
+	// string if the notification does not carry JSON
+	Message json.RawMessage `json:"message"`
+}
+
+// NewEvent decodes the payload observed on node into the MonitorEvent
+// streamed to remote listeners. The Event field of the MonitorEvent is set to
+// the verbose representation of the payload, e.g. DropNotifyVerbose for drop
+// notifications.
+func NewEvent(pl *payload.Payload, node string, ts time.Time) (*models.MonitorEvent, error) {
+	ev := &models.MonitorEvent{
+		Time: strfmt.DateTime(ts),
+		Node: node,
+		CPU:  int64(pl.CPU),
+	}
+
+	switch pl.Type {
+	case payload.RecordLost:
+		ev.Type = EventTypeLost
+		ev.Lost = int64(pl.Lost)
+		return ev, nil
+
+	case payload.EventSample:
+	default:
+		return nil, fmt.Errorf("unknown payload type %d", pl.Type)
+	}
+
+	if len(pl.Data) == 0 {
+		return nil, fmt.Errorf("empty event")
+	}
+
+	data := pl.Data
+	ev.Type = type2name(int(data[0]))
+
+	var err error
+	switch int(data[0]) {
+	case MessageTypeDrop:
+		dn := DropNotify{}
+		if err = binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err == nil {
+			ev.Event = dn.getVerbose(data, "")
+		}
+
+	case MessageTypeTrace:
+		tn := TraceNotify{}
+		if err = binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err == nil {
+			ev.Event = tn.getVerbose(data, "")
+		}
+
+	case MessageTypePolicyVerdict:
+		pn := PolicyVerdictNotify{}
+		if err = binary.Read(bytes.NewReader(data), byteorder.Native, &pn); err == nil {
+			ev.Event = pn.getVerbose(data, "")
+		}
+
+	case MessageTypeCapture:
+		dc := DebugCapture{}
+		if err = binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err == nil {
+			ev.Event = dc.getVerbose(data, "")
+		}
+
+	case MessageTypeDebug:
+		dm := DebugMsg{}
+		if err = binary.Read(bytes.NewReader(data), byteorder.Native, &dm); err == nil {
+			ev.Event = DebugMsgVerbose{
+				Source:  dm.Source,
+				Hash:    dm.Hash,
+				Message: dm.subTypeString(),
+			}
+		}
+
+	case MessageTypeAccessLog:
+		lr := LogRecordNotify{}
+		if err = gob.NewDecoder(bytes.NewBuffer(data[1:])).Decode(&lr); err == nil {
+			ev.Event = LogRecordNotifyToVerbose(&lr)
+		}
+
+	case MessageTypeAgent:
+		an := AgentNotify{}
+		if err = gob.NewDecoder(bytes.NewBuffer(data[1:])).Decode(&an); err == nil {
+			ev.Event = an.getVerbose()
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown message type %d", data[0])
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s event: %s", ev.Type, err)
+	}
+
+	return ev, nil
+}
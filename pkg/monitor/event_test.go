@@ -0,0 +1,88 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	"github.com/go-openapi/strfmt"
+	. "gopkg.in/check.v1"
+)
+
+func (s *MonitorSuite) TestNewEvent(c *C) {
+	now := time.Now()
+
+	ev, err := NewEvent(&payload.Payload{Type: payload.RecordLost, CPU: 2, Lost: 10}, "node1", now)
+	c.Assert(err, IsNil)
+	c.Assert(ev, DeepEquals, &models.MonitorEvent{Time: strfmt.DateTime(now), Node: "node1", CPU: 2, Type: EventTypeLost, Lost: 10})
+	c.Assert(ev.Validate(strfmt.Default), IsNil)
+
+	pl := buildDropPayload(c, DropNotify{Source: 10, SrcLabel: 100, DstLabel: 200}, "10.0.0.1", "10.0.0.2", 34567, 80)
+	ev, err = NewEvent(pl, "node1", now)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Type, Equals, "drop")
+	c.Assert(ev.Validate(strfmt.Default), IsNil)
+	dn, ok := ev.Event.(DropNotifyVerbose)
+	c.Assert(ok, Equals, true)
+	c.Assert(dn.Source, Equals, uint16(10))
+	c.Assert(dn.SrcLabel, Equals, uint32(100))
+	c.Assert(dn.Summary, Not(IsNil))
+
+	buf := bytes.NewBuffer([]byte{byte(MessageTypeAgent)})
+	c.Assert(gob.NewEncoder(buf).Encode(AgentNotify{Type: AgentNotifyGeneric, Text: "hello"}), IsNil)
+	ev, err = NewEvent(&payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}, "node1", now)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Type, Equals, "agent")
+	out, err := json.Marshal(ev.Event)
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, `{"subtype":"Message","message":"hello"}`)
+
+	buf = bytes.NewBuffer([]byte{byte(MessageTypeAgent)})
+	c.Assert(gob.NewEncoder(buf).Encode(AgentNotify{Type: AgentNotifyPolicyUpdated, Text: `{"revision":2}`}), IsNil)
+	ev, err = NewEvent(&payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}, "node1", now)
+	c.Assert(err, IsNil)
+	out, err = json.Marshal(ev.Event)
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, `{"subtype":"Policy updated","message":{"revision":2}}`)
+
+	_, err = NewEvent(&payload.Payload{Type: payload.EventSample, Data: []byte{200}}, "node1", now)
+	c.Assert(err, Not(IsNil))
+	_, err = NewEvent(&payload.Payload{Type: payload.EventSample, Data: []byte{MessageTypeDrop}}, "node1", now)
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *MonitorSuite) TestFilterJSON(c *C) {
+	f := Filter{
+		MessageTypes: MessageTypeFilter{MessageTypeDrop, MessageTypeAccessLog},
+		Ports:        []uint16{80},
+	}
+	out, err := json.Marshal(f)
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, `{"types":["drop","l7"],"ports":[80]}`)
+
+	parsed := Filter{}
+	c.Assert(json.Unmarshal(out, &parsed), IsNil)
+	c.Assert(parsed, DeepEquals, f)
+
+	c.Assert(json.Unmarshal([]byte(`{"types":["foo"]}`), &parsed), Not(IsNil))
+}
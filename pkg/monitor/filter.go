@@ -0,0 +1,380 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+)
+
+// Verdicts of monitor events which can be filtered on
+const (
+	// VerdictForwarded matches forwarded packets and requests
+	VerdictForwarded = "forwarded"
+	// VerdictDropped matches dropped packets and denied requests
+	VerdictDropped = "dropped"
+	// VerdictAudited matches packets which were only allowed because of
+	// policy audit mode
+	VerdictAudited = "audited"
+)
+
+// Filter selects monitor events. Each non-empty field restricts the events
+// matched by the filter to the events carrying one of the listed values.
+// Events which don't carry the information a field filters on, e.g. agent
+// notifications and a port filter, don't match.
+//
+// Filters are sent by listeners to the node monitor, which only forwards the
+// matching events.
+type Filter struct {
+	// MessageTypes is the list of event types, see MessageType*
+	MessageTypes MessageTypeFilter `json:"types,omitempty"`
+	// FromEndpoints is the list of source endpoint IDs
+	FromEndpoints []uint16 `json:"fromEndpoints,omitempty"`
+	// ToEndpoints is the list of destination endpoint IDs
+	ToEndpoints []uint16 `json:"toEndpoints,omitempty"`
+	// RelatedEndpoints is the list of source or destination endpoint IDs
+	RelatedEndpoints []uint16 `json:"relatedEndpoints,omitempty"`
+	// Identities is the list of source or destination security identities
+	Identities []uint32 `json:"identities,omitempty"`
+	// CIDRs is the list of prefixes or IPs the source or destination IP
+	// must be part of
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Ports is the list of source or destination L4 ports
+	Ports []uint16 `json:"ports,omitempty"`
+	// Verdicts is the list of verdicts, see Verdict*
+	Verdicts []string `json:"verdicts,omitempty"`
+
+	// cidrs is the parsed representation of CIDRs
+	cidrs []*net.IPNet
+}
+
+// IsEmpty returns true if the filter matches all events
+func (f *Filter) IsEmpty() bool {
+	return len(f.MessageTypes) == 0 && len(f.FromEndpoints) == 0 &&
+		len(f.ToEndpoints) == 0 && len(f.RelatedEndpoints) == 0 &&
+		len(f.Identities) == 0 && len(f.CIDRs) == 0 &&
+		len(f.Ports) == 0 && len(f.Verdicts) == 0
+}
+
+// NewFilterFromModel returns the Filter of the API model m, which may be nil.
+// The returned filter has been validated.
+func NewFilterFromModel(m *models.MonitorEventFilter) (*Filter, error) {
+	f := &Filter{}
+	if m == nil {
+		return f, nil
+	}
+
+	for _, name := range m.Types {
+		if err := f.MessageTypes.Set(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if f.FromEndpoints, err = toUint16s("fromEndpoints", m.FromEndpoints); err != nil {
+		return nil, err
+	}
+	if f.ToEndpoints, err = toUint16s("toEndpoints", m.ToEndpoints); err != nil {
+		return nil, err
+	}
+	if f.RelatedEndpoints, err = toUint16s("relatedEndpoints", m.RelatedEndpoints); err != nil {
+		return nil, err
+	}
+	if f.Ports, err = toUint16s("ports", m.Ports); err != nil {
+		return nil, err
+	}
+	for _, id := range m.Identities {
+		if id < 0 || id > math.MaxUint32 {
+			return nil, fmt.Errorf("invalid identity %d", id)
+		}
+		f.Identities = append(f.Identities, uint32(id))
+	}
+	f.CIDRs = m.Cidrs
+	f.Verdicts = m.Verdicts
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// toUint16s converts the values of the filter field name to uint16
+func toUint16s(name string, values []int64) ([]uint16, error) {
+	var res []uint16
+	for _, v := range values {
+		if v < 0 || v > math.MaxUint16 {
+			return nil, fmt.Errorf("invalid %s value %d", name, v)
+		}
+		res = append(res, uint16(v))
+	}
+	return res, nil
+}
+
+// Validate checks the CIDRs and verdicts of the filter. It must be called
+// before the filter is used.
+func (f *Filter) Validate() error {
+	f.cidrs = make([]*net.IPNet, 0, len(f.CIDRs))
+	for _, s := range f.CIDRs {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return fmt.Errorf("invalid IP address %q", s)
+			}
+			bits := net.IPv6len * 8
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), net.IPv4len*8
+			}
+			f.cidrs = append(f.cidrs, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(s)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR %q: %s", s, err)
+		}
+		f.cidrs = append(f.cidrs, cidr)
+	}
+
+	for _, v := range f.Verdicts {
+		switch v {
+		case VerdictForwarded, VerdictDropped, VerdictAudited:
+		default:
+			return fmt.Errorf("invalid verdict %q, must be one of %s, %s, %s",
+				v, VerdictForwarded, VerdictDropped, VerdictAudited)
+		}
+	}
+
+	return nil
+}
+
+// eventInfo is the information of an event which can be filtered on
+type eventInfo struct {
+	messageType int
+	src, dst    uint16
+	identities  []uint32
+	ips         []net.IP
+	ports       []uint16
+	verdict     string
+	// packet is the captured packet carried by the event, it is only
+	// decoded if the filter requires it
+	packet []byte
+}
+
+// Match returns true if the payload matches the filter. Lost event records
+// always match.
+func (f *Filter) Match(pl *payload.Payload) bool {
+	if pl.Type != payload.EventSample || f.IsEmpty() {
+		return true
+	}
+	if len(pl.Data) == 0 {
+		return false
+	}
+
+	messageType := int(pl.Data[0])
+	if len(f.MessageTypes) > 0 && !f.MessageTypes.Contains(messageType) {
+		return false
+	}
+
+	ev, err := getEventInfo(pl.Data)
+	if err != nil {
+		return false
+	}
+
+	return f.matchEvent(ev)
+}
+
+func (f *Filter) matchEvent(ev *eventInfo) bool {
+	if len(f.FromEndpoints) > 0 && !containsUint16(f.FromEndpoints, ev.src) {
+		return false
+	}
+	if len(f.ToEndpoints) > 0 && !containsUint16(f.ToEndpoints, ev.dst) {
+		return false
+	}
+	if len(f.RelatedEndpoints) > 0 &&
+		!containsUint16(f.RelatedEndpoints, ev.src) &&
+		!containsUint16(f.RelatedEndpoints, ev.dst) {
+		return false
+	}
+	if len(f.Identities) > 0 && !f.matchIdentities(ev.identities) {
+		return false
+	}
+	if len(f.Verdicts) > 0 && !f.matchVerdict(ev.verdict) {
+		return false
+	}
+
+	if (len(f.cidrs) > 0 || len(f.Ports) > 0) && len(ev.packet) > 0 {
+		ips, ports := getPacketEndpoints(ev.packet)
+		ev.ips = append(ev.ips, ips...)
+		ev.ports = append(ev.ports, ports...)
+	}
+	if len(f.cidrs) > 0 && !f.matchIPs(ev.ips) {
+		return false
+	}
+	if len(f.Ports) > 0 && !f.matchPorts(ev.ports) {
+		return false
+	}
+
+	return true
+}
+
+func (f *Filter) matchIdentities(identities []uint32) bool {
+	for _, id := range identities {
+		for _, want := range f.Identities {
+			if id == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchVerdict(verdict string) bool {
+	for _, want := range f.Verdicts {
+		if verdict == want {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchIPs(ips []net.IP) bool {
+	for _, ip := range ips {
+		for _, cidr := range f.cidrs {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchPorts(ports []uint16) bool {
+	for _, port := range ports {
+		if containsUint16(f.Ports, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint16(list []uint16, value uint16) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// getEventInfo decodes the information which can be filtered on from the
+// data of a monitor event.
+func getEventInfo(data []byte) (*eventInfo, error) {
+	ev := &eventInfo{messageType: int(data[0])}
+
+	switch ev.messageType {
+	case MessageTypeDrop:
+		dn := DropNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+			return nil, err
+		}
+		ev.src, ev.dst = dn.Source, uint16(dn.DstID)
+		ev.identities = []uint32{dn.SrcLabel, dn.DstLabel}
+		ev.verdict = VerdictDropped
+		if len(data) > DropNotifyLen {
+			ev.packet = data[DropNotifyLen:]
+		}
+
+	case MessageTypeTrace:
+		tn := TraceNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+			return nil, err
+		}
+		ev.src, ev.dst = tn.Source, tn.DstID
+		ev.identities = []uint32{tn.SrcLabel, tn.DstLabel}
+		ev.verdict = VerdictForwarded
+		if len(data) > TraceNotifyLen {
+			ev.packet = data[TraceNotifyLen:]
+		}
+
+	case MessageTypePolicyVerdict:
+		pn := PolicyVerdictNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &pn); err != nil {
+			return nil, err
+		}
+		ev.src = pn.Source
+		ev.identities = []uint32{pn.SrcLabel, pn.DstLabel}
+		ev.ports = []uint16{pn.Port()}
+		switch {
+		case pn.Verdict < 0 && pn.IsAudited():
+			ev.verdict = VerdictAudited
+		case pn.Verdict < 0:
+			ev.verdict = VerdictDropped
+		default:
+			ev.verdict = VerdictForwarded
+		}
+		if len(data) > PolicyVerdictNotifyLen {
+			ev.packet = data[PolicyVerdictNotifyLen:]
+		}
+
+	case MessageTypeCapture:
+		dc := DebugCapture{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err != nil {
+			return nil, err
+		}
+		ev.src = dc.Source
+		if len(data) > DebugCaptureLen {
+			ev.packet = data[DebugCaptureLen:]
+		}
+
+	case MessageTypeDebug:
+		dm := DebugMsg{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dm); err != nil {
+			return nil, err
+		}
+		ev.src = dm.Source
+
+	case MessageTypeAccessLog:
+		lr := LogRecordNotify{}
+		if err := gob.NewDecoder(bytes.NewBuffer(data[1:])).Decode(&lr); err != nil {
+			return nil, err
+		}
+		ev.src, ev.dst = uint16(lr.SourceEndpoint.ID), uint16(lr.DestinationEndpoint.ID)
+		ev.identities = []uint32{uint32(lr.SourceEndpoint.Identity), uint32(lr.DestinationEndpoint.Identity)}
+		ev.ports = []uint16{lr.SourceEndpoint.Port, lr.DestinationEndpoint.Port}
+		for _, s := range []string{lr.SourceEndpoint.IPv4, lr.SourceEndpoint.IPv6,
+			lr.DestinationEndpoint.IPv4, lr.DestinationEndpoint.IPv6} {
+			if ip := net.ParseIP(s); ip != nil {
+				ev.ips = append(ev.ips, ip)
+			}
+		}
+		switch lr.Verdict {
+		case accesslog.VerdictForwarded:
+			ev.verdict = VerdictForwarded
+		case accesslog.VerdictDenied:
+			ev.verdict = VerdictDropped
+		}
+	}
+
+	return ev, nil
+}
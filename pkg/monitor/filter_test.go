@@ -0,0 +1,196 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"net"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "gopkg.in/check.v1"
+)
+
+func buildDropPayload(c *C, dn DropNotify, srcIP, dstIP string, srcPort, dstPort uint16) *payload.Payload {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 7},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    net.ParseIP(srcIP).To4(),
+		DstIP:    net.ParseIP(dstIP).To4(),
+	}
+	tcp := &layers.TCP{SrcPort: layers.TCPPort(srcPort), DstPort: layers.TCPPort(dstPort), SYN: true}
+	pkt := gopacket.NewSerializeBuffer()
+	c.Assert(gopacket.SerializeLayers(pkt, gopacket.SerializeOptions{FixLengths: true}, eth, ip, tcp), IsNil)
+
+	buf := new(bytes.Buffer)
+	dn.Type = MessageTypeDrop
+	dn.CapLen = uint32(len(pkt.Bytes()))
+	c.Assert(binary.Write(buf, byteorder.Native, dn), IsNil)
+	buf.Write(pkt.Bytes())
+
+	return &payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}
+}
+
+func buildLogRecordPayload(c *C, lr accesslog.LogRecord) *payload.Payload {
+	buf := bytes.NewBuffer([]byte{byte(MessageTypeAccessLog)})
+	c.Assert(gob.NewEncoder(buf).Encode(LogRecordNotify{lr}), IsNil)
+	return &payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}
+}
+
+func (s *MonitorSuite) TestFilterValidate(c *C) {
+	f := Filter{CIDRs: []string{"10.0.0.0/8", "192.168.1.1", "f00d::1"}, Verdicts: []string{VerdictDropped}}
+	c.Assert(f.Validate(), IsNil)
+	c.Assert(f.cidrs, HasLen, 3)
+	c.Assert(f.cidrs[1].String(), Equals, "192.168.1.1/32")
+	c.Assert(f.cidrs[2].String(), Equals, "f00d::1/128")
+
+	f = Filter{CIDRs: []string{"10.0.0.0/33"}}
+	c.Assert(f.Validate(), Not(IsNil))
+	f = Filter{CIDRs: []string{"foo"}}
+	c.Assert(f.Validate(), Not(IsNil))
+	f = Filter{Verdicts: []string{"foo"}}
+	c.Assert(f.Validate(), Not(IsNil))
+}
+
+func (s *MonitorSuite) TestNewFilterFromModel(c *C) {
+	f, err := NewFilterFromModel(nil)
+	c.Assert(err, IsNil)
+	c.Assert(f.IsEmpty(), Equals, true)
+
+	f, err = NewFilterFromModel(&models.MonitorEventFilter{
+		Types:            []string{"drop", "l7"},
+		RelatedEndpoints: []int64{10},
+		Identities:       []int64{100},
+		Cidrs:            []string{"10.0.0.0/8"},
+		Ports:            []int64{80},
+		Verdicts:         []string{VerdictDropped},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(f.MessageTypes, DeepEquals, MessageTypeFilter{MessageTypeDrop, MessageTypeAccessLog})
+	c.Assert(f.RelatedEndpoints, DeepEquals, []uint16{10})
+	c.Assert(f.Identities, DeepEquals, []uint32{100})
+	c.Assert(f.Ports, DeepEquals, []uint16{80})
+	c.Assert(f.cidrs, HasLen, 1)
+
+	invalid := []*models.MonitorEventFilter{
+		{Types: []string{"foo"}},
+		{FromEndpoints: []int64{-1}},
+		{Ports: []int64{65536}},
+		{Identities: []int64{1 << 32}},
+		{Cidrs: []string{"foo"}},
+		{Verdicts: []string{"foo"}},
+	}
+	for _, m := range invalid {
+		_, err = NewFilterFromModel(m)
+		c.Assert(err, Not(IsNil), Commentf("Filter: %+v", m))
+	}
+}
+
+func (s *MonitorSuite) TestFilterMatch(c *C) {
+	drop := buildDropPayload(c, DropNotify{Source: 10, SrcLabel: 100, DstLabel: 200, DstID: 20},
+		"10.0.0.1", "192.168.0.1", 31000, 80)
+	lost := &payload.Payload{Type: payload.RecordLost, Lost: 10}
+
+	tests := []struct {
+		filter Filter
+		match  bool
+	}{
+		{Filter{}, true},
+		{Filter{MessageTypes: MessageTypeFilter{MessageTypeDrop}}, true},
+		{Filter{MessageTypes: MessageTypeFilter{MessageTypeTrace}}, false},
+		{Filter{FromEndpoints: []uint16{10}}, true},
+		{Filter{FromEndpoints: []uint16{20}}, false},
+		{Filter{ToEndpoints: []uint16{20}}, true},
+		{Filter{RelatedEndpoints: []uint16{20, 30}}, true},
+		{Filter{RelatedEndpoints: []uint16{30}}, false},
+		{Filter{Identities: []uint32{200}}, true},
+		{Filter{Identities: []uint32{300}}, false},
+		{Filter{CIDRs: []string{"10.0.0.0/8"}}, true},
+		{Filter{CIDRs: []string{"192.168.0.1"}}, true},
+		{Filter{CIDRs: []string{"172.16.0.0/12"}}, false},
+		{Filter{Ports: []uint16{80}}, true},
+		{Filter{Ports: []uint16{443}}, false},
+		{Filter{Verdicts: []string{VerdictDropped}}, true},
+		{Filter{Verdicts: []string{VerdictForwarded}}, false},
+		{Filter{FromEndpoints: []uint16{10}, Ports: []uint16{80}, Verdicts: []string{VerdictForwarded}}, false},
+	}
+	for i, tt := range tests {
+		c.Assert(tt.filter.Validate(), IsNil)
+		c.Assert(tt.filter.Match(drop), Equals, tt.match, Commentf("test %d", i))
+		c.Assert(tt.filter.Match(lost), Equals, true, Commentf("test %d", i))
+	}
+}
+
+func (s *MonitorSuite) TestFilterMatchLogRecord(c *C) {
+	pl := buildLogRecordPayload(c, accesslog.LogRecord{
+		Verdict:             accesslog.VerdictDenied,
+		SourceEndpoint:      accesslog.EndpointInfo{ID: 10, Identity: 100, IPv4: "10.0.0.1", Port: 31000},
+		DestinationEndpoint: accesslog.EndpointInfo{ID: 20, Identity: 200, IPv4: "10.0.0.2", Port: 80},
+	})
+
+	f := Filter{
+		MessageTypes: MessageTypeFilter{MessageTypeAccessLog},
+		ToEndpoints:  []uint16{20},
+		Identities:   []uint32{100},
+		CIDRs:        []string{"10.0.0.2"},
+		Ports:        []uint16{80},
+		Verdicts:     []string{VerdictDropped},
+	}
+	c.Assert(f.Validate(), IsNil)
+	c.Assert(f.Match(pl), Equals, true)
+
+	f = Filter{Verdicts: []string{VerdictAudited}}
+	c.Assert(f.Validate(), IsNil)
+	c.Assert(f.Match(pl), Equals, false)
+}
+
+func (s *MonitorSuite) TestFilterMatchPolicyVerdict(c *C) {
+	buf := new(bytes.Buffer)
+	c.Assert(binary.Write(buf, byteorder.Native, PolicyVerdictNotify{
+		Type:     MessageTypePolicyVerdict,
+		SrcLabel: 100,
+		DstLabel: 200,
+		Verdict:  -133,
+		DstPort:  byteorder.HostToNetwork(uint16(80)).(uint16),
+		Flags:    PolicyIngress | PolicyVerdictFlagAudited,
+	}), IsNil)
+	pl := &payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}
+
+	f := Filter{Ports: []uint16{80}, Verdicts: []string{VerdictAudited}}
+	c.Assert(f.Validate(), IsNil)
+	c.Assert(f.Match(pl), Equals, true)
+
+	// Agent notifications carry no identities
+	agent := &payload.Payload{Type: payload.EventSample, Data: []byte{MessageTypeAgent}}
+	f = Filter{Identities: []uint32{100}}
+	c.Assert(f.Validate(), IsNil)
+	c.Assert(f.Match(pl), Equals, true)
+	c.Assert(f.Match(agent), Equals, false)
+}
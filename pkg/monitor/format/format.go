@@ -132,6 +132,27 @@ func (m *MonitorFormatter) traceEvents(prefix string, data []byte) {
 	}
 }
 
+// policyVerdictEvents prints out all the received policy verdict
+// notifications.
+func (m *MonitorFormatter) policyVerdictEvents(prefix string, data []byte) {
+	pn := monitor.PolicyVerdictNotify{}
+
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &pn); err != nil {
+		fmt.Printf("Error while parsing policy verdict notification message: %s\n", err)
+	}
+	if m.match(monitor.MessageTypePolicyVerdict, pn.Source, 0) {
+		switch m.Verbosity {
+		case INFO:
+			pn.DumpInfo(data)
+		case JSON:
+			pn.DumpJSON(data, prefix)
+		default:
+			fmt.Println(msgSeparator)
+			pn.DumpVerbose(!m.Hex, data, prefix)
+		}
+	}
+}
+
 // debugEvents prints out all the debug messages.
 func (m *MonitorFormatter) debugEvents(prefix string, data []byte) {
 	dm := monitor.DebugMsg{}
@@ -227,6 +248,8 @@ func (m *MonitorFormatter) FormatSample(data []byte, cpu int) {
 		m.captureEvents(prefix, data)
 	case monitor.MessageTypeTrace:
 		m.traceEvents(prefix, data)
+	case monitor.MessageTypePolicyVerdict:
+		m.policyVerdictEvents(prefix, data)
 	case monitor.MessageTypeAccessLog:
 		m.logRecordEvents(prefix, data)
 	case monitor.MessageTypeAgent:
@@ -117,6 +117,7 @@ type LogRecordNotifyVerbose struct {
 	DstEpID          uint64                     `json:"dstEpID"`
 	DstEpLabels      []string                   `json:"dstEpLabels"`
 	DstIdentity      uint64                     `json:"dstIdentity"`
+	DstPort          uint16                     `json:"dstPort,omitempty"`
 	Verdict          accesslog.FlowVerdict      `json:"verdict"`
 	HTTP             *accesslog.LogRecordHTTP   `json:"http,omitempty"`
 	Kafka            *accesslog.LogRecordKafka  `json:"kafka,omitempty"`
@@ -136,6 +137,7 @@ func LogRecordNotifyToVerbose(n *LogRecordNotify) LogRecordNotifyVerbose {
 		DstEpID:          n.DestinationEndpoint.ID,
 		DstEpLabels:      n.DestinationEndpoint.Labels,
 		DstIdentity:      n.DestinationEndpoint.Identity,
+		DstPort:          n.DestinationEndpoint.Port,
 		Verdict:          n.Verdict,
 		HTTP:             n.HTTP,
 		Kafka:            n.Kafka,
@@ -0,0 +1,197 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+// PacketMetadataPrefix prefixes the machine readable metadata of the event a
+// packet has been taken from, see EventPacket.
+const PacketMetadataPrefix = "cilium-monitor"
+
+// EventPacket is the packet carried by a drop, trace or capture notification,
+// along with the metadata of the notification.
+type EventPacket struct {
+	// CPU is the CPU the notification has been emitted on
+	CPU int
+	// Data is the captured packet data
+	Data []byte
+	// OrigLen is the length of the packet, which may be larger than the
+	// captured data
+	OrigLen uint32
+	// Metadata is the machine readable metadata of the notification, in
+	// the form "cilium-monitor type=drop key=value ...". It allows to
+	// reconstruct the notification with ParseEventPacket.
+	Metadata string
+	// Summary is a human readable summary of the notification, including
+	// the endpoint, identities and drop reason
+	Summary string
+}
+
+// GetEventPacket returns the packet carried by the data of a monitor event,
+// or nil if the event does not carry a packet.
+func GetEventPacket(data []byte, cpu int) (*EventPacket, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	switch data[0] {
+	case MessageTypeDrop:
+		dn := DropNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+			return nil, fmt.Errorf("unable to decode drop notification: %s", err)
+		}
+		return &EventPacket{
+			CPU:     cpu,
+			Data:    data[DropNotifyLen:],
+			OrigLen: dn.OrigLen,
+			Metadata: fmt.Sprintf("%s type=drop cpu=%d source=%d hash=%#x reason=%d src-identity=%d dst-identity=%d dst-endpoint=%d ifindex=%d",
+				PacketMetadataPrefix, cpu, dn.Source, dn.Hash, dn.SubType, dn.SrcLabel, dn.DstLabel, dn.DstID, dn.Ifindex),
+			Summary: fmt.Sprintf("drop (%s) from endpoint %d to endpoint %d, identity %d->%d",
+				DropReason(dn.SubType), dn.Source, dn.DstID, dn.SrcLabel, dn.DstLabel),
+		}, nil
+
+	case MessageTypeTrace:
+		tn := TraceNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+			return nil, fmt.Errorf("unable to decode trace notification: %s", err)
+		}
+		return &EventPacket{
+			CPU:     cpu,
+			Data:    data[TraceNotifyLen:],
+			OrigLen: tn.OrigLen,
+			Metadata: fmt.Sprintf("%s type=trace cpu=%d source=%d hash=%#x obs-point=%d reason=%d src-identity=%d dst-identity=%d dst-endpoint=%d ifindex=%d",
+				PacketMetadataPrefix, cpu, tn.Source, tn.Hash, tn.ObsPoint, tn.Reason, tn.SrcLabel, tn.DstLabel, tn.DstID, tn.Ifindex),
+			Summary: fmt.Sprintf("trace %s from endpoint %d, identity %d->%d, state %s",
+				obsPoint(tn.ObsPoint), tn.Source, tn.SrcLabel, tn.DstLabel, connState(tn.Reason)),
+		}, nil
+
+	case MessageTypeCapture:
+		dc := DebugCapture{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err != nil {
+			return nil, fmt.Errorf("unable to decode debug capture: %s", err)
+		}
+		return &EventPacket{
+			CPU:     cpu,
+			Data:    data[DebugCaptureLen:],
+			OrigLen: dc.OrigLen,
+			Metadata: fmt.Sprintf("%s type=capture cpu=%d source=%d hash=%#x subtype=%d arg1=%d arg2=%d",
+				PacketMetadataPrefix, cpu, dc.Source, dc.Hash, dc.SubType, dc.Arg1, dc.Arg2),
+			Summary: fmt.Sprintf("capture from endpoint %d: %s", dc.Source, dc.subTypeString()),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// packetMetadata is the parsed metadata of an EventPacket
+type packetMetadata struct {
+	fields map[string]string
+	err    error
+}
+
+func (m *packetMetadata) get(key string, bits int) uint64 {
+	value, ok := m.fields[key]
+	if !ok {
+		if m.err == nil {
+			m.err = fmt.Errorf("missing %s", key)
+		}
+		return 0
+	}
+	v, err := strconv.ParseUint(value, 0, bits)
+	if err != nil && m.err == nil {
+		m.err = fmt.Errorf("invalid %s: %s", key, err)
+	}
+	return v
+}
+
+// ParseEventPacket reconstructs the data of the monitor event a packet has
+// been taken from, based on the metadata of the event. It returns the event
+// data and the CPU the event has been emitted on.
+func ParseEventPacket(metadata string, packet []byte, origLen uint32) ([]byte, int, error) {
+	fields := strings.Fields(metadata)
+	if len(fields) == 0 || fields[0] != PacketMetadataPrefix {
+		return nil, 0, fmt.Errorf("not a monitor event packet")
+	}
+
+	m := &packetMetadata{fields: map[string]string{}}
+	for _, field := range fields[1:] {
+		if kv := strings.SplitN(field, "=", 2); len(kv) == 2 {
+			m.fields[kv[0]] = kv[1]
+		}
+	}
+
+	var hdr interface{}
+	switch m.fields["type"] {
+	case "drop":
+		hdr = &DropNotify{
+			Type:     MessageTypeDrop,
+			SubType:  uint8(m.get("reason", 8)),
+			Source:   uint16(m.get("source", 16)),
+			Hash:     uint32(m.get("hash", 32)),
+			OrigLen:  origLen,
+			CapLen:   uint32(len(packet)),
+			SrcLabel: uint32(m.get("src-identity", 32)),
+			DstLabel: uint32(m.get("dst-identity", 32)),
+			DstID:    uint32(m.get("dst-endpoint", 32)),
+			Ifindex:  uint32(m.get("ifindex", 32)),
+		}
+	case "trace":
+		hdr = &TraceNotify{
+			Type:     MessageTypeTrace,
+			ObsPoint: uint8(m.get("obs-point", 8)),
+			Source:   uint16(m.get("source", 16)),
+			Hash:     uint32(m.get("hash", 32)),
+			OrigLen:  origLen,
+			CapLen:   uint32(len(packet)),
+			SrcLabel: uint32(m.get("src-identity", 32)),
+			DstLabel: uint32(m.get("dst-identity", 32)),
+			DstID:    uint16(m.get("dst-endpoint", 16)),
+			Reason:   uint8(m.get("reason", 8)),
+			Ifindex:  uint32(m.get("ifindex", 32)),
+		}
+	case "capture":
+		hdr = &DebugCapture{
+			Type:    MessageTypeCapture,
+			SubType: uint8(m.get("subtype", 8)),
+			Source:  uint16(m.get("source", 16)),
+			Hash:    uint32(m.get("hash", 32)),
+			Len:     uint32(len(packet)),
+			OrigLen: origLen,
+			Arg1:    uint32(m.get("arg1", 32)),
+			Arg2:    uint32(m.get("arg2", 32)),
+		}
+	default:
+		return nil, 0, fmt.Errorf("unsupported event type %q", m.fields["type"])
+	}
+	cpu := int(m.get("cpu", 16))
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, byteorder.Native, hdr); err != nil {
+		return nil, 0, err
+	}
+	buf.Write(packet)
+	return buf.Bytes(), cpu, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MonitorSuite) TestEventPacket(c *C) {
+	pkt := []byte{1, 2, 3, 4, 5, 6}
+	headers := []interface{}{
+		DropNotify{Type: MessageTypeDrop, SubType: 133, Source: 10, Hash: 0xabc, OrigLen: 100,
+			CapLen: uint32(len(pkt)), SrcLabel: 100, DstLabel: 200, DstID: 20, Ifindex: 4},
+		TraceNotify{Type: MessageTypeTrace, ObsPoint: TraceToLxc, Source: 10, Hash: 0xabc, OrigLen: 100,
+			CapLen: uint32(len(pkt)), SrcLabel: 100, DstLabel: 200, DstID: 20, Reason: TraceReasonCtReply, Ifindex: 4},
+		DebugCapture{Type: MessageTypeCapture, SubType: DbgCaptureDelivery, Source: 10, Hash: 0xabc,
+			Len: uint32(len(pkt)), OrigLen: 100, Arg1: 4, Arg2: 5},
+	}
+
+	for _, hdr := range headers {
+		buf := new(bytes.Buffer)
+		c.Assert(binary.Write(buf, byteorder.Native, hdr), IsNil)
+		buf.Write(pkt)
+		data := buf.Bytes()
+
+		ep, err := GetEventPacket(data, 3)
+		c.Assert(err, IsNil)
+		c.Assert(ep, Not(IsNil))
+		c.Assert(ep.Data, DeepEquals, pkt)
+		c.Assert(ep.OrigLen, Equals, uint32(100))
+		c.Assert(ep.CPU, Equals, 3)
+
+		parsed, cpu, err := ParseEventPacket(ep.Metadata, ep.Data, ep.OrigLen)
+		c.Assert(err, IsNil)
+		c.Assert(cpu, Equals, 3)
+		c.Assert(parsed, DeepEquals, data)
+	}
+
+	buf := new(bytes.Buffer)
+	c.Assert(binary.Write(buf, byteorder.Native, headers[0]), IsNil)
+	ep, err := GetEventPacket(buf.Bytes(), 0)
+	c.Assert(err, IsNil)
+	c.Assert(ep.Summary, Equals, "drop (Policy denied (L3)) from endpoint 10 to endpoint 20, identity 100->200")
+
+	ep, err = GetEventPacket([]byte{MessageTypeAgent}, 0)
+	c.Assert(err, IsNil)
+	c.Assert(ep, IsNil)
+}
+
+func (s *MonitorSuite) TestParseEventPacketInvalid(c *C) {
+	_, _, err := ParseEventPacket("foo type=drop", nil, 0)
+	c.Assert(err, Not(IsNil))
+	_, _, err = ParseEventPacket(PacketMetadataPrefix+" type=debug", nil, 0)
+	c.Assert(err, Not(IsNil))
+	_, _, err = ParseEventPacket(PacketMetadataPrefix+" type=drop cpu=0 source=1", nil, 0)
+	c.Assert(err, Not(IsNil))
+	_, _, err = ParseEventPacket(PacketMetadataPrefix+" type=capture cpu=0 source=70000 hash=0 subtype=0 arg1=0 arg2=0", nil, 0)
+	c.Assert(err, Not(IsNil))
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcapng
+
+import (
+	"time"
+)
+
+// LinkTypeEthernet is the link type of packets starting with an Ethernet
+// header
+const LinkTypeEthernet = 1
+
+// Block types
+const (
+	blockTypeSectionHeader    = 0x0A0D0D0A
+	blockTypeInterface        = 0x00000001
+	blockTypeSimplePacket     = 0x00000003
+	blockTypeEnhancedPacket   = 0x00000006
+	sectionHeaderByteOrder    = 0x1A2B3C4D
+	sectionHeaderVersionMajor = 1
+	sectionHeaderVersionMinor = 0
+)
+
+// Option codes
+const (
+	optEndOfOpt    = 0
+	optComment     = 1
+	optSHBUserAppl = 4
+	optIfTsresol   = 9
+)
+
+// maxBlockLen limits the size of blocks read to protect against corrupt files
+const maxBlockLen = 16 * 1024 * 1024
+
+// Packet is a packet stored in a pcapng file
+type Packet struct {
+	// Timestamp is the time the packet was captured
+	Timestamp time.Time
+	// Data is the captured packet data
+	Data []byte
+	// OrigLen is the length of the packet on the wire, which may be
+	// larger than the captured data
+	OrigLen uint32
+	// Comments is the list of comments attached to the packet
+	Comments []string
+}
+
+// padLen returns the number of bytes required to pad n bytes to 32 bits
+func padLen(n int) int {
+	return (4 - n%4) % 4
+}
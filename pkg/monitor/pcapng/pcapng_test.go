@@ -0,0 +1,156 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type PcapngSuite struct{}
+
+var _ = Suite(&PcapngSuite{})
+
+func (s *PcapngSuite) TestWriteRead(c *C) {
+	packets := []*Packet{
+		{
+			Timestamp: time.Unix(1500000000, 123456789),
+			Data:      []byte{1, 2, 3, 4, 5},
+			OrigLen:   100,
+			Comments:  []string{"first", "cilium-monitor type=drop"},
+		},
+		{
+			Timestamp: time.Unix(1500000001, 0),
+			Data:      []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	w, err := NewWriter(buf, LinkTypeEthernet, "test")
+	c.Assert(err, IsNil)
+	for _, p := range packets {
+		c.Assert(w.WritePacket(p), IsNil)
+	}
+	c.Assert(buf.Len()%4, Equals, 0)
+
+	r, err := NewReader(buf)
+	c.Assert(err, IsNil)
+
+	p, err := r.ReadPacket()
+	c.Assert(err, IsNil)
+	c.Assert(p.Timestamp.Equal(packets[0].Timestamp), Equals, true)
+	c.Assert(p.Data, DeepEquals, packets[0].Data)
+	c.Assert(p.OrigLen, Equals, uint32(100))
+	c.Assert(p.Comments, DeepEquals, packets[0].Comments)
+	linkType, ok := r.LinkType(0)
+	c.Assert(ok, Equals, true)
+	c.Assert(linkType, Equals, uint16(LinkTypeEthernet))
+
+	p, err = r.ReadPacket()
+	c.Assert(err, IsNil)
+	c.Assert(p.Data, DeepEquals, packets[1].Data)
+	c.Assert(p.OrigLen, Equals, uint32(8))
+	c.Assert(p.Comments, IsNil)
+
+	_, err = r.ReadPacket()
+	c.Assert(err, Equals, io.EOF)
+}
+
+// put writes values in big endian byte order
+func put(buf *bytes.Buffer, values ...interface{}) {
+	for _, v := range values {
+		binary.Write(buf, binary.BigEndian, v)
+	}
+}
+
+// block builds a big endian block with the given type and body
+func block(blockType uint32, body []byte) []byte {
+	buf := new(bytes.Buffer)
+	put(buf, blockType, uint32(len(body)+12))
+	buf.Write(body)
+	put(buf, uint32(len(body)+12))
+	return buf.Bytes()
+}
+
+func (s *PcapngSuite) TestReadBigEndian(c *C) {
+	shb := new(bytes.Buffer)
+	put(shb, uint32(sectionHeaderByteOrder), uint16(1), uint16(0), int64(-1))
+
+	// Interface with the default microsecond resolution
+	idb := new(bytes.Buffer)
+	put(idb, uint16(LinkTypeEthernet), uint16(0), uint32(0))
+
+	epb := new(bytes.Buffer)
+	ts := uint64(1500000000123456)
+	put(epb, uint32(0), uint32(ts>>32), uint32(ts), uint32(3), uint32(3))
+	epb.Write([]byte{7, 8, 9, 0})
+	// Unknown block types are skipped
+	unknown := block(0x0BADCAFE, []byte{0, 0, 0, 0})
+
+	file := bytes.Join([][]byte{
+		block(blockTypeSectionHeader, shb.Bytes()),
+		block(blockTypeInterface, idb.Bytes()),
+		unknown,
+		block(blockTypeEnhancedPacket, epb.Bytes()),
+	}, nil)
+
+	r, err := NewReader(bytes.NewReader(file))
+	c.Assert(err, IsNil)
+	p, err := r.ReadPacket()
+	c.Assert(err, IsNil)
+	c.Assert(p.Data, DeepEquals, []byte{7, 8, 9})
+	c.Assert(p.Timestamp.Equal(time.Unix(1500000000, 123456000)), Equals, true)
+	_, err = r.ReadPacket()
+	c.Assert(err, Equals, io.EOF)
+}
+
+func (s *PcapngSuite) TestReadInvalid(c *C) {
+	_, err := NewReader(bytes.NewReader(nil))
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewReader(bytes.NewReader(block(blockTypeInterface, make([]byte, 8))))
+	c.Assert(err, Not(IsNil))
+
+	// Classic pcap files in both byte orders
+	pcap := make([]byte, 24)
+	binary.LittleEndian.PutUint32(pcap, pcapMagic)
+	_, err = NewReader(bytes.NewReader(pcap))
+	c.Assert(err, Equals, ErrUnsupportedFormat)
+	binary.BigEndian.PutUint32(pcap, pcapMagicNs)
+	_, err = NewReader(bytes.NewReader(pcap))
+	c.Assert(err, Equals, ErrUnsupportedFormat)
+
+	// Truncated file
+	buf := new(bytes.Buffer)
+	_, err = NewWriter(buf, LinkTypeEthernet, "")
+	c.Assert(err, IsNil)
+	r, err := NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-2]))
+	c.Assert(err, IsNil)
+	_, err = r.ReadPacket()
+	c.Assert(err, Not(IsNil))
+	c.Assert(err, Not(Equals), io.EOF)
+}
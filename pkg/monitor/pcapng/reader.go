@@ -0,0 +1,255 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcapng
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ErrUnsupportedFormat is returned by NewReader if the file is a capture file
+// in another format than pcapng, e.g. a classic pcap file
+var ErrUnsupportedFormat = errors.New("unsupported capture format")
+
+// Magic numbers of classic pcap files with microsecond and nanosecond
+// timestamps
+const (
+	pcapMagic   = 0xA1B2C3D4
+	pcapMagicNs = 0xA1B23C4D
+)
+
+// iface is an interface described in the current section
+type iface struct {
+	linkType uint16
+	// unitsPerSecond is the timestamp resolution of the interface
+	unitsPerSecond uint64
+}
+
+// Reader reads packets from a pcapng file
+type Reader struct {
+	r      io.Reader
+	order  binary.ByteOrder
+	ifaces []iface
+}
+
+// NewReader reads the section header from r and returns a Reader for the
+// packets in r
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(4); err == nil {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			if m := order.Uint32(magic); m == pcapMagic || m == pcapMagicNs {
+				return nil, ErrUnsupportedFormat
+			}
+		}
+	}
+	pr := &Reader{r: br}
+
+	blockType, body, err := pr.readBlock()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty pcapng file")
+	} else if err != nil {
+		return nil, err
+	}
+	if blockType != blockTypeSectionHeader {
+		return nil, fmt.Errorf("not a pcapng file")
+	}
+	if err := pr.parseSectionHeader(body); err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// LinkType returns the link type of the interface with the given ID, or
+// false if the interface has not been described yet
+func (pr *Reader) LinkType(ifaceID int) (uint16, bool) {
+	if ifaceID < 0 || ifaceID >= len(pr.ifaces) {
+		return 0, false
+	}
+	return pr.ifaces[ifaceID].linkType, true
+}
+
+// ReadPacket returns the next packet. Blocks which don't carry packets are
+// skipped. It returns io.EOF at the end of the file.
+func (pr *Reader) ReadPacket() (*Packet, error) {
+	for {
+		blockType, body, err := pr.readBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		switch blockType {
+		case blockTypeSectionHeader:
+			if err := pr.parseSectionHeader(body); err != nil {
+				return nil, err
+			}
+		case blockTypeInterface:
+			if err := pr.parseInterface(body); err != nil {
+				return nil, err
+			}
+		case blockTypeEnhancedPacket:
+			return pr.parseEnhancedPacket(body)
+		case blockTypeSimplePacket:
+			return pr.parseSimplePacket(body)
+		}
+	}
+}
+
+// readBlock reads the next block and returns its type and body
+func (pr *Reader) readBlock() (uint32, []byte, error) {
+	hdr := make([]byte, 12)
+	if _, err := io.ReadFull(pr.r, hdr[:8]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, nil, fmt.Errorf("truncated pcapng block header")
+		}
+		return 0, nil, err
+	}
+
+	// The byte order of a section is only known once its header has
+	// been read, the block type of the section header is a palindrome.
+	if pr.order == nil || binary.LittleEndian.Uint32(hdr) == blockTypeSectionHeader {
+		if _, err := io.ReadFull(pr.r, hdr[8:12]); err != nil {
+			return 0, nil, fmt.Errorf("truncated pcapng section header")
+		}
+		switch {
+		case binary.LittleEndian.Uint32(hdr[8:]) == sectionHeaderByteOrder:
+			pr.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(hdr[8:]) == sectionHeaderByteOrder:
+			pr.order = binary.BigEndian
+		default:
+			return 0, nil, fmt.Errorf("invalid pcapng byte order magic")
+		}
+		hdr = hdr[:12]
+	} else {
+		hdr = hdr[:8]
+	}
+
+	blockType := pr.order.Uint32(hdr)
+	totalLen := pr.order.Uint32(hdr[4:])
+	if totalLen < uint32(len(hdr))+4 || totalLen%4 != 0 || totalLen > maxBlockLen {
+		return 0, nil, fmt.Errorf("invalid pcapng block length %d", totalLen)
+	}
+
+	rest := make([]byte, int(totalLen)-len(hdr))
+	if _, err := io.ReadFull(pr.r, rest); err != nil {
+		return 0, nil, fmt.Errorf("truncated pcapng block")
+	}
+	if pr.order.Uint32(rest[len(rest)-4:]) != totalLen {
+		return 0, nil, fmt.Errorf("pcapng block length mismatch")
+	}
+
+	// The body of a section header starts with the byte order magic
+	body := append(hdr[8:], rest[:len(rest)-4]...)
+	return blockType, body, nil
+}
+
+// parseOptions returns the values of the options in data by option code
+func (pr *Reader) parseOptions(data []byte) map[uint16][][]byte {
+	options := map[uint16][][]byte{}
+	for len(data) >= 4 {
+		code, length := pr.order.Uint16(data), int(pr.order.Uint16(data[2:]))
+		if code == optEndOfOpt || 4+length > len(data) {
+			break
+		}
+		options[code] = append(options[code], data[4:4+length])
+		next := 4 + length + padLen(length)
+		if next > len(data) {
+			break
+		}
+		data = data[next:]
+	}
+	return options
+}
+
+func (pr *Reader) parseSectionHeader(body []byte) error {
+	if len(body) < 16 {
+		return fmt.Errorf("truncated pcapng section header")
+	}
+	if major := pr.order.Uint16(body[4:]); major != sectionHeaderVersionMajor {
+		return fmt.Errorf("unsupported pcapng version %d", major)
+	}
+	// Interfaces are scoped to their section
+	pr.ifaces = nil
+	return nil
+}
+
+func (pr *Reader) parseInterface(body []byte) error {
+	if len(body) < 8 {
+		return fmt.Errorf("truncated pcapng interface description")
+	}
+
+	ifc := iface{linkType: pr.order.Uint16(body), unitsPerSecond: 1000000}
+	if tsresol, ok := pr.parseOptions(body[8:])[optIfTsresol]; ok && len(tsresol[0]) > 0 {
+		exp := uint(tsresol[0][0] & 0x7f)
+		if tsresol[0][0]&0x80 != 0 {
+			ifc.unitsPerSecond = 1 << exp
+		} else {
+			ifc.unitsPerSecond = uint64(math.Pow10(int(exp)))
+		}
+		if ifc.unitsPerSecond == 0 {
+			return fmt.Errorf("invalid pcapng timestamp resolution")
+		}
+	}
+	pr.ifaces = append(pr.ifaces, ifc)
+	return nil
+}
+
+func (pr *Reader) parseEnhancedPacket(body []byte) (*Packet, error) {
+	if len(body) < 20 {
+		return nil, fmt.Errorf("truncated pcapng enhanced packet")
+	}
+	ifaceID := pr.order.Uint32(body)
+	if int(ifaceID) >= len(pr.ifaces) {
+		return nil, fmt.Errorf("pcapng packet of unknown interface %d", ifaceID)
+	}
+	ifc := pr.ifaces[ifaceID]
+
+	ts := uint64(pr.order.Uint32(body[4:]))<<32 | uint64(pr.order.Uint32(body[8:]))
+	capLen := int(pr.order.Uint32(body[12:]))
+	if 20+capLen > len(body) {
+		return nil, fmt.Errorf("truncated pcapng packet data")
+	}
+
+	p := &Packet{
+		Timestamp: time.Unix(int64(ts/ifc.unitsPerSecond),
+			int64((ts%ifc.unitsPerSecond)*uint64(time.Second)/ifc.unitsPerSecond)),
+		Data:    body[20 : 20+capLen],
+		OrigLen: pr.order.Uint32(body[16:]),
+	}
+	options := 20 + capLen + padLen(capLen)
+	if options < len(body) {
+		for _, comment := range pr.parseOptions(body[options:])[optComment] {
+			p.Comments = append(p.Comments, string(comment))
+		}
+	}
+	return p, nil
+}
+
+func (pr *Reader) parseSimplePacket(body []byte) (*Packet, error) {
+	if len(body) < 4 {
+		return nil, fmt.Errorf("truncated pcapng simple packet")
+	}
+	p := &Packet{OrigLen: pr.order.Uint32(body), Data: body[4:]}
+	if uint32(len(p.Data)) > p.OrigLen {
+		p.Data = p.Data[:p.OrigLen]
+	}
+	return p, nil
+}
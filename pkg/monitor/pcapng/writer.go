@@ -0,0 +1,119 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+// Writer writes packets of a single interface to a pcapng file
+type Writer struct {
+	w     io.Writer
+	order binary.ByteOrder
+}
+
+// NewWriter writes the section header and the description of the interface
+// with the given link type to w and returns a Writer for packets of that
+// interface. userAppl names the application writing the file.
+func NewWriter(w io.Writer, linkType uint16, userAppl string) (*Writer, error) {
+	pw := &Writer{w: w, order: byteorder.Native}
+
+	shb := new(bytes.Buffer)
+	pw.write(shb, uint32(sectionHeaderByteOrder))
+	pw.write(shb, uint16(sectionHeaderVersionMajor))
+	pw.write(shb, uint16(sectionHeaderVersionMinor))
+	pw.write(shb, int64(-1)) // section length is unspecified
+	if userAppl != "" {
+		pw.writeOption(shb, optSHBUserAppl, []byte(userAppl))
+	}
+	pw.writeOption(shb, optEndOfOpt, nil)
+	if err := pw.writeBlock(blockTypeSectionHeader, shb.Bytes()); err != nil {
+		return nil, err
+	}
+
+	idb := new(bytes.Buffer)
+	pw.write(idb, linkType)
+	pw.write(idb, uint16(0))                     // reserved
+	pw.write(idb, uint32(0))                     // no snap length limit
+	pw.writeOption(idb, optIfTsresol, []byte{9}) // nanoseconds
+	pw.writeOption(idb, optEndOfOpt, nil)
+	if err := pw.writeBlock(blockTypeInterface, idb.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return pw, nil
+}
+
+// WritePacket writes the packet as enhanced packet block
+func (pw *Writer) WritePacket(p *Packet) error {
+	origLen := p.OrigLen
+	if origLen < uint32(len(p.Data)) {
+		origLen = uint32(len(p.Data))
+	}
+	ts := uint64(p.Timestamp.UnixNano())
+
+	epb := new(bytes.Buffer)
+	pw.write(epb, uint32(0)) // interface ID
+	pw.write(epb, uint32(ts>>32))
+	pw.write(epb, uint32(ts))
+	pw.write(epb, uint32(len(p.Data)))
+	pw.write(epb, origLen)
+	epb.Write(p.Data)
+	epb.Write(make([]byte, padLen(len(p.Data))))
+	for _, comment := range p.Comments {
+		if len(comment) > math.MaxUint16 {
+			comment = comment[:math.MaxUint16]
+		}
+		pw.writeOption(epb, optComment, []byte(comment))
+	}
+	pw.writeOption(epb, optEndOfOpt, nil)
+
+	return pw.writeBlock(blockTypeEnhancedPacket, epb.Bytes())
+}
+
+func (pw *Writer) write(buf *bytes.Buffer, v interface{}) {
+	// Writes to a bytes.Buffer can't fail
+	binary.Write(buf, pw.order, v)
+}
+
+func (pw *Writer) writeOption(buf *bytes.Buffer, code uint16, value []byte) {
+	pw.write(buf, code)
+	pw.write(buf, uint16(len(value)))
+	buf.Write(value)
+	buf.Write(make([]byte, padLen(len(value))))
+}
+
+// writeBlock writes a block with the given type and body, which must be
+// padded to 32 bits
+func (pw *Writer) writeBlock(blockType uint32, body []byte) error {
+	totalLen := uint32(len(body) + 12)
+
+	buf := new(bytes.Buffer)
+	pw.write(buf, blockType)
+	pw.write(buf, totalLen)
+	buf.Write(body)
+	pw.write(buf, totalLen)
+
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("unable to write pcapng block: %s", err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@
 package monitor
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
@@ -31,6 +32,7 @@ const (
 	MessageTypeDebug
 	MessageTypeCapture
 	MessageTypeTrace
+	MessageTypePolicyVerdict
 
 	// 129-255 are reserved for agent level events
 
@@ -43,12 +45,13 @@ const (
 
 var (
 	names = map[string]int{
-		"drop":    MessageTypeDrop,
-		"debug":   MessageTypeDebug,
-		"capture": MessageTypeCapture,
-		"trace":   MessageTypeTrace,
-		"l7":      MessageTypeAccessLog,
-		"agent":   MessageTypeAgent,
+		"drop":           MessageTypeDrop,
+		"debug":          MessageTypeDebug,
+		"capture":        MessageTypeCapture,
+		"trace":          MessageTypeTrace,
+		"policy-verdict": MessageTypePolicyVerdict,
+		"l7":             MessageTypeAccessLog,
+		"agent":          MessageTypeAgent,
 	}
 )
 
@@ -99,6 +102,33 @@ func (m *MessageTypeFilter) Contains(typ int) bool {
 	return false
 }
 
+// MarshalJSON encodes the filter as a list of message type names
+func (m MessageTypeFilter) MarshalJSON() ([]byte, error) {
+	pieces := make([]string, 0, len(m))
+	for _, typ := range m {
+		pieces = append(pieces, type2name(typ))
+	}
+
+	return json.Marshal(pieces)
+}
+
+// UnmarshalJSON decodes a list of message type names
+func (m *MessageTypeFilter) UnmarshalJSON(data []byte) error {
+	var pieces []string
+	if err := json.Unmarshal(data, &pieces); err != nil {
+		return err
+	}
+
+	*m = MessageTypeFilter{}
+	for _, name := range pieces {
+		if err := m.Set(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetAllTypes returns a slice of all known message types, sorted
 func GetAllTypes() []string {
 	types := make([]string, len(names))
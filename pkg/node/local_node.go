@@ -35,6 +35,15 @@ func GetLocalNode() *Node {
 	return &localNode
 }
 
+// CopyLocalNode returns a copy of the local node which is not affected by
+// later changes of the local node
+func CopyLocalNode() *Node {
+	clusterConf.RLock()
+	defer clusterConf.RUnlock()
+	n := localNode
+	return &n
+}
+
 // ConfigureLocalNode configures the local node. This is called on agent
 // startup to configure the local node based on the configuration options
 // passed to the agent
@@ -59,9 +68,17 @@ func configureLocalNode() {
 		IPv4HealthIP:  GetIPv4HealthIP(),
 		IPv6HealthIP:  GetIPv6HealthIP(),
 		ClusterID:     option.Config.ClusterID,
+		EncryptionKey: GetEncryptionKey(),
 		Source:        FromAgentLocal,
 	}
 
+	if ip := GetInternalIPv4(); ip != nil {
+		localNode.IPAddresses = append(localNode.IPAddresses, Address{
+			AddressType: NodeCiliumInternalIP,
+			IP:          ip,
+		})
+	}
+
 	UpdateNode(&localNode, TunnelRoute, nil)
 
 	go func() {
@@ -87,7 +104,7 @@ func NotifyLocalNodeUpdated() {
 		controller.NewManager().UpdateController("propagating local node change to kv-store",
 			controller.ControllerParams{
 				DoFunc: func() error {
-					err := nodeStore.UpdateLocalKeySync(GetLocalNode())
+					err := nodeStore.UpdateLocalKeySync(CopyLocalNode())
 					if err != nil {
 						log.WithError(err).Error("Unable to propagate local node change to kvstore")
 					}
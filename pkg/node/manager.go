@@ -21,8 +21,12 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/cilium/cilium/pkg/datapath/ipsec"
 	routeUtils "github.com/cilium/cilium/pkg/datapath/route"
+	"github.com/cilium/cilium/pkg/datapath/routing"
 	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipcache"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/tunnel"
@@ -225,6 +229,157 @@ func updateTunnelMapping(n *Node, ip *net.IPNet) {
 	}
 }
 
+// upsertNodeIdentities inserts the IP addresses of a remote node into the
+// ipcache with the remote-node identity. The cilium_host IP of the node is
+// only reachable via the node IP and inserted with the node IP as host IP.
+// Addresses which were announced by the previous version of the node but are
+// no longer present are removed. The addresses of the local node are
+// maintained by the daemon and associated with the host identity instead.
+func upsertNodeIdentities(oldNode, n *Node) {
+	if n.IsLocal() {
+		return
+	}
+
+	source := ipcache.Source(n.Source)
+	for _, address := range n.IPAddresses {
+		var hostIP net.IP
+		if address.AddressType == NodeCiliumInternalIP {
+			hostIP = n.GetNodeIP(false)
+		}
+		ipcache.IPIdentityCache.Upsert(address.IP.String(), hostIP, ipcache.Identity{
+			ID:     identity.ReservedIdentityRemoteNode,
+			Source: source,
+		})
+	}
+
+	if oldNode == nil {
+		return
+	}
+
+	for _, oldAddress := range oldNode.IPAddresses {
+		found := false
+		for _, address := range n.IPAddresses {
+			if oldAddress.IP.Equal(address.IP) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			deleteNodeIdentity(oldNode, oldAddress.IP)
+		}
+	}
+}
+
+// deleteNodeIdentities removes all IP addresses of a remote node from the
+// ipcache.
+func deleteNodeIdentities(n *Node) {
+	if n.IsLocal() {
+		return
+	}
+
+	for _, address := range n.IPAddresses {
+		deleteNodeIdentity(n, address.IP)
+	}
+}
+
+// deleteNodeIdentity removes a node IP from the ipcache if it is still
+// associated with the remote-node identity and owned by the source of the
+// node.
+func deleteNodeIdentity(n *Node, ip net.IP) {
+	ipStr := ip.String()
+	id, exists := ipcache.IPIdentityCache.LookupByIP(ipStr)
+	if !exists || id.ID != identity.ReservedIdentityRemoteNode || id.Source != ipcache.Source(n.Source) {
+		return
+	}
+
+	ipcache.IPIdentityCache.Delete(ipStr)
+}
+
+// updateEncryption installs the IPsec configuration to encrypt the traffic
+// exchanged with the remote node n. Without tunneling, the traffic between
+// the IPv4 and the IPv6 allocation CIDRs of both nodes is encrypted. With
+// tunneling, the IPv6 traffic is encrypted as part of the tunnel traffic.
+func updateEncryption(n *Node) {
+	if !option.Config.EnableIPSec || n.IsLocal() {
+		return
+	}
+
+	peer := ipsec.Peer{
+		Name:     n.Fullname(),
+		LocalIP:  GetExternalIPv4(),
+		RemoteIP: n.GetNodeIP(false),
+		KeyIndex: n.EncryptionKey,
+	}
+	if option.Config.Tunnel != option.TunnelDisabled {
+		ipsec.UpsertPeer(peer)
+		return
+	}
+
+	if !option.Config.IPv4Disabled {
+		peer.LocalCIDR = GetIPv4AllocRange()
+		peer.RemoteCIDR = n.IPv4AllocCIDR
+		ipsec.UpsertPeer(peer)
+	}
+
+	ipsec.UpsertPeer(ipsec.Peer{
+		Name:       n.Fullname(),
+		LocalIP:    GetIPv6(),
+		RemoteIP:   n.GetNodeIP(true),
+		LocalCIDR:  GetIPv6AllocRange(),
+		RemoteCIDR: n.IPv6AllocCIDR,
+		KeyIndex:   n.EncryptionKey,
+		IPv6:       true,
+	})
+}
+
+// deleteEncryption removes the IPsec configuration of the remote node n
+func deleteEncryption(n *Node) {
+	if !option.Config.EnableIPSec || n.IsLocal() {
+		return
+	}
+
+	ipsec.DeletePeer(n.Fullname())
+}
+
+// updateRouting routes the allocation prefixes of node n when tunneling is
+// disabled. The prefixes of the local node are exported, the prefixes of
+// remote nodes are reached via direct routes if possible. IPv6 prefixes are
+// left to the auto-ipv6-node-routes option if enabled.
+func updateRouting(n *Node) {
+	if option.Config.Tunnel != option.TunnelDisabled {
+		return
+	}
+
+	rn := routing.Node{
+		Name:  n.Fullname(),
+		Local: n.IsLocal(),
+	}
+	if n.IPv4AllocCIDR != nil {
+		rn.Prefixes = append(rn.Prefixes, routing.Prefix{
+			CIDR:   n.IPv4AllocCIDR,
+			NodeIP: n.GetNodeIP(false),
+		})
+	}
+	if n.IPv6AllocCIDR != nil && (rn.Local || !option.Config.AutoIPv6NodeRoutes) {
+		rn.Prefixes = append(rn.Prefixes, routing.Prefix{
+			CIDR:   n.IPv6AllocCIDR,
+			NodeIP: n.GetNodeIP(true),
+		})
+	}
+
+	routing.UpsertNode(rn)
+}
+
+// deleteRouting removes the routes to the allocation prefixes of node n
+func deleteRouting(n *Node) {
+	if option.Config.Tunnel != option.TunnelDisabled || n.IsLocal() {
+		return
+	}
+
+	routing.DeleteNode(n.Fullname())
+}
+
 // UpdateNode updates the new node in the nodes' map with the given identity.
 // When using DirectRoute RouteType the field ownAddr should contain the IPv6
 // address of the interface that can reach the other nodes.
@@ -280,6 +435,10 @@ func UpdateNode(n *Node, routesTypes RouteType, ownAddr net.IP) {
 		updateIPRoute(oldNode, n, ownAddr)
 	}
 
+	upsertNodeIdentities(oldNode, n)
+	updateEncryption(n)
+	updateRouting(n)
+
 	clusterConf.nodes[ni] = n
 	clusterConf.replaceHostRoutes()
 }
@@ -308,6 +467,9 @@ func DeleteNode(ni Identity, routesTypes RouteType) {
 		if (routesTypes & DirectRoute) != 0 {
 			deleteIPRoute(n)
 		}
+		deleteNodeIdentities(n)
+		deleteEncryption(n)
+		deleteRouting(n)
 		delete(clusterConf.nodes, ni)
 		clusterConf.replaceHostRoutes()
 	}
@@ -19,6 +19,9 @@ package node
 import (
 	"net"
 
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipcache"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -45,3 +48,45 @@ func (s *NodeSuite) TestTunnelCIDRDeletionRequired(c *C) {
 	c.Assert(tunnelCIDRDeletionRequired(c1, c2), Equals, true)    // c1 -> c2
 	c.Assert(tunnelCIDRDeletionRequired(c2, nil), Equals, true)   // c2 -> disabled
 }
+
+func (s *NodeSuite) TestNodeIdentities(c *C) {
+	n1 := &Node{
+		Name:    "remote-node-1",
+		Cluster: "default",
+		IPAddresses: []Address{
+			{IP: net.ParseIP("10.0.0.1")},
+			{IP: net.ParseIP("10.0.0.2")},
+		},
+		Source: FromKubernetes,
+	}
+	upsertNodeIdentities(nil, n1)
+
+	id, exists := ipcache.IPIdentityCache.LookupByIP("10.0.0.1")
+	c.Assert(exists, Equals, true)
+	c.Assert(id.ID, Equals, identity.ReservedIdentityRemoteNode)
+	c.Assert(id.Source, Equals, ipcache.FromKubernetes)
+
+	// 10.0.0.2 is no longer announced by the node
+	n2 := &Node{
+		Name:    "remote-node-1",
+		Cluster: "default",
+		IPAddresses: []Address{
+			{IP: net.ParseIP("10.0.0.1")},
+			{IP: net.ParseIP("10.0.0.3")},
+		},
+		Source: FromKubernetes,
+	}
+	upsertNodeIdentities(n1, n2)
+
+	_, exists = ipcache.IPIdentityCache.LookupByIP("10.0.0.2")
+	c.Assert(exists, Equals, false)
+	id, exists = ipcache.IPIdentityCache.LookupByIP("10.0.0.3")
+	c.Assert(exists, Equals, true)
+	c.Assert(id.ID, Equals, identity.ReservedIdentityRemoteNode)
+
+	deleteNodeIdentities(n2)
+	_, exists = ipcache.IPIdentityCache.LookupByIP("10.0.0.1")
+	c.Assert(exists, Equals, false)
+	_, exists = ipcache.IPIdentityCache.LookupByIP("10.0.0.3")
+	c.Assert(exists, Equals, false)
+}
@@ -68,6 +68,10 @@ type Node struct {
 	// ClusterID is the unique identifier of the cluster
 	ClusterID int
 
+	// EncryptionKey is the index of the key the node uses to encrypt
+	// traffic, 0 if encryption is disabled
+	EncryptionKey uint8
+
 	// cluster membership
 	cluster *clusterConfiguation
 
@@ -85,6 +89,11 @@ func (n *Node) Fullname() string {
 	return n.Name
 }
 
+// NodeCiliumInternalIP is the address type of the IP of the cilium_host
+// interface of a node. The address is allocated out of the allocation CIDR of
+// the node and is only reachable via the Cilium network.
+const NodeCiliumInternalIP v1.NodeAddressType = "CiliumInternalIP"
+
 // Address is a node address which contains an IP and the address type.
 type Address struct {
 	AddressType v1.NodeAddressType
@@ -110,6 +119,9 @@ func (n *Node) getNodeIP(ipv6 bool) (net.IP, v1.NodeAddressType) {
 			// if no internal IP could be found
 			backupIP = addr.IP
 			ipType = addr.AddressType
+		case NodeCiliumInternalIP:
+			// Only reachable via the Cilium network itself
+			continue
 		default:
 			// As a last resort, if no internal or external
 			// IP was found, use any node address available
@@ -262,6 +274,7 @@ func (n *Node) PublicAttrEquals(o *Node) bool {
 		n.IPv4HealthIP.Equal(o.IPv4HealthIP) &&
 		n.IPv6HealthIP.Equal(o.IPv6HealthIP) &&
 		n.ClusterID == o.ClusterID &&
+		n.EncryptionKey == o.EncryptionKey &&
 		n.Source == o.Source {
 
 		if len(n.IPAddresses) != len(o.IPAddresses) {
@@ -45,6 +45,7 @@ var (
 	ipv6AllocRange      *net.IPNet
 	ipv4HealthAddress   net.IP
 	ipv6HealthAddress   net.IP
+	encryptionKey       uint8
 )
 
 func makeIPv6HostIP() net.IP {
@@ -376,6 +377,25 @@ func SetIPv6HealthIP(ip net.IP) error {
 	return nil
 }
 
+// GetEncryptionKey returns the index of the key used to encrypt traffic to
+// the local node, 0 if encryption is disabled
+func GetEncryptionKey() uint8 {
+	clusterConf.RLock()
+	defer clusterConf.RUnlock()
+	return encryptionKey
+}
+
+// SetEncryptionKey sets the index of the key used to encrypt traffic to the
+// local node
+func SetEncryptionKey(key uint8) {
+	// The local node is part of the cluster configuration and is read by
+	// the node manager while holding its lock
+	clusterConf.Lock()
+	defer clusterConf.Unlock()
+	encryptionKey = key
+	localNode.EncryptionKey = key
+}
+
 // GetIPv6NodeRoute returns a route pointing to the IPv6 node address
 func GetIPv6NodeRoute() net.IPNet {
 	return net.IPNet{
@@ -59,6 +59,14 @@ const (
 	// AutoIPv6NodeRoutesName is the name of the AutoIPv6NodeRoutes option
 	AutoIPv6NodeRoutesName = "auto-ipv6-node-routes"
 
+	// AccessLogSinkName is the name of the option specifying the URLs of
+	// the sinks proxy access log records are exported to
+	AccessLogSinkName = "access-log-sink"
+
+	// AccessLogSinkBufferSizeName is the name of the option specifying the
+	// number of access log records buffered per sink
+	AccessLogSinkBufferSizeName = "access-log-sink-buffer-size"
+
 	// MTUName is the name of the MTU option
 	MTUName = "mtu"
 
@@ -80,6 +88,35 @@ const (
 	// comandline.
 	MonitorAggregationName = "monitor-aggregation"
 
+	// MonitorHistorySizeName is the name of the option specifying the
+	// number of flow events recorded by the node monitor
+	MonitorHistorySizeName = "monitor-history-size"
+
+	// MonitorRemoteListenAddressName is the name of the option specifying
+	// the TCP address the node monitor serves events to remote listeners on
+	MonitorRemoteListenAddressName = "monitor-remote-listen-address"
+
+	// MonitorRemoteTLSCertName is the name of the option specifying the TLS
+	// certificate served to remote monitor listeners
+	MonitorRemoteTLSCertName = "monitor-remote-tls-cert"
+
+	// MonitorRemoteTLSKeyName is the name of the option specifying the key
+	// of the TLS certificate served to remote monitor listeners
+	MonitorRemoteTLSKeyName = "monitor-remote-tls-key"
+
+	// MonitorRemoteTLSClientCAName is the name of the option specifying the
+	// CA bundle client certificates of remote monitor listeners are
+	// verified with
+	MonitorRemoteTLSClientCAName = "monitor-remote-tls-client-ca"
+
+	// MonitorRemoteInsecureName is the name of the option serving remote
+	// monitor listeners without TLS
+	MonitorRemoteInsecureName = "monitor-remote-insecure"
+
+	// PolicyAuditModeArg argument enables policy audit mode for all
+	// endpoints
+	PolicyAuditModeArg = "policy-audit-mode"
+
 	// ClusterName is the name of the ClusterName option
 	ClusterName = "cluster-name"
 
@@ -111,6 +148,80 @@ const (
 	// the ClusterMeshConfig option
 	ClusterMeshConfigNameEnv = "CILIUM_CLUSTERMESH_CONFIG"
 
+	// ClusterMeshConfigSecretName is the name of the
+	// ClusterMeshConfigSecret option
+	ClusterMeshConfigSecretName = "clustermesh-config-secret"
+
+	// ClusterMeshConfigSecretNameEnv is the name of the environment
+	// variable of the ClusterMeshConfigSecret option
+	ClusterMeshConfigSecretNameEnv = "CILIUM_CLUSTERMESH_CONFIG_SECRET"
+
+	// EnableIPSecName is the name of the option to enable IPsec
+	// encryption of the traffic between nodes
+	EnableIPSecName = "enable-ipsec"
+
+	// EnableIPSecNameEnv is the name of the environment variable of the
+	// EnableIPSec option
+	EnableIPSecNameEnv = "CILIUM_ENABLE_IPSEC"
+
+	// IPSecKeyFileName is the name of the option pointing to the file
+	// holding the IPsec key
+	IPSecKeyFileName = "ipsec-key-file"
+
+	// IPSecKeyFileNameEnv is the name of the environment variable of the
+	// IPSecKeyFile option
+	IPSecKeyFileNameEnv = "CILIUM_IPSEC_KEY_FILE"
+
+	// IPSecKeySecretName is the name of the option referring to the
+	// Kubernetes secret holding the IPsec key
+	IPSecKeySecretName = "ipsec-key-secret"
+
+	// IPSecKeySecretNameEnv is the name of the environment variable of the
+	// IPSecKeySecret option
+	IPSecKeySecretNameEnv = "CILIUM_IPSEC_KEY_SECRET"
+
+	// AutoDirectNodeRoutesName is the name of the option to install
+	// routes to the allocation prefixes of remote nodes sharing an L2
+	// segment with the local node
+	AutoDirectNodeRoutesName = "auto-direct-node-routes"
+
+	// AutoDirectNodeRoutesNameEnv is the name of the environment variable
+	// of the AutoDirectNodeRoutes option
+	AutoDirectNodeRoutesNameEnv = "CILIUM_AUTO_DIRECT_NODE_ROUTES"
+
+	// RouteExporterName is the name of the option selecting the exporter
+	// announcing the allocation prefixes of the local node
+	RouteExporterName = "route-exporter"
+
+	// RouteExporterNameEnv is the name of the environment variable of the
+	// RouteExporter option
+	RouteExporterNameEnv = "CILIUM_ROUTE_EXPORTER"
+
+	// RouteExportTableName is the name of the option selecting the kernel
+	// routing table used by the kernel-table route exporter
+	RouteExportTableName = "route-export-table"
+
+	// RouteExportTableNameEnv is the name of the environment variable of
+	// the RouteExportTable option
+	RouteExportTableNameEnv = "CILIUM_ROUTE_EXPORT_TABLE"
+
+	// EnableEgressNATName is the name of the option to enable steering of
+	// traffic selected by CiliumEgressNATPolicy resources towards an
+	// egress NAT gateway node
+	EnableEgressNATName = "enable-egress-nat"
+
+	// EnableEgressNATNameEnv is the name of the environment variable of
+	// the EnableEgressNAT option
+	EnableEgressNATNameEnv = "CILIUM_ENABLE_EGRESS_NAT"
+
+	// IdentityAllocationModeName is the name of the option selecting the
+	// backend used to allocate security identities
+	IdentityAllocationModeName = "identity-allocation-mode"
+
+	// IdentityAllocationModeNameEnv is the name of the environment
+	// variable of the IdentityAllocationMode option
+	IdentityAllocationModeNameEnv = "CILIUM_IDENTITY_ALLOCATION_MODE"
+
 	// BPFCompileDebugName is the name of the option to enable BPF compiliation debugging
 	BPFCompileDebugName = "bpf-compile-debug"
 
@@ -146,6 +257,10 @@ const (
 
 	// SockopsEnableName is the name of the option to enable sockops
 	SockopsEnableName = "sockops-enable"
+
+	// ToGroupsGenericSourceName is the name of the ToGroupsGenericSource
+	// option
+	ToGroupsGenericSourceName = "togroups-generic-source"
 )
 
 // Available option for daemonConfig.Tunnel
@@ -160,6 +275,16 @@ const (
 	TunnelDisabled = "disabled"
 )
 
+// Available options for daemonConfig.IdentityAllocationMode
+const (
+	// IdentityAllocationModeKVstore stores identities in the kvstore
+	IdentityAllocationModeKVstore = "kvstore"
+
+	// IdentityAllocationModeCRD stores identities as CiliumIdentity
+	// custom resources
+	IdentityAllocationModeCRD = "crd"
+)
+
 // GetTunnelModes returns the list of all tunnel modes
 func GetTunnelModes() string {
 	return fmt.Sprintf("%s, %s, %s", TunnelVXLAN, TunnelGeneve, TunnelDisabled)
@@ -217,6 +342,14 @@ type daemonConfig struct {
 	// AccessLog is the path to the access log of supported L7 requests observed.
 	AccessLog string
 
+	// AccessLogSinks are the URLs of the sinks proxy access log records are
+	// exported to, see pkg/proxy/logger/sink.NewSink.
+	AccessLogSinks []string
+
+	// AccessLogSinkBufferSize is the number of access log records buffered
+	// per sink before records are dropped.
+	AccessLogSinkBufferSize int
+
 	// AgentLabels contains additional labels to identify this agent in monitor events.
 	AgentLabels []string
 
@@ -257,6 +390,41 @@ type daemonConfig struct {
 	// ClusterMeshConfig is the path to the clustermesh configuration directory
 	ClusterMeshConfig string
 
+	// ClusterMeshConfigSecret is the namespace/name of the Kubernetes
+	// secret holding the clustermesh configuration
+	ClusterMeshConfigSecret string
+
+	// EnableIPSec enables IPsec encryption of the traffic between nodes
+	EnableIPSec bool
+
+	// IPSecKeyFile is the path to the file holding the IPsec key
+	IPSecKeyFile string
+
+	// IPSecKeySecret is the namespace/name of the Kubernetes secret
+	// holding the IPsec key
+	IPSecKeySecret string
+
+	// AutoDirectNodeRoutes enables the installation of routes to the
+	// allocation prefixes of remote nodes sharing an L2 segment with the
+	// local node
+	AutoDirectNodeRoutes bool
+
+	// RouteExporter is the name of the exporter announcing the allocation
+	// prefixes of the local node, empty if disabled
+	RouteExporter string
+
+	// RouteExportTable is the kernel routing table used by the
+	// kernel-table route exporter
+	RouteExportTable int
+
+	// EnableEgressNAT enables steering of traffic selected by
+	// CiliumEgressNATPolicy resources towards an egress NAT gateway node
+	EnableEgressNAT bool
+
+	// IdentityAllocationMode is the backend used to allocate security
+	// identities { kvstore | crd }
+	IdentityAllocationMode string
+
 	// CTMapEntriesGlobalTCP is the maximum number of conntrack entries
 	// allowed in each TCP CT table for IPv4/IPv6.
 	CTMapEntriesGlobalTCP int
@@ -275,6 +443,35 @@ type daemonConfig struct {
 	// UseSingleClusterRoute specifies whether to use a single cluster route
 	// instead of per-node routes.
 	UseSingleClusterRoute bool
+
+	// ToGroupsGenericSource is the file path or http(s):// URL of the
+	// document listing the group members resolved by the generic ToGroups
+	// provider. The generic provider is disabled if empty.
+	ToGroupsGenericSource string
+
+	// MonitorHistorySize is the number of flow events recorded by the node
+	// monitor for later retrieval. Zero disables recording.
+	MonitorHistorySize int
+
+	// MonitorRemoteListenAddress is the TCP address the node monitor serves
+	// events over HTTPS to remote listeners on. Empty disables remote
+	// listeners.
+	MonitorRemoteListenAddress string
+
+	// MonitorRemoteTLSCert and MonitorRemoteTLSKey are the paths to the TLS
+	// certificate and key served to remote monitor listeners. They are
+	// required unless MonitorRemoteInsecure is set.
+	MonitorRemoteTLSCert string
+	MonitorRemoteTLSKey  string
+
+	// MonitorRemoteTLSClientCA is the path to the CA bundle client
+	// certificates of remote monitor listeners are verified with. Client
+	// certificates are not required if empty.
+	MonitorRemoteTLSClientCA string
+
+	// MonitorRemoteInsecure serves remote monitor listeners over plain
+	// HTTP
+	MonitorRemoteInsecure bool
 }
 
 var (
@@ -361,6 +558,33 @@ func (c *daemonConfig) Validate() error {
 		return fmt.Errorf("invalid tunnel mode '%s', valid modes = {%s}", c.Tunnel, GetTunnelModes())
 	}
 
+	if c.EnableIPSec && c.IPSecKeyFile == "" && c.IPSecKeySecret == "" {
+		return fmt.Errorf("option --%s requires either --%s or --%s",
+			EnableIPSecName, IPSecKeyFileName, IPSecKeySecretName)
+	}
+
+	if c.Tunnel != TunnelDisabled {
+		if c.AutoDirectNodeRoutes {
+			return fmt.Errorf("option --%s requires --%s=%s",
+				AutoDirectNodeRoutesName, TunnelName, TunnelDisabled)
+		}
+		if c.RouteExporter != "" {
+			return fmt.Errorf("option --%s requires --%s=%s",
+				RouteExporterName, TunnelName, TunnelDisabled)
+		}
+	}
+
+	if c.EnableEgressNAT {
+		if c.Tunnel == TunnelDisabled {
+			return fmt.Errorf("option --%s requires tunneling to be enabled",
+				EnableEgressNATName)
+		}
+		if c.IPv4Disabled {
+			return fmt.Errorf("option --%s requires IPv4 to be enabled",
+				EnableEgressNATName)
+		}
+	}
+
 	if c.ClusterID < ClusterIDMin || c.ClusterID > ClusterIDMax {
 		return fmt.Errorf("invalid cluster id %d: must be in range %d..%d",
 			c.ClusterID, ClusterIDMin, ClusterIDMax)
@@ -17,6 +17,8 @@
 package option
 
 import (
+	"github.com/cilium/cilium/pkg/defaults"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -41,3 +43,26 @@ func (s *OptionSuite) TestValidateIPv6ClusterAllocCIDR(c *C) {
 	invalid4 := &daemonConfig{}
 	c.Assert(invalid4.validateIPv6ClusterAllocCIDR(), Not(IsNil))
 }
+
+func (s *OptionSuite) TestValidateEgressNAT(c *C) {
+	newConfig := func(tunnel string) *daemonConfig {
+		return &daemonConfig{
+			IPv6ClusterAllocCIDR:  defaults.IPv6ClusterAllocCIDR,
+			MTU:                   1500,
+			Tunnel:                tunnel,
+			CTMapEntriesGlobalTCP: CTMapEntriesGlobalTCPDefault,
+			CTMapEntriesGlobalAny: CTMapEntriesGlobalAnyDefault,
+			EnableEgressNAT:       true,
+		}
+	}
+
+	c.Assert(newConfig(TunnelVXLAN).Validate(), IsNil)
+	c.Assert(newConfig(TunnelGeneve).Validate(), IsNil)
+
+	// Traffic can only be steered to the gateway node through the tunnel
+	c.Assert(newConfig(TunnelDisabled).Validate(), Not(IsNil))
+
+	noIPv4 := newConfig(TunnelVXLAN)
+	noIPv4.IPv4Disabled = true
+	c.Assert(noIPv4.Validate(), Not(IsNil))
+}
@@ -33,8 +33,10 @@ var (
 		DebugLB:             &specDebugLB,
 		DropNotify:          &specDropNotify,
 		TraceNotify:         &specTraceNotify,
+		PolicyVerdictNotify: &specPolicyVerdictNotify,
 		MonitorAggregation:  &specMonitorAggregation,
 		NAT46:               &specNAT46,
+		PolicyAuditMode:     &specPolicyAuditMode,
 	}
 )
 
@@ -27,8 +27,10 @@ const (
 	DebugLB             = "DebugLB"
 	DropNotify          = "DropNotification"
 	TraceNotify         = "TraceNotification"
+	PolicyVerdictNotify = "PolicyVerdictNotification"
 	MonitorAggregation  = "MonitorAggregationLevel"
 	NAT46               = "NAT46"
+	PolicyAuditMode     = "PolicyAuditMode"
 	AlwaysEnforce       = "always"
 	NeverEnforce        = "never"
 	DefaultEnforcement  = "default"
@@ -77,6 +79,11 @@ var (
 		Description: "Enable trace notifications",
 	}
 
+	specPolicyVerdictNotify = Option{
+		Define:      "POLICY_VERDICT_NOTIFY",
+		Description: "Enable policy verdict notifications",
+	}
+
 	specMonitorAggregation = Option{
 		Define:      "MONITOR_AGGREGATION",
 		Description: "Set the level of aggregation for monitor events in the datapath",
@@ -97,6 +104,11 @@ var (
 		},
 	}
 
+	specPolicyAuditMode = Option{
+		Define:      "POLICY_AUDIT_MODE",
+		Description: "Enable policy audit (non-drop) mode",
+	}
+
 	IngressSpecPolicy = Option{
 		Define:      "POLICY_INGRESS",
 		Description: "Enable ingress policy enforcement",
@@ -132,6 +132,22 @@ type EgressRule struct {
 	//
 	// +optional
 	ToFQDNs []FQDNSelector `json:"toFQDNs,omitempty"`
+
+	// ToGroups allows whitelisting the members of groups maintained outside
+	// of Cilium, such as cloud provider security groups. The members of each
+	// group are periodically resolved to IPs by the group's provider and the
+	// IPs are added to the same EgressRule object as ToCIDRSet entries, and
+	// behave accordingly. Any L4 and L7 rules within this EgressRule will
+	// also apply to these IPs.
+	// Note: ToGroups cannot occur in the same policy as other To* rules.
+	//
+	// Example:
+	// Any endpoint with the label "app=backend" is allowed to initiate
+	// connections to all VMs which are members of the security group
+	// "sg-0123" as resolved by the "generic" provider.
+	//
+	// +optional
+	ToGroups []GroupSelector `json:"toGroups,omitempty"`
 }
 
 // GetDestinationEndpointSelectors returns a slice of endpoints selectors
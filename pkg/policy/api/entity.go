@@ -41,6 +41,13 @@ const (
 
 	// EntityInit is an entity that represents an initializing endpoint
 	EntityInit Entity = "init"
+
+	// EntityRemoteNode is an entity that represents all remote nodes
+	EntityRemoteNode Entity = "remote-node"
+
+	// EntityKubeAPIServer is an entity that represents the IPs backing
+	// the Kubernetes API server
+	EntityKubeAPIServer Entity = "kube-apiserver"
 )
 
 var (
@@ -62,6 +69,18 @@ var (
 		Source: labels.LabelSourceReserved,
 	})
 
+	endpointSelectorRemoteNode = NewESFromLabels(&labels.Label{
+		Key:    labels.IDNameRemoteNode,
+		Value:  "",
+		Source: labels.LabelSourceReserved,
+	})
+
+	endpointSelectorKubeAPIServer = NewESFromLabels(&labels.Label{
+		Key:    labels.IDNameKubeAPIServer,
+		Value:  "",
+		Source: labels.LabelSourceReserved,
+	})
+
 	endpointSelectorUnmanaged = NewESFromLabels(&labels.Label{
 		Key:    labels.IDNameUnmanaged,
 		Value:  "",
@@ -71,10 +90,12 @@ var (
 	// EntitySelectorMapping maps special entity names that come in
 	// policies to selectors
 	EntitySelectorMapping = map[Entity]EndpointSelectorSlice{
-		EntityAll:   {WildcardEndpointSelector},
-		EntityWorld: {endpointSelectorWorld},
-		EntityHost:  {endpointSelectorHost},
-		EntityInit:  {endpointSelectorInit},
+		EntityAll:           {WildcardEndpointSelector},
+		EntityWorld:         {endpointSelectorWorld},
+		EntityHost:          {endpointSelectorHost},
+		EntityInit:          {endpointSelectorInit},
+		EntityRemoteNode:    {endpointSelectorRemoteNode},
+		EntityKubeAPIServer: {endpointSelectorKubeAPIServer},
 
 		// EntityCluster is populated with an empty entry to allow the
 		// cilium client importing this package to perform basic rule
@@ -128,6 +149,8 @@ func (s EntitySlice) GetAsEndpointSelectors() EndpointSelectorSlice {
 func InitEntities(clusterName string) {
 	EntitySelectorMapping[EntityCluster] = EndpointSelectorSlice{
 		endpointSelectorHost,
+		endpointSelectorRemoteNode,
+		endpointSelectorKubeAPIServer,
 		endpointSelectorInit,
 		endpointSelectorUnmanaged,
 		NewESFromLabels(&labels.Label{
@@ -39,6 +39,8 @@ func (s *PolicyAPITestSuite) TestEntityMatches(c *C) {
 
 	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:host")), Equals, true)
 	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:init")), Equals, true)
+	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:remote-node")), Equals, true)
+	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:kube-apiserver")), Equals, true)
 	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:world")), Equals, false)
 
 	clusterLabel := fmt.Sprintf("k8s:%s=%s", k8sapi.PolicyLabelCluster, "cluster1")
@@ -50,6 +52,14 @@ func (s *PolicyAPITestSuite) TestEntityMatches(c *C) {
 	c.Assert(EntityWorld.Matches(labels.ParseLabelArray("reserved:world")), Equals, true)
 	c.Assert(EntityWorld.Matches(labels.ParseLabelArray("id=foo")), Equals, false)
 	c.Assert(EntityWorld.Matches(labels.ParseLabelArray("id=foo", "id=bar")), Equals, false)
+
+	c.Assert(EntityRemoteNode.Matches(labels.ParseLabelArray("reserved:remote-node")), Equals, true)
+	c.Assert(EntityRemoteNode.Matches(labels.ParseLabelArray("reserved:host")), Equals, false)
+	c.Assert(EntityRemoteNode.Matches(labels.ParseLabelArray("id=foo")), Equals, false)
+
+	c.Assert(EntityKubeAPIServer.Matches(labels.ParseLabelArray("reserved:kube-apiserver")), Equals, true)
+	c.Assert(EntityKubeAPIServer.Matches(labels.ParseLabelArray("reserved:remote-node")), Equals, false)
+	c.Assert(EntityKubeAPIServer.Matches(labels.ParseLabelArray("reserved:world")), Equals, false)
 }
 
 func (s *PolicyAPITestSuite) TestEntitySliceMatches(c *C) {
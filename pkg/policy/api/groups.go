@@ -0,0 +1,48 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+)
+
+// GroupSelector selects the members of a group which is maintained outside
+// of Cilium, e.g. a cloud provider security group. The members of the group
+// are resolved to IP addresses by the named provider.
+type GroupSelector struct {
+	// Provider is the name of the provider responsible for resolving the
+	// members of the group, e.g. "generic".
+	Provider string `json:"provider"`
+
+	// Name is the name of the group as known to the provider.
+	Name string `json:"name"`
+}
+
+// String returns the string representation of the group selector
+func (g GroupSelector) String() string {
+	return g.Provider + "/" + g.Name
+}
+
+func (g *GroupSelector) sanitize() error {
+	if g.Provider == "" {
+		return fmt.Errorf("group %q has no provider", g.Name)
+	}
+
+	if g.Name == "" {
+		return fmt.Errorf("group of provider %q has no name", g.Provider)
+	}
+
+	return nil
+}
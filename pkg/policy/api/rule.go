@@ -60,4 +60,13 @@ type Rule struct {
 	//
 	// +optional
 	Description string `json:"description,omitempty"`
+
+	// Audit marks the rule as an audit rule. Endpoints which are only
+	// selected by audit rules have their policy computed as usual, but
+	// packets which would be dropped by policy are allowed instead and
+	// accounted in the policy_audit_total metric. The first packet of each
+	// audited flow is reported as an audited policy verdict notification.
+	//
+	// +optional
+	Audit bool `json:"audit,omitempty"`
 }
@@ -154,6 +154,7 @@ func (e *EgressRule) sanitize() error {
 		"ToEntities":  len(e.ToEntities),
 		"ToServices":  len(e.ToServices),
 		"ToFQDNs":     len(e.ToFQDNs),
+		"ToGroups":    len(e.ToGroups),
 	}
 	l3DependentL4Support := map[interface{}]bool{
 		"ToCIDR":      true,
@@ -162,6 +163,7 @@ func (e *EgressRule) sanitize() error {
 		"ToEntities":  true,
 		"ToServices":  true,
 		"ToFQDNs":     true,
+		"ToGroups":    true,
 	}
 	for m1 := range l3Members {
 		for m2 := range l3Members {
@@ -217,6 +219,12 @@ func (e *EgressRule) sanitize() error {
 		}
 	}
 
+	for _, toGroup := range e.ToGroups {
+		if err := toGroup.sanitize(); err != nil {
+			return err
+		}
+	}
+
 	// FIXME GH-1781 count coalesced CIDRs and restrict the number of
 	// prefix lengths based on the CIDRSet exclusions.
 	if l := len(prefixLengths); l > MaxCIDRPrefixLengths {
@@ -276,6 +276,48 @@ func (s *PolicyAPITestSuite) TestToServicesSanitize(c *C) {
 
 }
 
+func (s *PolicyAPITestSuite) TestToGroupsSanitize(c *C) {
+	toGroupsL3L4 := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress: []EgressRule{
+			{
+				ToGroups: []GroupSelector{
+					{Provider: "generic", Name: "sg-0123"},
+				},
+				ToPorts: []PortRule{{
+					Ports: []PortProtocol{
+						{Port: "5432", Protocol: ProtoTCP},
+					},
+				}},
+			},
+		},
+	}
+	c.Assert(toGroupsL3L4.Sanitize(), IsNil)
+
+	// Groups must name their provider
+	toGroupsNoProvider := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress: []EgressRule{
+			{
+				ToGroups: []GroupSelector{{Name: "sg-0123"}},
+			},
+		},
+	}
+	c.Assert(toGroupsNoProvider.Sanitize(), Not(IsNil))
+
+	// ToGroups cannot be combined with other L3 selectors
+	toGroupsAndCIDR := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress: []EgressRule{
+			{
+				ToGroups: []GroupSelector{{Provider: "generic", Name: "sg-0123"}},
+				ToCIDR:   []CIDR{"10.0.0.0/8"},
+			},
+		},
+	}
+	c.Assert(toGroupsAndCIDR.Sanitize(), Not(IsNil))
+}
+
 // This test ensures that PortRules using key-value pairs do not have empty keys
 func (s *PolicyAPITestSuite) TestL7Rules(c *C) {
 
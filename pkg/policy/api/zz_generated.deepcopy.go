@@ -139,6 +139,11 @@ func (in *EgressRule) DeepCopyInto(out *EgressRule) {
 		*out = make([]FQDNSelector, len(*in))
 		copy(*out, *in)
 	}
+	if in.ToGroups != nil {
+		in, out := &in.ToGroups, &out.ToGroups
+		*out = make([]GroupSelector, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -242,6 +247,22 @@ func (in *FQDNSelector) DeepCopy() *FQDNSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSelector) DeepCopyInto(out *GroupSelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSelector.
+func (in *GroupSelector) DeepCopy() *GroupSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressRule) DeepCopyInto(out *IngressRule) {
 	*out = *in
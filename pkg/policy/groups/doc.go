@@ -0,0 +1,29 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package groups handles policy enforcement towards groups of IPs maintained
+// outside of Cilium, e.g. cloud provider security groups. This is expressed
+// via ToGroups rules. The members of each group are resolved to IPs by a
+// pluggable Provider, registered under a name via RegisterProvider.
+//
+// Note: We add a ToGroups-UUID label to rules when we process a ToGroups
+// section. This has the source cilium-generated and should not be modified
+// outside pkg/policy/groups
+//
+// The poller will update imported policy rules that contain ToGroups sections
+// with matching ToCIDRSet sections (in the same egress rule, thus inheriting
+// the same L4/L7 policy) in the same way pkg/fqdn derives ToCIDRSet sections
+// from ToFQDNs sections. Each CIDR is a fully qualified IP (i.e. a /32 or
+// /128).
+package groups
@@ -0,0 +1,30 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groups
+
+import (
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/policy/rulegen"
+)
+
+// getGroups returns the ToGroups targets of the egress rule to be resolved
+// by the poller.
+func getGroups(egressRule *api.EgressRule) (groups []rulegen.Target) {
+	for _, group := range egressRule.ToGroups {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
@@ -0,0 +1,173 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groups
+
+import (
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/policy/rulegen"
+)
+
+const (
+	// generatedLabelNameUUID is the label key for policy rules that contain a
+	// ToGroups section and need to be updated
+	generatedLabelNameUUID = "ToGroups-UUID"
+
+	// GroupsPollerInterval is the time between 2 complete lookup runs of the
+	// GroupsPoller controller
+	GroupsPollerInterval = 30 * time.Second
+)
+
+// uuidLabelSearchKey is an *extended* label key. This is because .Has
+// expects the source:key delimiter to be the labels.PathDelimiter
+var uuidLabelSearchKey = rulegen.UUIDLabelSearchKey(generatedLabelNameUUID)
+
+// StartGroupsPoller spawns a singleton group polling controller. The
+// controller will, periodically, resolve the members of each group inserted
+// with StartPollForGroups.
+// Note: Repeated calls will replace earlier instances of the controller.
+func StartGroupsPoller(poller *GroupsPoller) {
+	log.Debug("Starting groups poller for ToGroups rules")
+	controller.NewManager().UpdateController("togroups-poller", controller.ControllerParams{
+		RunInterval: poller.config.Interval,
+		DoFunc:      poller.LookupUpdateGroups,
+		StopFunc: func() error {
+			log.Debug("Stopping groups poller for ToGroups rules")
+			return nil
+		},
+	})
+}
+
+// GroupsPoller periodically resolves the members of registered groups. It
+// will emit regenerated policy rules when the IPs change.
+// The rules are tracked by the embedded rulegen.Poller, its targets are the
+// ToGroups selectors.
+type GroupsPoller struct {
+	*rulegen.Poller
+
+	// config is a copy from when this instance was initialized.
+	// It is read-only once set
+	config GroupsPollerConfig
+}
+
+// GroupsPollerConfig is a simple configuration structure to set how
+// GroupsPoller resolves groups and emits generated policy rules via the
+// AddGeneratedRules callback.
+type GroupsPollerConfig struct {
+	// Interval is the time between two lookup runs.
+	// When set to 0, GroupsPollerInterval is used.
+	Interval time.Duration
+
+	// LookupGroups is a callback to resolve the provided groups.
+	// When set to nil, LookupGroupsWithProviders is used.
+	LookupGroups func(groups []api.GroupSelector) (groupIPs map[api.GroupSelector][]net.IP, groupErrors map[api.GroupSelector]error)
+
+	// AddGeneratedRules is a callback to emit generated rules.
+	// When set to nil, it is a no-op.
+	AddGeneratedRules func([]*api.Rule) error
+}
+
+// NewGroupsPoller creates an initialized GroupsPoller. It does not start the
+// controller (use StartGroupsPoller)
+func NewGroupsPoller(config GroupsPollerConfig) *GroupsPoller {
+	if config.Interval == 0 {
+		config.Interval = GroupsPollerInterval
+	}
+
+	if config.LookupGroups == nil {
+		config.LookupGroups = LookupGroupsWithProviders
+	}
+
+	if config.AddGeneratedRules == nil {
+		config.AddGeneratedRules = func(generatedRules []*api.Rule) error { return nil }
+	}
+
+	poller := &GroupsPoller{config: config}
+	poller.Poller = rulegen.NewPoller(rulegen.PollerConfig{
+		LabelKey:          generatedLabelNameUUID,
+		Targets:           getGroups,
+		Lookup:            poller.lookupGroups,
+		AddGeneratedRules: config.AddGeneratedRules,
+		Log:               log,
+	})
+
+	return poller
+}
+
+// MarkToGroupsRules adds a tracking label to the rule, if it contains
+// ToGroups rules. The label is used to ensure that the ToGroups rules are
+// replaced correctly when they are regenerated with IPs. It will also include
+// the generated ToCIDRSet section for IPs that are already known.
+// NOTE: It edits the rules in-place
+func (poller *GroupsPoller) MarkToGroupsRules(sourceRules []*api.Rule) {
+	poller.MarkRules(sourceRules)
+}
+
+// StartPollForGroups sets up the polling for ToGroups rules in each
+// api.Rule. It only adds rules with the ToGroups-UUID label, added by
+// MarkToGroupsRules, and repeat inserts are effectively no-ops.
+func (poller *GroupsPoller) StartPollForGroups(sourceRules []*api.Rule) {
+	poller.StartPoll(sourceRules)
+}
+
+// StopPollForGroups runs the bookkeeping to remove each api.Rule from the
+// corresponding group entries. When no more rules rely on a specific group,
+// it is no longer polled for.
+func (poller *GroupsPoller) StopPollForGroups(sourceRules []*api.Rule) {
+	poller.StopPoll(sourceRules)
+}
+
+// LookupUpdateGroups resolves each stored group, stores the updates and then
+// emits regenerated policy rules for all rules depending on groups whose
+// members have changed.
+func (poller *GroupsPoller) LookupUpdateGroups() error {
+	return poller.LookupUpdate()
+}
+
+// GetGroups returns a snapshot of the groups in GroupsPoller
+func (poller *GroupsPoller) GetGroups() (groups []api.GroupSelector) {
+	for _, target := range poller.GetTargets() {
+		groups = append(groups, target.(api.GroupSelector))
+	}
+
+	return groups
+}
+
+// lookupGroups resolves the members of each group in targets via
+// LookupGroups. It returns the sorted IPs of each group that could be
+// resolved. Groups with failures are not returned, and keep their most
+// recent IPs.
+func (poller *GroupsPoller) lookupGroups(targets []rulegen.Target) map[rulegen.Target][]net.IP {
+	groups := make([]api.GroupSelector, 0, len(targets))
+	for _, target := range targets {
+		groups = append(groups, target.(api.GroupSelector))
+	}
+
+	groupIPs, groupErrors := poller.config.LookupGroups(groups)
+	for group, err := range groupErrors {
+		log.WithError(err).WithField("group", group.String()).
+			Warn("Cannot resolve group members. Traffic egressing to this group may be incorrectly dropped due to stale data.")
+	}
+
+	updatedIPs := make(map[rulegen.Target][]net.IP, len(groupIPs))
+	for group, ips := range groupIPs {
+		updatedIPs[group] = rulegen.SortIPs(ips)
+	}
+
+	return updatedIPs
+}
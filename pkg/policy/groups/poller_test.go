@@ -0,0 +1,129 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package groups
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+var (
+	groupA = api.GroupSelector{Provider: "test", Name: "a"}
+	groupB = api.GroupSelector{Provider: "test", Name: "b"}
+)
+
+func makeRule(key string, groups ...api.GroupSelector) *api.Rule {
+	rule := &api.Rule{
+		EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("class=xwing")),
+		Egress: []api.EgressRule{
+			{ToGroups: groups},
+		},
+	}
+	if key != "" {
+		rule.Labels = labels.ParseLabelArray(key)
+	}
+	return rule
+}
+
+func (ds *GroupsTestSuite) TestGroupsPoller(c *C) {
+	var (
+		lookups   = map[api.GroupSelector]int{}
+		members   = map[api.GroupSelector][]net.IP{}
+		generated []*api.Rule
+	)
+
+	poller := NewGroupsPoller(GroupsPollerConfig{
+		LookupGroups: func(groups []api.GroupSelector) (map[api.GroupSelector][]net.IP, map[api.GroupSelector]error) {
+			groupIPs := map[api.GroupSelector][]net.IP{}
+			for _, group := range groups {
+				lookups[group]++
+				groupIPs[group] = members[group]
+			}
+			return groupIPs, nil
+		},
+		AddGeneratedRules: func(rules []*api.Rule) error {
+			generated = append(generated, rules...)
+			return nil
+		},
+	})
+
+	rules := []*api.Rule{makeRule("rule1", groupA), makeRule("rule2", groupA, groupB)}
+	poller.MarkToGroupsRules(rules)
+	for _, rule := range rules {
+		c.Assert(rule.Labels.Has(uuidLabelSearchKey), Equals, true)
+	}
+	poller.StartPollForGroups(rules)
+	c.Assert(len(poller.GetGroups()), Equals, 2)
+
+	members[groupA] = []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")}
+	members[groupB] = []net.IP{net.ParseIP("f00d::1")}
+	c.Assert(poller.LookupUpdateGroups(), IsNil)
+	c.Assert(lookups[groupA], Equals, 1)
+	c.Assert(lookups[groupB], Equals, 1)
+	c.Assert(len(generated), Equals, 2)
+	for _, rule := range generated {
+		switch {
+		case rule.Labels.Has("unspec.rule1"):
+			c.Assert(rule.Egress[0].ToCIDRSet, DeepEquals, api.CIDRRuleSlice{
+				{Cidr: "10.0.0.1/32", ExceptCIDRs: []api.CIDR{}, Generated: true},
+				{Cidr: "10.0.0.2/32", ExceptCIDRs: []api.CIDR{}, Generated: true},
+			})
+		case rule.Labels.Has("unspec.rule2"):
+			c.Assert(len(rule.Egress[0].ToCIDRSet), Equals, 3)
+		default:
+			c.Errorf("unexpected generated rule %v", rule)
+		}
+	}
+
+	// Unchanged members do not generate new rules
+	generated = nil
+	c.Assert(poller.LookupUpdateGroups(), IsNil)
+	c.Assert(len(generated), Equals, 0)
+
+	// Only rules depending on changed groups are regenerated
+	members[groupB] = []net.IP{net.ParseIP("f00d::2")}
+	c.Assert(poller.LookupUpdateGroups(), IsNil)
+	c.Assert(len(generated), Equals, 1)
+	c.Assert(generated[0].Labels.Has("unspec.rule2"), Equals, true)
+
+	// groupB is no longer polled once rule2 is removed
+	poller.StopPollForGroups(rules[1:])
+	c.Assert(poller.GetGroups(), DeepEquals, []api.GroupSelector{groupA})
+
+	poller.StopPollForGroups(rules[:1])
+	c.Assert(len(poller.GetGroups()), Equals, 0)
+}
+
+func (ds *GroupsTestSuite) TestMarkToGroupsRulesInjectsKnownIPs(c *C) {
+	poller := NewGroupsPoller(GroupsPollerConfig{})
+	poller.IPs[groupA] = []net.IP{net.ParseIP("10.0.0.1")}
+
+	rule := makeRule("", groupA)
+	poller.MarkToGroupsRules([]*api.Rule{rule})
+	c.Assert(len(rule.Egress[0].ToCIDRSet), Equals, 1)
+
+	// Rules without ToGroups are left untouched
+	other := &api.Rule{
+		EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("class=xwing")),
+	}
+	poller.MarkToGroupsRules([]*api.Rule{other})
+	c.Assert(other.Labels.Has(uuidLabelSearchKey), Equals, false)
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+const (
+	// providerTimeout is the maximum time a provider may take to resolve
+	// the members of all groups referring to it
+	providerTimeout = 10 * time.Second
+)
+
+// Provider resolves the members of groups maintained outside of Cilium to IP
+// addresses.
+type Provider interface {
+	// GetGroupMembers returns the IP addresses of all members of each group
+	// with the given names. It is called once per lookup run with all
+	// groups referring to the provider. Groups which could not be resolved
+	// are returned in groupErrors and are not part of groupIPs.
+	GetGroupMembers(ctx context.Context, groups []string) (groupIPs map[string][]net.IP, groupErrors map[string]error)
+}
+
+var (
+	providersMutex lock.RWMutex
+	providers      = map[string]Provider{}
+)
+
+// RegisterProvider registers the provider under the given name. The name is
+// referred to by the provider field of ToGroups rules. Registering a provider
+// under an existing name replaces the previous provider.
+func RegisterProvider(name string, provider Provider) {
+	providersMutex.Lock()
+	providers[name] = provider
+	providersMutex.Unlock()
+}
+
+// UnregisterProvider removes the provider registered under the given name.
+func UnregisterProvider(name string) {
+	providersMutex.Lock()
+	delete(providers, name)
+	providersMutex.Unlock()
+}
+
+func getProvider(name string) (Provider, bool) {
+	providersMutex.RLock()
+	provider, ok := providers[name]
+	providersMutex.RUnlock()
+	return provider, ok
+}
+
+// LookupGroupsWithProviders resolves each group via the provider it refers to.
+// Each provider is queried once for all groups referring to it. Groups which
+// could not be resolved are returned in groupErrors and are not part of
+// groupIPs.
+func LookupGroupsWithProviders(groups []api.GroupSelector) (groupIPs map[api.GroupSelector][]net.IP, groupErrors map[api.GroupSelector]error) {
+	groupIPs = make(map[api.GroupSelector][]net.IP, len(groups))
+	groupErrors = make(map[api.GroupSelector]error)
+
+	namesByProvider := make(map[string][]string)
+	for _, group := range groups {
+		namesByProvider[group.Provider] = append(namesByProvider[group.Provider], group.Name)
+	}
+
+	for providerName, names := range namesByProvider {
+		provider, ok := getProvider(providerName)
+		if !ok {
+			for _, name := range names {
+				group := api.GroupSelector{Provider: providerName, Name: name}
+				groupErrors[group] = fmt.Errorf("unknown provider %q", providerName)
+			}
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+		ips, errs := provider.GetGroupMembers(ctx, names)
+		cancel()
+
+		for name, err := range errs {
+			groupErrors[api.GroupSelector{Provider: providerName, Name: name}] = err
+		}
+		for name, members := range ips {
+			groupIPs[api.GroupSelector{Provider: providerName, Name: name}] = members
+		}
+	}
+
+	return groupIPs, groupErrors
+}
@@ -0,0 +1,155 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// GenericProviderName is the name under which the generic provider is
+	// registered
+	GenericProviderName = "generic"
+)
+
+// GenericProvider resolves groups based on a JSON document which maps group
+// names to the IP addresses of their members, e.g.:
+//
+//	{
+//	  "sg-0123": ["10.0.0.1", "10.0.0.2"],
+//	  "sg-4567": ["192.168.1.10"]
+//	}
+//
+// The document is read from a local file or fetched via HTTP(S) once per
+// lookup run. This allows any inventory system to act as a provider by
+// rendering or serving the document.
+type GenericProvider struct {
+	// source is the path or the http(s):// URL of the document
+	source string
+
+	client *http.Client
+}
+
+// NewGenericProvider returns a new GenericProvider reading the group document
+// from the given file path or http(s):// URL.
+func NewGenericProvider(source string) *GenericProvider {
+	return &GenericProvider{
+		source: source,
+		client: &http.Client{},
+	}
+}
+
+func (p *GenericProvider) isHTTP() bool {
+	return strings.HasPrefix(p.source, "http://") || strings.HasPrefix(p.source, "https://")
+}
+
+func (p *GenericProvider) open(ctx context.Context) (io.ReadCloser, error) {
+	if !p.isHTTP() {
+		return os.Open(p.source)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected HTTP status %s from %s", resp.Status, p.source)
+	}
+
+	return resp.Body, nil
+}
+
+// readDocument reads and parses the group document.
+func (p *GenericProvider) readDocument(ctx context.Context) (map[string][]string, error) {
+	r, err := p.open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read group document: %s", err)
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read group document: %s", err)
+	}
+
+	var document map[string][]string
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("unable to parse group document: %s", err)
+	}
+
+	return document, nil
+}
+
+// parseMembers returns the IP addresses of the members of a group.
+func parseMembers(group string, members []string) ([]net.IP, error) {
+	ips := make([]net.IP, 0, len(members))
+	for _, member := range members {
+		ip := net.ParseIP(member)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP %q in group %q", member, group)
+		}
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+// GetGroupMembers returns the IP addresses of all members of each group with
+// the given names as listed in the group document. The document is read once
+// for all groups.
+func (p *GenericProvider) GetGroupMembers(ctx context.Context, groups []string) (groupIPs map[string][]net.IP, groupErrors map[string]error) {
+	groupIPs = make(map[string][]net.IP, len(groups))
+	groupErrors = make(map[string]error)
+
+	document, err := p.readDocument(ctx)
+	if err != nil {
+		for _, group := range groups {
+			groupErrors[group] = err
+		}
+		return groupIPs, groupErrors
+	}
+
+	for _, group := range groups {
+		members, ok := document[group]
+		if !ok {
+			groupErrors[group] = fmt.Errorf("group %q not found", group)
+			continue
+		}
+
+		ips, err := parseMembers(group, members)
+		if err != nil {
+			groupErrors[group] = err
+			continue
+		}
+		groupIPs[group] = ips
+	}
+
+	return groupIPs, groupErrors
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package groups
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+const testGroupDocument = `{
+	"sg-0123": ["10.0.0.1", "f00d::1"],
+	"sg-4567": ["not-an-ip"]
+}`
+
+func (ds *GroupsTestSuite) testGenericProvider(c *C, p *GenericProvider) {
+	ips, errs := p.GetGroupMembers(context.Background(), []string{"sg-0123", "sg-4567", "sg-unknown"})
+	c.Assert(ips, DeepEquals, map[string][]net.IP{
+		"sg-0123": {net.ParseIP("10.0.0.1"), net.ParseIP("f00d::1")},
+	})
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["sg-4567"], Not(IsNil))
+	c.Assert(errs["sg-unknown"], Not(IsNil))
+}
+
+func (ds *GroupsTestSuite) TestGenericProviderFile(c *C) {
+	f, err := ioutil.TempFile("", "togroups")
+	c.Assert(err, IsNil)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(testGroupDocument)
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+
+	ds.testGenericProvider(c, NewGenericProvider(f.Name()))
+
+	ips, errs := NewGenericProvider(f.Name()+".missing").GetGroupMembers(context.Background(), []string{"sg-0123"})
+	c.Assert(ips, HasLen, 0)
+	c.Assert(errs["sg-0123"], Not(IsNil))
+}
+
+func (ds *GroupsTestSuite) TestGenericProviderHTTP(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/groups" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, testGroupDocument)
+	}))
+	defer srv.Close()
+
+	ds.testGenericProvider(c, NewGenericProvider(srv.URL+"/groups"))
+
+	ips, errs := NewGenericProvider(srv.URL+"/missing").GetGroupMembers(context.Background(), []string{"sg-0123"})
+	c.Assert(ips, HasLen, 0)
+	c.Assert(errs["sg-0123"], Not(IsNil))
+}
+
+func (ds *GroupsTestSuite) TestLookupGroupsWithProviders(c *C) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, testGroupDocument)
+	}))
+	defer srv.Close()
+
+	RegisterProvider(GenericProviderName, NewGenericProvider(srv.URL))
+	defer UnregisterProvider(GenericProviderName)
+
+	known := api.GroupSelector{Provider: GenericProviderName, Name: "sg-0123"}
+	invalid := api.GroupSelector{Provider: GenericProviderName, Name: "sg-4567"}
+	unknown := api.GroupSelector{Provider: "unknown", Name: "sg-0123"}
+
+	groupIPs, groupErrors := LookupGroupsWithProviders([]api.GroupSelector{known, invalid, unknown})
+	c.Assert(len(groupIPs[known]), Equals, 2)
+	c.Assert(groupErrors[known], IsNil)
+	c.Assert(groupErrors[invalid], Not(IsNil))
+	c.Assert(groupErrors[unknown], Not(IsNil))
+
+	// The document must only be fetched once per lookup run
+	c.Assert(requests, Equals, 1)
+}
@@ -642,6 +642,25 @@ func (p *Repository) GetRulesMatching(labels labels.LabelArray) (ingressMatch bo
 	return
 }
 
+// GetRulesMatchingAudit returns whether the rules in a repository which
+// contain labels matching the labels in the provided LabelArray are all
+// audit rules. Returns false if no rule matches.
+//
+// Must be called with p.Mutex held
+func (p *Repository) GetRulesMatchingAudit(labels labels.LabelArray) bool {
+	audit := false
+	for _, r := range p.rules {
+		if !r.EndpointSelector.Matches(labels) {
+			continue
+		}
+		if !r.Audit {
+			return false
+		}
+		audit = true
+	}
+	return audit
+}
+
 // NumRules returns the amount of rules in the policy repository.
 //
 // Must be called with p.Mutex held
@@ -192,6 +192,36 @@ func (ds *PolicyTestSuite) TestContainsAllRLocked(c *C) {
 	c.Assert(repoEmpty.ContainsAllRLocked(a), Equals, false)    // a is NOT in empty
 }
 
+func (ds *PolicyTestSuite) TestGetRulesMatchingAudit(c *C) {
+	fooLabels := labels.LabelArray{labels.ParseLabel("foo")}
+	barLabels := labels.LabelArray{labels.ParseLabel("bar")}
+
+	repo := NewPolicyRepository()
+	c.Assert(repo.GetRulesMatchingAudit(fooLabels), Equals, false)
+
+	_, err := repo.Add(api.Rule{
+		EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+		Ingress:          []api.IngressRule{{}},
+		Labels:           labels.LabelArray{labels.ParseLabel("audit")},
+		Audit:            true,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(repo.GetRulesMatchingAudit(fooLabels), Equals, true)
+	c.Assert(repo.GetRulesMatchingAudit(barLabels), Equals, false)
+
+	_, err = repo.Add(api.Rule{
+		EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+		Egress:           []api.EgressRule{{}},
+		Labels:           labels.LabelArray{labels.ParseLabel("enforce")},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(repo.GetRulesMatchingAudit(fooLabels), Equals, false)
+
+	_, n := repo.DeleteByLabels(labels.LabelArray{labels.ParseLabel("enforce")})
+	c.Assert(n, Equals, 1)
+	c.Assert(repo.GetRulesMatchingAudit(fooLabels), Equals, true)
+}
+
 func (ds *PolicyTestSuite) TestCanReachIngress(c *C) {
 	repo := NewPolicyRepository()
 
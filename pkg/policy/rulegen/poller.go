@@ -0,0 +1,444 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rulegen
+
+import (
+	"net"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Target is an egress destination which a Poller resolves to IPs, e.g. a DNS
+// name or a group. Targets are used as map keys and must be comparable.
+type Target interface{}
+
+// PollerConfig configures which rules a Poller tracks, how it resolves their
+// targets and how it emits the generated rules.
+type PollerConfig struct {
+	// LabelKey is the key of the UUID label added to tracked rules, e.g.
+	// "ToFQDN-UUID".
+	LabelKey string
+
+	// Targets returns the targets selected by an egress rule. The
+	// ToCIDRSet of egress rules with targets is generated by the Poller.
+	Targets func(egressRule *api.EgressRule) []Target
+
+	// Lookup resolves the provided targets. Targets missing in the result
+	// keep their most recent IPs. The IPs must be sorted, e.g. via SortIPs.
+	Lookup func(targets []Target) map[Target][]net.IP
+
+	// AddGeneratedRules is a callback to emit generated rules.
+	// When set to nil, it is a no-op.
+	AddGeneratedRules func([]*api.Rule) error
+
+	// Log is the logger used to report on the poll list and the generated
+	// rules.
+	Log *logrus.Entry
+}
+
+// Poller periodically resolves the targets of the egress rules it tracks. It
+// emits regenerated policy rules, with the IPs of the targets in the
+// ToCIDRSet section, when the IPs change.
+// Rules are tracked via a UUID label. As the generated rules are inserted
+// into the policy repository like any other rule, the ToCIDRSet section of
+// egress rules with targets is stripped before a rule is tracked, so the
+// generated rules are equivalent to their source.
+type Poller struct {
+	lock.Mutex // this guards both maps and their contents
+
+	// config is a copy from when this instance was initialized.
+	// It is read-only once set
+	config PollerConfig
+
+	// uuidLabelSearchKey is the extended key of the UUID label
+	uuidLabelSearchKey string
+
+	// IPs maps targets to the most recent IPs seen for them. It is the
+	// source of information for the CIDR rules we generate.
+	// Note: The IP slices are sorted, and should not be reshuffled.
+	IPs map[Target][]net.IP
+
+	// sourceRules maps targets to a set of rule UUIDs that depend on that
+	// target. The inner map acts as a refcount, a target is polled for as
+	// long as a rule depends on it.
+	// The UUID -> rule mapping is allRules below.
+	sourceRules map[Target]map[string]struct{}
+
+	// allRules is the global source of truth for rules we are managing. It
+	// maps UUID to the rule copy.
+	allRules map[string]*api.Rule
+}
+
+// NewPoller creates an initialized Poller. It is up to the caller to run
+// LookupUpdate periodically.
+func NewPoller(config PollerConfig) *Poller {
+	if config.AddGeneratedRules == nil {
+		config.AddGeneratedRules = func(generatedRules []*api.Rule) error { return nil }
+	}
+
+	return &Poller{
+		config:             config,
+		uuidLabelSearchKey: UUIDLabelSearchKey(config.LabelKey),
+		IPs:                make(map[Target][]net.IP),
+		sourceRules:        make(map[Target]map[string]struct{}),
+		allRules:           make(map[string]*api.Rule),
+	}
+}
+
+// MarkRules adds a tracking label to each rule which selects targets. The
+// label is used to ensure that the rule is replaced correctly when it is
+// regenerated with IPs. It will also include the generated ToCIDRSet section
+// for IPs that are already known.
+// NOTE: It edits the rules in-place
+func (poller *Poller) MarkRules(sourceRules []*api.Rule) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	for _, sourceRule := range sourceRules {
+		// This rule has already been seen, and has a UUID label OR it
+		// selects no targets. Do no more processing on it.
+		// Note: this label can only come from us. An external rule add or
+		// replace would lack the UUID label and we would add a new one
+		// here. Cleanup for existing rules with UUIDs is handled in
+		// StopPoll.
+		if !poller.hasTargets(sourceRule) || sourceRule.Labels.Has(poller.uuidLabelSearchKey) {
+			continue
+		}
+
+		// add a unique ID that we can use later to replace this rule.
+		sourceRule.Labels = append(sourceRule.Labels, NewUUIDLabel(poller.config.LabelKey))
+
+		// Inject initial IPs in this rule, best effort from the last lookup
+		poller.injectToCIDRSetRules(sourceRule)
+	}
+}
+
+// StartPoll sets up the polling for the targets of each api.Rule. It only
+// adds rules with the UUID label, added by MarkRules, and repeat inserts are
+// effectively no-ops.
+func (poller *Poller) StartPoll(sourceRules []*api.Rule) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	for _, sourceRule := range sourceRules {
+		if !sourceRule.Labels.Has(poller.uuidLabelSearchKey) {
+			continue
+		}
+
+		// Make a copy to avoid breaking the input rules. Strip ToCIDRSet to
+		// avoid accumulating anything we included during MarkRules
+		sourceRuleCopy := sourceRule.DeepCopy()
+		StripToCIDRSet(sourceRuleCopy, func(egressRule *api.EgressRule) bool {
+			return len(poller.config.Targets(egressRule)) > 0
+		})
+
+		uuid := GetRuleUUIDLabel(sourceRuleCopy, poller.uuidLabelSearchKey)
+		// only debug print for new targets, since this function is called
+		// unconditionally, even when we insert generated rules (which
+		// aren't new)
+		if newTargets := poller.addRule(uuid, sourceRuleCopy); len(newTargets) > 0 {
+			poller.config.Log.WithFields(logrus.Fields{
+				"newTargets": newTargets,
+				"numRules":   len(sourceRules),
+			}).Debug("Added targets to poll list")
+		}
+	}
+}
+
+// StopPoll runs the bookkeeping to remove each api.Rule from the
+// corresponding target entries. When no more rules rely on a specific target,
+// it is no longer polled for. It expects rules to still be labelled with the
+// UUID label if MarkRules had added it.
+// Note: rule deletion in policy.Repository is by label, where the rules must
+// have at least the labels in the delete. This means our UUID label, and
+// later ToCIDRSet additions will also be deleted correctly, and no action is
+// needed here.
+func (poller *Poller) StopPoll(sourceRules []*api.Rule) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	for _, sourceRule := range sourceRules {
+		// skip unmarked rules, nothing to do
+		if !sourceRule.Labels.Has(poller.uuidLabelSearchKey) {
+			continue
+		}
+
+		uuid := GetRuleUUIDLabel(sourceRule, poller.uuidLabelSearchKey)
+		noLongerPolled := poller.removeRule(uuid, sourceRule)
+		poller.config.Log.WithField("noLongerPolled", noLongerPolled).
+			Debug("Removed targets from poll list")
+	}
+}
+
+// LookupUpdate resolves each stored target, stores the updates and then
+// emits regenerated policy rules for all rules depending on targets whose
+// IPs have changed.
+// The general steps are:
+// 1- take a snapshot of the targets to resolve, to avoid locking poller
+// during lookups
+// 2- resolve the targets via Lookup
+// 3- update IPs for each target. If the IPs have changed, store which rules
+// must be updated
+// 4- for each of these rules, generate a new policy rule with IPs
+// 5- if we have any rules to update, emit them with AddGeneratedRules
+func (poller *Poller) LookupUpdate() error {
+	targets := poller.GetTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	uuidsToUpdate, updatedTargets := poller.UpdateIPs(poller.config.Lookup(targets))
+	for target, IPs := range updatedTargets {
+		poller.config.Log.WithFields(logrus.Fields{
+			"target":        target,
+			"IPs":           IPs,
+			"uuidsToUpdate": uuidsToUpdate,
+		}).Debug("Updated target with new IPs")
+	}
+
+	rulesToUpdate, notFoundUUIDs := poller.GetRulesByUUID(uuidsToUpdate)
+	if len(notFoundUUIDs) != 0 {
+		poller.config.Log.WithField("uuid", strings.Join(notFoundUUIDs, ",")).
+			Debug("Did not find all rules during update")
+	}
+
+	generatedRules, targetsMissingIPs := poller.GenerateRulesFromSources(rulesToUpdate)
+	if len(targetsMissingIPs) != 0 {
+		poller.config.Log.WithField("targets", targetsMissingIPs).
+			Warn("Missing IPs for targets of generated rules")
+	}
+
+	// no rules to add, do not call AddGeneratedRules below
+	if len(generatedRules) == 0 {
+		return nil
+	}
+
+	return poller.config.AddGeneratedRules(generatedRules)
+}
+
+// GetTargets returns a snapshot of the targets polled for
+func (poller *Poller) GetTargets() (targets []Target) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	for target := range poller.IPs {
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// UpdateIPs updates the IPs for each target in targetIPs. The IPs must be
+// sorted. Targets which are no longer polled for are ignored.
+// It returns:
+// affectedRules: the UUIDs of the rules affected by changed IPs
+// updatedTargets: the targets whose IPs changed, with the new IPs
+func (poller *Poller) UpdateIPs(targetIPs map[Target][]net.IP) (affectedRules []string, updatedTargets map[Target][]net.IP) {
+	updatedTargets = make(map[Target][]net.IP, len(targetIPs))
+	affectedRulesSet := make(map[string]struct{}, len(targetIPs))
+
+	poller.Lock()
+	defer poller.Unlock()
+
+	for target, newIPs := range targetIPs {
+		oldIPs, polled := poller.IPs[target]
+		// The target may have been removed during the lookup
+		if !polled || SortedIPsAreEqual(newIPs, oldIPs) {
+			continue
+		}
+		poller.IPs[target] = newIPs
+		updatedTargets[target] = newIPs
+
+		// accumulate the rules affected by new IPs, that we need to update
+		// with CIDR rules
+		for uuid := range poller.sourceRules[target] {
+			affectedRulesSet[uuid] = struct{}{}
+		}
+	}
+
+	for uuid := range affectedRulesSet {
+		affectedRules = append(affectedRules, uuid)
+	}
+
+	return affectedRules, updatedTargets
+}
+
+// GetRulesByUUID returns the sourceRule copies of inserted rules. These are
+// the source of truth when generating rules with updated IPs.
+// notFoundUUIDs is the set of UUIDs not found. This can occur when a delete
+// races with other operations. It is benign in the sense that if a rule UUID
+// is not found, no action is supposed to be taken on it by the poller.
+func (poller *Poller) GetRulesByUUID(uuids []string) (sourceRules []*api.Rule, notFoundUUIDs []string) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	for _, uuid := range uuids {
+		rule, ok := poller.allRules[uuid]
+		// This may happen if a rule was deleted during the lookups
+		if !ok {
+			notFoundUUIDs = append(notFoundUUIDs, uuid)
+			continue
+		}
+
+		sourceRules = append(sourceRules, rule)
+	}
+
+	return sourceRules, notFoundUUIDs
+}
+
+// GenerateRulesFromSources creates new api.Rule instances with all targets
+// resolved to IPs. The IPs are in generated CIDRSet rules in the ToCIDRSet
+// section. Pre-existing rules in ToCIDRSet are preserved.
+// Note: GenerateRulesFromSources will make a copy each sourceRule
+func (poller *Poller) GenerateRulesFromSources(sourceRules []*api.Rule) (generatedRules []*api.Rule, targetsMissingIPs []Target) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	missing := make(map[Target]struct{})
+
+	for _, sourceRule := range sourceRules {
+		newRule := sourceRule.DeepCopy()
+		for _, target := range poller.injectToCIDRSetRules(newRule) {
+			missing[target] = struct{}{}
+		}
+
+		generatedRules = append(generatedRules, newRule)
+	}
+
+	for target := range missing {
+		targetsMissingIPs = append(targetsMissingIPs, target)
+	}
+	return generatedRules, targetsMissingIPs
+}
+
+// hasTargets indicates whether any egress rule of the api.Rule selects
+// targets
+func (poller *Poller) hasTargets(rule *api.Rule) bool {
+	for i := range rule.Egress {
+		if len(poller.config.Targets(&rule.Egress[i])) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// injectToCIDRSetRules adds a ToCIDRSet section to the rule with all targets
+// resolved to the most recent IPs.
+// Pre-existing rules in ToCIDRSet are preserved.
+func (poller *Poller) injectToCIDRSetRules(rule *api.Rule) (targetsMissingIPs []Target) {
+	missing := make(map[Target]struct{}) // a set to dedup missing targets
+
+	// we need to edit Egress[*] in-place
+	for egressIdx := range rule.Egress {
+		egressRule := &rule.Egress[egressIdx]
+
+		for _, target := range poller.config.Targets(egressRule) {
+			IPs, present := poller.IPs[target]
+			if !present || len(IPs) == 0 {
+				missing[target] = struct{}{}
+			}
+
+			egressRule.ToCIDRSet = append(egressRule.ToCIDRSet, IPsToRules(IPs)...)
+		}
+	}
+
+	for target := range missing {
+		targetsMissingIPs = append(targetsMissingIPs, target)
+	}
+
+	return targetsMissingIPs
+}
+
+// addRule places an api.Rule in the source list of each target it selects
+// and returns the targets which were not polled for before.
+// uuid must be the unique identifier of the UUID label.
+// Note: we keep the newest instance of a rule to allow handling policy
+// updates for parts of the rule we don't look at, but need to retain while
+// generating.
+func (poller *Poller) addRule(uuid string, sourceRule *api.Rule) (newTargets []Target) {
+	// if we are updating a rule, track which old targets are removed. As we
+	// add targets from the new rule below, these are cleared.
+	targetsToStopPolling := make(map[Target]struct{})
+	if oldRule, exists := poller.allRules[uuid]; exists {
+		for i := range oldRule.Egress {
+			for _, target := range poller.config.Targets(&oldRule.Egress[i]) {
+				targetsToStopPolling[target] = struct{}{}
+			}
+		}
+	}
+
+	// Always add to allRules
+	poller.allRules[uuid] = sourceRule
+
+	// Add a target -> rule reference
+	for i := range sourceRule.Egress {
+		for _, target := range poller.config.Targets(&sourceRule.Egress[i]) {
+			delete(targetsToStopPolling, target)
+
+			if _, exists := poller.IPs[target]; !exists {
+				poller.IPs[target] = make([]net.IP, 0)
+				poller.sourceRules[target] = make(map[string]struct{})
+				newTargets = append(newTargets, target)
+			}
+			poller.sourceRules[target][uuid] = struct{}{}
+		}
+	}
+
+	// Remove references to the uuid that were present in the old rule but
+	// not re-added by the new one. This may stop polling for the target, if
+	// no other rules depend on it.
+	for target := range targetsToStopPolling {
+		poller.removeFromTarget(target, uuid)
+	}
+
+	return newTargets
+}
+
+// removeRule removes an api.Rule from the source rule set of each target it
+// selects and returns the targets which are no longer polled for.
+func (poller *Poller) removeRule(uuid string, sourceRule *api.Rule) (noLongerPolled []Target) {
+	// Always delete from allRules
+	delete(poller.allRules, uuid)
+
+	for i := range sourceRule.Egress {
+		for _, target := range poller.config.Targets(&sourceRule.Egress[i]) {
+			if poller.removeFromTarget(target, uuid) {
+				noLongerPolled = append(noLongerPolled, target)
+			}
+		}
+	}
+
+	return noLongerPolled
+}
+
+// removeFromTarget removes the uuid from the set of rules depending on the
+// target. If no rule depends on the target anymore, it is no longer polled
+// for and true is returned.
+func (poller *Poller) removeFromTarget(target Target, uuid string) (stoppedPolling bool) {
+	delete(poller.sourceRules[target], uuid)
+
+	if len(poller.sourceRules[target]) == 0 {
+		delete(poller.sourceRules, target)
+		delete(poller.IPs, target)
+		return true
+	}
+
+	return false
+}
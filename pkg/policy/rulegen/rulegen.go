@@ -0,0 +1,122 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rulegen contains the Poller and helpers shared by the pollers which
+// derive ToCIDRSet sections from higher level egress selectors, e.g. pkg/fqdn
+// for ToFQDNs and pkg/policy/groups for ToGroups. Rules handled by a poller are
+// tracked via a cilium-generated UUID label whose key is chosen by the
+// poller.
+package rulegen
+
+import (
+	"bytes"
+	"net"
+	"sort"
+
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/uuid"
+)
+
+// NewUUIDLabel builds a random UUID label with the given key that can be
+// used to uniquely identify rules augmented with a generated "toCIDRSet".
+func NewUUIDLabel(key string) *labels.Label {
+	return &labels.Label{
+		Key:    key,
+		Value:  uuid.NewUUID().String(),
+		Source: labels.LabelSourceCiliumGenerated,
+	}
+}
+
+// UUIDLabelSearchKey returns the *extended* label key of UUID labels built
+// with NewUUIDLabel(key). This is because .Has expects the source:key
+// delimiter to be the labels.PathDelimiter
+func UUIDLabelSearchKey(key string) string {
+	return NewUUIDLabel(key).GetExtendedKey()
+}
+
+// GetRuleUUIDLabel returns the value of the UUID label found under searchKey,
+// as returned by UUIDLabelSearchKey.
+func GetRuleUUIDLabel(rule *api.Rule, searchKey string) (uuid string) {
+	return rule.Labels.Get(searchKey)
+}
+
+// StripToCIDRSet removes the ToCIDRSet section of every egress rule for
+// which generated returns true, i.e. every egress rule whose ToCIDRSet is
+// derived from another selector.
+func StripToCIDRSet(rule *api.Rule, generated func(egressRule *api.EgressRule) bool) {
+	for i := range rule.Egress {
+		egressRule := &rule.Egress[i]
+		if generated(egressRule) {
+			egressRule.ToCIDRSet = nil
+		}
+	}
+}
+
+// IPsToRules generates CIDRRules for the IPs passed in.
+func IPsToRules(ips []net.IP) (cidrRules []api.CIDRRule) {
+	for _, ip := range ips {
+		rule := api.CIDRRule{ExceptCIDRs: make([]api.CIDR, 0)}
+		rule.Generated = true
+		if ip.To4() != nil {
+			rule.Cidr = api.CIDR(ip.String() + "/32")
+		} else {
+			rule.Cidr = api.CIDR(ip.String() + "/128")
+		}
+
+		cidrRules = append(cidrRules, rule)
+	}
+
+	return cidrRules
+}
+
+// SortIPs returns a sorted copy of ips with duplicates removed. All IPs are
+// converted to their 16-byte representation.
+func SortIPs(ips []net.IP) []net.IP {
+	sorted := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		sorted = append(sorted, ip.To16())
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	unique := sorted[:0]
+	for i, ip := range sorted {
+		if i == 0 || !ip.Equal(sorted[i-1]) {
+			unique = append(unique, ip)
+		}
+	}
+
+	return unique
+}
+
+// SortedIPsAreEqual compares two lists of sorted IPs. If any differ it returns
+// false.
+func SortedIPsAreEqual(a, b []net.IP) bool {
+	// the IP set is definitely different if the lengths are different
+	if len(a) != len(b) {
+		return false
+	}
+
+	// lengths are equal, so each member in one set must be in the other.
+	// If any IPs at the same index differ, the sets differ.
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
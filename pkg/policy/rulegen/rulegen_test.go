@@ -0,0 +1,170 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package rulegen
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	"github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type RulegenTestSuite struct{}
+
+var _ = Suite(&RulegenTestSuite{})
+
+func (s *RulegenTestSuite) TestUUIDLabel(c *C) {
+	searchKey := UUIDLabelSearchKey("Test-UUID")
+	rule := &api.Rule{}
+	c.Assert(GetRuleUUIDLabel(rule, searchKey), Equals, "")
+
+	label := NewUUIDLabel("Test-UUID")
+	rule.Labels = append(rule.Labels, label)
+	c.Assert(rule.Labels.Has(searchKey), Equals, true)
+	c.Assert(GetRuleUUIDLabel(rule, searchKey), Equals, label.Value)
+	c.Assert(NewUUIDLabel("Test-UUID").Value, Not(Equals), label.Value)
+	c.Assert(rule.Labels.Has(UUIDLabelSearchKey("Other-UUID")), Equals, false)
+}
+
+func (s *RulegenTestSuite) TestStripToCIDRSet(c *C) {
+	rule := &api.Rule{
+		Egress: []api.EgressRule{
+			{ToCIDRSet: api.CIDRRuleSlice{{Cidr: "1.1.1.1/32"}}, ToFQDNs: []api.FQDNSelector{{MatchName: "cilium.io"}}},
+			{ToCIDRSet: api.CIDRRuleSlice{{Cidr: "2.2.2.2/32"}}},
+		},
+	}
+
+	StripToCIDRSet(rule, func(egressRule *api.EgressRule) bool {
+		return len(egressRule.ToFQDNs) > 0
+	})
+	c.Assert(rule.Egress[0].ToCIDRSet, IsNil)
+	c.Assert(rule.Egress[1].ToCIDRSet, DeepEquals, api.CIDRRuleSlice{{Cidr: "2.2.2.2/32"}})
+}
+
+func (s *RulegenTestSuite) TestIPsToRules(c *C) {
+	rules := IPsToRules([]net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("f00d::1")})
+	c.Assert(rules, DeepEquals, []api.CIDRRule{
+		{Cidr: "1.1.1.1/32", ExceptCIDRs: []api.CIDR{}, Generated: true},
+		{Cidr: "f00d::1/128", ExceptCIDRs: []api.CIDR{}, Generated: true},
+	})
+	c.Assert(IPsToRules(nil), IsNil)
+}
+
+func (s *RulegenTestSuite) TestSortIPs(c *C) {
+	ips := []net.IP{
+		net.ParseIP("f00d::1"),
+		net.ParseIP("2.2.2.2").To4(),
+		net.ParseIP("1.1.1.1"),
+		net.ParseIP("2.2.2.2"),
+	}
+
+	sorted := SortIPs(ips)
+	c.Assert(sorted, HasLen, 3)
+	c.Assert(sorted[0].String(), Equals, "1.1.1.1")
+	c.Assert(sorted[1].String(), Equals, "2.2.2.2")
+	c.Assert(sorted[2].String(), Equals, "f00d::1")
+	// The input must not be modified
+	c.Assert(ips[0].String(), Equals, "f00d::1")
+
+	c.Assert(SortedIPsAreEqual(sorted, SortIPs([]net.IP{net.ParseIP("f00d::1"), net.ParseIP("2.2.2.2"), net.ParseIP("1.1.1.1")})), Equals, true)
+	c.Assert(SortedIPsAreEqual(sorted, sorted[:2]), Equals, false)
+	c.Assert(SortedIPsAreEqual(sorted[:2], sorted[1:]), Equals, false)
+	c.Assert(SortedIPsAreEqual(nil, []net.IP{}), Equals, true)
+}
+
+func (s *RulegenTestSuite) TestPoller(c *C) {
+	var (
+		resolved  = map[Target][]net.IP{}
+		generated []*api.Rule
+	)
+
+	poller := NewPoller(PollerConfig{
+		LabelKey: "Test-UUID",
+		Targets: func(egressRule *api.EgressRule) (targets []Target) {
+			for _, fqdn := range egressRule.ToFQDNs {
+				targets = append(targets, fqdn.MatchName)
+			}
+			return targets
+		},
+		Lookup: func(targets []Target) map[Target][]net.IP {
+			targetIPs := map[Target][]net.IP{}
+			for _, target := range targets {
+				if ips, ok := resolved[target]; ok {
+					targetIPs[target] = SortIPs(ips)
+				}
+			}
+			return targetIPs
+		},
+		AddGeneratedRules: func(rules []*api.Rule) error {
+			generated = append(generated, rules...)
+			return nil
+		},
+		Log: logrus.NewEntry(logrus.New()),
+	})
+
+	makeRule := func(names ...string) *api.Rule {
+		egressRule := api.EgressRule{ToCIDRSet: api.CIDRRuleSlice{{Cidr: "9.9.9.9/32"}}}
+		for _, name := range names {
+			egressRule.ToFQDNs = append(egressRule.ToFQDNs, api.FQDNSelector{MatchName: name})
+		}
+		return &api.Rule{Egress: []api.EgressRule{egressRule}}
+	}
+
+	rules := []*api.Rule{makeRule("a"), makeRule("a", "b"), {}}
+	poller.MarkRules(rules)
+	c.Assert(rules[0].Labels.Has(UUIDLabelSearchKey("Test-UUID")), Equals, true)
+	c.Assert(rules[2].Labels, HasLen, 0)
+	poller.StartPoll(rules)
+	c.Assert(poller.GetTargets(), HasLen, 2)
+
+	resolved["a"] = []net.IP{net.ParseIP("1.1.1.1")}
+	c.Assert(poller.LookupUpdate(), IsNil)
+	c.Assert(generated, HasLen, 2)
+	for _, rule := range generated {
+		// the ToCIDRSet of the source rule is replaced by the resolved IPs
+		c.Assert(rule.Egress[0].ToCIDRSet, DeepEquals, api.CIDRRuleSlice(IPsToRules([]net.IP{net.ParseIP("1.1.1.1")})))
+	}
+
+	// Unchanged IPs do not generate new rules
+	generated = nil
+	c.Assert(poller.LookupUpdate(), IsNil)
+	c.Assert(generated, HasLen, 0)
+
+	// Replacing the second rule without "b" stops polling "b" but "a" is
+	// still polled for both rules
+	update := makeRule("a")
+	update.Labels = rules[1].Labels
+	poller.StartPoll([]*api.Rule{update})
+	c.Assert(poller.GetTargets(), DeepEquals, []Target{"a"})
+
+	resolved["a"] = []net.IP{net.ParseIP("2.2.2.2")}
+	c.Assert(poller.LookupUpdate(), IsNil)
+	c.Assert(generated, HasLen, 2)
+
+	poller.StopPoll(rules[:1])
+	c.Assert(poller.GetTargets(), DeepEquals, []Target{"a"})
+	poller.StopPoll([]*api.Rule{update})
+	c.Assert(poller.GetTargets(), HasLen, 0)
+}
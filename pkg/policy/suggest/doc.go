@@ -0,0 +1,20 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package suggest generates policy rules from observed traffic. Flows are
+// collected from policy verdict notifications and proxy access log records
+// of the monitor, aggregated by the security identities of both sides of the
+// flow and by port and L7 attributes, and turned into rules which allow
+// exactly the observed traffic.
+package suggest
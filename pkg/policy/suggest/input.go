@@ -0,0 +1,147 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suggest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+// AddPayload adds the flow carried by a monitor payload. Payloads which do
+// not carry a policy verdict notification or an access log record are
+// ignored.
+func (s *Suggester) AddPayload(pl *payload.Payload) error {
+	if pl.Type != payload.EventSample || len(pl.Data) == 0 {
+		return nil
+	}
+
+	switch pl.Data[0] {
+	case monitor.MessageTypePolicyVerdict:
+		pn := monitor.PolicyVerdictNotify{}
+		if err := binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &pn); err != nil {
+			return fmt.Errorf("unable to decode policy verdict notification: %s", err)
+		}
+		s.addPolicyVerdict(pn.Direction(), pn.Verdict >= 0 || pn.IsAudited(),
+			pn.SrcLabel, pn.DstLabel, pn.Port(), u8proto.U8proto(pn.Proto))
+
+	case monitor.MessageTypeAccessLog:
+		lr := monitor.LogRecordNotify{}
+		if err := gob.NewDecoder(bytes.NewBuffer(pl.Data[1:])).Decode(&lr); err != nil {
+			return fmt.Errorf("unable to decode access log record: %s", err)
+		}
+		v := monitor.LogRecordNotifyToVerbose(&lr)
+		s.addLogRecord(&v)
+	}
+
+	return nil
+}
+
+// AddJSON adds the flow carried by a monitor event in the JSON format of
+// "cilium monitor --json". Other events are ignored.
+func (s *Suggester) AddJSON(data []byte) error {
+	var event struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("unable to parse monitor event: %s", err)
+	}
+
+	switch event.Type {
+	case "policy-verdict":
+		v := monitor.PolicyVerdictNotifyVerbose{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("unable to parse policy verdict notification: %s", err)
+		}
+		proto, err := u8proto.ParseProtocol(v.Protocol)
+		if err != nil {
+			// Ports of unknown protocols can't be expressed in policy
+			proto = u8proto.All
+		}
+		s.addPolicyVerdict(v.Direction, v.Verdict >= 0 || v.Audited,
+			v.SrcLabel, v.DstLabel, v.DstPort, proto)
+
+	case "logRecord":
+		v := monitor.LogRecordNotifyVerbose{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("unable to parse access log record: %s", err)
+		}
+		s.addLogRecord(&v)
+	}
+
+	return nil
+}
+
+// addPolicyVerdict adds the flow of a policy verdict notification. Flows
+// which were denied by policy are ignored, flows which were only allowed
+// because of policy audit mode are added.
+func (s *Suggester) addPolicyVerdict(direction string, allowed bool, src, dst uint32, port uint16, proto u8proto.U8proto) {
+	if !allowed {
+		return
+	}
+
+	f := Flow{Port: port, Protocol: proto}
+	switch direction {
+	case "ingress":
+		f.Direction = Ingress
+		f.Subject, f.Peer = identity.NumericIdentity(dst), identity.NumericIdentity(src)
+	case "egress":
+		f.Direction = Egress
+		f.Subject, f.Peer = identity.NumericIdentity(src), identity.NumericIdentity(dst)
+	default:
+		return
+	}
+	s.Add(f)
+}
+
+// addLogRecord adds the flow of a forwarded request of an access log record.
+func (s *Suggester) addLogRecord(v *monitor.LogRecordNotifyVerbose) {
+	if v.FlowType != accesslog.TypeRequest || v.Verdict != accesslog.VerdictForwarded {
+		return
+	}
+
+	src, dst := identity.NumericIdentity(v.SrcIdentity), identity.NumericIdentity(v.DstIdentity)
+	s.SetLabels(src, labels.NewLabelsFromModel(v.SrcEpLabels).LabelArray())
+	s.SetLabels(dst, labels.NewLabelsFromModel(v.DstEpLabels).LabelArray())
+
+	f := Flow{Port: v.DstPort, Protocol: u8proto.TCP}
+	switch v.ObservationPoint {
+	case accesslog.Ingress:
+		f.Direction = Ingress
+		f.Subject, f.Peer = dst, src
+	case accesslog.Egress:
+		f.Direction = Egress
+		f.Subject, f.Peer = src, dst
+	default:
+		return
+	}
+	if v.HTTP != nil {
+		f.HTTP = &HTTPRequest{Method: v.HTTP.Method}
+		if v.HTTP.URL != nil {
+			f.HTTP.Path = v.HTTP.URL.Path
+		}
+	}
+	s.Add(f)
+}
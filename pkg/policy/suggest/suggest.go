@@ -0,0 +1,366 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suggest
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+// Description is the description of all suggested rules
+const Description = "Suggested from observed traffic"
+
+// Direction is the direction of a flow relative to the endpoint which is
+// subject to the suggested policy.
+type Direction int
+
+const (
+	// Ingress flows are received by the subject endpoint
+	Ingress Direction = iota
+	// Egress flows are initiated by the subject endpoint
+	Egress
+)
+
+// Flow is an observed flow which must be allowed by the suggested policy.
+type Flow struct {
+	Direction Direction
+	// Subject is the identity of the endpoint the policy applies to
+	Subject identity.NumericIdentity
+	// Peer is the identity of the remote side of the flow
+	Peer identity.NumericIdentity
+	// Port is the destination port of the flow. A port of 0 allows all
+	// ports of the peer.
+	Port     uint16
+	Protocol u8proto.U8proto
+	// HTTP is the observed HTTP request, if any
+	HTTP *HTTPRequest
+}
+
+// HTTPRequest is an observed HTTP request
+type HTTPRequest struct {
+	Method string
+	Path   string
+}
+
+// LabelResolver returns the labels of a security identity
+type LabelResolver func(id identity.NumericIdentity) (labels.LabelArray, error)
+
+type peerKey struct {
+	direction Direction
+	subject   identity.NumericIdentity
+	peer      identity.NumericIdentity
+}
+
+type portKey struct {
+	port  uint16
+	proto u8proto.U8proto
+}
+
+// httpSet is the set of HTTP requests observed on a port, nil if L4-only
+// traffic was observed on the port.
+type httpSet map[HTTPRequest]struct{}
+
+// Suggester aggregates observed flows and generates rules allowing them.
+type Suggester struct {
+	resolve LabelResolver
+	labels  map[identity.NumericIdentity]labels.LabelArray
+	flows   map[peerKey]map[portKey]httpSet
+}
+
+// NewSuggester returns a new Suggester which resolves the labels of
+// identities, which are not known from the observed flows, with resolve.
+func NewSuggester(resolve LabelResolver) *Suggester {
+	return &Suggester{
+		resolve: resolve,
+		labels:  map[identity.NumericIdentity]labels.LabelArray{},
+		flows:   map[peerKey]map[portKey]httpSet{},
+	}
+}
+
+// SetLabels records the labels of an identity, e.g. as carried by an access
+// log record, so that it does not need to be resolved.
+func (s *Suggester) SetLabels(id identity.NumericIdentity, lbls labels.LabelArray) {
+	if len(lbls) > 0 {
+		s.labels[id] = lbls
+	}
+}
+
+// Add adds an observed flow.
+func (s *Suggester) Add(f Flow) {
+	pk := peerKey{direction: f.Direction, subject: f.Subject, peer: f.Peer}
+	ports, ok := s.flows[pk]
+	if !ok {
+		ports = map[portKey]httpSet{}
+		s.flows[pk] = ports
+	}
+
+	// Only TCP and UDP ports can be expressed in policy, allow all
+	// ports of the peer for anything else.
+	port := portKey{port: f.Port, proto: f.Protocol}
+	if port.port == 0 || (port.proto != u8proto.TCP && port.proto != u8proto.UDP) {
+		port = portKey{}
+	}
+
+	set, ok := ports[port]
+	switch {
+	case !ok && f.HTTP != nil:
+		ports[port] = httpSet{*f.HTTP: {}}
+	case !ok:
+		ports[port] = nil
+	case set != nil && f.HTTP != nil:
+		set[*f.HTTP] = struct{}{}
+	case set != nil:
+		// L4-only traffic on a port with L7 traffic, the port
+		// can't be restricted at L7.
+		ports[port] = nil
+	}
+}
+
+// NumFlows returns the number of aggregated flows.
+func (s *Suggester) NumFlows() int {
+	n := 0
+	for _, ports := range s.flows {
+		n += len(ports)
+	}
+	return n
+}
+
+func (s *Suggester) getLabels(id identity.NumericIdentity) (labels.LabelArray, error) {
+	if lbls, ok := s.labels[id]; ok {
+		return lbls, nil
+	}
+	if s.resolve == nil {
+		return nil, fmt.Errorf("labels of identity %s are unknown", id)
+	}
+	lbls, err := s.resolve(id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve identity %s: %s", id, err)
+	}
+	s.labels[id] = lbls
+	return lbls, nil
+}
+
+// Rules returns one rule per subject identity which allows all aggregated
+// flows of that identity. Flows of subjects which can't be selected by
+// policy, e.g. the host or world, are ignored.
+func (s *Suggester) Rules() (api.Rules, error) {
+	keys := make([]peerKey, 0, len(s.flows))
+	for pk := range s.flows {
+		keys = append(keys, pk)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.subject != b.subject {
+			return a.subject < b.subject
+		}
+		if a.direction != b.direction {
+			return a.direction < b.direction
+		}
+		return a.peer < b.peer
+	})
+
+	rules := api.Rules{}
+	var rule *api.Rule
+	var ruleSubject identity.NumericIdentity
+	for _, pk := range keys {
+		subjectLabels, err := s.getLabels(pk.subject)
+		if err != nil {
+			return nil, err
+		}
+		subject := endpointLabels(subjectLabels)
+		if len(subject) == 0 {
+			continue
+		}
+		peerLabels, err := s.getLabels(pk.peer)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule == nil || ruleSubject != pk.subject {
+			rule = &api.Rule{
+				EndpointSelector: api.NewESFromLabels(subject...),
+				Description:      Description,
+			}
+			ruleSubject = pk.subject
+			rules = append(rules, rule)
+		}
+
+		toPorts := portRules(s.flows[pk])
+		switch pk.direction {
+		case Ingress:
+			ingress := api.IngressRule{ToPorts: toPorts}
+			switch cidr, entity := peerCIDR(peerLabels), peerEntity(peerLabels); {
+			case cidr != "":
+				// L4 restrictions are not supported for
+				// ingress CIDR rules.
+				ingress.FromCIDR = api.CIDRSlice{cidr}
+				ingress.ToPorts = nil
+			case entity != "":
+				ingress.FromEntities = api.EntitySlice{entity}
+			default:
+				ingress.FromEndpoints = []api.EndpointSelector{
+					api.NewESFromLabels(endpointLabels(peerLabels)...),
+				}
+			}
+			rule.Ingress = append(rule.Ingress, ingress)
+		case Egress:
+			egress := api.EgressRule{ToPorts: toPorts}
+			switch cidr, entity := peerCIDR(peerLabels), peerEntity(peerLabels); {
+			case cidr != "":
+				egress.ToCIDR = api.CIDRSlice{cidr}
+			case entity != "":
+				egress.ToEntities = api.EntitySlice{entity}
+			default:
+				egress.ToEndpoints = []api.EndpointSelector{
+					api.NewESFromLabels(endpointLabels(peerLabels)...),
+				}
+			}
+			rule.Egress = append(rule.Egress, egress)
+		}
+	}
+
+	return rules, nil
+}
+
+// portRules returns the port rules allowing the aggregated ports. Returns nil
+// if all ports must be allowed.
+func portRules(ports map[portKey]httpSet) []api.PortRule {
+	if _, ok := ports[portKey{}]; ok {
+		return nil
+	}
+
+	keys := make([]portKey, 0, len(ports))
+	for port := range ports {
+		keys = append(keys, port)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].port != keys[j].port {
+			return keys[i].port < keys[j].port
+		}
+		return keys[i].proto < keys[j].proto
+	})
+
+	l4 := api.PortRule{}
+	result := []api.PortRule{}
+	for _, port := range keys {
+		pp := api.PortProtocol{
+			Port:     strconv.Itoa(int(port.port)),
+			Protocol: api.L4Proto(strings.ToUpper(port.proto.String())),
+		}
+		set := ports[port]
+		if set == nil {
+			l4.Ports = append(l4.Ports, pp)
+			continue
+		}
+		result = append(result, api.PortRule{
+			Ports: []api.PortProtocol{pp},
+			Rules: &api.L7Rules{HTTP: httpRules(set)},
+		})
+	}
+	if len(l4.Ports) > 0 {
+		result = append([]api.PortRule{l4}, result...)
+	}
+	return result
+}
+
+func httpRules(set httpSet) []api.PortRuleHTTP {
+	result := make([]api.PortRuleHTTP, 0, len(set))
+	for req := range set {
+		result = append(result, api.PortRuleHTTP{
+			Method: req.Method,
+			Path:   regexp.QuoteMeta(req.Path),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// endpointLabels returns the labels of lbls which can be used to select an
+// endpoint.
+func endpointLabels(lbls labels.LabelArray) []*labels.Label {
+	result := []*labels.Label{}
+	for _, lbl := range lbls {
+		switch lbl.Source {
+		case labels.LabelSourceCIDR:
+			continue
+		case labels.LabelSourceReserved:
+			if peerEntity(labels.LabelArray{lbl}) != "" {
+				continue
+			}
+		}
+		result = append(result, lbl)
+	}
+	return result
+}
+
+var reservedEntities = map[string]api.Entity{
+	labels.IDNameWorld:         api.EntityWorld,
+	labels.IDNameHost:          api.EntityHost,
+	labels.IDNameRemoteNode:    api.EntityRemoteNode,
+	labels.IDNameKubeAPIServer: api.EntityKubeAPIServer,
+	labels.IDNameInit:          api.EntityInit,
+}
+
+// peerEntity returns the entity matching the reserved labels of a peer, or
+// an empty string if the peer is not an entity.
+func peerEntity(lbls labels.LabelArray) api.Entity {
+	for _, lbl := range lbls {
+		if lbl.Source != labels.LabelSourceReserved {
+			continue
+		}
+		if entity, ok := reservedEntities[lbl.Key]; ok {
+			return entity
+		}
+	}
+	return ""
+}
+
+// peerCIDR returns the most specific CIDR of the CIDR labels of a peer, or an
+// empty string if the peer has no CIDR labels.
+func peerCIDR(lbls labels.LabelArray) api.CIDR {
+	var (
+		result api.CIDR
+		best   = -1
+	)
+	for _, lbl := range lbls {
+		if lbl.Source != labels.LabelSourceCIDR {
+			continue
+		}
+		// IPv6 CIDR labels have their colons replaced by dashes
+		_, cidr, err := net.ParseCIDR(strings.Replace(lbl.Key, "-", ":", -1))
+		if err != nil {
+			continue
+		}
+		if ones, _ := cidr.Mask.Size(); ones > best {
+			best = ones
+			result = api.CIDR(cidr.String())
+		}
+	}
+	return result
+}
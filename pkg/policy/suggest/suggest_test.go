@@ -0,0 +1,175 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package suggest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type SuggestTestSuite struct{}
+
+var _ = Suite(&SuggestTestSuite{})
+
+var (
+	idFrontend = identity.NumericIdentity(1000)
+	idBackend  = identity.NumericIdentity(1001)
+	idCIDR     = identity.NumericIdentity(16777217)
+
+	testLabels = map[identity.NumericIdentity]labels.LabelArray{
+		idFrontend:                     labels.ParseLabelArray("k8s:app=frontend"),
+		idBackend:                      labels.ParseLabelArray("k8s:app=backend"),
+		identity.ReservedIdentityWorld: labels.ParseLabelArray("reserved:world"),
+		idCIDR: labels.ParseLabelArray("cidr:10.0.0.0/8", "cidr:10.1.0.0/16",
+			"reserved:world"),
+	}
+)
+
+func resolveTestLabels(id identity.NumericIdentity) (labels.LabelArray, error) {
+	if lbls, ok := testLabels[id]; ok {
+		return lbls, nil
+	}
+	return nil, fmt.Errorf("unknown identity")
+}
+
+func (s *SuggestTestSuite) TestRules(c *C) {
+	sg := NewSuggester(resolveTestLabels)
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 80, Protocol: u8proto.TCP})
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 80, Protocol: u8proto.TCP})
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 53, Protocol: u8proto.UDP})
+	sg.Add(Flow{Direction: Egress, Subject: idBackend, Peer: idCIDR, Port: 443, Protocol: u8proto.TCP})
+	sg.Add(Flow{Direction: Egress, Subject: idFrontend, Peer: identity.ReservedIdentityWorld, Protocol: u8proto.ICMP})
+	// Flows of the world can't be restricted by policy
+	sg.Add(Flow{Direction: Egress, Subject: identity.ReservedIdentityWorld, Peer: idFrontend, Port: 80, Protocol: u8proto.TCP})
+	c.Assert(sg.NumFlows(), Equals, 5)
+
+	rules, err := sg.Rules()
+	c.Assert(err, IsNil)
+	c.Assert(rules, DeepEquals, api.Rules{
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("k8s:app=frontend")),
+			Egress: []api.EgressRule{{
+				ToEntities: api.EntitySlice{api.EntityWorld},
+			}},
+			Description: Description,
+		},
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("k8s:app=backend")),
+			Ingress: []api.IngressRule{{
+				FromEndpoints: []api.EndpointSelector{
+					api.NewESFromLabels(labels.ParseSelectLabel("k8s:app=frontend")),
+				},
+				ToPorts: []api.PortRule{{
+					Ports: []api.PortProtocol{
+						{Port: "53", Protocol: api.ProtoUDP},
+						{Port: "80", Protocol: api.ProtoTCP},
+					},
+				}},
+			}},
+			Egress: []api.EgressRule{{
+				ToCIDR: api.CIDRSlice{"10.1.0.0/16"},
+				ToPorts: []api.PortRule{{
+					Ports: []api.PortProtocol{{Port: "443", Protocol: api.ProtoTCP}},
+				}},
+			}},
+			Description: Description,
+		},
+	})
+
+	for _, r := range rules {
+		c.Assert(r.Sanitize(), IsNil)
+	}
+}
+
+func (s *SuggestTestSuite) TestRulesHTTP(c *C) {
+	sg := NewSuggester(resolveTestLabels)
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 80, Protocol: u8proto.TCP,
+		HTTP: &HTTPRequest{Method: "GET", Path: "/public/index.html"}})
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 80, Protocol: u8proto.TCP,
+		HTTP: &HTTPRequest{Method: "POST", Path: "/api"}})
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 8080, Protocol: u8proto.TCP,
+		HTTP: &HTTPRequest{Method: "GET", Path: "/"}})
+	// L4-only traffic on a port removes its L7 restrictions
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: idFrontend, Port: 8080, Protocol: u8proto.TCP})
+
+	rules, err := sg.Rules()
+	c.Assert(err, IsNil)
+	c.Assert(len(rules), Equals, 1)
+	c.Assert(rules[0].Ingress, HasLen, 1)
+	c.Assert(rules[0].Ingress[0].ToPorts, DeepEquals, []api.PortRule{
+		{
+			Ports: []api.PortProtocol{{Port: "8080", Protocol: api.ProtoTCP}},
+		},
+		{
+			Ports: []api.PortProtocol{{Port: "80", Protocol: api.ProtoTCP}},
+			Rules: &api.L7Rules{HTTP: []api.PortRuleHTTP{
+				{Method: "POST", Path: "/api"},
+				{Method: "GET", Path: `/public/index\.html`},
+			}},
+		},
+	})
+	c.Assert(rules[0].Sanitize(), IsNil)
+}
+
+func (s *SuggestTestSuite) TestRulesUnknownIdentity(c *C) {
+	sg := NewSuggester(resolveTestLabels)
+	sg.Add(Flow{Direction: Ingress, Subject: idBackend, Peer: 4242, Port: 80, Protocol: u8proto.TCP})
+
+	_, err := sg.Rules()
+	c.Assert(err, Not(IsNil))
+
+	// Labels known from the flows don't need to be resolved
+	sg.SetLabels(4242, labels.ParseLabelArray("k8s:app=client"))
+	rules, err := sg.Rules()
+	c.Assert(err, IsNil)
+	c.Assert(rules, HasLen, 1)
+}
+
+func (s *SuggestTestSuite) TestAddJSON(c *C) {
+	sg := NewSuggester(resolveTestLabels)
+
+	events := []string{
+		// allowed
+		`{"type":"policy-verdict","direction":"ingress","action":"allow","protocol":"TCP","srcLabel":1000,"dstLabel":1001,"dstPort":80,"verdict":0,"audited":false}`,
+		// denied
+		`{"type":"policy-verdict","direction":"egress","action":"deny","protocol":"TCP","srcLabel":1001,"dstLabel":1000,"dstPort":22,"verdict":-133,"audited":false}`,
+		// audited
+		`{"type":"policy-verdict","direction":"egress","action":"audit","protocol":"UDP","srcLabel":1001,"dstLabel":1000,"dstPort":53,"verdict":-133,"audited":true}`,
+		// forwarded HTTP request
+		`{"type":"logRecord","observationPoint":"Ingress","flowType":"Request","verdict":"Forwarded","dstPort":8080,"srcIdentity":1000,"dstIdentity":1001,"srcEpLabels":["k8s:app=frontend"],"dstEpLabels":["k8s:app=backend"],"HTTP":{"Method":"GET","URL":{"Path":"/healthz"}}}`,
+		// unrelated event
+		`{"type":"trace","source":0}`,
+	}
+	for _, event := range events {
+		c.Assert(sg.AddJSON([]byte(event)), IsNil)
+	}
+	c.Assert(sg.NumFlows(), Equals, 3)
+
+	c.Assert(sg.AddJSON([]byte("not json")), Not(IsNil))
+}
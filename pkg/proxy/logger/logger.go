@@ -38,6 +38,7 @@ var (
 	logMutex lock.Mutex
 	logger   *lumberjack.Logger
 	notifier LogRecordNotifier
+	sinks    []RecordSink
 	logPath  string
 	metadata []string
 )
@@ -332,6 +333,11 @@ func (lr *LogRecord) Log() {
 		notifier.NewProxyLogRecord(lr)
 	}
 
+	for _, s := range sinks {
+		record := lr.LogRecord
+		s.Enqueue(&record)
+	}
+
 	if logger == nil {
 		flowdebug.Log(log.WithField(FieldFilePath, logPath),
 			"Skipping writing to access log (logger nil)")
@@ -366,6 +372,12 @@ type LogRecordNotifier interface {
 	NewProxyLogRecord(l *LogRecord) error
 }
 
+// RecordSink is the interface to implement exporting of LogRecords
+type RecordSink interface {
+	// Enqueue is called for each new log record, it must not block
+	Enqueue(r *accesslog.LogRecord)
+}
+
 // OpenLogfile opens a file for logging
 func OpenLogfile(lf string) error {
 	logMutex.Lock()
@@ -381,6 +393,13 @@ func SetNotifier(n LogRecordNotifier) {
 	logMutex.Unlock()
 }
 
+// AddSink adds a sink to export all L7 records to
+func AddSink(s RecordSink) {
+	logMutex.Lock()
+	sinks = append(sinks, s)
+	logMutex.Unlock()
+}
+
 // SetMetadata sets the metadata to include in each record
 func SetMetadata(md []string) {
 	logMutex.Lock()
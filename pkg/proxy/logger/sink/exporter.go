@@ -0,0 +1,158 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/backoff"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBufferSize is the default number of records buffered per sink
+	DefaultBufferSize = 4096
+
+	// batchSize is the maximum number of records written in one batch
+	batchSize = 100
+
+	// flushInterval is the maximum time a record is buffered before it
+	// is written
+	flushInterval = time.Second
+
+	// maxRetries is the number of times a failed batch is retried before
+	// its records are dropped
+	maxRetries = 3
+)
+
+// Exporter buffers access log records and writes them to a sink in batches.
+// Records are dropped if the buffer is full, i.e. a slow or unavailable sink
+// never blocks the proxies.
+type Exporter struct {
+	sink  Sink
+	queue chan *accesslog.LogRecord
+	done  chan struct{}
+
+	// retryBackoff is the minimal wait time before retrying a batch
+	retryBackoff time.Duration
+}
+
+// NewExporter returns an exporter writing to sink which buffers up to
+// bufferSize records. DefaultBufferSize is used if bufferSize is not
+// positive.
+func NewExporter(sink Sink, bufferSize int) *Exporter {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	e := &Exporter{
+		sink:         sink,
+		queue:        make(chan *accesslog.LogRecord, bufferSize),
+		done:         make(chan struct{}),
+		retryBackoff: 500 * time.Millisecond,
+	}
+
+	go e.run()
+
+	return e
+}
+
+// Enqueue buffers the record for export. It never blocks.
+func (e *Exporter) Enqueue(r *accesslog.LogRecord) {
+	select {
+	case e.queue <- r:
+		metrics.AccessLogSinkQueueLength.WithLabelValues(e.sink.Name()).Inc()
+	default:
+		metrics.AccessLogSinkRecords.WithLabelValues(e.sink.Name(), metrics.LabelValueOutcomeDropped).Inc()
+	}
+}
+
+// Close writes all buffered records and closes the sink. The exporter must
+// not be used afterwards.
+func (e *Exporter) Close() {
+	close(e.queue)
+	<-e.done
+}
+
+func (e *Exporter) run() {
+	defer func() {
+		if err := e.sink.Close(); err != nil {
+			log.WithError(err).WithField("sink", e.sink.Name()).Warn("Error while closing access log sink")
+		}
+		close(e.done)
+	}()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*accesslog.LogRecord, 0, batchSize)
+	for {
+		select {
+		case r, ok := <-e.queue:
+			if !ok {
+				e.write(batch)
+				return
+			}
+
+			metrics.AccessLogSinkQueueLength.WithLabelValues(e.sink.Name()).Dec()
+			batch = append(batch, r)
+			if len(batch) < batchSize {
+				continue
+			}
+
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		e.write(batch)
+		batch = batch[:0]
+	}
+}
+
+// write writes the batch to the sink, retrying with an exponential backoff,
+// and accounts the outcome
+func (e *Exporter) write(batch []*accesslog.LogRecord) {
+	if len(batch) == 0 {
+		return
+	}
+
+	name := e.sink.Name()
+	retry := backoff.Exponential{Min: e.retryBackoff, Name: name}
+	for attempt := 0; ; attempt++ {
+		err := e.sink.Write(batch)
+		if err == nil {
+			metrics.AccessLogSinkRecords.WithLabelValues(name, metrics.LabelValueOutcomeSuccess).Add(float64(len(batch)))
+			return
+		}
+
+		scopedLog := log.WithError(err).WithFields(logrus.Fields{
+			"sink":    name,
+			"records": len(batch),
+		})
+		if attempt == maxRetries {
+			scopedLog.Warn("Dropping access log records after failing to write them to sink")
+			metrics.AccessLogSinkRecords.WithLabelValues(name, metrics.LabelValueOutcomeFail).Add(float64(len(batch)))
+			return
+		}
+
+		scopedLog.Debug("Unable to write access log records to sink, retrying")
+		retry.Wait()
+	}
+}
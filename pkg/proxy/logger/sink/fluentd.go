@@ -0,0 +1,118 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/fluent/fluent-logger-golang/fluent"
+)
+
+const (
+	// defaultFluentdTag is the Fluentd tag of records unless configured
+	defaultFluentdTag = "cilium.accesslog"
+
+	// sinkTimeout is the timeout of connecting to and writing to sinks
+	sinkTimeout = 10 * time.Second
+)
+
+// fluentdSink writes batches of records to a Fluentd server using the
+// Forward mode of the forward protocol
+type fluentdSink struct {
+	name    string
+	address string
+	tag     string
+
+	// conn is nil while not connected
+	conn net.Conn
+}
+
+func newFluentdSink(u *url.URL) *fluentdSink {
+	tag := u.Query().Get("tag")
+	if tag == "" {
+		tag = defaultFluentdTag
+	}
+
+	return &fluentdSink{
+		name:    sinkName(u),
+		address: u.Host,
+		tag:     tag,
+	}
+}
+
+func (s *fluentdSink) Name() string {
+	return s.name
+}
+
+func (s *fluentdSink) Write(records []*accesslog.LogRecord) error {
+	msg := fluent.Forward{Tag: s.tag}
+	for _, r := range records {
+		ts := time.Now()
+		if t, err := time.Parse(time.RFC3339Nano, r.Timestamp); err == nil {
+			ts = t
+		}
+
+		// Records are converted to generic maps which can be encoded
+		// by msgpack using the JSON field names
+		b, err := marshalRecord(r)
+		if err != nil {
+			log.WithError(err).Warn("Unable to marshal access log record")
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal(b, &record); err != nil {
+			log.WithError(err).Warn("Unable to marshal access log record")
+			continue
+		}
+
+		msg.Entries = append(msg.Entries, fluent.Entry{Time: ts.Unix(), Record: record})
+	}
+
+	data, err := msg.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.address, sinkTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(sinkTimeout))
+	if _, err := s.conn.Write(data); err != nil {
+		s.Close()
+		return err
+	}
+
+	return nil
+}
+
+func (s *fluentdSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
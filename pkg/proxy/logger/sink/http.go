@@ -0,0 +1,78 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+)
+
+// httpSink posts batches of records as JSON arrays to an HTTP endpoint
+type httpSink struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func newHTTPSink(u *url.URL) *httpSink {
+	return &httpSink{
+		name:   sinkName(u),
+		url:    u.String(),
+		client: &http.Client{Timeout: sinkTimeout},
+	}
+}
+
+func (s *httpSink) Name() string {
+	return s.name
+}
+
+func (s *httpSink) Write(records []*accesslog.LogRecord) error {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	n := 0
+	for _, r := range records {
+		b, err := marshalRecord(r)
+		if err != nil {
+			log.WithError(err).Warn("Unable to marshal access log record")
+			continue
+		}
+		if n > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(b)
+		n++
+	}
+	body.WriteByte(']')
+
+	resp, err := s.client.Post(s.url, "application/json", &body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/optiopay/kafka"
+	"github.com/optiopay/kafka/proto"
+)
+
+// kafkaClientID is the client ID of the Kafka sink
+const kafkaClientID = "cilium-accesslog"
+
+// kafkaSink produces each record as a JSON message to a Kafka topic
+type kafkaSink struct {
+	name      string
+	brokers   []string
+	topic     string
+	partition int32
+
+	// dial connects to the brokers, replaced in tests
+	dial func(brokers []string) (kafka.Producer, func(), error)
+
+	// producer is nil while not connected, closeFn closes the broker
+	// connections of producer
+	producer kafka.Producer
+	closeFn  func()
+}
+
+func newKafkaSink(u *url.URL) (*kafkaSink, error) {
+	topic := strings.Trim(u.Path, "/")
+	if topic == "" {
+		return nil, fmt.Errorf("missing Kafka topic in path")
+	}
+
+	var partition int64
+	if p := u.Query().Get("partition"); p != "" {
+		var err error
+		if partition, err = strconv.ParseInt(p, 10, 32); err != nil {
+			return nil, fmt.Errorf("invalid Kafka partition %q: %s", p, err)
+		}
+	}
+
+	return &kafkaSink{
+		name:      sinkName(u),
+		brokers:   strings.Split(u.Host, ","),
+		topic:     topic,
+		partition: int32(partition),
+		dial:      dialKafka,
+	}, nil
+}
+
+// dialKafka connects to the Kafka brokers
+func dialKafka(brokers []string) (kafka.Producer, func(), error) {
+	conf := kafka.NewBrokerConf(kafkaClientID)
+	conf.DialTimeout = sinkTimeout
+	conf.DialRetryLimit = 1
+	broker, err := kafka.Dial(brokers, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prodConf := kafka.NewProducerConf()
+	prodConf.RequiredAcks = proto.RequiredAcksLocal
+	prodConf.RetryLimit = 1
+	return broker.Producer(prodConf), broker.Close, nil
+}
+
+func (s *kafkaSink) Name() string {
+	return s.name
+}
+
+func (s *kafkaSink) Write(records []*accesslog.LogRecord) error {
+	messages := make([]*proto.Message, 0, len(records))
+	for _, r := range records {
+		b, err := marshalRecord(r)
+		if err != nil {
+			log.WithError(err).Warn("Unable to marshal access log record")
+			continue
+		}
+		messages = append(messages, &proto.Message{Value: b})
+	}
+
+	if s.producer == nil {
+		producer, closeFn, err := s.dial(s.brokers)
+		if err != nil {
+			return err
+		}
+		s.producer, s.closeFn = producer, closeFn
+	}
+
+	if _, err := s.producer.Produce(s.topic, s.partition, messages...); err != nil {
+		s.Close()
+		return err
+	}
+
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+	if s.producer == nil {
+		return nil
+	}
+
+	s.closeFn()
+	s.producer, s.closeFn = nil, nil
+	return nil
+}
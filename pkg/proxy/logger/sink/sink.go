@@ -0,0 +1,85 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "accesslog-sink")
+
+// Sink writes access log records to an external system. Sinks are not safe
+// for concurrent use, they are driven by a single Exporter.
+type Sink interface {
+	// Name identifies the sink in logs and metrics
+	Name() string
+
+	// Write writes a batch of records. Records of a failed batch may be
+	// retried.
+	Write(records []*accesslog.LogRecord) error
+
+	// Close releases all resources of the sink
+	Close() error
+}
+
+// NewSink returns the sink configured by the URL rawurl. The scheme of the
+// URL selects the sink:
+//
+//	syslog://host:514?tag=cilium    syslog over UDP (syslog+tcp for TCP)
+//	fluentd://host:24224?tag=cilium Fluentd forward protocol
+//	kafka://host1:9092,host2:9092/topic?partition=0
+//	http://host/path                HTTP POST of JSON arrays (or https)
+func NewSink(rawurl string) (Sink, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host in %q", rawurl)
+	}
+
+	switch u.Scheme {
+	case "syslog", "syslog+udp":
+		return newSyslogSink(u, "udp"), nil
+	case "syslog+tcp":
+		return newSyslogSink(u, "tcp"), nil
+	case "fluentd":
+		return newFluentdSink(u), nil
+	case "kafka":
+		return newKafkaSink(u)
+	case "http", "https":
+		return newHTTPSink(u), nil
+	default:
+		return nil, fmt.Errorf("unsupported access log sink %q", u.Scheme)
+	}
+}
+
+// sinkName returns the name of the sink configured by u, omitting
+// credentials and parameters
+func sinkName(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// marshalRecord returns the JSON representation of the record as written to
+// the access log file
+func marshalRecord(r *accesslog.LogRecord) ([]byte, error) {
+	return json.Marshal(r)
+}
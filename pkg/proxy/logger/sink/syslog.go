@@ -0,0 +1,89 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"log/syslog"
+	"net/url"
+
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+)
+
+// defaultSyslogTag is the syslog tag of records unless configured
+const defaultSyslogTag = "cilium-accesslog"
+
+// syslogSink writes each record as a JSON message to a syslog server
+type syslogSink struct {
+	name    string
+	network string
+	address string
+	tag     string
+
+	// writer is nil while not connected
+	writer *syslog.Writer
+}
+
+func newSyslogSink(u *url.URL, network string) *syslogSink {
+	tag := u.Query().Get("tag")
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+
+	return &syslogSink{
+		name:    sinkName(u),
+		network: network,
+		address: u.Host,
+		tag:     tag,
+	}
+}
+
+func (s *syslogSink) Name() string {
+	return s.name
+}
+
+func (s *syslogSink) Write(records []*accesslog.LogRecord) error {
+	if s.writer == nil {
+		w, err := syslog.Dial(s.network, s.address, syslog.LOG_INFO|syslog.LOG_DAEMON, s.tag)
+		if err != nil {
+			return err
+		}
+		s.writer = w
+	}
+
+	for _, r := range records {
+		msg, err := marshalRecord(r)
+		if err != nil {
+			log.WithError(err).Warn("Unable to marshal access log record")
+			continue
+		}
+
+		if err := s.writer.Info(string(msg)); err != nil {
+			s.Close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
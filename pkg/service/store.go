@@ -0,0 +1,155 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/store"
+	"github.com/cilium/cilium/pkg/loadbalancer"
+)
+
+var (
+	// ServiceStorePrefix is the kvstore prefix of the shared store for
+	// global services. The services of each cluster are stored in a
+	// subdirectory named after the cluster.
+	//
+	// WARNING - STABLE API: Changing the structure or values of this will
+	// break backwards compatibility
+	ServiceStorePrefix = path.Join(kvstore.BaseKeyPrefix, "state", "services", "v1")
+
+	// KeyCreator creates a cluster service for a shared store
+	KeyCreator = func() store.Key {
+		s := ClusterService{}
+		return &s
+	}
+)
+
+// ClusterService is the definition of a global service as shared with other
+// clusters. Only the backends are shared, the frontend of a global service
+// is defined individually in each cluster. Each node of a cluster shares the
+// service under its own key so the service is not withdrawn when a single
+// node goes away, readers merge the keys of all nodes of a cluster.
+type ClusterService struct {
+	// Cluster is the name of the cluster the backends belong to
+	Cluster string `json:"cluster"`
+
+	// Node is the name of the node sharing the service
+	Node string `json:"node"`
+
+	// Namespace is the namespace of the service
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the service. It must be identical in all
+	// clusters for the backends to be merged.
+	Name string `json:"name"`
+
+	// Backends is the set of backend IPs of the service
+	Backends map[string]bool `json:"backends"`
+
+	// Ports is the map of backend ports indexed by port name
+	Ports map[loadbalancer.FEPortName]*loadbalancer.L4Addr `json:"ports"`
+}
+
+// NewClusterService returns the cluster service representing the backends
+// of the local endpoint ep of a service as shared by the given node of the
+// given cluster
+func NewClusterService(cluster, node string, svc loadbalancer.K8sServiceNamespace, ep *loadbalancer.K8sServiceEndpoint) *ClusterService {
+	s := &ClusterService{
+		Cluster:   cluster,
+		Node:      node,
+		Namespace: svc.Namespace,
+		Name:      svc.ServiceName,
+		Backends:  map[string]bool{},
+		Ports:     map[loadbalancer.FEPortName]*loadbalancer.L4Addr{},
+	}
+
+	if ep != nil {
+		for ip := range ep.BEIPs {
+			s.Backends[ip] = true
+		}
+		for name, port := range ep.Ports {
+			s.Ports[name] = port
+		}
+	}
+
+	return s
+}
+
+// NamespacedName returns the namespace and name of the service
+func (s *ClusterService) NamespacedName() loadbalancer.K8sServiceNamespace {
+	return loadbalancer.K8sServiceNamespace{
+		Namespace:   s.Namespace,
+		ServiceName: s.Name,
+	}
+}
+
+// Endpoint returns the backends of the service as service endpoint
+func (s *ClusterService) Endpoint() *loadbalancer.K8sServiceEndpoint {
+	ep := loadbalancer.NewK8sServiceEndpoint()
+	for ip := range s.Backends {
+		ep.BEIPs[ip] = true
+	}
+	for name, port := range s.Ports {
+		ep.Ports[name] = port
+	}
+	return ep
+}
+
+// GetKeyName returns the kvstore key to be used for the cluster service
+func (s *ClusterService) GetKeyName() string {
+	// WARNING - STABLE API: Changing the structure of the key may break
+	// backwards compatibility
+	return path.Join(s.Cluster, s.Namespace, s.Name, s.Node)
+}
+
+// OnDelete is called when the cluster service has been deleted from the
+// shared store
+func (s *ClusterService) OnDelete() {}
+
+// OnUpdate is called when the cluster service has been updated in the shared
+// store
+func (s *ClusterService) OnUpdate() {}
+
+// Marshal returns the cluster service object as JSON byte slice
+func (s *ClusterService) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Unmarshal parses the JSON byte slice and updates the cluster service
+// receiver. The receiver is replaced as a whole so backends removed
+// remotely do not linger.
+func (s *ClusterService) Unmarshal(data []byte) error {
+	newService := ClusterService{}
+	if err := json.Unmarshal(data, &newService); err != nil {
+		return err
+	}
+
+	*s = newService
+	return nil
+}
+
+// JoinClusterServiceStore joins the shared store holding the global services
+// of the given cluster
+func JoinClusterServiceStore(cluster string) (*store.SharedStore, error) {
+	return store.JoinSharedStore(store.Configuration{
+		Prefix:                  path.Join(ServiceStorePrefix, cluster),
+		KeyCreator:              KeyCreator,
+		SynchronizationInterval: time.Minute,
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package service
+
+import (
+	"github.com/cilium/cilium/pkg/loadbalancer"
+
+	. "gopkg.in/check.v1"
+)
+
+type ClusterServiceSuite struct{}
+
+var _ = Suite(&ClusterServiceSuite{})
+
+func (s *ClusterServiceSuite) TestClusterService(c *C) {
+	ep := loadbalancer.NewK8sServiceEndpoint()
+	ep.BEIPs["10.0.0.1"] = true
+	ep.BEIPs["10.0.0.2"] = true
+	ep.Ports["http"] = loadbalancer.NewL4Addr(loadbalancer.TCP, 80)
+
+	svc := loadbalancer.K8sServiceNamespace{Namespace: "default", ServiceName: "foo"}
+	clusterService := NewClusterService("cluster1", "node1", svc, ep)
+	c.Assert(clusterService.GetKeyName(), Equals, "cluster1/default/foo/node1")
+	c.Assert(clusterService.NamespacedName(), Equals, svc)
+	c.Assert(clusterService.Endpoint().DeepEqual(ep), Equals, true)
+
+	data, err := clusterService.Marshal()
+	c.Assert(err, IsNil)
+
+	// Unmarshal must replace the backends rather than merge them
+	key := KeyCreator().(*ClusterService)
+	key.Backends = map[string]bool{"10.0.0.3": true}
+	c.Assert(key.Unmarshal(data), IsNil)
+	c.Assert(key, DeepEquals, clusterService)
+}